@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+// DefaultMaxBodyBytes is the largest JSON request body accepted unless
+// configured otherwise. It leaves room for the default 10000 characters of
+// comment content at up to four bytes each, plus the other fields.
+const DefaultMaxBodyBytes = 64 << 10
+
+// errTrailingData reports a body with more after its JSON value
+var errTrailingData = errors.New("unexpected data after the JSON value")
+
+// decodeBody decodes the JSON body of r into v, reading no more than the
+// handler's body limit. The body must be a single JSON value, and fields v
+// doesn't have are refused, so a typo such as "contnet" is reported as such
+// rather than as missing content. When it fails it has answered the request:
+// 413 for a body over the limit, 400 for anything else.
+func (h *CommentHandler) decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	return h.decode(w, r, v, false)
+}
+
+// decodeOptionalBody is decodeBody for endpoints whose body may be left
+// empty, leaving v as it was
+func (h *CommentHandler) decodeOptionalBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	return h.decode(w, r, v, true)
+}
+
+func (h *CommentHandler) decode(w http.ResponseWriter, r *http.Request, v interface{}, optional bool) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodyBytes))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(v)
+	if err == nil {
+		err = expectEnd(decoder)
+	}
+	if err == nil || (optional && err == io.EOF) {
+		return true
+	}
+
+	var tooLarge *http.MaxBytesError
+	field := unknownField(err)
+	switch {
+	case errors.As(err, &tooLarge):
+		h.sendErrorResponse(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds %d bytes", tooLarge.Limit))
+	case field != "":
+		message := fmt.Sprintf("Unknown field %q in request body", field)
+		h.sendJSONResponse(w, http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   message,
+			Fields:  []service.FieldError{{Field: field, Rule: "unknown", Message: message}},
+		})
+	case errors.Is(err, models.ErrInvalidVoteType):
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, errTrailingData):
+		h.sendErrorResponse(w, http.StatusBadRequest, "Invalid JSON format: "+err.Error())
+	default:
+		h.sendErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+	}
+	return false
+}
+
+// expectEnd checks that nothing but whitespace follows the value decoder
+// has read, returning errTrailingData if something does, or the read error
+func expectEnd(decoder *json.Decoder) error {
+	_, err := decoder.Token()
+	var tooLarge *http.MaxBytesError
+	switch {
+	case err == io.EOF:
+		return nil
+	case errors.As(err, &tooLarge):
+		return err
+	default:
+		return errTrailingData
+	}
+}
+
+// unknownField returns the field named by the error encoding/json reports
+// for an unknown field, which has no type of its own, or "" for other errors
+func unknownField(err error) string {
+	quoted, ok := strings.CutPrefix(err.Error(), "json: unknown field ")
+	if !ok {
+		return ""
+	}
+	field, err := strconv.Unquote(quoted)
+	if err != nil {
+		return quoted
+	}
+	return field
+}
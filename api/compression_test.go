@@ -0,0 +1,166 @@
+package api_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/api"
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// seedLargeTree creates a root whose tree serializes to well over the
+// compression threshold
+func seedLargeTree(t *testing.T, commentService *service.CommentService) {
+	t.Helper()
+	ctx := context.Background()
+	for i := 0; i < 20; i++ {
+		parent, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: strings.Repeat("A long comment. ", 20)})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		if _, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: &parent.ID, UserID: "bob", Content: strings.Repeat("A long reply. ", 20)}); err != nil {
+			t.Fatalf("Failed to create reply: %v", err)
+		}
+	}
+}
+
+func TestCompression_GzipsLargeTree(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+	seedLargeTree(t, commentService)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/roots/post-1/tree", nil)
+	req.Header.Set("Accept-Encoding", "br;q=1.0, gzip;q=0.8")
+	rec := httptest.NewRecorder()
+
+	// Execute
+	router.ServeHTTP(rec, req)
+
+	// Assert
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Expected Content-Encoding gzip, got %q", got)
+	}
+	if got := rec.Header().Get("Content-Length"); got != "" {
+		t.Errorf("Expected no Content-Length on a compressed body, got %q", got)
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "application/json") {
+		t.Errorf("Expected the JSON content type to survive compression, got %q", got)
+	}
+	if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+		t.Errorf("Expected Vary: Accept-Encoding, got %q", got)
+	}
+	compressed := rec.Body.Len()
+	reader, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("Expected a gzip body, got: %v", err)
+	}
+	plain, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Failed to decompress body: %v", err)
+	}
+	if compressed >= len(plain) {
+		t.Errorf("Expected the body to shrink, got %d compressed bytes for %d plain", compressed, len(plain))
+	}
+	var response struct {
+		Data []*models.CommentTree `json:"data"`
+	}
+	if err := json.Unmarshal(plain, &response); err != nil {
+		t.Fatalf("Failed to decode tree: %v", err)
+	}
+	if len(response.Data) != 20 {
+		t.Errorf("Expected 20 threads, got %d", len(response.Data))
+	}
+}
+
+func TestCompression_SkipsSmallAndUnacceptedResponses(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+	seedLargeTree(t, commentService)
+
+	cases := map[string]struct {
+		path           string
+		acceptEncoding string
+	}{
+		"small body":       {path: "/health/live", acceptEncoding: "gzip"},
+		"no gzip accepted": {path: "/api/v1/roots/post-1/tree", acceptEncoding: ""},
+		"gzip refused":     {path: "/api/v1/roots/post-1/tree", acceptEncoding: "gzip;q=0, identity"},
+		"not modified":     {path: "/api/v1/roots/post-1/tree", acceptEncoding: "gzip"},
+		"small error":      {path: "/api/v1/comments/missing", acceptEncoding: "gzip"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			}
+			if name == "not modified" {
+				first := httptest.NewRecorder()
+				router.ServeHTTP(first, httptest.NewRequest(http.MethodGet, tc.path, nil))
+				req.Header.Set("If-None-Match", first.Header().Get("ETag"))
+			}
+			rec := httptest.NewRecorder()
+
+			// Execute
+			router.ServeHTTP(rec, req)
+
+			// Assert
+			if got := rec.Header().Get("Content-Encoding"); got != "" {
+				t.Errorf("Expected an uncompressed response, got Content-Encoding %q", got)
+			}
+			if rec.Code == http.StatusOK && !json.Valid(rec.Body.Bytes()) {
+				t.Errorf("Expected a plain JSON body, got %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestFiberAdapter_Compression(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	seedLargeTree(t, commentService)
+	app := fiber.New()
+	adapter := api.NewFiberAdapter(commentService)
+	adapter.EnableCompression(0)
+	adapter.RegisterRoutes(app)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/roots/post-1/tree", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	// Execute
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to get tree: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Assert
+	if got := resp.Header.Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Expected Content-Encoding gzip, got %q", got)
+	}
+	reader, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatalf("Expected a gzip body, got: %v", err)
+	}
+	var response struct {
+		Data []*models.CommentTree `json:"data"`
+	}
+	if err := json.NewDecoder(reader).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode tree: %v", err)
+	}
+	if len(response.Data) != 20 {
+		t.Errorf("Expected 20 threads, got %d", len(response.Data))
+	}
+}
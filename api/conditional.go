@@ -0,0 +1,62 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+// commentETag identifies one state of a comment. The version moves with
+// edits and the update time with everything else, votes included, so the
+// version stays readable at the front for If-Match.
+func commentETag(comment *models.Comment) string {
+	return strconv.Quote(strconv.FormatInt(comment.Version, 10) + "-" + strconv.FormatInt(comment.UpdatedAt.UnixNano(), 36))
+}
+
+// rootETag identifies what a read of the root returns for this request. The
+// root's version changes with any write to its comments; the query and the
+// viewer are mixed in because they change the response for the same data.
+// It is weak since the body may still differ in ways that don't matter, such
+// as a relative time.
+func rootETag(version *models.RootVersion, r *http.Request, viewerID string) string {
+	var lastUpdated int64
+	if version.LastUpdatedAt != nil {
+		lastUpdated = version.LastUpdatedAt.UnixNano()
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%d\x00%s\x00%s",
+		version.RootID, version.CommentCount, lastUpdated, r.URL.RawQuery, viewerID)))
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified answers 304 Not Modified and reports true when the request's
+// If-None-Match already names etag. Otherwise the caller serves the full
+// response, setting the ETag header itself so errors don't carry one.
+func notModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	if !etagMatches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	w.Header().Set("ETag", etag)
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches applies the weak comparison If-None-Match calls for to a
+// header that may list several tags or be "*"
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
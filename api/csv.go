@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/csv"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+// csvColumns is the header row of the CSV exports
+var csvColumns = []string{"id", "parent_id", "user_id", "content", "score", "depth", "created_at"}
+
+// csvRecord flattens a comment into a row matching csvColumns
+func csvRecord(comment *models.Comment) []string {
+	parentID := ""
+	if comment.ParentID != nil {
+		parentID = *comment.ParentID
+	}
+	return []string{
+		comment.ID,
+		parentID,
+		comment.UserID,
+		comment.Content,
+		strconv.FormatInt(comment.Score, 10),
+		strconv.Itoa(comment.Depth),
+		comment.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// writeCommentsCSV streams the comments produced by export as a CSV
+// attachment. The header row is written before export starts, so a failure
+// part way leaves the client with a truncated file rather than an error
+// response; callers validate their input first.
+func writeCommentsCSV(w http.ResponseWriter, filename string, export func(fn func(*models.Comment) error) error) error {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvColumns); err != nil {
+		return err
+	}
+
+	err := export(func(comment *models.Comment) error {
+		return writer.Write(csvRecord(comment))
+	})
+	writer.Flush()
+	if err != nil {
+		return err
+	}
+	return writer.Error()
+}
@@ -10,77 +10,186 @@ import (
 
 // EchoAdapter wraps the CommentHandler for Echo framework
 type EchoAdapter struct {
-	handler *CommentHandler
+	handler    *CommentHandler
+	health     *healthChecks
+	middleware []echo.MiddlewareFunc
 }
 
 // NewEchoAdapter creates a new Echo adapter for Commentific
 func NewEchoAdapter(commentService *service.CommentService) *EchoAdapter {
 	return &EchoAdapter{
 		handler: NewCommentHandler(commentService),
+		health:  newHealthChecks(nil),
 	}
 }
 
+// SetHealthChecker makes the health endpoints check checker, e.g. a
+// postgres.PostgresProvider, and report 503 when it fails, or, if it is a
+// SchemaChecker, until its schema check first passes
+func (a *EchoAdapter) SetHealthChecker(checker HealthChecker) {
+	a.health = newHealthChecks(checker)
+}
+
+// SetModeratorCheck decides which requests come from moderators; see
+// RouterConfig.IsModerator
+func (a *EchoAdapter) SetModeratorCheck(check func(r *http.Request) bool) {
+	a.handler.SetModeratorCheck(check)
+}
+
+// SetAdminCheck decides which requests come from admins; see
+// RouterConfig.IsAdmin
+func (a *EchoAdapter) SetAdminCheck(check func(r *http.Request) bool) {
+	a.handler.SetAdminCheck(check)
+}
+
+// SetMaxBodyBytes caps the size of JSON request bodies; see
+// RouterConfig.MaxBodyBytes
+func (a *EchoAdapter) SetMaxBodyBytes(limit int64) {
+	a.handler.SetMaxBodyBytes(limit)
+}
+
+// EnableCompression gzips API responses of at least minSize bytes for
+// clients that accept gzip, as NewRouter does. Call it before RegisterRoutes.
+func (a *EchoAdapter) EnableCompression(minSize int) {
+	a.middleware = append(a.middleware, echo.WrapMiddleware(CompressionMiddleware(minSize)))
+}
+
 // RegisterRoutes registers all Commentific routes with an Echo instance
 func (a *EchoAdapter) RegisterRoutes(e *echo.Echo) {
 	// Create API group
-	api := e.Group("/api/v1")
+	api := e.Group("/api/v1", a.middleware...)
 
 	// Comment operations
 	api.POST("/comments", a.CreateComment)
+	api.POST("/comments/validate", a.ValidateComment)
 	api.GET("/comments/:id", a.GetComment)
 	api.PUT("/comments/:id", a.UpdateComment)
 	api.DELETE("/comments/:id", a.DeleteComment)
 	api.GET("/comments/:id/path", a.GetCommentPath)
+	api.GET("/comments/:id/ancestors", a.GetCommentAncestors)
 	api.GET("/comments/:id/children", a.GetCommentChildren)
+	api.GET("/comments/:id/tree", a.GetCommentSubtree)
+	api.PATCH("/comments/:id/parent", a.MoveComment)
+	api.GET("/comments/:id/link-preview", a.GetLinkPreview)
+
+	// Moderation
+	api.POST("/comments/:id/approve", a.ApproveComment)
+	api.POST("/comments/:id/reject", a.RejectComment)
+	api.POST("/comments/:id/remove", a.RemoveComment)
+	api.GET("/roots/:root_id/moderation-queue", a.GetModerationQueue)
+	api.GET("/roots/:root_id/settings", a.GetRootSettings)
+	api.PUT("/roots/:root_id/settings", a.UpdateRootSettings)
+	api.PUT("/roots/:root_id/subscription", a.Subscribe)
+	api.DELETE("/roots/:root_id/subscription", a.Unsubscribe)
 
 	// Voting operations
 	api.POST("/comments/:id/vote", a.VoteComment)
+	api.PATCH("/comments/:id/vote", a.VoteComment)
 	api.DELETE("/comments/:id/vote", a.RemoveVote)
+	api.GET("/comments/:id/vote", a.GetUserVote)
+	api.GET("/comments/:id/votes/summary", a.GetVoteBreakdown)
 
 	// Root-based operations
 	api.GET("/roots/:root_id/comments", a.GetCommentsByRoot)
+	api.GET("/roots/:root_id/comments/since", a.GetCommentsSince)
 	api.GET("/roots/:root_id/comments/with-votes", a.GetCommentsWithVotes)
 	api.GET("/roots/:root_id/tree", a.GetCommentTree)
+	api.GET("/roots/:root_id/tree/with-votes", a.GetCommentTreeWithVotes)
+	api.GET("/roots/:root_id/export", a.ExportRoot)
+	api.GET("/roots/:root_id/export.csv", a.ExportRootCSV)
 	api.GET("/roots/:root_id/stats", a.GetCommentStats)
 	api.GET("/roots/:root_id/top", a.GetTopComments)
+	api.GET("/roots/:root_id/top-commenters", a.GetTopCommenters)
 	api.GET("/roots/:root_id/search", a.SearchComments)
 
 	// User operations
 	api.GET("/users/:user_id/comments", a.GetCommentsByUser)
+	api.GET("/users/:user_id/votes", a.GetUserVotes)
+	api.DELETE("/users/:user_id/votes", a.RemoveUserVotes)
+	api.GET("/users/:user_id/replies", a.GetRepliesToUser)
 	api.GET("/users/:user_id/count", a.GetUserCommentCount)
-
-	// Health check
+	api.GET("/users/:user_id/reputation", a.GetUserReputation)
+	api.GET("/users/:user_id/blocks", a.GetBlockedUsers)
+	api.PUT("/users/:user_id/blocks/:blocked_id", a.BlockUser)
+	api.DELETE("/users/:user_id/blocks/:blocked_id", a.UnblockUser)
+	api.GET("/users/:user_id/export.csv", a.ExportUserCSV)
+
+	// Cross-root search and trending
+	api.GET("/search", a.SearchAllComments)
+	api.GET("/trending", a.GetTrendingRoots)
+	api.GET("/limits", a.GetLimits)
+
+	// Health checks
 	e.GET("/health", a.HealthCheck)
+	e.GET("/health/ready", a.HealthCheck)
+	e.GET("/health/detailed", a.DetailedHealthCheck)
+	e.GET("/health/live", a.LivenessCheck)
 }
 
 // RegisterRoutesWithPrefix registers routes with a custom prefix
 func (a *EchoAdapter) RegisterRoutesWithPrefix(e *echo.Echo, prefix string) {
 	// Create API group with custom prefix
-	api := e.Group(prefix)
+	api := e.Group(prefix, a.middleware...)
 
 	// Comment operations
 	api.POST("/comments", a.CreateComment)
+	api.POST("/comments/validate", a.ValidateComment)
 	api.GET("/comments/:id", a.GetComment)
 	api.PUT("/comments/:id", a.UpdateComment)
 	api.DELETE("/comments/:id", a.DeleteComment)
 	api.GET("/comments/:id/path", a.GetCommentPath)
+	api.GET("/comments/:id/ancestors", a.GetCommentAncestors)
 	api.GET("/comments/:id/children", a.GetCommentChildren)
+	api.GET("/comments/:id/tree", a.GetCommentSubtree)
+	api.PATCH("/comments/:id/parent", a.MoveComment)
+	api.GET("/comments/:id/link-preview", a.GetLinkPreview)
+
+	// Moderation
+	api.POST("/comments/:id/approve", a.ApproveComment)
+	api.POST("/comments/:id/reject", a.RejectComment)
+	api.POST("/comments/:id/remove", a.RemoveComment)
+	api.GET("/roots/:root_id/moderation-queue", a.GetModerationQueue)
+	api.GET("/roots/:root_id/settings", a.GetRootSettings)
+	api.PUT("/roots/:root_id/settings", a.UpdateRootSettings)
+	api.PUT("/roots/:root_id/subscription", a.Subscribe)
+	api.DELETE("/roots/:root_id/subscription", a.Unsubscribe)
 
 	// Voting operations
 	api.POST("/comments/:id/vote", a.VoteComment)
+	api.PATCH("/comments/:id/vote", a.VoteComment)
 	api.DELETE("/comments/:id/vote", a.RemoveVote)
+	api.GET("/comments/:id/vote", a.GetUserVote)
+	api.GET("/comments/:id/votes/summary", a.GetVoteBreakdown)
 
 	// Root-based operations
 	api.GET("/roots/:root_id/comments", a.GetCommentsByRoot)
+	api.GET("/roots/:root_id/comments/since", a.GetCommentsSince)
 	api.GET("/roots/:root_id/comments/with-votes", a.GetCommentsWithVotes)
 	api.GET("/roots/:root_id/tree", a.GetCommentTree)
+	api.GET("/roots/:root_id/tree/with-votes", a.GetCommentTreeWithVotes)
+	api.GET("/roots/:root_id/export", a.ExportRoot)
+	api.GET("/roots/:root_id/export.csv", a.ExportRootCSV)
 	api.GET("/roots/:root_id/stats", a.GetCommentStats)
 	api.GET("/roots/:root_id/top", a.GetTopComments)
+	api.GET("/roots/:root_id/top-commenters", a.GetTopCommenters)
 	api.GET("/roots/:root_id/search", a.SearchComments)
 
 	// User operations
 	api.GET("/users/:user_id/comments", a.GetCommentsByUser)
+	api.GET("/users/:user_id/votes", a.GetUserVotes)
+	api.DELETE("/users/:user_id/votes", a.RemoveUserVotes)
+	api.GET("/users/:user_id/replies", a.GetRepliesToUser)
 	api.GET("/users/:user_id/count", a.GetUserCommentCount)
+	api.GET("/users/:user_id/reputation", a.GetUserReputation)
+	api.GET("/users/:user_id/blocks", a.GetBlockedUsers)
+	api.PUT("/users/:user_id/blocks/:blocked_id", a.BlockUser)
+	api.DELETE("/users/:user_id/blocks/:blocked_id", a.UnblockUser)
+	api.GET("/users/:user_id/export.csv", a.ExportUserCSV)
+
+	// Cross-root search and trending
+	api.GET("/search", a.SearchAllComments)
+	api.GET("/trending", a.GetTrendingRoots)
+	api.GET("/limits", a.GetLimits)
 }
 
 // Echo handler adapters - these convert Echo contexts to http.Request/ResponseWriter
@@ -90,6 +199,11 @@ func (a *EchoAdapter) CreateComment(c echo.Context) error {
 	return nil
 }
 
+func (a *EchoAdapter) ValidateComment(c echo.Context) error {
+	a.handler.ValidateComment(c.Response().Writer, c.Request())
+	return nil
+}
+
 func (a *EchoAdapter) GetComment(c echo.Context) error {
 	// Convert Echo params to mux.Vars format
 	req := c.Request()
@@ -119,6 +233,20 @@ func (a *EchoAdapter) GetCommentPath(c echo.Context) error {
 	return nil
 }
 
+func (a *EchoAdapter) GetCommentAncestors(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"id": c.Param("id")})
+	a.handler.GetCommentAncestors(c.Response().Writer, req)
+	return nil
+}
+
+func (a *EchoAdapter) GetCommentSubtree(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"id": c.Param("id")})
+	a.handler.GetCommentSubtree(c.Response().Writer, req)
+	return nil
+}
+
 func (a *EchoAdapter) GetCommentChildren(c echo.Context) error {
 	req := c.Request()
 	req = addMuxVars(req, map[string]string{"id": c.Param("id")})
@@ -126,6 +254,76 @@ func (a *EchoAdapter) GetCommentChildren(c echo.Context) error {
 	return nil
 }
 
+func (a *EchoAdapter) MoveComment(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"id": c.Param("id")})
+	a.handler.MoveComment(c.Response().Writer, req)
+	return nil
+}
+
+func (a *EchoAdapter) GetLinkPreview(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"id": c.Param("id")})
+	a.handler.GetLinkPreview(c.Response().Writer, req)
+	return nil
+}
+
+func (a *EchoAdapter) ApproveComment(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"id": c.Param("id")})
+	a.handler.ApproveComment(c.Response().Writer, req)
+	return nil
+}
+
+func (a *EchoAdapter) RejectComment(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"id": c.Param("id")})
+	a.handler.RejectComment(c.Response().Writer, req)
+	return nil
+}
+
+func (a *EchoAdapter) RemoveComment(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"id": c.Param("id")})
+	a.handler.RemoveComment(c.Response().Writer, req)
+	return nil
+}
+
+func (a *EchoAdapter) GetModerationQueue(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"root_id": c.Param("root_id")})
+	a.handler.GetModerationQueue(c.Response().Writer, req)
+	return nil
+}
+
+func (a *EchoAdapter) GetRootSettings(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"root_id": c.Param("root_id")})
+	a.handler.GetRootSettings(c.Response().Writer, req)
+	return nil
+}
+
+func (a *EchoAdapter) UpdateRootSettings(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"root_id": c.Param("root_id")})
+	a.handler.UpdateRootSettings(c.Response().Writer, req)
+	return nil
+}
+
+func (a *EchoAdapter) Subscribe(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"root_id": c.Param("root_id")})
+	a.handler.Subscribe(c.Response().Writer, req)
+	return nil
+}
+
+func (a *EchoAdapter) Unsubscribe(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"root_id": c.Param("root_id")})
+	a.handler.Unsubscribe(c.Response().Writer, req)
+	return nil
+}
+
 func (a *EchoAdapter) VoteComment(c echo.Context) error {
 	req := c.Request()
 	req = addMuxVars(req, map[string]string{"id": c.Param("id")})
@@ -140,6 +338,20 @@ func (a *EchoAdapter) RemoveVote(c echo.Context) error {
 	return nil
 }
 
+func (a *EchoAdapter) GetUserVote(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"id": c.Param("id")})
+	a.handler.GetUserVote(c.Response().Writer, req)
+	return nil
+}
+
+func (a *EchoAdapter) GetVoteBreakdown(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"id": c.Param("id")})
+	a.handler.GetVoteBreakdown(c.Response().Writer, req)
+	return nil
+}
+
 func (a *EchoAdapter) GetCommentsByRoot(c echo.Context) error {
 	req := c.Request()
 	req = addMuxVars(req, map[string]string{"root_id": c.Param("root_id")})
@@ -147,6 +359,13 @@ func (a *EchoAdapter) GetCommentsByRoot(c echo.Context) error {
 	return nil
 }
 
+func (a *EchoAdapter) GetCommentsSince(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"root_id": c.Param("root_id")})
+	a.handler.GetCommentsSince(c.Response().Writer, req)
+	return nil
+}
+
 func (a *EchoAdapter) GetCommentsWithVotes(c echo.Context) error {
 	req := c.Request()
 	req = addMuxVars(req, map[string]string{"root_id": c.Param("root_id")})
@@ -154,6 +373,13 @@ func (a *EchoAdapter) GetCommentsWithVotes(c echo.Context) error {
 	return nil
 }
 
+func (a *EchoAdapter) GetCommentTreeWithVotes(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"root_id": c.Param("root_id")})
+	a.handler.GetCommentTreeWithVotes(c.Response().Writer, req)
+	return nil
+}
+
 func (a *EchoAdapter) GetCommentTree(c echo.Context) error {
 	req := c.Request()
 	req = addMuxVars(req, map[string]string{"root_id": c.Param("root_id")})
@@ -161,6 +387,20 @@ func (a *EchoAdapter) GetCommentTree(c echo.Context) error {
 	return nil
 }
 
+func (a *EchoAdapter) ExportRoot(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"root_id": c.Param("root_id")})
+	a.handler.ExportRoot(c.Response().Writer, req)
+	return nil
+}
+
+func (a *EchoAdapter) ExportRootCSV(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"root_id": c.Param("root_id")})
+	a.handler.ExportRootCSV(c.Response().Writer, req)
+	return nil
+}
+
 func (a *EchoAdapter) GetCommentStats(c echo.Context) error {
 	req := c.Request()
 	req = addMuxVars(req, map[string]string{"root_id": c.Param("root_id")})
@@ -175,6 +415,13 @@ func (a *EchoAdapter) GetTopComments(c echo.Context) error {
 	return nil
 }
 
+func (a *EchoAdapter) GetTopCommenters(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"root_id": c.Param("root_id")})
+	a.handler.GetTopCommenters(c.Response().Writer, req)
+	return nil
+}
+
 func (a *EchoAdapter) SearchComments(c echo.Context) error {
 	req := c.Request()
 	req = addMuxVars(req, map[string]string{"root_id": c.Param("root_id")})
@@ -182,6 +429,21 @@ func (a *EchoAdapter) SearchComments(c echo.Context) error {
 	return nil
 }
 
+func (a *EchoAdapter) SearchAllComments(c echo.Context) error {
+	a.handler.SearchAllComments(c.Response().Writer, c.Request())
+	return nil
+}
+
+func (a *EchoAdapter) GetTrendingRoots(c echo.Context) error {
+	a.handler.GetTrendingRoots(c.Response().Writer, c.Request())
+	return nil
+}
+
+func (a *EchoAdapter) GetLimits(c echo.Context) error {
+	a.handler.GetLimits(c.Response().Writer, c.Request())
+	return nil
+}
+
 func (a *EchoAdapter) GetCommentsByUser(c echo.Context) error {
 	req := c.Request()
 	req = addMuxVars(req, map[string]string{"user_id": c.Param("user_id")})
@@ -189,6 +451,13 @@ func (a *EchoAdapter) GetCommentsByUser(c echo.Context) error {
 	return nil
 }
 
+func (a *EchoAdapter) GetRepliesToUser(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"user_id": c.Param("user_id")})
+	a.handler.GetRepliesToUser(c.Response().Writer, req)
+	return nil
+}
+
 func (a *EchoAdapter) GetUserCommentCount(c echo.Context) error {
 	req := c.Request()
 	req = addMuxVars(req, map[string]string{"user_id": c.Param("user_id")})
@@ -196,13 +465,67 @@ func (a *EchoAdapter) GetUserCommentCount(c echo.Context) error {
 	return nil
 }
 
+func (a *EchoAdapter) GetUserReputation(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"user_id": c.Param("user_id")})
+	a.handler.GetUserReputation(c.Response().Writer, req)
+	return nil
+}
+
+func (a *EchoAdapter) GetBlockedUsers(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"user_id": c.Param("user_id")})
+	a.handler.GetBlockedUsers(c.Response().Writer, req)
+	return nil
+}
+
+func (a *EchoAdapter) BlockUser(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"user_id": c.Param("user_id"), "blocked_id": c.Param("blocked_id")})
+	a.handler.BlockUser(c.Response().Writer, req)
+	return nil
+}
+
+func (a *EchoAdapter) UnblockUser(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"user_id": c.Param("user_id"), "blocked_id": c.Param("blocked_id")})
+	a.handler.UnblockUser(c.Response().Writer, req)
+	return nil
+}
+
+func (a *EchoAdapter) GetUserVotes(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"user_id": c.Param("user_id")})
+	a.handler.GetUserVotes(c.Response().Writer, req)
+	return nil
+}
+
+func (a *EchoAdapter) RemoveUserVotes(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"user_id": c.Param("user_id")})
+	a.handler.RemoveUserVotes(c.Response().Writer, req)
+	return nil
+}
+
+func (a *EchoAdapter) ExportUserCSV(c echo.Context) error {
+	req := c.Request()
+	req = addMuxVars(req, map[string]string{"user_id": c.Param("user_id")})
+	a.handler.ExportUserCSV(c.Response().Writer, req)
+	return nil
+}
+
 func (a *EchoAdapter) HealthCheck(c echo.Context) error {
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"status":    "healthy",
-		"service":   "commentific",
-		"version":   "1.0.1",
-		"timestamp": "2024-01-01T00:00:00Z", // You might want to use time.Now()
-	})
+	status, report := a.health.readiness(c.Request().Context())
+	return c.JSON(status, report)
+}
+
+func (a *EchoAdapter) DetailedHealthCheck(c echo.Context) error {
+	status, report := a.health.detailed(c.Request().Context())
+	return c.JSON(status, report)
+}
+
+func (a *EchoAdapter) LivenessCheck(c echo.Context) error {
+	return c.JSON(http.StatusOK, livenessReport())
 }
 
 // Helper function to add mux-style vars to request context
@@ -0,0 +1,359 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/christopher18/commentific/v2/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+// FiberAdapter wraps the CommentHandler for the Fiber framework.
+// Fiber runs on fasthttp, so requests are bridged to the net/http handlers
+// through Fiber's adaptor middleware.
+type FiberAdapter struct {
+	handler     *CommentHandler
+	health      *healthChecks
+	compression func(http.Handler) http.Handler
+}
+
+// NewFiberAdapter creates a new Fiber adapter for Commentific
+func NewFiberAdapter(commentService *service.CommentService) *FiberAdapter {
+	return &FiberAdapter{
+		handler: NewCommentHandler(commentService),
+		health:  newHealthChecks(nil),
+	}
+}
+
+// SetHealthChecker makes the health endpoints check checker, e.g. a
+// postgres.PostgresProvider, and report 503 when it fails, or, if it is a
+// SchemaChecker, until its schema check first passes
+func (a *FiberAdapter) SetHealthChecker(checker HealthChecker) {
+	a.health = newHealthChecks(checker)
+}
+
+// SetModeratorCheck decides which requests come from moderators; see
+// RouterConfig.IsModerator
+func (a *FiberAdapter) SetModeratorCheck(check func(r *http.Request) bool) {
+	a.handler.SetModeratorCheck(check)
+}
+
+// SetAdminCheck decides which requests come from admins; see
+// RouterConfig.IsAdmin
+func (a *FiberAdapter) SetAdminCheck(check func(r *http.Request) bool) {
+	a.handler.SetAdminCheck(check)
+}
+
+// SetMaxBodyBytes caps the size of JSON request bodies; see
+// RouterConfig.MaxBodyBytes
+func (a *FiberAdapter) SetMaxBodyBytes(limit int64) {
+	a.handler.SetMaxBodyBytes(limit)
+}
+
+// EnableCompression gzips API responses of at least minSize bytes for
+// clients that accept gzip, as NewRouter does
+func (a *FiberAdapter) EnableCompression(minSize int) {
+	a.compression = CompressionMiddleware(minSize)
+}
+
+// RegisterRoutes registers all Commentific routes with a Fiber app
+func (a *FiberAdapter) RegisterRoutes(app *fiber.App) {
+	a.RegisterRoutesWithPrefix(app, "/api/v1")
+
+	// Health checks
+	app.Get("/health", a.HealthCheck)
+	app.Get("/health/ready", a.HealthCheck)
+	app.Get("/health/detailed", a.DetailedHealthCheck)
+	app.Get("/health/live", a.LivenessCheck)
+}
+
+// RegisterRoutesWithPrefix registers routes with a custom prefix
+func (a *FiberAdapter) RegisterRoutesWithPrefix(app *fiber.App, prefix string) {
+	api := app.Group(prefix)
+
+	// Comment operations
+	api.Post("/comments", a.CreateComment)
+	api.Post("/comments/validate", a.ValidateComment)
+	api.Get("/comments/:id", a.GetComment)
+	api.Put("/comments/:id", a.UpdateComment)
+	api.Delete("/comments/:id", a.DeleteComment)
+	api.Get("/comments/:id/path", a.GetCommentPath)
+	api.Get("/comments/:id/ancestors", a.GetCommentAncestors)
+	api.Get("/comments/:id/children", a.GetCommentChildren)
+	api.Get("/comments/:id/tree", a.GetCommentSubtree)
+	api.Patch("/comments/:id/parent", a.MoveComment)
+	api.Get("/comments/:id/link-preview", a.GetLinkPreview)
+
+	// Moderation
+	api.Post("/comments/:id/approve", a.ApproveComment)
+	api.Post("/comments/:id/reject", a.RejectComment)
+	api.Post("/comments/:id/remove", a.RemoveComment)
+	api.Get("/roots/:root_id/moderation-queue", a.GetModerationQueue)
+	api.Get("/roots/:root_id/settings", a.GetRootSettings)
+	api.Put("/roots/:root_id/settings", a.UpdateRootSettings)
+	api.Put("/roots/:root_id/subscription", a.Subscribe)
+	api.Delete("/roots/:root_id/subscription", a.Unsubscribe)
+
+	// Voting operations
+	api.Post("/comments/:id/vote", a.VoteComment)
+	api.Patch("/comments/:id/vote", a.VoteComment)
+	api.Delete("/comments/:id/vote", a.RemoveVote)
+	api.Get("/comments/:id/vote", a.GetUserVote)
+	api.Get("/comments/:id/votes/summary", a.GetVoteBreakdown)
+
+	// Root-based operations
+	api.Get("/roots/:root_id/comments", a.GetCommentsByRoot)
+	api.Get("/roots/:root_id/comments/since", a.GetCommentsSince)
+	api.Get("/roots/:root_id/comments/with-votes", a.GetCommentsWithVotes)
+	api.Get("/roots/:root_id/tree", a.GetCommentTree)
+	api.Get("/roots/:root_id/tree/with-votes", a.GetCommentTreeWithVotes)
+	api.Get("/roots/:root_id/export", a.ExportRoot)
+	api.Get("/roots/:root_id/export.csv", a.ExportRootCSV)
+	api.Get("/roots/:root_id/stats", a.GetCommentStats)
+	api.Get("/roots/:root_id/top", a.GetTopComments)
+	api.Get("/roots/:root_id/top-commenters", a.GetTopCommenters)
+	api.Get("/roots/:root_id/search", a.SearchComments)
+	api.Get("/roots/:root_id/edited", a.GetEditedComments)
+
+	// User operations
+	api.Get("/users/:user_id/comments", a.GetCommentsByUser)
+	api.Get("/users/:user_id/votes", a.GetUserVotes)
+	api.Delete("/users/:user_id/votes", a.RemoveUserVotes)
+	api.Get("/users/:user_id/replies", a.GetRepliesToUser)
+	api.Get("/users/:user_id/count", a.GetUserCommentCount)
+	api.Get("/users/:user_id/reputation", a.GetUserReputation)
+	api.Get("/users/:user_id/blocks", a.GetBlockedUsers)
+	api.Put("/users/:user_id/blocks/:blocked_id", a.BlockUser)
+	api.Delete("/users/:user_id/blocks/:blocked_id", a.UnblockUser)
+	api.Get("/users/:user_id/export.csv", a.ExportUserCSV)
+
+	// Cross-root search and trending
+	api.Get("/search", a.SearchAllComments)
+	api.Get("/trending", a.GetTrendingRoots)
+	api.Get("/limits", a.GetLimits)
+}
+
+// Fiber handler adapters - these bridge fasthttp requests to the net/http handlers
+
+func (a *FiberAdapter) CreateComment(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.CreateComment)
+}
+
+func (a *FiberAdapter) ValidateComment(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.ValidateComment)
+}
+
+func (a *FiberAdapter) GetComment(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetComment, "id")
+}
+
+func (a *FiberAdapter) UpdateComment(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.UpdateComment, "id")
+}
+
+func (a *FiberAdapter) DeleteComment(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.DeleteComment, "id")
+}
+
+func (a *FiberAdapter) GetCommentPath(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetCommentPath, "id")
+}
+
+func (a *FiberAdapter) GetCommentAncestors(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetCommentAncestors, "id")
+}
+
+func (a *FiberAdapter) GetCommentSubtree(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetCommentSubtree, "id")
+}
+
+func (a *FiberAdapter) GetCommentChildren(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetCommentChildren, "id")
+}
+
+func (a *FiberAdapter) MoveComment(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.MoveComment, "id")
+}
+
+func (a *FiberAdapter) GetLinkPreview(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetLinkPreview, "id")
+}
+
+func (a *FiberAdapter) ApproveComment(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.ApproveComment, "id")
+}
+
+func (a *FiberAdapter) RejectComment(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.RejectComment, "id")
+}
+
+func (a *FiberAdapter) RemoveComment(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.RemoveComment, "id")
+}
+
+func (a *FiberAdapter) GetModerationQueue(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetModerationQueue, "root_id")
+}
+
+func (a *FiberAdapter) GetRootSettings(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetRootSettings, "root_id")
+}
+
+func (a *FiberAdapter) UpdateRootSettings(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.UpdateRootSettings, "root_id")
+}
+
+func (a *FiberAdapter) Subscribe(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.Subscribe, "root_id")
+}
+
+func (a *FiberAdapter) Unsubscribe(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.Unsubscribe, "root_id")
+}
+
+func (a *FiberAdapter) VoteComment(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.VoteComment, "id")
+}
+
+func (a *FiberAdapter) RemoveVote(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.RemoveVote, "id")
+}
+
+func (a *FiberAdapter) GetUserVote(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetUserVote, "id")
+}
+
+func (a *FiberAdapter) GetVoteBreakdown(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetVoteBreakdown, "id")
+}
+
+func (a *FiberAdapter) GetCommentsByRoot(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetCommentsByRoot, "root_id")
+}
+
+func (a *FiberAdapter) GetCommentsSince(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetCommentsSince, "root_id")
+}
+
+func (a *FiberAdapter) GetCommentsWithVotes(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetCommentsWithVotes, "root_id")
+}
+
+func (a *FiberAdapter) GetCommentTreeWithVotes(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetCommentTreeWithVotes, "root_id")
+}
+
+func (a *FiberAdapter) GetCommentTree(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetCommentTree, "root_id")
+}
+
+func (a *FiberAdapter) ExportRoot(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.ExportRoot, "root_id")
+}
+
+func (a *FiberAdapter) ExportRootCSV(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.ExportRootCSV, "root_id")
+}
+
+func (a *FiberAdapter) GetCommentStats(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetCommentStats, "root_id")
+}
+
+func (a *FiberAdapter) GetTopComments(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetTopComments, "root_id")
+}
+
+func (a *FiberAdapter) GetTopCommenters(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetTopCommenters, "root_id")
+}
+
+func (a *FiberAdapter) SearchComments(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.SearchComments, "root_id")
+}
+
+func (a *FiberAdapter) GetEditedComments(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetEditedComments, "root_id")
+}
+
+func (a *FiberAdapter) GetCommentsByUser(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetCommentsByUser, "user_id")
+}
+
+func (a *FiberAdapter) GetRepliesToUser(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetRepliesToUser, "user_id")
+}
+
+func (a *FiberAdapter) GetUserCommentCount(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetUserCommentCount, "user_id")
+}
+
+func (a *FiberAdapter) GetUserReputation(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetUserReputation, "user_id")
+}
+
+func (a *FiberAdapter) GetBlockedUsers(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetBlockedUsers, "user_id")
+}
+
+func (a *FiberAdapter) BlockUser(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.BlockUser, "user_id", "blocked_id")
+}
+
+func (a *FiberAdapter) UnblockUser(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.UnblockUser, "user_id", "blocked_id")
+}
+
+func (a *FiberAdapter) GetUserVotes(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetUserVotes, "user_id")
+}
+
+func (a *FiberAdapter) RemoveUserVotes(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.RemoveUserVotes, "user_id")
+}
+
+func (a *FiberAdapter) ExportUserCSV(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.ExportUserCSV, "user_id")
+}
+
+func (a *FiberAdapter) HealthCheck(c *fiber.Ctx) error {
+	status, report := a.health.readiness(c.UserContext())
+	return c.Status(status).JSON(report)
+}
+
+func (a *FiberAdapter) DetailedHealthCheck(c *fiber.Ctx) error {
+	status, report := a.health.detailed(c.UserContext())
+	return c.Status(status).JSON(report)
+}
+
+func (a *FiberAdapter) LivenessCheck(c *fiber.Ctx) error {
+	return c.Status(http.StatusOK).JSON(livenessReport())
+}
+
+func (a *FiberAdapter) SearchAllComments(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.SearchAllComments)
+}
+
+func (a *FiberAdapter) GetTrendingRoots(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetTrendingRoots)
+}
+
+func (a *FiberAdapter) GetLimits(c *fiber.Ctx) error {
+	return a.serve(c, a.handler.GetLimits)
+}
+
+// serve copies the named route params into mux vars and runs the net/http handler
+func (a *FiberAdapter) serve(c *fiber.Ctx, handler http.HandlerFunc, params ...string) error {
+	vars := make(map[string]string, len(params))
+	for _, param := range params {
+		// Copy the value since fasthttp reuses the underlying buffer
+		vars[param] = string([]byte(c.Params(param)))
+	}
+
+	var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, addMuxVars(r, vars))
+	})
+	if a.compression != nil {
+		h = a.compression(h)
+	}
+	return adaptor.HTTPHandler(h)(c)
+}
@@ -0,0 +1,96 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/api"
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestFiberAdapter_CreateAndGetComment(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	app := fiber.New()
+	api.NewFiberAdapter(commentService).RegisterRoutes(app)
+
+	// Create a comment
+	body := `{"root_id": "product-1", "content": "Hello from Fiber"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/comments", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", "user-123")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+	}
+
+	var created struct {
+		Success bool            `json:"success"`
+		Data    *models.Comment `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("Failed to decode create response: %v", err)
+	}
+	if created.Data == nil || created.Data.ID == "" {
+		t.Fatal("Expected created comment with an ID")
+	}
+
+	// Fetch it back through the path parameter route
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/comments/"+created.Data.ID, nil)
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	var fetched struct {
+		Success bool            `json:"success"`
+		Data    *models.Comment `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&fetched); err != nil {
+		t.Fatalf("Failed to decode get response: %v", err)
+	}
+
+	if fetched.Data.ID != created.Data.ID {
+		t.Errorf("Expected comment %s, got %s", created.Data.ID, fetched.Data.ID)
+	}
+	if fetched.Data.Content != "Hello from Fiber" {
+		t.Errorf("Expected content to round-trip, got %q", fetched.Data.Content)
+	}
+	if fetched.Data.UserID != "user-123" {
+		t.Errorf("Expected user_id user-123, got %s", fetched.Data.UserID)
+	}
+}
+
+func TestFiberAdapter_GetMissingComment(t *testing.T) {
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	app := fiber.New()
+	api.NewFiberAdapter(commentService).RegisterRoutes(app)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/does-not-exist", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.StatusCode)
+	}
+}
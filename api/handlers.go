@@ -1,34 +1,95 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/christopher18/commentific/v2/models"
 	"github.com/christopher18/commentific/v2/service"
-	"github.com/gorilla/mux"
 )
 
 // CommentHandler handles HTTP requests for comment operations
 type CommentHandler struct {
 	commentService *service.CommentService
+	pathParam      PathParams
+	isModerator    func(r *http.Request) bool
+	isAdmin        func(r *http.Request) bool
+	maxBodyBytes   int64
+
+	streams     *StreamHub
+	streamsOnce sync.Once
 }
 
-// NewCommentHandler creates a new comment handler
+// NewCommentHandler creates a new comment handler for routes served by
+// gorilla/mux
 func NewCommentHandler(commentService *service.CommentService) *CommentHandler {
+	return NewCommentHandlerWithParams(commentService, MuxPathParams)
+}
+
+// NewCommentHandlerWithParams creates a comment handler that reads path
+// parameters such as {id} with params, so it can sit behind any router
+func NewCommentHandlerWithParams(commentService *service.CommentService, params PathParams) *CommentHandler {
 	return &CommentHandler{
 		commentService: commentService,
+		pathParam:      params,
+		maxBodyBytes:   DefaultMaxBodyBytes,
+		streams:        NewStreamHub(),
+	}
+}
+
+// SetModeratorCheck makes the handler consult check to decide whether the
+// requesting user moderates. Moderators may remove anyone's comment and see
+// who deleted a comment and why. Without a check nobody is a moderator.
+func (h *CommentHandler) SetModeratorCheck(check func(r *http.Request) bool) {
+	h.isModerator = check
+}
+
+// SetAdminCheck makes the handler consult check to decide whether the
+// requesting user administers roots, which lets them read and change root
+// settings. Without a check nobody is an admin.
+func (h *CommentHandler) SetAdminCheck(check func(r *http.Request) bool) {
+	h.isAdmin = check
+}
+
+// SetMaxBodyBytes caps the size of JSON request bodies; larger ones are
+// answered with 413. Zero or less restores
+// DefaultMaxBodyBytes.
+func (h *CommentHandler) SetMaxBodyBytes(limit int64) {
+	if limit <= 0 {
+		limit = DefaultMaxBodyBytes
 	}
+	h.maxBodyBytes = limit
+}
+
+// moderates reports whether the requesting user is a moderator
+func (h *CommentHandler) moderates(r *http.Request) bool {
+	return h.isModerator != nil && h.isModerator(r)
+}
+
+// StreamComments handles GET /roots/{root_id}/stream
+func (h *CommentHandler) StreamComments(w http.ResponseWriter, r *http.Request) {
+	// Only start receiving events once someone is listening
+	h.streamsOnce.Do(func() {
+		h.commentService.AddEventEmitter(h.streams)
+	})
+
+	h.streams.serveRoot(w, r, h.pathParam(r, "root_id"))
 }
 
 // APIResponse represents a standard API response
 type APIResponse struct {
-	Success bool        `json:"success"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
-	Message string      `json:"message,omitempty"`
+	Success bool                 `json:"success"`
+	Data    interface{}          `json:"data,omitempty"`
+	Error   string               `json:"error,omitempty"`
+	Message string               `json:"message,omitempty"`
+	Fields  []service.FieldError `json:"fields,omitempty"` // The request fields a 400 rejected, when known
 }
 
 // PaginatedResponse represents a paginated API response
@@ -46,11 +107,34 @@ type Pagination struct {
 	Total  int `json:"total,omitempty"`
 }
 
-// VoteRequest should only contain the vote type
+// VoteRequest should only contain the vote type: "up"/"down" or 1/-1
 type VoteRequest struct {
 	VoteType models.VoteType `json:"vote_type" validate:"required,oneof=1 -1"`
 }
 
+// MoveCommentRequest represents the request to re-parent a comment
+type MoveCommentRequest struct {
+	ParentID string `json:"parent_id" validate:"required"`
+}
+
+// RemoveVotesRequest lists the comments to take a user's votes off
+type RemoveVotesRequest struct {
+	CommentIDs []string `json:"comment_ids"`
+}
+
+// RemoveCommentRequest represents the optional body of a moderator removal
+type RemoveCommentRequest struct {
+	Reason string `json:"reason"`
+}
+
+// VoteResponse represents the result of a vote: the comment with its updated
+// counts and the vote as stored
+type VoteResponse struct {
+	Comment    *models.Comment `json:"comment"`
+	Vote       *models.Vote    `json:"vote"`        // null when ToggleVotes removed a repeated vote
+	ScoreDelta int64           `json:"score_delta"` // How much this vote moved the score, e.g. -2 for switching up to down
+}
+
 // Helper functions
 
 func (h *CommentHandler) sendJSONResponse(w http.ResponseWriter, statusCode int, response interface{}) {
@@ -67,6 +151,21 @@ func (h *CommentHandler) sendErrorResponse(w http.ResponseWriter, statusCode int
 	h.sendJSONResponse(w, statusCode, response)
 }
 
+// sendInputError answers 400 for a request the service rejected, listing the
+// offending fields alongside the message so forms can highlight them
+func (h *CommentHandler) sendInputError(w http.ResponseWriter, err error) {
+	response := APIResponse{
+		Success: false,
+		Error:   err.Error(),
+	}
+	for _, field := range service.FieldErrors(err) {
+		if field.Field != "" {
+			response.Fields = append(response.Fields, field)
+		}
+	}
+	h.sendJSONResponse(w, http.StatusBadRequest, response)
+}
+
 func (h *CommentHandler) sendSuccessResponse(w http.ResponseWriter, data interface{}) {
 	response := APIResponse{
 		Success: true,
@@ -86,34 +185,105 @@ func (h *CommentHandler) getUserID(r *http.Request) string {
 	return userID
 }
 
-// parseCommentFilter parses query parameters into CommentFilter
-func (h *CommentHandler) parseCommentFilter(r *http.Request) *models.CommentFilter {
-	filter := &models.CommentFilter{}
+// viewerContext returns the request context carrying the requesting user as
+// the viewer, so reads include their own comments awaiting moderation
+func (h *CommentHandler) viewerContext(r *http.Request) context.Context {
+	return service.WithViewer(r.Context(), h.getUserID(r))
+}
+
+// viewerTag identifies the requesting user and whom they have blocked, for
+// the ETags of reads that differ by viewer
+func (h *CommentHandler) viewerTag(r *http.Request) (string, error) {
+	userID := h.getUserID(r)
+	if userID == "" {
+		return "", nil
+	}
+	blocked, err := h.commentService.GetBlockedUsers(r.Context(), userID)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(append([]string{userID}, blocked...), "\x00"), nil
+}
+
+// moderatorContext returns the request context, marked as a moderator's when
+// the requesting user moderates so reads keep the deletion audit fields
+func (h *CommentHandler) moderatorContext(r *http.Request) context.Context {
+	if h.moderates(r) {
+		return service.WithModerator(r.Context())
+	}
+	return r.Context()
+}
+
+// sortParam reads sort_by, returning fallback when it is absent. Values the
+// repositories can't sort by are refused rather than quietly replaced with
+// created_at, so a client learns its sort was not applied.
+func sortParam(r *http.Request, fallback string) (string, error) {
+	sortBy := r.URL.Query().Get("sort_by")
+	if sortBy == "" {
+		return fallback, nil
+	}
+	if !models.ValidSortField(sortBy) {
+		return "", fmt.Errorf("unsupported sort_by %q; use one of %s", sortBy, strings.Join(models.SortFields, ", "))
+	}
+	return sortBy, nil
+}
 
-	if limit := r.URL.Query().Get("limit"); limit != "" {
-		if l, err := strconv.Atoi(limit); err == nil {
-			filter.Limit = &l
+// pageParams reads limit and offset, leaving either nil when it is absent.
+// Out-of-range pages are clamped by the service; only non-numbers are refused.
+func pageParams(r *http.Request) (limit, offset *int, err error) {
+	if l := r.URL.Query().Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil {
+			return nil, nil, errors.New("limit must be an integer")
 		}
+		limit = &n
 	}
 
-	if offset := r.URL.Query().Get("offset"); offset != "" {
-		if o, err := strconv.Atoi(offset); err == nil {
-			filter.Offset = &o
+	if o := r.URL.Query().Get("offset"); o != "" {
+		n, err := strconv.Atoi(o)
+		if err != nil {
+			return nil, nil, errors.New("offset must be an integer")
 		}
+		offset = &n
+	}
+	return limit, offset, nil
+}
+
+// parseCommentFilter parses query parameters into CommentFilter. A limit or
+// offset that isn't a number, an unsupported sort_by, a malformed edit or
+// time bound, or bounds in the wrong order are an error.
+func (h *CommentHandler) parseCommentFilter(r *http.Request) (*models.CommentFilter, error) {
+	filter := &models.CommentFilter{}
+
+	var err error
+	if filter.Limit, filter.Offset, err = pageParams(r); err != nil {
+		return nil, err
 	}
 
-	if sortBy := r.URL.Query().Get("sort_by"); sortBy != "" {
-		filter.SortBy = sortBy
+	sortBy, err := sortParam(r, "")
+	if err != nil {
+		return nil, err
 	}
+	filter.SortBy = sortBy
 
 	if sortOrder := r.URL.Query().Get("sort_order"); sortOrder != "" {
 		filter.SortOrder = sortOrder
 	}
 
 	if maxDepth := r.URL.Query().Get("max_depth"); maxDepth != "" {
-		if d, err := strconv.Atoi(maxDepth); err == nil {
-			filter.MaxDepth = &d
+		d, err := strconv.Atoi(maxDepth)
+		if err != nil || d < 0 {
+			return nil, errors.New("max_depth must be a non-negative integer")
 		}
+		filter.MaxDepth = &d
+	}
+
+	if topLevel := r.URL.Query().Get("top_level"); topLevel != "" {
+		top, err := strconv.ParseBool(topLevel)
+		if err != nil {
+			return nil, errors.New("top_level must be true or false")
+		}
+		filter.TopLevelOnly = top
 	}
 
 	if parentID := r.URL.Query().Get("parent_id"); parentID != "" {
@@ -121,37 +291,68 @@ func (h *CommentHandler) parseCommentFilter(r *http.Request) *models.CommentFilt
 	}
 
 	if isEdited := r.URL.Query().Get("is_edited"); isEdited != "" {
-		if edited, err := strconv.ParseBool(isEdited); err == nil {
-			filter.IsEdited = &edited
+		edited, err := strconv.ParseBool(isEdited)
+		if err != nil {
+			return nil, errors.New("is_edited must be true or false")
 		}
+		filter.IsEdited = &edited
 	}
 
 	if minEdits := r.URL.Query().Get("min_edits"); minEdits != "" {
-		if min, err := strconv.Atoi(minEdits); err == nil {
-			filter.MinEdits = &min
+		min, err := strconv.Atoi(minEdits)
+		if err != nil || min < 0 {
+			return nil, errors.New("min_edits must be a non-negative integer")
 		}
+		filter.MinEdits = &min
 	}
 
 	if maxEdits := r.URL.Query().Get("max_edits"); maxEdits != "" {
-		if max, err := strconv.Atoi(maxEdits); err == nil {
-			filter.MaxEdits = &max
+		max, err := strconv.Atoi(maxEdits)
+		if err != nil || max < 0 {
+			return nil, errors.New("max_edits must be a non-negative integer")
+		}
+		filter.MaxEdits = &max
+	}
+
+	if filter.MinEdits != nil && filter.MaxEdits != nil && *filter.MinEdits > *filter.MaxEdits {
+		return nil, errors.New("min_edits must not be greater than max_edits")
+	}
+
+	if after := r.URL.Query().Get("created_after"); after != "" {
+		t, err := time.Parse(time.RFC3339, after)
+		if err != nil {
+			return nil, errors.New("created_after must be an RFC 3339 timestamp")
 		}
+		filter.CreatedAfter = &t
 	}
 
-	return filter
+	if before := r.URL.Query().Get("created_before"); before != "" {
+		t, err := time.Parse(time.RFC3339, before)
+		if err != nil {
+			return nil, errors.New("created_before must be an RFC 3339 timestamp")
+		}
+		filter.CreatedBefore = &t
+	}
+
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && filter.CreatedAfter.After(*filter.CreatedBefore) {
+		return nil, errors.New("created_after must not be later than created_before")
+	}
+
+	return filter, nil
 }
 
 // CreateComment handles POST /comments
 func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateCommentRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+	if !h.decodeBody(w, r, &req) {
 		return
 	}
 
-	// If user_id not in request body, try to get from headers/query
-	if req.UserID == "" {
+	// If user_id not in request body, try to get from headers/query. Guests
+	// pass their token in the body, or none to be issued one, so a signed-in
+	// user's ID is never attached to an anonymous comment.
+	if req.UserID == "" && !req.Anonymous {
 		req.UserID = h.getUserID(r)
 		if req.UserID == "" {
 			h.sendErrorResponse(w, http.StatusBadRequest, "User ID is required")
@@ -159,16 +360,31 @@ func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	comment, err := h.commentService.CreateComment(r.Context(), &req)
+	// A retry carrying the same Idempotency-Key gets the original comment back
+	comment, replayed, err := h.commentService.CreateCommentIdempotent(r.Context(), &req, r.Header.Get("Idempotency-Key"))
 	if err != nil {
-		if strings.Contains(err.Error(), "validation failed") {
-			h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
-		} else {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			h.sendInputError(w, err)
+		case errors.Is(err, service.ErrRootLocked):
+			h.sendErrorResponse(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, service.ErrNotFound):
+			h.sendErrorResponse(w, http.StatusNotFound, "Comment created with this idempotency key no longer exists")
+		default:
 			h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
 		}
 		return
 	}
 
+	if replayed {
+		h.sendJSONResponse(w, http.StatusOK, APIResponse{
+			Success: true,
+			Data:    comment,
+			Message: "Comment already created with this idempotency key",
+		})
+		return
+	}
+
 	h.sendJSONResponse(w, http.StatusCreated, APIResponse{
 		Success: true,
 		Data:    comment,
@@ -176,17 +392,42 @@ func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// ValidateComment handles POST /comments/validate - runs CreateComment's
+// checks on a draft without storing it. An invalid draft still gets 200, with
+// the problems listed field by field.
+func (h *CommentHandler) ValidateComment(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateCommentRequest
+
+	if !h.decodeBody(w, r, &req) {
+		return
+	}
+
+	// As for CreateComment; a missing user is reported with the other problems
+	if req.UserID == "" && !req.Anonymous {
+		req.UserID = h.getUserID(r)
+	}
+
+	result, err := h.commentService.ValidateCreate(r.Context(), &req)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.sendSuccessResponse(w, result)
+}
+
 // GetComment handles GET /comments/{id}
 func (h *CommentHandler) GetComment(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	commentID := vars["id"]
+	commentID := h.pathParam(r, "id")
 
 	if commentID == "" {
 		h.sendErrorResponse(w, http.StatusBadRequest, "Comment ID is required")
 		return
 	}
 
-	comment, err := h.commentService.GetComment(r.Context(), commentID)
+	// Moderators, like authors, may read deleted comments when the service allows it
+	ctx := service.WithViewer(h.moderatorContext(r), h.getUserID(r))
+	comment, err := h.commentService.GetComment(ctx, commentID)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			h.sendErrorResponse(w, http.StatusNotFound, "Comment not found")
@@ -196,13 +437,29 @@ func (h *CommentHandler) GetComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	etag := commentETag(comment)
+	if notModified(w, r, etag) {
+		return
+	}
+	w.Header().Set("ETag", etag)
 	h.sendSuccessResponse(w, comment)
 }
 
+// parseETagVersion reads a comment version from an If-Match header holding
+// the ETag served by GetComment, or just the quoted version
+func parseETagVersion(header string) (int64, error) {
+	tag := strings.Trim(strings.TrimPrefix(strings.TrimSpace(header), "W/"), `"`)
+	tag, _, _ = strings.Cut(tag, "-")
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("If-Match must be the ETag of the comment, got %q", header)
+	}
+	return version, nil
+}
+
 // UpdateComment handles PUT /comments/{id}
 func (h *CommentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	commentID := vars["id"]
+	commentID := h.pathParam(r, "id")
 	userID := h.getUserID(r)
 
 	if commentID == "" {
@@ -216,18 +473,35 @@ func (h *CommentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req models.UpdateCommentRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+	if !h.decodeBody(w, r, &req) {
 		return
 	}
 
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		version, err := parseETagVersion(ifMatch)
+		if err != nil {
+			h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if req.Version != nil && *req.Version != version {
+			h.sendErrorResponse(w, http.StatusBadRequest, "If-Match and version disagree")
+			return
+		}
+		req.Version = &version
+	}
+
 	err := h.commentService.UpdateComment(r.Context(), commentID, userID, &req)
 	if err != nil {
-		if strings.Contains(err.Error(), "not authorized") {
+		switch {
+		case errors.Is(err, service.ErrNotAuthorized):
 			h.sendErrorResponse(w, http.StatusForbidden, err.Error())
-		} else if strings.Contains(err.Error(), "not found") {
+		case errors.Is(err, service.ErrNotFound):
 			h.sendErrorResponse(w, http.StatusNotFound, err.Error())
-		} else {
+		case errors.Is(err, service.ErrVersionConflict):
+			h.sendErrorResponse(w, http.StatusConflict, err.Error())
+		case errors.Is(err, service.ErrInvalidInput):
+			h.sendInputError(w, err)
+		default:
 			h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
 		}
 		return
@@ -241,8 +515,7 @@ func (h *CommentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
 
 // DeleteComment handles DELETE /comments/{id}
 func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	commentID := vars["id"]
+	commentID := h.pathParam(r, "id")
 	userID := h.getUserID(r)
 
 	if commentID == "" {
@@ -255,9 +528,17 @@ func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.commentService.DeleteComment(r.Context(), commentID, userID)
+	// hard=true erases the comment instead of soft deleting it
+	if hard, _ := strconv.ParseBool(r.URL.Query().Get("hard")); hard {
+		h.hardDeleteComment(w, r, commentID, userID)
+		return
+	}
+
+	err := h.commentService.DeleteCommentWithReason(r.Context(), commentID, userID, r.URL.Query().Get("reason"))
 	if err != nil {
-		if strings.Contains(err.Error(), "not authorized") {
+		if errors.Is(err, service.ErrInvalidInput) {
+			h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if strings.Contains(err.Error(), "not authorized") {
 			h.sendErrorResponse(w, http.StatusForbidden, err.Error())
 		} else if strings.Contains(err.Error(), "not found") {
 			h.sendErrorResponse(w, http.StatusNotFound, err.Error())
@@ -273,18 +554,63 @@ func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// hardDeleteComment serves DELETE /comments/{id}?hard=true for the author or
+// a moderator
+func (h *CommentHandler) hardDeleteComment(w http.ResponseWriter, r *http.Request, commentID, userID string) {
+	err := h.commentService.HardDeleteComment(h.moderatorContext(r), commentID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotAuthorized):
+			h.sendErrorResponse(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, service.ErrNotFound):
+			h.sendErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrHasReplies):
+			h.sendErrorResponse(w, http.StatusConflict, err.Error())
+		case errors.Is(err, service.ErrInvalidInput):
+			h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		default:
+			h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Comment permanently deleted",
+	})
+}
+
 // GetCommentsByRoot handles GET /roots/{root_id}/comments
 func (h *CommentHandler) GetCommentsByRoot(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	rootID := vars["root_id"]
+	rootID := h.pathParam(r, "root_id")
 
 	if rootID == "" {
 		h.sendErrorResponse(w, http.StatusBadRequest, "Root ID is required")
 		return
 	}
 
-	filter := h.parseCommentFilter(r)
-	comments, err := h.commentService.GetCommentsByRoot(r.Context(), rootID, filter)
+	filter, err := h.parseCommentFilter(r)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, err := h.commentService.GetRootVersion(r.Context(), rootID)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	viewer, err := h.viewerTag(r)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	etag := rootETag(version, r, viewer)
+	if notModified(w, r, etag) {
+		return
+	}
+
+	comments, err := h.commentService.GetCommentsByRoot(h.viewerContext(r), rootID, filter)
 	if err != nil {
 		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -302,125 +628,125 @@ func (h *CommentHandler) GetCommentsByRoot(w http.ResponseWriter, r *http.Reques
 		}
 	}
 
+	w.Header().Set("ETag", etag)
 	h.sendJSONResponse(w, http.StatusOK, response)
 }
 
-// GetCommentTree handles GET /roots/{root_id}/tree
-func (h *CommentHandler) GetCommentTree(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	rootID := vars["root_id"]
+// GetCommentsSince handles GET /roots/{root_id}/comments/since
+func (h *CommentHandler) GetCommentsSince(w http.ResponseWriter, r *http.Request) {
+	rootID := h.pathParam(r, "root_id")
 
 	if rootID == "" {
 		h.sendErrorResponse(w, http.StatusBadRequest, "Root ID is required")
 		return
 	}
 
-	maxDepth := 10 // default
-	if d := r.URL.Query().Get("max_depth"); d != "" {
-		if depth, err := strconv.Atoi(d); err == nil {
-			maxDepth = depth
-		}
+	since, err := time.Parse(time.RFC3339Nano, r.URL.Query().Get("since"))
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "since must be an RFC 3339 time")
+		return
 	}
 
-	sortBy := r.URL.Query().Get("sort_by")
-	if sortBy == "" {
-		sortBy = "score"
+	limit, _, err := pageParams(r)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	pageSize := 0
+	if limit != nil {
+		pageSize = max(*limit, 1)
 	}
 
-	tree, err := h.commentService.GetCommentTree(r.Context(), rootID, maxDepth, sortBy)
+	comments, err := h.commentService.GetCommentsSince(h.moderatorContext(r), rootID, since, r.URL.Query().Get("after_id"), pageSize)
 	if err != nil {
+		if errors.Is(err, service.ErrTimeout) {
+			h.sendErrorResponse(w, http.StatusGatewayTimeout, err.Error())
+			return
+		}
 		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	h.sendSuccessResponse(w, tree)
+	h.sendSuccessResponse(w, comments)
 }
 
-// GetCommentsByUser handles GET /users/{user_id}/comments
-func (h *CommentHandler) GetCommentsByUser(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID := vars["user_id"]
+// GetCommentTree handles GET /roots/{root_id}/tree
+func (h *CommentHandler) GetCommentTree(w http.ResponseWriter, r *http.Request) {
+	rootID := h.pathParam(r, "root_id")
 
-	if userID == "" {
-		h.sendErrorResponse(w, http.StatusBadRequest, "User ID is required")
+	if rootID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Root ID is required")
 		return
 	}
 
-	if userID != h.getUserID(r) {
-		h.sendErrorResponse(w, http.StatusForbidden, "User ID does not match")
-		return
+	maxDepth := 10 // default
+	if d := r.URL.Query().Get("max_depth"); d != "" {
+		if depth, err := strconv.Atoi(d); err == nil {
+			maxDepth = depth
+		}
 	}
 
-	filter := h.parseCommentFilter(r)
-	comments, err := h.commentService.GetCommentsByUser(r.Context(), userID, filter)
+	sortBy, err := sortParam(r, "score")
 	if err != nil {
-		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	response := PaginatedResponse{
-		Success: true,
-		Data:    comments,
+	var opts service.TreeOptions
+	if minScore := r.URL.Query().Get("min_score"); minScore != "" {
+		score, err := strconv.ParseInt(minScore, 10, 64)
+		if err != nil {
+			h.sendErrorResponse(w, http.StatusBadRequest, "min_score must be an integer")
+			return
+		}
+		opts.MinScore = &score
 	}
-
-	if filter.Limit != nil && filter.Offset != nil {
-		response.Pagination = &Pagination{
-			Limit:  *filter.Limit,
-			Offset: *filter.Offset,
+	opts.CollapseReplies, _ = strconv.ParseBool(r.URL.Query().Get("collapse_replies"))
+	if maxChildren := r.URL.Query().Get("max_children"); maxChildren != "" {
+		n, err := strconv.Atoi(maxChildren)
+		if err != nil || n < 0 {
+			h.sendErrorResponse(w, http.StatusBadRequest, "max_children must be a non-negative integer")
+			return
 		}
+		opts.MaxChildrenPerNode = n
 	}
 
-	h.sendJSONResponse(w, http.StatusOK, response)
-}
-
-// VoteComment handles POST /comments/{id}/vote
-func (h *CommentHandler) VoteComment(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	commentID := vars["id"]
-	userID := h.getUserID(r)
-
-	if commentID == "" {
-		h.sendErrorResponse(w, http.StatusBadRequest, "Comment ID is required")
+	version, err := h.commentService.GetRootVersion(r.Context(), rootID)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-
-	if userID == "" {
-		h.sendErrorResponse(w, http.StatusUnauthorized, "User ID is required")
+	viewer, err := h.viewerTag(r)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-
-	var req VoteRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+	etag := rootETag(version, r, viewer)
+	if notModified(w, r, etag) {
 		return
 	}
 
-	err := h.commentService.VoteComment(r.Context(), commentID, userID, req.VoteType)
+	tree, err := h.commentService.GetCommentTreeWithOptions(h.viewerContext(r), rootID, maxDepth, sortBy, opts)
 	if err != nil {
-		if strings.Contains(err.Error(), "cannot vote on their own") {
-			h.sendErrorResponse(w, http.StatusForbidden, err.Error())
-		} else if strings.Contains(err.Error(), "not found") {
-			h.sendErrorResponse(w, http.StatusNotFound, err.Error())
-		} else {
-			h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		if errors.Is(err, service.ErrTimeout) {
+			h.sendErrorResponse(w, http.StatusGatewayTimeout, err.Error())
+			return
 		}
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	h.sendJSONResponse(w, http.StatusOK, APIResponse{
-		Success: true,
-		Message: "Vote recorded successfully",
-	})
+	w.Header().Set("ETag", etag)
+	h.sendSuccessResponse(w, tree)
 }
 
-// RemoveVote handles DELETE /comments/{id}/vote
-func (h *CommentHandler) RemoveVote(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	commentID := vars["id"]
+// GetCommentTreeWithVotes handles GET /roots/{root_id}/tree/with-votes
+func (h *CommentHandler) GetCommentTreeWithVotes(w http.ResponseWriter, r *http.Request) {
+	rootID := h.pathParam(r, "root_id")
 	userID := h.getUserID(r)
 
-	if commentID == "" {
-		h.sendErrorResponse(w, http.StatusBadRequest, "Comment ID is required")
+	if rootID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Root ID is required")
 		return
 	}
 
@@ -429,43 +755,711 @@ func (h *CommentHandler) RemoveVote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.commentService.RemoveVote(r.Context(), commentID, userID)
+	maxDepth := 10 // default
+	if d := r.URL.Query().Get("max_depth"); d != "" {
+		if depth, err := strconv.Atoi(d); err == nil {
+			maxDepth = depth
+		}
+	}
+
+	sortBy, err := sortParam(r, "")
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tree, err := h.commentService.GetCommentTreeWithUserVotes(r.Context(), rootID, userID, maxDepth, sortBy)
 	if err != nil {
+		if errors.Is(err, service.ErrTimeout) {
+			h.sendErrorResponse(w, http.StatusGatewayTimeout, err.Error())
+			return
+		}
 		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	h.sendJSONResponse(w, http.StatusOK, APIResponse{
-		Success: true,
-		Message: "Vote removed successfully",
-	})
+	h.sendSuccessResponse(w, tree)
 }
 
-// GetCommentsWithVotes handles GET /roots/{root_id}/comments/with-votes
-func (h *CommentHandler) GetCommentsWithVotes(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	rootID := vars["root_id"]
-	userID := h.getUserID(r)
+// GetCommentSubtree handles GET /comments/{id}/tree
+func (h *CommentHandler) GetCommentSubtree(w http.ResponseWriter, r *http.Request) {
+	commentID := h.pathParam(r, "id")
 
-	if rootID == "" {
-		h.sendErrorResponse(w, http.StatusBadRequest, "Root ID is required")
+	if commentID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Comment ID is required")
 		return
 	}
 
-	if userID == "" {
-		h.sendErrorResponse(w, http.StatusUnauthorized, "User ID is required")
-		return
+	maxDepth := 10 // default
+	if d := r.URL.Query().Get("max_depth"); d != "" {
+		if depth, err := strconv.Atoi(d); err == nil {
+			maxDepth = depth
+		}
 	}
 
-	filter := h.parseCommentFilter(r)
-	comments, votes, err := h.commentService.GetCommentsWithUserVotes(r.Context(), rootID, userID, filter)
+	sortBy, err := sortParam(r, "score")
 	if err != nil {
-		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	data := map[string]interface{}{
-		"comments": comments,
+	tree, err := h.commentService.GetCommentSubtree(h.viewerContext(r), commentID, maxDepth, sortBy)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotFound):
+			h.sendErrorResponse(w, http.StatusNotFound, "Comment not found")
+		case errors.Is(err, service.ErrTimeout):
+			h.sendErrorResponse(w, http.StatusGatewayTimeout, err.Error())
+		default:
+			h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, tree)
+}
+
+// GetCommentsByUser handles GET /users/{user_id}/comments
+func (h *CommentHandler) GetCommentsByUser(w http.ResponseWriter, r *http.Request) {
+	userID := h.pathParam(r, "user_id")
+
+	if userID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "User ID is required")
+		return
+	}
+
+	if userID != h.getUserID(r) {
+		h.sendErrorResponse(w, http.StatusForbidden, "User ID does not match")
+		return
+	}
+
+	filter, err := h.parseCommentFilter(r)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if rootID := r.URL.Query().Get("root_id"); rootID != "" {
+		filter.RootID = &rootID
+	}
+
+	comments, err := h.commentService.GetCommentsByUser(h.viewerContext(r), userID, filter)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := PaginatedResponse{
+		Success: true,
+		Data:    comments,
+	}
+
+	if filter.Limit != nil && filter.Offset != nil {
+		response.Pagination = &Pagination{
+			Limit:  *filter.Limit,
+			Offset: *filter.Offset,
+		}
+	}
+
+	h.sendJSONResponse(w, http.StatusOK, response)
+}
+
+// GetUserVotes handles GET /users/{user_id}/votes - lists the comments a
+// user has voted on, most recently voted first
+func (h *CommentHandler) GetUserVotes(w http.ResponseWriter, r *http.Request) {
+	userID := h.pathParam(r, "user_id")
+
+	if userID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "User ID is required")
+		return
+	}
+
+	if userID != h.getUserID(r) {
+		h.sendErrorResponse(w, http.StatusForbidden, "User ID does not match")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := &models.VoteFilter{}
+	if rootID := query.Get("root_id"); rootID != "" {
+		filter.RootID = &rootID
+	}
+	if name := query.Get("vote_type"); name != "" {
+		voteType, err := models.ParseVoteType(name)
+		if err != nil {
+			h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter.VoteType = &voteType
+	}
+	if includeDeleted := query.Get("include_deleted"); includeDeleted != "" {
+		include, err := strconv.ParseBool(includeDeleted)
+		if err != nil {
+			h.sendErrorResponse(w, http.StatusBadRequest, "include_deleted must be true or false")
+			return
+		}
+		filter.IncludeDeleted = include
+	}
+	var err error
+	if filter.Limit, filter.Offset, err = pageParams(r); err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	voted, err := h.commentService.GetUserVotes(h.moderatorContext(r), userID, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.sendJSONResponse(w, http.StatusOK, PaginatedResponse{
+		Success: true,
+		Data:    voted,
+		Pagination: &Pagination{
+			Limit:  *filter.Limit,
+			Offset: *filter.Offset,
+		},
+	})
+}
+
+// GetRepliesToUser handles GET /users/{user_id}/replies
+func (h *CommentHandler) GetRepliesToUser(w http.ResponseWriter, r *http.Request) {
+	userID := h.pathParam(r, "user_id")
+
+	if userID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "User ID is required")
+		return
+	}
+
+	if userID != h.getUserID(r) {
+		h.sendErrorResponse(w, http.StatusForbidden, "User ID does not match")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := &models.CommentFilter{}
+	if rootID := query.Get("root_id"); rootID != "" {
+		filter.RootID = &rootID
+	}
+	var err error
+	if filter.Limit, filter.Offset, err = pageParams(r); err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	replies, err := h.commentService.GetRepliesToUser(r.Context(), userID, filter)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.sendJSONResponse(w, http.StatusOK, PaginatedResponse{
+		Success: true,
+		Data:    replies,
+		Pagination: &Pagination{
+			Limit:  *filter.Limit,
+			Offset: *filter.Offset,
+		},
+	})
+}
+
+// VoteComment handles POST and PATCH /comments/{id}/vote
+func (h *CommentHandler) VoteComment(w http.ResponseWriter, r *http.Request) {
+	commentID := h.pathParam(r, "id")
+	userID := h.getUserID(r)
+
+	if commentID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Comment ID is required")
+		return
+	}
+
+	if userID == "" {
+		h.sendErrorResponse(w, http.StatusUnauthorized, "User ID is required")
+		return
+	}
+
+	var req VoteRequest
+	if !h.decodeBody(w, r, &req) {
+		return
+	}
+	if req.VoteType != models.VoteTypeUp && req.VoteType != models.VoteTypeDown {
+		h.sendErrorResponse(w, http.StatusBadRequest, `vote_type must be "up" or "down" (1 or -1); remove a vote with DELETE`)
+		return
+	}
+
+	comment, vote, scoreDelta, err := h.commentService.VoteComment(r.Context(), commentID, userID, req.VoteType)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSelfVote):
+			h.sendErrorResponse(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, service.ErrNotFound):
+			h.sendErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrInvalidInput):
+			h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		default:
+			h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	message := "Vote recorded successfully"
+	if vote == nil {
+		// ToggleVotes removed a repeated vote
+		message = "Vote removed successfully"
+	}
+	h.sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data: VoteResponse{
+			Comment:    comment,
+			Vote:       vote,
+			ScoreDelta: scoreDelta,
+		},
+		Message: message,
+	})
+}
+
+// MoveComment handles PATCH /comments/{id}/parent
+func (h *CommentHandler) MoveComment(w http.ResponseWriter, r *http.Request) {
+	commentID := h.pathParam(r, "id")
+	userID := h.getUserID(r)
+
+	if commentID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Comment ID is required")
+		return
+	}
+
+	if userID == "" {
+		h.sendErrorResponse(w, http.StatusUnauthorized, "User ID is required")
+		return
+	}
+
+	if !h.moderates(r) {
+		h.sendErrorResponse(w, http.StatusForbidden, "Only moderators may move comments")
+		return
+	}
+
+	var req MoveCommentRequest
+	if !h.decodeBody(w, r, &req) {
+		return
+	}
+
+	comment, err := h.commentService.MoveComment(r.Context(), commentID, req.ParentID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrNotFound):
+			h.sendErrorResponse(w, http.StatusNotFound, err.Error())
+		default:
+			h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, comment)
+}
+
+// GetLinkPreview handles GET /comments/{id}/link-preview
+func (h *CommentHandler) GetLinkPreview(w http.ResponseWriter, r *http.Request) {
+	commentID := h.pathParam(r, "id")
+
+	if commentID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Comment ID is required")
+		return
+	}
+
+	preview, err := h.commentService.GetLinkPreview(h.viewerContext(r), commentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotFound):
+			h.sendErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrLinkPreviewFailed):
+			h.sendErrorResponse(w, http.StatusBadGateway, err.Error())
+		case errors.Is(err, service.ErrInvalidInput):
+			h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		default:
+			h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, preview)
+}
+
+// ApproveComment handles POST /comments/{id}/approve
+func (h *CommentHandler) ApproveComment(w http.ResponseWriter, r *http.Request) {
+	h.moderateComment(w, r, h.commentService.ApproveComment)
+}
+
+// RejectComment handles POST /comments/{id}/reject
+func (h *CommentHandler) RejectComment(w http.ResponseWriter, r *http.Request) {
+	h.moderateComment(w, r, h.commentService.RejectComment)
+}
+
+// RemoveComment handles POST /comments/{id}/remove - a moderator soft
+// deletes someone else's comment, optionally saying why
+func (h *CommentHandler) RemoveComment(w http.ResponseWriter, r *http.Request) {
+	commentID := h.pathParam(r, "id")
+	userID := h.getUserID(r)
+
+	if commentID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Comment ID is required")
+		return
+	}
+
+	if userID == "" {
+		h.sendErrorResponse(w, http.StatusUnauthorized, "User ID is required")
+		return
+	}
+
+	if !h.moderates(r) {
+		h.sendErrorResponse(w, http.StatusForbidden, "Only moderators may remove comments")
+		return
+	}
+
+	// The body is optional
+	var req RemoveCommentRequest
+	if !h.decodeOptionalBody(w, r, &req) {
+		return
+	}
+
+	comment, err := h.commentService.RemoveComment(r.Context(), commentID, userID, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrNotFound):
+			h.sendErrorResponse(w, http.StatusNotFound, err.Error())
+		default:
+			h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, comment)
+}
+
+// moderateComment applies an approve or reject decision made by the requesting user
+func (h *CommentHandler) moderateComment(w http.ResponseWriter, r *http.Request, decide func(ctx context.Context, commentID, moderatorID string) (*models.Comment, error)) {
+	commentID := h.pathParam(r, "id")
+	userID := h.getUserID(r)
+
+	if commentID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Comment ID is required")
+		return
+	}
+
+	if userID == "" {
+		h.sendErrorResponse(w, http.StatusUnauthorized, "User ID is required")
+		return
+	}
+
+	if !h.moderates(r) {
+		h.sendErrorResponse(w, http.StatusForbidden, "Only moderators may approve or reject comments")
+		return
+	}
+
+	comment, err := decide(r.Context(), commentID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrNotFound):
+			h.sendErrorResponse(w, http.StatusNotFound, err.Error())
+		default:
+			h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, comment)
+}
+
+// GetModerationQueue handles GET /roots/{root_id}/moderation-queue
+func (h *CommentHandler) GetModerationQueue(w http.ResponseWriter, r *http.Request) {
+	rootID := h.pathParam(r, "root_id")
+
+	if rootID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Root ID is required")
+		return
+	}
+
+	if h.getUserID(r) == "" {
+		h.sendErrorResponse(w, http.StatusUnauthorized, "User ID is required")
+		return
+	}
+
+	if !h.moderates(r) {
+		h.sendErrorResponse(w, http.StatusForbidden, "Only moderators may read the moderation queue")
+		return
+	}
+
+	filter, err := h.parseCommentFilter(r)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	comments, err := h.commentService.GetModerationQueue(r.Context(), rootID, filter)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.sendJSONResponse(w, http.StatusOK, PaginatedResponse{
+		Success: true,
+		Data:    comments,
+		Pagination: &Pagination{
+			Limit:  *filter.Limit,
+			Offset: *filter.Offset,
+		},
+	})
+}
+
+// GetRootSettings handles GET /roots/{root_id}/settings
+func (h *CommentHandler) GetRootSettings(w http.ResponseWriter, r *http.Request) {
+	rootID, ok := h.rootSettingsRequest(w, r)
+	if !ok {
+		return
+	}
+
+	settings, err := h.commentService.GetRootSettings(r.Context(), rootID)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.sendSuccessResponse(w, settings)
+}
+
+// UpdateRootSettings handles PUT /roots/{root_id}/settings - replaces the
+// root's overrides; fields left out fall back to the global configuration
+func (h *CommentHandler) UpdateRootSettings(w http.ResponseWriter, r *http.Request) {
+	rootID, ok := h.rootSettingsRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var settings models.RootSettings
+	if !h.decodeBody(w, r, &settings) {
+		return
+	}
+	settings.RootID = rootID
+
+	updated, err := h.commentService.SetRootSettings(r.Context(), &settings)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, updated)
+}
+
+// rootSettingsRequest checks that a settings request names a root and comes
+// from an admin, answering it with an error otherwise
+func (h *CommentHandler) rootSettingsRequest(w http.ResponseWriter, r *http.Request) (string, bool) {
+	rootID := h.pathParam(r, "root_id")
+
+	if rootID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Root ID is required")
+		return "", false
+	}
+
+	if h.getUserID(r) == "" {
+		h.sendErrorResponse(w, http.StatusUnauthorized, "User ID is required")
+		return "", false
+	}
+
+	if h.isAdmin == nil || !h.isAdmin(r) {
+		h.sendErrorResponse(w, http.StatusForbidden, "Only admins may manage root settings")
+		return "", false
+	}
+
+	return rootID, true
+}
+
+// Subscribe handles PUT /roots/{root_id}/subscription
+func (h *CommentHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	h.changeSubscription(w, r, h.commentService.Subscribe, "Subscribed successfully")
+}
+
+// Unsubscribe handles DELETE /roots/{root_id}/subscription
+func (h *CommentHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	h.changeSubscription(w, r, h.commentService.Unsubscribe, "Unsubscribed successfully")
+}
+
+// changeSubscription applies change to the requesting user's subscription to
+// the root in the path
+func (h *CommentHandler) changeSubscription(w http.ResponseWriter, r *http.Request, change func(ctx context.Context, userID, rootID string) error, message string) {
+	rootID := h.pathParam(r, "root_id")
+	userID := h.getUserID(r)
+
+	if rootID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Root ID is required")
+		return
+	}
+
+	if userID == "" {
+		h.sendErrorResponse(w, http.StatusUnauthorized, "User ID is required")
+		return
+	}
+
+	if err := change(r.Context(), userID, rootID); err != nil {
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: message,
+	})
+}
+
+// RemoveVote handles DELETE /comments/{id}/vote
+func (h *CommentHandler) RemoveVote(w http.ResponseWriter, r *http.Request) {
+	commentID := h.pathParam(r, "id")
+	userID := h.getUserID(r)
+
+	if commentID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Comment ID is required")
+		return
+	}
+
+	if userID == "" {
+		h.sendErrorResponse(w, http.StatusUnauthorized, "User ID is required")
+		return
+	}
+
+	err := h.commentService.RemoveVote(r.Context(), commentID, userID)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Vote removed successfully",
+	})
+}
+
+// RemoveUserVotes handles DELETE /users/{user_id}/votes - removes the user's
+// votes on every comment listed in the body
+func (h *CommentHandler) RemoveUserVotes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.selfRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req RemoveVotesRequest
+	if !h.decodeBody(w, r, &req) {
+		return
+	}
+
+	if err := h.commentService.BatchRemoveVotes(r.Context(), req.CommentIDs, userID); err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Votes removed successfully",
+	})
+}
+
+// GetUserVote handles GET /comments/{id}/vote. Data is null when the user
+// hasn't voted on the comment.
+func (h *CommentHandler) GetUserVote(w http.ResponseWriter, r *http.Request) {
+	commentID := h.pathParam(r, "id")
+	userID := h.getUserID(r)
+
+	if commentID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Comment ID is required")
+		return
+	}
+
+	if userID == "" {
+		h.sendErrorResponse(w, http.StatusUnauthorized, "User ID is required")
+		return
+	}
+
+	vote, err := h.commentService.GetUserVote(r.Context(), commentID, userID)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// A typed nil keeps "data": null in the response instead of omitting it
+	var voteType *models.VoteType
+	if vote != nil {
+		voteType = &vote.VoteType
+	}
+
+	h.sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    voteType,
+	})
+}
+
+// GetVoteBreakdown handles GET /comments/{id}/votes/summary
+func (h *CommentHandler) GetVoteBreakdown(w http.ResponseWriter, r *http.Request) {
+	commentID := h.pathParam(r, "id")
+
+	if commentID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Comment ID is required")
+		return
+	}
+
+	breakdown, err := h.commentService.GetVoteBreakdown(r.Context(), commentID)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			h.sendErrorResponse(w, http.StatusNotFound, "Comment not found")
+		} else {
+			h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, breakdown)
+}
+
+// GetCommentsWithVotes handles GET /roots/{root_id}/comments/with-votes
+func (h *CommentHandler) GetCommentsWithVotes(w http.ResponseWriter, r *http.Request) {
+	rootID := h.pathParam(r, "root_id")
+	userID := h.getUserID(r)
+
+	if rootID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Root ID is required")
+		return
+	}
+
+	if userID == "" {
+		h.sendErrorResponse(w, http.StatusUnauthorized, "User ID is required")
+		return
+	}
+
+	filter, err := h.parseCommentFilter(r)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	comments, votes, err := h.commentService.GetCommentsWithUserVotes(r.Context(), rootID, userID, filter)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data := map[string]interface{}{
+		"comments": comments,
 		"votes":    votes,
 	}
 
@@ -486,8 +1480,7 @@ func (h *CommentHandler) GetCommentsWithVotes(w http.ResponseWriter, r *http.Req
 
 // GetCommentStats handles GET /roots/{root_id}/stats
 func (h *CommentHandler) GetCommentStats(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	rootID := vars["root_id"]
+	rootID := h.pathParam(r, "root_id")
 
 	if rootID == "" {
 		h.sendErrorResponse(w, http.StatusBadRequest, "Root ID is required")
@@ -505,8 +1498,7 @@ func (h *CommentHandler) GetCommentStats(w http.ResponseWriter, r *http.Request)
 
 // GetTopComments handles GET /roots/{root_id}/top
 func (h *CommentHandler) GetTopComments(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	rootID := vars["root_id"]
+	rootID := h.pathParam(r, "root_id")
 
 	if rootID == "" {
 		h.sendErrorResponse(w, http.StatusBadRequest, "Root ID is required")
@@ -527,6 +1519,10 @@ func (h *CommentHandler) GetTopComments(w http.ResponseWriter, r *http.Request)
 
 	comments, err := h.commentService.GetTopComments(r.Context(), rootID, limit, timeRange)
 	if err != nil {
+		if errors.Is(err, service.ErrTimeout) {
+			h.sendErrorResponse(w, http.StatusGatewayTimeout, err.Error())
+			return
+		}
 		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -534,10 +1530,62 @@ func (h *CommentHandler) GetTopComments(w http.ResponseWriter, r *http.Request)
 	h.sendSuccessResponse(w, comments)
 }
 
+// GetTopCommenters handles GET /roots/{root_id}/top-commenters
+func (h *CommentHandler) GetTopCommenters(w http.ResponseWriter, r *http.Request) {
+	rootID := h.pathParam(r, "root_id")
+
+	if rootID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Root ID is required")
+		return
+	}
+
+	limit := 10
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil {
+			limit = parsed
+		}
+	}
+
+	commenters, err := h.commentService.GetTopCommenters(r.Context(), rootID, limit)
+	if err != nil {
+		if errors.Is(err, service.ErrTimeout) {
+			h.sendErrorResponse(w, http.StatusGatewayTimeout, err.Error())
+			return
+		}
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.sendSuccessResponse(w, commenters)
+}
+
+// GetTrendingRoots handles GET /trending - ranks roots by recent comment activity
+func (h *CommentHandler) GetTrendingRoots(w http.ResponseWriter, r *http.Request) {
+	limit := 10
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil {
+			limit = parsed
+		}
+	}
+
+	roots, err := h.commentService.GetTrendingRoots(r.Context(), r.URL.Query().Get("time_range"), limit)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.sendSuccessResponse(w, roots)
+}
+
+// GetLimits handles GET /limits - reports the limits clients should size
+// their requests to
+func (h *CommentHandler) GetLimits(w http.ResponseWriter, r *http.Request) {
+	h.sendSuccessResponse(w, h.commentService.Limits())
+}
+
 // SearchComments handles GET /roots/{root_id}/search
 func (h *CommentHandler) SearchComments(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	rootID := vars["root_id"]
+	rootID := h.pathParam(r, "root_id")
 	query := r.URL.Query().Get("q")
 
 	if rootID == "" {
@@ -550,9 +1598,17 @@ func (h *CommentHandler) SearchComments(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	filter := h.parseCommentFilter(r)
-	comments, err := h.commentService.SearchComments(r.Context(), rootID, query, filter)
+	filter, err := h.parseCommentFilter(r)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	comments, err := h.commentService.SearchComments(h.viewerContext(r), rootID, query, filter)
 	if err != nil {
+		if errors.Is(err, service.ErrTimeout) {
+			h.sendErrorResponse(w, http.StatusGatewayTimeout, err.Error())
+			return
+		}
 		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -560,10 +1616,54 @@ func (h *CommentHandler) SearchComments(w http.ResponseWriter, r *http.Request)
 	h.sendSuccessResponse(w, comments)
 }
 
+// SearchAllComments handles GET /search
+func (h *CommentHandler) SearchAllComments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Search query is required")
+		return
+	}
+
+	filter, err := h.parseCommentFilter(r)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if userID := r.URL.Query().Get("user_id"); userID != "" {
+		filter.UserID = &userID
+	}
+	if rootID := r.URL.Query().Get("root_id"); rootID != "" {
+		filter.RootID = &rootID
+	}
+
+	comments, err := h.commentService.SearchAllComments(h.viewerContext(r), query, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrTimeout) {
+			h.sendErrorResponse(w, http.StatusGatewayTimeout, err.Error())
+			return
+		}
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := PaginatedResponse{
+		Success: true,
+		Data:    comments,
+		Pagination: &Pagination{
+			Limit:  *filter.Limit,
+			Offset: *filter.Offset,
+		},
+	}
+	h.sendJSONResponse(w, http.StatusOK, response)
+}
+
 // GetUserCommentCount handles GET /users/{user_id}/count
 func (h *CommentHandler) GetUserCommentCount(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID := vars["user_id"]
+	userID := h.pathParam(r, "user_id")
 
 	if userID == "" {
 		h.sendErrorResponse(w, http.StatusBadRequest, "User ID is required")
@@ -587,17 +1687,124 @@ func (h *CommentHandler) GetUserCommentCount(w http.ResponseWriter, r *http.Requ
 	})
 }
 
+// GetUserReputation handles GET /users/{user_id}/reputation
+func (h *CommentHandler) GetUserReputation(w http.ResponseWriter, r *http.Request) {
+	userID := h.pathParam(r, "user_id")
+
+	if userID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "User ID is required")
+		return
+	}
+
+	reputation, err := h.commentService.GetUserReputation(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTimeout) {
+			h.sendErrorResponse(w, http.StatusGatewayTimeout, err.Error())
+			return
+		}
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.sendSuccessResponse(w, reputation)
+}
+
+// GetBlockedUsers handles GET /users/{user_id}/blocks
+func (h *CommentHandler) GetBlockedUsers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.selfRequest(w, r)
+	if !ok {
+		return
+	}
+
+	blocked, err := h.commentService.GetBlockedUsers(r.Context(), userID)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.sendSuccessResponse(w, blocked)
+}
+
+// BlockUser handles PUT /users/{user_id}/blocks/{blocked_id}
+func (h *CommentHandler) BlockUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.selfRequest(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.commentService.AddBlock(r.Context(), userID, h.pathParam(r, "blocked_id"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			h.sendInputError(w, err)
+		} else {
+			h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "User blocked successfully",
+	})
+}
+
+// UnblockUser handles DELETE /users/{user_id}/blocks/{blocked_id}
+func (h *CommentHandler) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.selfRequest(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.commentService.RemoveBlock(r.Context(), userID, h.pathParam(r, "blocked_id"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			h.sendInputError(w, err)
+		} else {
+			h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "User unblocked successfully",
+	})
+}
+
+// selfRequest checks that a request about a user's blocks or votes comes from
+// that user, answering it with an error otherwise
+func (h *CommentHandler) selfRequest(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := h.pathParam(r, "user_id")
+
+	if userID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "User ID is required")
+		return "", false
+	}
+
+	requester := h.getUserID(r)
+	if requester == "" {
+		h.sendErrorResponse(w, http.StatusUnauthorized, "User ID is required")
+		return "", false
+	}
+
+	if userID != requester {
+		h.sendErrorResponse(w, http.StatusForbidden, "User ID does not match")
+		return "", false
+	}
+
+	return userID, true
+}
+
 // GetCommentPath handles GET /comments/{id}/path
 func (h *CommentHandler) GetCommentPath(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	commentID := vars["id"]
+	commentID := h.pathParam(r, "id")
 
 	if commentID == "" {
 		h.sendErrorResponse(w, http.StatusBadRequest, "Comment ID is required")
 		return
 	}
 
-	path, err := h.commentService.GetCommentPath(r.Context(), commentID)
+	path, err := h.commentService.GetCommentPath(h.viewerContext(r), commentID)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			h.sendErrorResponse(w, http.StatusNotFound, "Comment not found")
@@ -610,16 +1817,43 @@ func (h *CommentHandler) GetCommentPath(w http.ResponseWriter, r *http.Request)
 	h.sendSuccessResponse(w, path)
 }
 
+// GetCommentAncestors handles GET /comments/{id}/ancestors
+func (h *CommentHandler) GetCommentAncestors(w http.ResponseWriter, r *http.Request) {
+	commentID := h.pathParam(r, "id")
+
+	if commentID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Comment ID is required")
+		return
+	}
+
+	ancestors, err := h.commentService.GetCommentAncestors(h.viewerContext(r), commentID)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			h.sendErrorResponse(w, http.StatusNotFound, "Comment not found")
+		} else {
+			h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, ancestors)
+}
+
 // GetCommentChildren handles GET /comments/{id}/children
 func (h *CommentHandler) GetCommentChildren(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	commentID := vars["id"]
+	commentID := h.pathParam(r, "id")
 
 	if commentID == "" {
 		h.sendErrorResponse(w, http.StatusBadRequest, "Comment ID is required")
 		return
 	}
 
+	// depth=1 pages through immediate replies only
+	if r.URL.Query().Get("depth") == "1" {
+		h.getDirectChildren(w, r, commentID)
+		return
+	}
+
 	// Parse max_depth parameter
 	maxDepth := 10 // default
 	if maxDepthStr := r.URL.Query().Get("max_depth"); maxDepthStr != "" {
@@ -628,7 +1862,39 @@ func (h *CommentHandler) GetCommentChildren(w http.ResponseWriter, r *http.Reque
 		}
 	}
 
-	children, err := h.commentService.GetCommentChildren(r.Context(), commentID, maxDepth)
+	filter, err := h.parseCommentFilter(r)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	children, err := h.commentService.GetCommentChildren(h.viewerContext(r), commentID, maxDepth, filter)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.sendErrorResponse(w, http.StatusNotFound, "Comment not found")
+		} else {
+			h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.sendJSONResponse(w, http.StatusOK, PaginatedResponse{
+		Success: true,
+		Data:    children,
+		Pagination: &Pagination{
+			Limit:  *filter.Limit,
+			Offset: *filter.Offset,
+		},
+	})
+}
+
+// getDirectChildren serves GET /comments/{id}/children?depth=1
+func (h *CommentHandler) getDirectChildren(w http.ResponseWriter, r *http.Request, commentID string) {
+	filter, err := h.parseCommentFilter(r)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	children, err := h.commentService.GetDirectChildren(h.viewerContext(r), commentID, filter)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			h.sendErrorResponse(w, http.StatusNotFound, "Comment not found")
@@ -638,25 +1904,35 @@ func (h *CommentHandler) GetCommentChildren(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	h.sendSuccessResponse(w, children)
+	h.sendJSONResponse(w, http.StatusOK, PaginatedResponse{
+		Success: true,
+		Data:    children,
+		Pagination: &Pagination{
+			Limit:  *filter.Limit,
+			Offset: *filter.Offset,
+		},
+	})
 }
 
 // GetEditedComments handles GET /roots/{root_id}/edited - gets comments that have been edited
 func (h *CommentHandler) GetEditedComments(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	rootID := vars["root_id"]
+	rootID := h.pathParam(r, "root_id")
 
 	if rootID == "" {
 		h.sendErrorResponse(w, http.StatusBadRequest, "Root ID is required")
 		return
 	}
 
-	filter := h.parseCommentFilter(r)
+	filter, err := h.parseCommentFilter(r)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	// Force filter to only show edited comments
 	isEdited := true
 	filter.IsEdited = &isEdited
 
-	comments, err := h.commentService.GetCommentsByRoot(r.Context(), rootID, filter)
+	comments, err := h.commentService.GetCommentsByRoot(h.viewerContext(r), rootID, filter)
 	if err != nil {
 		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -676,3 +1952,88 @@ func (h *CommentHandler) GetEditedComments(w http.ResponseWriter, r *http.Reques
 
 	h.sendJSONResponse(w, http.StatusOK, response)
 }
+
+// ExportRoot handles GET /roots/{root_id}/export - streams every comment in
+// a root as newline-delimited JSON, parents before their replies
+func (h *CommentHandler) ExportRoot(w http.ResponseWriter, r *http.Request) {
+	rootID := h.pathParam(r, "root_id")
+
+	if rootID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Root ID is required")
+		return
+	}
+
+	var opts service.ExportOptions
+	opts.IncludeDeleted, _ = strconv.ParseBool(r.URL.Query().Get("include_deleted"))
+	opts.IncludeVotes, _ = strconv.ParseBool(r.URL.Query().Get("include_votes"))
+
+	// Headers are written with the first line, so a failure before any
+	// output still gets a regular error response
+	started := false
+	encoder := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	err := h.commentService.StreamExport(h.moderatorContext(r), rootID, opts, func(comment *models.ExportedComment) error {
+		if !started {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+		if err := encoder.Encode(comment); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// Once streaming has begun the status is sent; the client sees a
+		// truncated body
+		if !started {
+			h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	if !started {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// ExportRootCSV handles GET /roots/{root_id}/export.csv - streams a root's
+// live comments as CSV, parents before their replies
+func (h *CommentHandler) ExportRootCSV(w http.ResponseWriter, r *http.Request) {
+	rootID := h.pathParam(r, "root_id")
+
+	if rootID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Root ID is required")
+		return
+	}
+
+	writeCommentsCSV(w, rootID+".csv", func(fn func(*models.Comment) error) error {
+		return h.commentService.StreamExport(r.Context(), rootID, service.ExportOptions{}, func(comment *models.ExportedComment) error {
+			return fn(comment.Comment)
+		})
+	})
+}
+
+// ExportUserCSV handles GET /users/{user_id}/export.csv - streams a user's
+// live comments as CSV, oldest first
+func (h *CommentHandler) ExportUserCSV(w http.ResponseWriter, r *http.Request) {
+	userID := h.pathParam(r, "user_id")
+
+	if userID == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "User ID is required")
+		return
+	}
+
+	if userID != h.getUserID(r) {
+		h.sendErrorResponse(w, http.StatusForbidden, "User ID does not match")
+		return
+	}
+
+	writeCommentsCSV(w, userID+".csv", func(fn func(*models.Comment) error) error {
+		return h.commentService.StreamUserExport(r.Context(), userID, fn)
+	})
+}
@@ -0,0 +1,1597 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/api"
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+	"github.com/gorilla/mux"
+)
+
+func TestVoteComment_ReturnsUpdatedComment(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+
+	comment, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Vote on me"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Execute
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/"+comment.ID+"/vote", strings.NewReader(`{"vote_type": 1}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", "voter")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	// Assert
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Data api.VoteResponse `json:"data"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Data.Comment == nil || resp.Data.Comment.Score != 1 || resp.Data.Comment.Upvotes != 1 {
+		t.Fatalf("Expected comment with score 1 in response, got: %+v", resp.Data.Comment)
+	}
+	if resp.Data.Vote == nil || resp.Data.Vote.UserID != "voter" || resp.Data.Vote.VoteType != models.VoteTypeUp {
+		t.Fatalf("Expected stored upvote in response, got: %+v", resp.Data.Vote)
+	}
+}
+
+func TestGetUserVote(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Vote on me"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, comment.ID, "voter", models.VoteTypeDown); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		userID   string
+		expected string
+	}{
+		{name: "existing vote", userID: "voter", expected: `"down"`},
+		{name: "no vote", userID: "lurker", expected: "null"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Execute
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/"+comment.ID+"/vote?user_id="+tt.userID, nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			// Assert
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+
+			var resp struct {
+				Data json.RawMessage `json:"data"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if string(resp.Data) != tt.expected {
+				t.Fatalf("Expected data %s, got: %s", tt.expected, resp.Data)
+			}
+		})
+	}
+}
+
+func TestRouterLogsRequests(t *testing.T) {
+	// Setup
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	router := api.NewRouterWithLogger(service.NewCommentService(memory.NewMemoryRepository()), logger)
+
+	// Execute
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/missing", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	// Assert
+	line := buf.String()
+	for _, want := range []string{"msg=\"http request\"", "method=GET", "path=/api/v1/comments/missing", "status=404", "duration="} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("Expected log line to contain %q, got: %s", want, line)
+		}
+	}
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	// Setup
+	router := api.NewRouter(service.NewCommentService(memory.NewMemoryRepository()))
+
+	// Execute
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	// Assert
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var spec struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		t.Fatalf("Expected an OpenAPI 3 document, got version %q", spec.OpenAPI)
+	}
+	if _, ok := spec.Paths["/api/v1/comments"]["post"]; !ok {
+		t.Fatalf("Expected spec to document POST /api/v1/comments, got paths: %v", spec.Paths)
+	}
+
+	// Every registered API route must appear in the spec
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil || !strings.HasPrefix(path, "/api/v1/") {
+			return nil
+		}
+		methods, _ := route.GetMethods()
+		for _, method := range methods {
+			if _, ok := spec.Paths[path][strings.ToLower(method)]; !ok {
+				t.Errorf("Expected spec to document %s %s", method, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to walk routes: %v", err)
+	}
+}
+
+func TestExportRoot_StreamsNDJSON(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+
+	parent, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Top"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	reply, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: &parent.ID, UserID: "bob", Content: "Reply"})
+	if err != nil {
+		t.Fatalf("Failed to create reply: %v", err)
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, reply.ID, "alice", models.VoteTypeDown); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	// Execute
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/roots/post-1/export?include_votes=true", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	// Assert
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Fatalf("Expected application/x-ndjson, got: %s", ct)
+	}
+
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d: %s", len(lines), rec.Body.String())
+	}
+	var first, second models.ExportedComment
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("Failed to decode first line: %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("Failed to decode second line: %v", err)
+	}
+	if first.ID != parent.ID || second.ID != reply.ID {
+		t.Fatalf("Expected parent then reply, got: %s, %s", first.ID, second.ID)
+	}
+	if len(second.Votes) != 1 || second.Votes[0].VoteType != models.VoteTypeDown {
+		t.Fatalf("Expected the reply's downvote, got: %+v", second.Votes)
+	}
+}
+
+func TestExportRootCSV_EscapesContent(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+
+	parent, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Apples, pears and plums"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	reply, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: &parent.ID, UserID: "bob", Content: `She said "hi", then left`})
+	if err != nil {
+		t.Fatalf("Failed to create reply: %v", err)
+	}
+
+	// Execute
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/roots/post-1/export.csv", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	// Assert
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Fatalf("Expected text/csv, got: %s", ct)
+	}
+
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a header and 2 rows, got %d lines: %s", len(lines), rec.Body.String())
+	}
+	if lines[0] != "id,parent_id,user_id,content,score,depth,created_at" {
+		t.Fatalf("Unexpected header row: %s", lines[0])
+	}
+	wantParent := parent.ID + ",,alice,\"Apples, pears and plums\",0,0,"
+	if !strings.HasPrefix(lines[1], wantParent) {
+		t.Fatalf("Expected row starting %q, got: %s", wantParent, lines[1])
+	}
+	wantReply := reply.ID + "," + parent.ID + ",bob,\"She said \"\"hi\"\", then left\",0,1,"
+	if !strings.HasPrefix(lines[2], wantReply) {
+		t.Fatalf("Expected row starting %q, got: %s", wantReply, lines[2])
+	}
+}
+
+func TestExportUserCSV_RequiresMatchingUser(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+
+	// Execute
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/alice/export.csv", nil)
+	req.Header.Set("X-User-ID", "mallory")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	// Assert
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestCreateComment_AnonymousRendersGuestFields(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{AllowAnonymous: true})
+	router := api.NewRouter(commentService)
+
+	body := `{"root_id": "post-1", "content": "Hello from a guest", "anonymous": true, "display_name": "Passerby"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/comments", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", "alice")
+
+	// Execute
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	// Assert
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Data["is_anonymous"] != true || resp.Data["display_name"] != "Passerby" {
+		t.Fatalf("Expected anonymous comment with display name, got: %v", resp.Data)
+	}
+	userID, _ := resp.Data["user_id"].(string)
+	if !strings.HasPrefix(userID, models.GuestUserIDPrefix) {
+		t.Fatalf("Expected a guest token instead of the signed-in user, got: %q", userID)
+	}
+}
+
+func TestApproveComment_PublishesPendingComment(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{PreModeration: true})
+	router := api.NewRouterWithConfig(commentService, &api.RouterConfig{
+		IsModerator: func(r *http.Request) bool { return r.Header.Get("X-User-ID") == "moderator" },
+	})
+	comment, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Awaiting review"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	get := func(userID string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/"+comment.ID, nil)
+		req.Header.Set("X-User-ID", userID)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := get("bob"); code != http.StatusNotFound {
+		t.Fatalf("Expected 404 for another user before approval, got %d", code)
+	}
+	if code := get("alice"); code != http.StatusOK {
+		t.Fatalf("Expected the author to see their pending comment, got %d", code)
+	}
+
+	// Execute
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/"+comment.ID+"/approve", nil)
+	req.Header.Set("X-User-ID", "moderator")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	// Assert
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"status":"approved"`) {
+		t.Fatalf("Expected the approved comment in the response, got: %s", rec.Body.String())
+	}
+	if code := get("bob"); code != http.StatusOK {
+		t.Fatalf("Expected 200 for another user after approval, got %d", code)
+	}
+}
+
+func TestModeration_ModeratorsOnly(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{PreModeration: true})
+	router := api.NewRouterWithConfig(commentService, &api.RouterConfig{
+		IsModerator: func(r *http.Request) bool { return r.Header.Get("X-User-ID") == "mod-1" },
+	})
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Awaiting review"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	serve := func(method, path, userID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("X-User-ID", userID)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, userID := range []string{"alice", "bob"} {
+		for _, route := range []struct{ method, path string }{
+			{http.MethodPost, "/api/v1/comments/" + comment.ID + "/approve"},
+			{http.MethodPost, "/api/v1/comments/" + comment.ID + "/reject"},
+			{http.MethodGet, "/api/v1/roots/post-1/moderation-queue"},
+		} {
+			// Execute
+			rec := serve(route.method, route.path, userID)
+
+			// Assert
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("Expected 403 for %s on %s %s, got %d: %s", userID, route.method, route.path, rec.Code, rec.Body.String())
+			}
+		}
+	}
+	stored, err := commentService.GetComment(service.WithViewer(ctx, "alice"), comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	if stored.Status != models.CommentStatusPending {
+		t.Errorf("Expected the comment to stay pending, got %s", stored.Status)
+	}
+
+	// The moderator may read the queue
+	if rec := serve(http.MethodGet, "/api/v1/roots/post-1/moderation-queue", "mod-1"); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), comment.ID) {
+		t.Errorf("Expected the moderator to see the pending comment, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestMoveComment_ModeratorsOnly(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouterWithConfig(commentService, &api.RouterConfig{
+		IsModerator: func(r *http.Request) bool { return r.Header.Get("X-User-ID") == "mod-1" },
+	})
+	first, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "First"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	second, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "bob", Content: "Second"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	move := func(userID string) *httptest.ResponseRecorder {
+		body := `{"parent_id": "` + first.ID + `"}`
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/comments/"+second.ID+"/parent", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User-ID", userID)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Execute
+	authorRec := move("bob")
+	moderatorRec := move("mod-1")
+
+	// Assert
+	if authorRec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a non-moderator, got %d: %s", authorRec.Code, authorRec.Body.String())
+	}
+	if moderatorRec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 for the moderator, got %d: %s", moderatorRec.Code, moderatorRec.Body.String())
+	}
+	moved, err := commentService.GetComment(ctx, second.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	if moved.ParentID == nil || *moved.ParentID != first.ID {
+		t.Errorf("Expected the comment under %s, got parent %v", first.ID, moved.ParentID)
+	}
+}
+
+func TestHardDeleteComment_AuthorOrModerator(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouterWithConfig(commentService, &api.RouterConfig{
+		IsModerator: func(r *http.Request) bool { return r.Header.Get("X-User-ID") == "mod-1" },
+	})
+	parent, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Parent"})
+	if err != nil {
+		t.Fatalf("Failed to create parent: %v", err)
+	}
+	leaf, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: &parent.ID, UserID: "bob", Content: "Leaf"})
+	if err != nil {
+		t.Fatalf("Failed to create leaf: %v", err)
+	}
+	hardDelete := func(userID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/comments/"+leaf.ID+"?hard=true", nil)
+		req.Header.Set("X-User-ID", userID)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Execute
+	strangerRec := hardDelete("carol")
+	moderatorRec := hardDelete("mod-1")
+
+	// Assert
+	if strangerRec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a non-moderator who isn't the author, got %d: %s", strangerRec.Code, strangerRec.Body.String())
+	}
+	if moderatorRec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 for the moderator, got %d: %s", moderatorRec.Code, moderatorRec.Body.String())
+	}
+	remaining, err := commentService.GetCommentsByIDs(ctx, []string{leaf.ID}, true)
+	if err != nil {
+		t.Fatalf("Failed to look up comment: %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Error("Expected the leaf to be erased")
+	}
+}
+
+func TestRemoveComment_ModeratorsOnly(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouterWithConfig(commentService, &api.RouterConfig{
+		IsModerator: func(r *http.Request) bool { return r.Header.Get("X-User-ID") == "mod-1" },
+	})
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Buy now"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	remove := func(userID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/"+comment.ID+"/remove", strings.NewReader(`{"reason": "Spam"}`))
+		req.Header.Set("X-User-ID", userID)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	since := func(userID string) string {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/roots/post-1/comments/since?since=2000-01-01T00:00:00Z", nil)
+		req.Header.Set("X-User-ID", userID)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+
+	// Execute
+	refused := remove("bob")
+	removed := remove("mod-1")
+
+	// Assert
+	if refused.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403 for a non-moderator, got %d: %s", refused.Code, refused.Body.String())
+	}
+	if removed.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", removed.Code, removed.Body.String())
+	}
+	if body := removed.Body.String(); !strings.Contains(body, `"deleted_by":"mod-1"`) || !strings.Contains(body, `"delete_reason":"Spam"`) {
+		t.Errorf("Expected the removal to record the moderator and reason, got: %s", body)
+	}
+	if body := since("mod-1"); !strings.Contains(body, `"deleted_by":"mod-1"`) {
+		t.Errorf("Expected moderators to see who removed the comment, got: %s", body)
+	}
+	if body := since("bob"); strings.Contains(body, "deleted_by") || strings.Contains(body, "delete_reason") {
+		t.Errorf("Expected the audit fields hidden from other users, got: %s", body)
+	}
+}
+
+func TestRootSettings_AdminsLockRoot(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouterWithConfig(commentService, &api.RouterConfig{
+		IsAdmin: func(r *http.Request) bool { return r.Header.Get("X-User-ID") == "admin" },
+	})
+	serve := func(method, path, userID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("X-User-ID", userID)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Execute
+	refused := serve(http.MethodPut, "/api/v1/roots/article-1/settings", "alice", `{"locked": true}`)
+	updated := serve(http.MethodPut, "/api/v1/roots/article-1/settings", "admin", `{"locked": true}`)
+	read := serve(http.MethodGet, "/api/v1/roots/article-1/settings", "admin", "")
+	created := serve(http.MethodPost, "/api/v1/comments", "alice", `{"root_id": "article-1", "user_id": "alice", "content": "Hello"}`)
+
+	// Assert
+	if refused.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403 for a non-admin, got %d: %s", refused.Code, refused.Body.String())
+	}
+	if updated.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", updated.Code, updated.Body.String())
+	}
+	if !strings.Contains(read.Body.String(), `"locked":true`) || !strings.Contains(read.Body.String(), `"max_depth":null`) {
+		t.Errorf("Expected the stored settings, got: %s", read.Body.String())
+	}
+	if created.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 creating on a locked root, got %d: %s", created.Code, created.Body.String())
+	}
+}
+
+func TestValidateComment(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+	validate := func(body string) service.ValidationResult {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/validate", strings.NewReader(body))
+		req.Header.Set("X-User-ID", "alice")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var response struct {
+			Data service.ValidationResult `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response.Data
+	}
+
+	// Execute
+	valid := validate(`{"root_id": "post-1", "content": "Hello"}`)
+	invalid := validate(`{"root_id": "post-1", "content": "Hello", "link_url": "javascript:alert(1)"}`)
+
+	// Assert
+	if !valid.Valid {
+		t.Errorf("Expected the draft to be valid, got %+v", valid)
+	}
+	if invalid.Valid || len(invalid.Fields) != 1 || invalid.Fields[0].Field != "link_url" {
+		t.Errorf("Expected a link_url problem, got %+v", invalid)
+	}
+	tree, err := commentService.GetCommentTree(context.Background(), "post-1", 0, "")
+	if err != nil || len(tree) != 0 {
+		t.Errorf("Expected nothing to be stored, got %d comments (err %v)", len(tree), err)
+	}
+}
+
+func TestCreateComment_FieldErrors(t *testing.T) {
+	// Setup
+	router := api.NewRouter(service.NewCommentService(memory.NewMemoryRepository()))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/comments", strings.NewReader(`{"parent_id": null}`))
+	req.Header.Set("X-User-ID", "alice")
+	rec := httptest.NewRecorder()
+
+	// Execute
+	router.ServeHTTP(rec, req)
+
+	// Assert
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var response api.APIResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Error != "validation failed: root_id is required; content is required" {
+		t.Errorf("Expected a readable message, got %q", response.Error)
+	}
+	want := []service.FieldError{
+		{Field: "root_id", Rule: "required", Message: "root_id is required"},
+		{Field: "content", Rule: "required", Message: "content is required"},
+	}
+	if len(response.Fields) != len(want) {
+		t.Fatalf("Expected %d field errors, got %+v", len(want), response.Fields)
+	}
+	for i := range want {
+		if response.Fields[i] != want[i] {
+			t.Errorf("Expected %+v, got %+v", want[i], response.Fields[i])
+		}
+	}
+}
+
+func TestBlockUser_RefreshesBlockersTree(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+	serve := func(method, path, userID, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("X-User-ID", userID)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	if _, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "post-1", UserID: "bob", Content: "Hello"}); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	before := serve(http.MethodGet, "/api/v1/roots/post-1/tree", "alice", "")
+
+	// Execute
+	refused := serve(http.MethodPut, "/api/v1/users/bob/blocks/alice", "alice", "")
+	blocked := serve(http.MethodPut, "/api/v1/users/alice/blocks/bob", "alice", "")
+	after := serve(http.MethodGet, "/api/v1/roots/post-1/tree", "alice", before.Header().Get("ETag"))
+	other := serve(http.MethodGet, "/api/v1/roots/post-1/tree", "carol", "")
+	list := serve(http.MethodGet, "/api/v1/users/alice/blocks", "alice", "")
+
+	// Assert
+	if refused.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 blocking for someone else, got %d: %s", refused.Code, refused.Body.String())
+	}
+	if blocked.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", blocked.Code, blocked.Body.String())
+	}
+	// The block changes alice's tree, so her cached copy is stale
+	if after.Code != http.StatusOK || strings.Contains(after.Body.String(), "Hello") {
+		t.Errorf("Expected a fresh tree without bob's comment, got %d: %s", after.Code, after.Body.String())
+	}
+	if !strings.Contains(other.Body.String(), "Hello") {
+		t.Errorf("Expected carol to still see bob's comment, got: %s", other.Body.String())
+	}
+	if !strings.Contains(list.Body.String(), `"data":["bob"]`) {
+		t.Errorf("Expected alice's blocks to list bob, got: %s", list.Body.String())
+	}
+}
+
+func TestRemoveUserVotes(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+	ctx := context.Background()
+	var ids []string
+	for i := 0; i < 2; i++ {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Vote on me"})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		if _, _, _, err := commentService.VoteComment(ctx, comment.ID, "carol", models.VoteTypeUp); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+		ids = append(ids, comment.ID)
+	}
+	serve := func(path, userID string) *httptest.ResponseRecorder {
+		body := `{"comment_ids": ["` + strings.Join(ids, `", "`) + `"]}`
+		req := httptest.NewRequest(http.MethodDelete, path, strings.NewReader(body))
+		req.Header.Set("X-User-ID", userID)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Execute
+	refused := serve("/api/v1/users/carol/votes", "alice")
+	removed := serve("/api/v1/users/carol/votes", "carol")
+
+	// Assert
+	if refused.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 removing someone else's votes, got %d: %s", refused.Code, refused.Body.String())
+	}
+	if removed.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", removed.Code, removed.Body.String())
+	}
+	votes, err := commentService.GetUserVotesForComments(ctx, ids, "carol")
+	if err != nil {
+		t.Fatalf("Failed to get votes: %v", err)
+	}
+	if len(votes) != 0 {
+		t.Errorf("Expected carol's votes to be gone, got %d", len(votes))
+	}
+}
+
+func TestGetLimits_ReflectsConfig(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{
+		MinCommentLength: 3,
+		MaxCommentLength: 500,
+		MaxCommentDepth:  4,
+		MaxPageSize:      200,
+	})
+	router := api.NewRouter(commentService)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/limits", nil)
+	rec := httptest.NewRecorder()
+
+	// Execute
+	router.ServeHTTP(rec, req)
+
+	// Assert
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var response struct {
+		Data service.Limits `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	limits := response.Data
+	if limits.MinCommentLength != 3 || limits.MaxCommentLength != 500 || limits.MaxDepth != 4 || limits.MaxPageSize != 200 {
+		t.Errorf("Expected the configured limits, got %+v", limits)
+	}
+	if !slices.Equal(limits.SortFields, models.SortFields) {
+		t.Errorf("Expected sort fields %v, got %v", models.SortFields, limits.SortFields)
+	}
+
+	// The reported maximum is the one enforced
+	body := `{"root_id": "post-1", "content": "` + strings.Repeat("a", 501) + `"}`
+	create := httptest.NewRequest(http.MethodPost, "/api/v1/comments", strings.NewReader(body))
+	create.Header.Set("X-User-ID", "alice")
+	created := httptest.NewRecorder()
+	router.ServeHTTP(created, create)
+	if created.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for content over the limit, got %d: %s", created.Code, created.Body.String())
+	}
+}
+
+func TestSortBy_RejectsUnsupportedField(t *testing.T) {
+	// Setup
+	router := api.NewRouter(service.NewCommentService(memory.NewMemoryRepository()))
+
+	for _, path := range []string{
+		"/api/v1/roots/post-1/comments?sort_by=bogus",
+		"/api/v1/roots/post-1/tree?sort_by=bogus",
+		"/api/v1/search?q=hello&sort_by=bogus",
+	} {
+		t.Run(path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			// Execute
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+			// Assert
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status 400, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), "bogus") {
+				t.Errorf("Expected the error to name the rejected sort, got %s", rec.Body.String())
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/roots/post-1/comments?sort_by=edit_count", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected a supported sort to be accepted, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestGetCommentsByRoot_EditFilters(t *testing.T) {
+	// Setup: comments edited zero, one and two times
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+	ctx := context.Background()
+	for edits := 0; edits < 3; edits++ {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Original"})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		for edit := 1; edit <= edits; edit++ {
+			content := strings.Repeat("Edited ", edit)
+			if err := commentService.UpdateComment(ctx, comment.ID, "alice", &models.UpdateCommentRequest{Content: &content}); err != nil {
+				t.Fatalf("Failed to edit comment: %v", err)
+			}
+		}
+	}
+
+	cases := map[string]struct {
+		query      string
+		wantStatus int
+		wantCount  int
+	}{
+		"edited only":         {query: "is_edited=true", wantStatus: http.StatusOK, wantCount: 2},
+		"unedited only":       {query: "is_edited=false", wantStatus: http.StatusOK, wantCount: 1},
+		"at least two edits":  {query: "min_edits=2", wantStatus: http.StatusOK, wantCount: 1},
+		"one edit exactly":    {query: "min_edits=1&max_edits=1", wantStatus: http.StatusOK, wantCount: 1},
+		"malformed is_edited": {query: "is_edited=maybe", wantStatus: http.StatusBadRequest},
+		"negative min_edits":  {query: "min_edits=-1", wantStatus: http.StatusBadRequest},
+		"inverted range":      {query: "min_edits=3&max_edits=1", wantStatus: http.StatusBadRequest},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			// Execute
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/roots/post-1/comments?"+tc.query, nil))
+
+			// Assert
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			var response struct {
+				Data []*models.Comment `json:"data"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.Data) != tc.wantCount {
+				t.Errorf("Expected %d comments, got %d", tc.wantCount, len(response.Data))
+			}
+		})
+	}
+}
+
+func TestGetCommentsByRoot_StructureFilters(t *testing.T) {
+	// Setup: a top-level comment with one reply
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+	ctx := context.Background()
+	parent, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Parent"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	if _, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: &parent.ID, UserID: "bob", Content: "Reply"}); err != nil {
+		t.Fatalf("Failed to create reply: %v", err)
+	}
+
+	cases := map[string]struct {
+		query      string
+		wantStatus int
+		wantCount  int
+	}{
+		"top level only":      {query: "top_level=true", wantStatus: http.StatusOK, wantCount: 1},
+		"replies included":    {query: "top_level=false", wantStatus: http.StatusOK, wantCount: 2},
+		"malformed top_level": {query: "top_level=yes", wantStatus: http.StatusBadRequest},
+		"malformed max_depth": {query: "max_depth=deep", wantStatus: http.StatusBadRequest},
+		"negative max_depth":  {query: "max_depth=-1", wantStatus: http.StatusBadRequest},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			// Execute
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/roots/post-1/comments?"+tc.query, nil))
+
+			// Assert
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			var response struct {
+				Data []*models.Comment `json:"data"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.Data) != tc.wantCount {
+				t.Errorf("Expected %d comments, got %d", tc.wantCount, len(response.Data))
+			}
+		})
+	}
+}
+
+func TestUserLists_MalformedPagination(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+
+	cases := map[string]struct {
+		path       string
+		wantStatus int
+	}{
+		"votes":                   {path: "/api/v1/users/alice/votes?limit=10&offset=0", wantStatus: http.StatusOK},
+		"votes bad limit":         {path: "/api/v1/users/alice/votes?limit=ten", wantStatus: http.StatusBadRequest},
+		"votes bad offset":        {path: "/api/v1/users/alice/votes?offset=1.5", wantStatus: http.StatusBadRequest},
+		"votes bad include":       {path: "/api/v1/users/alice/votes?include_deleted=sometimes", wantStatus: http.StatusBadRequest},
+		"replies":                 {path: "/api/v1/users/alice/replies?limit=10&offset=0", wantStatus: http.StatusOK},
+		"replies bad limit":       {path: "/api/v1/users/alice/replies?limit=ten", wantStatus: http.StatusBadRequest},
+		"replies bad offset":      {path: "/api/v1/users/alice/replies?offset=first", wantStatus: http.StatusBadRequest},
+		"replies negative offset": {path: "/api/v1/users/alice/replies?offset=-5", wantStatus: http.StatusOK},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.Header.Set("X-User-ID", "alice")
+			rec := httptest.NewRecorder()
+
+			// Execute
+			router.ServeHTTP(rec, req)
+
+			// Assert
+			if rec.Code != tc.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetCommentsSince_Paginated(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+	for _, content := range []string{"First", "Second", "Third"} {
+		if _, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: content}); err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+	}
+	poll := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/roots/post-1/comments/since?"+query, nil))
+		return rec
+	}
+
+	// Execute
+	first := poll("since=2000-01-01T00:00:00Z&limit=2")
+	malformed := poll("since=2000-01-01T00:00:00Z&limit=two")
+
+	// Assert
+	if malformed.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a malformed limit, got %d", malformed.Code)
+	}
+	var response struct {
+		Data []*models.Comment `json:"data"`
+	}
+	if err := json.Unmarshal(first.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Data) != 2 {
+		t.Fatalf("Expected a page of 2, got %d", len(response.Data))
+	}
+	last := response.Data[1]
+	rest := poll("since=" + url.QueryEscape(last.UpdatedAt.Format(time.RFC3339Nano)) + "&after_id=" + url.QueryEscape(last.ID))
+	if err := json.Unmarshal(rest.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Data) != 1 || response.Data[0].Content != "Third" {
+		t.Errorf("Expected the third comment on the next page, got %+v", response.Data)
+	}
+}
+
+func TestGetComment_DeletedVisibleToAuthor(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{AuthorsSeeDeleted: true})
+	router := api.NewRouter(commentService)
+	ctx := context.Background()
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Soon gone"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	if err := commentService.DeleteComment(ctx, comment.ID, "alice"); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+	get := func(userID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/"+comment.ID, nil)
+		if userID != "" {
+			req.Header.Set("X-User-ID", userID)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Execute
+	author := get("alice")
+	anonymous := get("")
+
+	// Assert
+	if author.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for the author, got %d: %s", author.Code, author.Body.String())
+	}
+	var response struct {
+		Data models.Comment `json:"data"`
+	}
+	if err := json.Unmarshal(author.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !response.Data.IsDeleted {
+		t.Errorf("Expected the comment marked deleted")
+	}
+	if anonymous.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 without a user, got %d: %s", anonymous.Code, anonymous.Body.String())
+	}
+}
+
+func TestVoteComment_PatchReportsScoreDelta(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Vote on me"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, comment.ID, "voter", models.VoteTypeUp); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	// Execute
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/comments/"+comment.ID+"/vote", strings.NewReader(`{"vote_type": "down"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", "voter")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	// Assert
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Data api.VoteResponse `json:"data"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Data.ScoreDelta != -2 {
+		t.Errorf("Expected score_delta -2 for switching up to down, got %d", resp.Data.ScoreDelta)
+	}
+	if resp.Data.Comment == nil || resp.Data.Comment.Score != -1 {
+		t.Errorf("Expected the comment with score -1, got: %+v", resp.Data.Comment)
+	}
+}
+
+func TestCreateComment_OversizedBody(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	comment, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Original"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	huge := `{"root_id": "post-1", "content": "` + strings.Repeat("a", api.DefaultMaxBodyBytes) + `"}`
+	small := `{"root_id": "post-1", "content": "` + strings.Repeat("a", 200) + `"}`
+
+	cases := map[string]struct {
+		config *api.RouterConfig
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		"create over the default":   {config: &api.RouterConfig{}, method: http.MethodPost, path: "/api/v1/comments", body: huge, want: http.StatusRequestEntityTooLarge},
+		"update over the default":   {config: &api.RouterConfig{}, method: http.MethodPut, path: "/api/v1/comments/" + comment.ID, body: huge, want: http.StatusRequestEntityTooLarge},
+		"create over a set limit":   {config: &api.RouterConfig{MaxBodyBytes: 128}, method: http.MethodPost, path: "/api/v1/comments", body: small, want: http.StatusRequestEntityTooLarge},
+		"create within the default": {config: &api.RouterConfig{}, method: http.MethodPost, path: "/api/v1/comments", body: small, want: http.StatusCreated},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			router := api.NewRouterWithConfig(commentService, tc.config)
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-User-ID", "alice")
+			rec := httptest.NewRecorder()
+
+			// Execute
+			router.ServeHTTP(rec, req)
+
+			// Assert
+			if rec.Code != tc.want {
+				t.Fatalf("Expected status %d, got %d: %s", tc.want, rec.Code, rec.Body.String())
+			}
+			if tc.want == http.StatusRequestEntityTooLarge && !strings.Contains(rec.Body.String(), "Request body exceeds") {
+				t.Errorf("Expected the limit to be explained, got: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestDecodeBody_RejectsUnknownFields(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+	comment, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Original"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	cases := map[string]struct {
+		method string
+		path   string
+		body   string
+		field  string
+	}{
+		"create": {method: http.MethodPost, path: "/api/v1/comments", body: `{"root_id": "post-1", "contnet": "Hello"}`, field: "contnet"},
+		"update": {method: http.MethodPut, path: "/api/v1/comments/" + comment.ID, body: `{"content": "Edited", "pinned": true}`, field: "pinned"},
+		"vote":   {method: http.MethodPost, path: "/api/v1/comments/" + comment.ID + "/vote", body: `{"vote_type": "up", "weight": 5}`, field: "weight"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-User-ID", "alice")
+			rec := httptest.NewRecorder()
+
+			// Execute
+			router.ServeHTTP(rec, req)
+
+			// Assert
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status 400, got %d: %s", rec.Code, rec.Body.String())
+			}
+			var resp api.APIResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if want := `Unknown field "` + tc.field + `" in request body`; resp.Error != want {
+				t.Errorf("Expected error %q, got %q", want, resp.Error)
+			}
+			if len(resp.Fields) != 1 || resp.Fields[0].Field != tc.field || resp.Fields[0].Rule != "unknown" {
+				t.Errorf("Expected the unknown field to be listed, got: %+v", resp.Fields)
+			}
+		})
+	}
+
+	// Nothing was written
+	stored, err := commentService.GetComment(context.Background(), comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	if stored.Content != "Original" || stored.Score != 0 {
+		t.Errorf("Expected the comment untouched, got content %q and score %d", stored.Content, stored.Score)
+	}
+}
+
+func TestDecodeBody_FailureModes(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouterWithConfig(commentService, &api.RouterConfig{
+		MaxBodyBytes: 16 << 10,
+		IsModerator:  func(r *http.Request) bool { return true },
+	})
+	comment, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Original"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	valid := `{"root_id": "post-2", "content": "Hello"}`
+	remove := "/api/v1/comments/" + comment.ID + "/remove"
+
+	cases := map[string]struct {
+		path  string
+		body  string
+		want  int
+		error string
+	}{
+		"too large":             {path: "/api/v1/comments", body: `{"root_id": "post-2", "content": "` + strings.Repeat("a", 17<<10) + `"}`, want: http.StatusRequestEntityTooLarge, error: "Request body exceeds 16384 bytes"},
+		"padded past the limit": {path: "/api/v1/comments", body: valid + strings.Repeat(" ", 17<<10), want: http.StatusRequestEntityTooLarge, error: "Request body exceeds 16384 bytes"},
+		"malformed":             {path: "/api/v1/comments", body: `{"root_id": "post-2", "content": `, want: http.StatusBadRequest, error: "Invalid JSON format"},
+		"deeply nested":         {path: "/api/v1/comments", body: `{"content": ` + strings.Repeat("[", 10001), want: http.StatusBadRequest, error: "Invalid JSON format"},
+		"trailing garbage":      {path: "/api/v1/comments", body: valid + ` garbage`, want: http.StatusBadRequest, error: "Invalid JSON format: unexpected data after the JSON value"},
+		"second value":          {path: "/api/v1/comments", body: valid + valid, want: http.StatusBadRequest, error: "Invalid JSON format: unexpected data after the JSON value"},
+		"empty":                 {path: "/api/v1/comments", body: "", want: http.StatusBadRequest, error: "Invalid JSON format"},
+		"trailing whitespace":   {path: "/api/v1/comments", body: valid + "\n\t ", want: http.StatusCreated},
+		"optional body empty":   {path: remove, body: "", want: http.StatusOK},
+		"optional body garbage": {path: remove, body: `{"reason": "spam"} x`, want: http.StatusBadRequest, error: "Invalid JSON format: unexpected data after the JSON value"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-User-ID", "moderator")
+			rec := httptest.NewRecorder()
+
+			// Execute
+			router.ServeHTTP(rec, req)
+
+			// Assert
+			if rec.Code != tc.want {
+				t.Fatalf("Expected status %d, got %d: %s", tc.want, rec.Code, rec.Body.String())
+			}
+			if tc.error == "" {
+				return
+			}
+			var resp api.APIResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.Error != tc.error {
+				t.Errorf("Expected error %q, got %q", tc.error, resp.Error)
+			}
+		})
+	}
+
+	// Only the well-formed create was stored
+	comments, err := commentService.GetCommentsByRoot(context.Background(), "post-2", nil)
+	if err != nil {
+		t.Fatalf("Failed to list comments: %v", err)
+	}
+	if len(comments) != 1 {
+		t.Errorf("Expected 1 comment from the rejected and accepted bodies, got %d", len(comments))
+	}
+}
+
+func TestUpdateComment_IfMatch(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+	comment, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "First draft"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/"+comment.ID, nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	etag := rec.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `"1-`) {
+		t.Fatalf("Expected an ETag for version 1, got %q", etag)
+	}
+	update := func(content string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/comments/"+comment.ID, strings.NewReader(`{"content":"`+content+`"}`))
+		req.Header.Set("X-User-ID", "alice")
+		req.Header.Set("If-Match", etag)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Execute
+	first := update("Edited once")
+	second := update("Edited from a stale copy")
+
+	// Assert
+	if first.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for the first update, got %d: %s", first.Code, first.Body.String())
+	}
+	if second.Code != http.StatusConflict {
+		t.Fatalf("Expected status 409 for the stale update, got %d: %s", second.Code, second.Body.String())
+	}
+}
+
+func TestGetCommentsByRoot_TopLevel(t *testing.T) {
+	// Setup: three threads, each with a reply
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+	var threads []*models.Comment
+	for _, content := range []string{"First", "Second", "Third"} {
+		thread, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: content})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		if _, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: &thread.ID, UserID: "bob", Content: "Reply"}); err != nil {
+			t.Fatalf("Failed to create reply: %v", err)
+		}
+		threads = append(threads, thread)
+	}
+
+	// Execute
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/roots/post-1/comments?top_level=true&sort_by=created_at&sort_order=asc&limit=2&offset=1", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	// Assert
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Data []*models.Comment `json:"data"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Data) != 2 || resp.Data[0].ID != threads[1].ID || resp.Data[1].ID != threads[2].ID {
+		t.Fatalf("Expected the second page of top-level comments, got: %+v", resp.Data)
+	}
+	for _, comment := range resp.Data {
+		if comment.Depth != 0 || comment.ReplyCount != 1 {
+			t.Fatalf("Expected top-level comments with their reply counts, got: %+v", comment)
+		}
+	}
+}
+
+func TestGetCommentsByRoot_CreatedRangeParams(t *testing.T) {
+	// Setup
+	router := api.NewRouter(service.NewCommentService(memory.NewMemoryRepository()))
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{query: "created_after=2025-03-01T00:00:00Z&created_before=2025-03-08T00:00:00Z", want: http.StatusOK},
+		{query: "created_after=2025-03-01T00:00:00%2B02:00", want: http.StatusOK},
+		{query: "created_after=last-week", want: http.StatusBadRequest},
+		{query: "created_after=2025-03-08T00:00:00Z&created_before=2025-03-01T00:00:00Z", want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			// Execute
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/roots/post-1/comments?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			// Assert
+			if rec.Code != tt.want {
+				t.Fatalf("Expected status %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetCommentsByRoot_ClampsPagination(t *testing.T) {
+	// Setup
+	router := api.NewRouter(service.NewCommentService(memory.NewMemoryRepository()))
+
+	tests := []struct {
+		query      string
+		wantStatus int
+		wantLimit  int
+		wantOffset int
+	}{
+		{query: "limit=-5", wantStatus: http.StatusOK, wantLimit: 1, wantOffset: 0},
+		{query: "offset=-1", wantStatus: http.StatusOK, wantLimit: 50, wantOffset: 0},
+		{query: "limit=999999&offset=20", wantStatus: http.StatusOK, wantLimit: 1000, wantOffset: 20},
+		{query: "limit=ten", wantStatus: http.StatusBadRequest},
+		{query: "offset=1.5", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			// Execute
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/roots/post-1/comments?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			// Assert
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var resp api.PaginatedResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.Pagination == nil || resp.Pagination.Limit != tt.wantLimit || resp.Pagination.Offset != tt.wantOffset {
+				t.Fatalf("Expected limit %d and offset %d, got %+v", tt.wantLimit, tt.wantOffset, resp.Pagination)
+			}
+		})
+	}
+}
+
+func TestVoteComment_InvalidVoteType(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+	comment, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Vote on me"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	tests := []struct {
+		body string
+		want int
+	}{
+		{body: `{"vote_type": 5}`, want: http.StatusBadRequest},
+		{body: `{"vote_type": 2}`, want: http.StatusBadRequest},
+		{body: `{"vote_type": 0}`, want: http.StatusBadRequest},
+		{body: `{"vote_type": "sideways"}`, want: http.StatusBadRequest},
+		{body: `{}`, want: http.StatusBadRequest},
+		{body: `{"vote_type": -1}`, want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			// Execute
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/"+comment.ID+"/vote", strings.NewReader(tt.body))
+			req.Header.Set("X-User-ID", "voter")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			// Assert
+			if rec.Code != tt.want {
+				t.Fatalf("Expected status %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+			if tt.want == http.StatusBadRequest && !strings.Contains(rec.Body.String(), "vote") {
+				t.Fatalf("Expected the error to name the vote type, got: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestCreateComment_IdempotencyKey(t *testing.T) {
+	// Setup
+	router := api.NewRouter(service.NewCommentService(memory.NewMemoryRepository()))
+	post := func() (int, string) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments", strings.NewReader(`{"root_id":"post-1","content":"Posted twice?"}`))
+		req.Header.Set("X-User-ID", "alice")
+		req.Header.Set("Idempotency-Key", "6f1c2f9e-retry")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		var resp struct {
+			Data models.Comment `json:"data"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return rec.Code, resp.Data.ID
+	}
+
+	// Execute
+	firstStatus, firstID := post()
+	secondStatus, secondID := post()
+
+	// Assert
+	if firstStatus != http.StatusCreated {
+		t.Fatalf("Expected status 201 for the first request, got %d", firstStatus)
+	}
+	if secondStatus != http.StatusOK {
+		t.Fatalf("Expected status 200 for the retry, got %d", secondStatus)
+	}
+	if firstID == "" || secondID != firstID {
+		t.Fatalf("Expected the retry to return comment %q, got %q", firstID, secondID)
+	}
+}
+
+func TestConditionalGet(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+	first, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "First"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	get := func(path, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, path := range []string{
+		"/api/v1/comments/" + first.ID,
+		"/api/v1/roots/post-1/tree",
+		"/api/v1/roots/post-1/comments?limit=10",
+	} {
+		t.Run(path, func(t *testing.T) {
+			// Execute
+			fresh := get(path, "")
+			etag := fresh.Header().Get("ETag")
+			cached := get(path, etag)
+
+			// Assert
+			if fresh.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", fresh.Code, fresh.Body.String())
+			}
+			if etag == "" {
+				t.Fatal("Expected an ETag on the response")
+			}
+			if cached.Code != http.StatusNotModified {
+				t.Fatalf("Expected status 304 for a matching If-None-Match, got %d", cached.Code)
+			}
+			if cached.Body.Len() != 0 {
+				t.Errorf("Expected an empty 304 body, got %q", cached.Body.String())
+			}
+			if got := get(path, `"stale", `+etag).Code; got != http.StatusNotModified {
+				t.Errorf("Expected a match anywhere in the list to answer 304, got %d", got)
+			}
+		})
+	}
+
+	t.Run("new comment changes the root's ETag", func(t *testing.T) {
+		tree := get("/api/v1/roots/post-1/tree", "").Header().Get("ETag")
+		list := get("/api/v1/roots/post-1/comments", "").Header().Get("ETag")
+		if _, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "bob", Content: "Second"}); err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+
+		for path, etag := range map[string]string{"/api/v1/roots/post-1/tree": tree, "/api/v1/roots/post-1/comments": list} {
+			rec := get(path, etag)
+			if rec.Code != http.StatusOK {
+				t.Errorf("Expected status 200 for %s after a new comment, got %d", path, rec.Code)
+			}
+			if rec.Header().Get("ETag") == etag {
+				t.Errorf("Expected a new ETag for %s", path)
+			}
+		}
+	})
+
+	t.Run("vote changes the comment's ETag", func(t *testing.T) {
+		etag := get("/api/v1/comments/"+first.ID, "").Header().Get("ETag")
+		if _, _, _, err := commentService.VoteComment(ctx, first.ID, "bob", models.VoteTypeUp); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+
+		if got := get("/api/v1/comments/"+first.ID, etag).Code; got != http.StatusOK {
+			t.Errorf("Expected status 200 after a vote, got %d", got)
+		}
+	})
+}
+
+func TestGetCommentTree_MinScore(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	router := api.NewRouter(commentService)
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Unpopular"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, comment.ID, "bob", models.VoteTypeDown); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+	get := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/roots/post-1/tree"+query, nil))
+		return rec
+	}
+
+	// Execute
+	collapsed := get("?min_score=0")
+	expanded := get("")
+	invalid := get("?min_score=low")
+
+	// Assert
+	if !strings.Contains(collapsed.Body.String(), `"collapsed":true`) {
+		t.Errorf("Expected the comment below min_score to be collapsed, got %s", collapsed.Body.String())
+	}
+	if strings.Contains(expanded.Body.String(), `"collapsed"`) {
+		t.Errorf("Expected nothing collapsed without min_score, got %s", expanded.Body.String())
+	}
+	if invalid.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a non-numeric min_score, got %d", invalid.Code)
+	}
+}
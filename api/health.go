@@ -0,0 +1,229 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// serviceVersion is reported by the health endpoints and the OpenAPI spec
+const serviceVersion = "2.0.1"
+
+// healthCheckTimeout bounds how long a readiness check waits on the database
+const healthCheckTimeout = 2 * time.Second
+
+// HealthChecker reports whether a backing store is reachable.
+// repository.RepositoryProvider implementations such as
+// postgres.PostgresProvider satisfy it.
+type HealthChecker interface {
+	Health() error
+}
+
+// ContextHealthChecker is a HealthChecker that can give up when a context is
+// done. The readiness checks use it when the checker provides it, so a hung
+// check doesn't outlive the request. postgres.PostgresProvider satisfies it.
+type ContextHealthChecker interface {
+	HealthChecker
+	HealthContext(ctx context.Context) error
+}
+
+// SchemaChecker reports whether the database schema the service needs is in
+// place, e.g. that migrations have finished. postgres.PostgresProvider
+// satisfies it.
+type SchemaChecker interface {
+	CheckSchema(ctx context.Context) error
+}
+
+// PoolStatsReporter reports the state of a connection pool.
+// postgres.PostgresProvider satisfies it.
+type PoolStatsReporter interface {
+	Stats() sql.DBStats
+}
+
+// HealthReport is the body of the health endpoints
+type HealthReport struct {
+	Status    string                 `json:"status"`
+	Service   string                 `json:"service"`
+	Version   string                 `json:"version"`
+	Timestamp string                 `json:"timestamp"`
+	Checks    map[string]HealthCheck `json:"checks,omitempty"`
+	Pool      *PoolStats             `json:"pool,omitempty"` // Only on /health/detailed, when the checker reports pool stats
+}
+
+// PoolStats is the state of the database connection pool, for spotting
+// connection exhaustion
+type PoolStats struct {
+	MaxOpenConnections int   `json:"max_open_connections"` // Configured limit; 0 means unlimited
+	OpenConnections    int   `json:"open_connections"`     // In use plus idle
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`       // Times a caller had to wait for a connection
+	WaitDurationMs     int64 `json:"wait_duration_ms"` // Total time spent waiting
+	MaxIdleClosed      int64 `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64 `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
+}
+
+// newPoolStats converts the pool stats database/sql reports
+func newPoolStats(stats sql.DBStats) *PoolStats {
+	return &PoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}
+}
+
+// HealthCheck is the outcome of checking a single dependency
+type HealthCheck struct {
+	Status string `json:"status"` // "up" or "down"
+	Error  string `json:"error,omitempty"`
+}
+
+// livenessReport reports that the process is up without touching dependencies
+func livenessReport() HealthReport {
+	return HealthReport{
+		Status:    "alive",
+		Service:   "commentific",
+		Version:   serviceVersion,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
+
+// healthChecks runs the readiness checks against a HealthChecker. A checker
+// that is also a SchemaChecker keeps readiness down until its schema check
+// passes, so a deploy isn't sent traffic before migrations finish. Once the
+// schema check passes it isn't run again, since migrations aren't undone
+// under a running server.
+type healthChecks struct {
+	checker     HealthChecker
+	schemaReady atomic.Bool
+}
+
+func newHealthChecks(checker HealthChecker) *healthChecks {
+	return &healthChecks{checker: checker}
+}
+
+// readiness checks the database, if a checker is configured, and its schema,
+// and returns the status code to respond with: 200 when every check passed,
+// 503 otherwise
+func (h *healthChecks) readiness(ctx context.Context) (int, HealthReport) {
+	report := HealthReport{
+		Status:    "healthy",
+		Service:   "commentific",
+		Version:   serviceVersion,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	if h.checker == nil {
+		return http.StatusOK, report
+	}
+
+	report.Checks = map[string]HealthCheck{"database": checkResult(checkWithTimeout(ctx, h.checker))}
+	if schemaChecker, ok := h.checker.(SchemaChecker); ok {
+		// The schema can't be checked without the database
+		if report.Checks["database"].Status == "up" {
+			report.Checks["schema"] = checkResult(h.checkSchema(ctx, schemaChecker))
+		} else {
+			report.Checks["schema"] = HealthCheck{Status: "down", Error: "database unreachable"}
+		}
+	}
+
+	for _, check := range report.Checks {
+		if check.Status != "up" {
+			report.Status = "unhealthy"
+			return http.StatusServiceUnavailable, report
+		}
+	}
+	return http.StatusOK, report
+}
+
+// detailed is readiness plus the state of the connection pool, when the
+// checker reports one
+func (h *healthChecks) detailed(ctx context.Context) (int, HealthReport) {
+	status, report := h.readiness(ctx)
+	if reporter, ok := h.checker.(PoolStatsReporter); ok {
+		report.Pool = newPoolStats(reporter.Stats())
+	}
+	return status, report
+}
+
+// checkSchema runs the schema check until it first passes
+func (h *healthChecks) checkSchema(ctx context.Context, checker SchemaChecker) error {
+	if h.schemaReady.Load() {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	if err := checker.CheckSchema(ctx); err != nil {
+		return err
+	}
+	h.schemaReady.Store(true)
+	return nil
+}
+
+// checkResult describes the outcome of one check
+func checkResult(err error) HealthCheck {
+	if err != nil {
+		return HealthCheck{Status: "down", Error: err.Error()}
+	}
+	return HealthCheck{Status: "up"}
+}
+
+// checkWithTimeout runs the checker, giving up once ctx is done or
+// healthCheckTimeout passes. A checker without HealthContext can't be
+// stopped, so a hung check is left to finish in the background.
+func checkWithTimeout(ctx context.Context, checker HealthChecker) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	if contextChecker, ok := checker.(ContextHealthChecker); ok {
+		return contextChecker.HealthContext(ctx)
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- checker.Health()
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// healthCheckHandler serves GET /health and GET /health/ready
+func healthCheckHandler(checks *healthChecks) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status, report := checks.readiness(r.Context())
+		writeHealthReport(w, status, report)
+	}
+}
+
+// detailedHealthHandler serves GET /health/detailed
+func detailedHealthHandler(checks *healthChecks) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status, report := checks.detailed(r.Context())
+		writeHealthReport(w, status, report)
+	}
+}
+
+// livenessHandler serves GET /health/live
+func livenessHandler(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, http.StatusOK, livenessReport())
+}
+
+func writeHealthReport(w http.ResponseWriter, status int, report HealthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,190 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/api"
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/postgres"
+	"github.com/christopher18/commentific/v2/service"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// healthyChecker stands in for a reachable database
+type healthyChecker struct{}
+
+func (healthyChecker) Health() error { return nil }
+
+func serveHealth(t *testing.T, checker api.HealthChecker, path string) (int, api.HealthReport) {
+	t.Helper()
+
+	router := api.NewRouterWithConfig(service.NewCommentService(memory.NewMemoryRepository()), &api.RouterConfig{HealthChecker: checker})
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var report api.HealthReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("Failed to decode health report: %v", err)
+	}
+	return rec.Code, report
+}
+
+// unreachableProvider returns a provider for a port nothing listens on
+func unreachableProvider(t *testing.T) *postgres.PostgresProvider {
+	t.Helper()
+
+	db, err := sqlx.Open("postgres", "postgres://commentific@127.0.0.1:1/commentific?sslmode=disable&connect_timeout=1")
+	if err != nil {
+		t.Fatalf("Failed to open database handle: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return postgres.NewPostgresProvider(db)
+}
+
+func TestHealth_DatabaseReachable(t *testing.T) {
+	// Execute
+	status, report := serveHealth(t, healthyChecker{}, "/health")
+
+	// Assert
+	if status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+	if report.Status != "healthy" || report.Checks["database"].Status != "up" {
+		t.Fatalf("Expected healthy report with database up, got: %+v", report)
+	}
+	if report.Timestamp == "" {
+		t.Fatalf("Expected a timestamp, got: %+v", report)
+	}
+}
+
+func TestHealth_DatabaseUnreachable(t *testing.T) {
+	// Setup
+	provider := unreachableProvider(t)
+
+	for _, path := range []string{"/health", "/health/ready"} {
+		// Execute
+		status, report := serveHealth(t, provider, path)
+
+		// Assert
+		if status != http.StatusServiceUnavailable {
+			t.Fatalf("Expected status 503 from %s, got %d", path, status)
+		}
+		database := report.Checks["database"]
+		if report.Status != "unhealthy" || database.Status != "down" || database.Error == "" {
+			t.Fatalf("Expected unhealthy report with database error from %s, got: %+v", path, report)
+		}
+	}
+}
+
+func TestHealth_LivenessIgnoresDatabase(t *testing.T) {
+	// Execute
+	status, report := serveHealth(t, unreachableProvider(t), "/health/live")
+
+	// Assert
+	if status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+	if report.Status != "alive" || report.Checks != nil {
+		t.Fatalf("Expected alive report without checks, got: %+v", report)
+	}
+}
+
+func TestHealth_DetailedReportsPoolStats(t *testing.T) {
+	// Setup
+	db, err := sqlx.Open("postgres", "postgres://commentific@127.0.0.1:1/commentific?sslmode=disable&connect_timeout=1")
+	if err != nil {
+		t.Fatalf("Failed to open database handle: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(7)
+	provider := postgres.NewPostgresProvider(db)
+
+	// Execute
+	stats := provider.Stats()
+	status, report := serveHealth(t, provider, "/health/detailed")
+
+	// Assert
+	if stats.MaxOpenConnections != 7 {
+		t.Errorf("Expected Stats to report the limit of 7 open connections, got %d", stats.MaxOpenConnections)
+	}
+	if status != http.StatusServiceUnavailable || report.Checks["database"].Status != "down" {
+		t.Errorf("Expected the readiness checks to run and fail, got %d: %+v", status, report)
+	}
+	if report.Pool == nil {
+		t.Fatalf("Expected pool stats, got: %+v", report)
+	}
+	if report.Pool.MaxOpenConnections != 7 || report.Pool.InUse != 0 {
+		t.Errorf("Expected a limit of 7 with nothing in use, got: %+v", report.Pool)
+	}
+
+	// The plain readiness check leaves the pool out
+	if _, report := serveHealth(t, provider, "/health"); report.Pool != nil {
+		t.Errorf("Expected no pool stats from /health, got: %+v", report.Pool)
+	}
+}
+
+// migratingChecker stands in for a reachable database whose migrations
+// finish once migrated is set
+type migratingChecker struct {
+	migrated     bool
+	schemaChecks int
+}
+
+func (c *migratingChecker) Health() error { return nil }
+
+func (c *migratingChecker) CheckSchema(ctx context.Context) error {
+	c.schemaChecks++
+	if !c.migrated {
+		return errors.New("votes.weight missing")
+	}
+	return nil
+}
+
+func TestHealth_ReadyOnceSchemaCheckPasses(t *testing.T) {
+	// Setup
+	checker := &migratingChecker{}
+	router := api.NewRouterWithConfig(service.NewCommentService(memory.NewMemoryRepository()), &api.RouterConfig{HealthChecker: checker})
+	probe := func(path string) (int, api.HealthReport) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var report api.HealthReport
+		if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+			t.Fatalf("Failed to decode health report: %v", err)
+		}
+		return rec.Code, report
+	}
+
+	// Execute & Assert: not ready while migrations run, though alive
+	status, report := probe("/health/ready")
+	if status != http.StatusServiceUnavailable || report.Checks["database"].Status != "up" || report.Checks["schema"].Status != "down" {
+		t.Fatalf("Expected 503 with the schema down, got %d: %+v", status, report)
+	}
+	if report.Checks["schema"].Error != "votes.weight missing" {
+		t.Errorf("Expected the schema error to be reported, got %q", report.Checks["schema"].Error)
+	}
+	if status, _ := probe("/health/live"); status != http.StatusOK {
+		t.Errorf("Expected liveness to pass while migrating, got %d", status)
+	}
+
+	// Execute & Assert: ready once they finish
+	checker.migrated = true
+	status, report = probe("/health/ready")
+	if status != http.StatusOK || report.Checks["schema"].Status != "up" {
+		t.Fatalf("Expected 200 with the schema up, got %d: %+v", status, report)
+	}
+
+	// Execute & Assert: the passed check isn't run again
+	checker.migrated = false
+	if status, _ := probe("/health/ready"); status != http.StatusOK {
+		t.Errorf("Expected readiness to stay up, got %d", status)
+	}
+	if checker.schemaChecks != 2 {
+		t.Errorf("Expected the schema to be checked twice, got %d", checker.schemaChecks)
+	}
+}
@@ -0,0 +1,734 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+// route describes one /api/v1 endpoint. NewRouter registers handlers from
+// the same table the OpenAPI spec is built from, so the two cannot drift.
+type route struct {
+	method  string
+	path    string // relative to /api/v1
+	handle  func(*CommentHandler, http.ResponseWriter, *http.Request)
+	summary string
+
+	auth      bool        // requires a user ID (X-User-ID header or user_id query parameter)
+	query     []parameter // query parameters besides user_id
+	body      interface{} // zero value of the JSON request body, nil when there is none
+	data      interface{} // zero value of the success payload, nil when there is none
+	paginated bool        // success responses use PaginatedResponse
+	stream    string      // content type streamed on success instead of the JSON envelope
+	status    int         // success status, http.StatusOK when zero
+	errors    []int       // documented error statuses besides 500
+
+	conditional bool // serves an ETag and answers a matching If-None-Match with 304
+}
+
+// parameter is an OpenAPI query parameter
+type parameter struct {
+	name        string
+	kind        string // OpenAPI schema type
+	description string
+	required    bool
+}
+
+var (
+	paginationParams = []parameter{
+		{name: "limit", kind: "integer", description: "Number of results (default: 50); clamped to between 1 and 1000"},
+		{name: "offset", kind: "integer", description: "Pagination offset; negative values become 0"},
+	}
+	sortParams = []parameter{
+		{name: "sort_by", kind: "string", description: "score, created_at, updated_at, content_updated_at, edit_count, hot, best, controversial or decayed; anything else is refused with 400"},
+		{name: "sort_order", kind: "string", description: "asc or desc"},
+	}
+	filterParams = []parameter{
+		{name: "max_depth", kind: "integer", description: "Maximum comment depth"},
+		{name: "top_level", kind: "boolean", description: "Only top-level comments, without replies; reply_count tells which have replies"},
+		{name: "parent_id", kind: "string", description: "Only replies to this comment"},
+		{name: "is_edited", kind: "boolean", description: "Filter by edit status"},
+		{name: "min_edits", kind: "integer", description: "Minimum number of edits; not above max_edits"},
+		{name: "max_edits", kind: "integer", description: "Maximum number of edits"},
+		{name: "created_after", kind: "string", description: "RFC 3339 time; only comments created at or after it"},
+		{name: "created_before", kind: "string", description: "RFC 3339 time; only comments created before it"},
+	}
+	listParams = concatParams(paginationParams, sortParams, filterParams)
+)
+
+// apiRoutes lists every endpoint served under /api/v1
+func apiRoutes() []route {
+	return []route{
+		// Comment operations
+		{
+			method: http.MethodPost, path: "/comments", handle: (*CommentHandler).CreateComment,
+			summary: "Create a comment; user_id may come from the body, header or query. A retry with the same Idempotency-Key header returns the original comment with 200",
+			body:    models.CreateCommentRequest{}, data: models.Comment{},
+			status: http.StatusCreated, errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusRequestEntityTooLarge},
+		},
+		{
+			method: http.MethodPost, path: "/comments/validate", handle: (*CommentHandler).ValidateComment,
+			summary: "Check a draft comment the way creating it would, without storing it; problems are listed by field with 200",
+			body:    models.CreateCommentRequest{}, data: service.ValidationResult{},
+			errors: []int{http.StatusBadRequest, http.StatusRequestEntityTooLarge},
+		},
+		{
+			method: http.MethodGet, path: "/comments/{id}", handle: (*CommentHandler).GetComment,
+			summary: "Get a comment, including edit tracking fields",
+			data:    models.Comment{}, errors: []int{http.StatusBadRequest, http.StatusNotFound},
+			conditional: true,
+		},
+		{
+			method: http.MethodPut, path: "/comments/{id}", handle: (*CommentHandler).UpdateComment,
+			summary: "Update a comment (requires ownership); a version in the body or an If-Match ETag refuses stale edits with 409", auth: true,
+			body:   models.UpdateCommentRequest{},
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusRequestEntityTooLarge},
+		},
+		{
+			method: http.MethodDelete, path: "/comments/{id}", handle: (*CommentHandler).DeleteComment,
+			summary: "Delete a comment (requires ownership); soft delete unless hard=true", auth: true,
+			query: []parameter{
+				{name: "hard", kind: "boolean", description: "Permanently erase the comment and its votes; refused with 409 when it has replies"},
+				{name: "reason", kind: "string", description: "Why the comment was deleted, recorded with a soft delete and shown to moderators"},
+			},
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict},
+		},
+		{
+			method: http.MethodGet, path: "/comments/{id}/path", handle: (*CommentHandler).GetCommentPath,
+			summary: "Get the chain of comments from the top-level ancestor down to this comment",
+			data:    []*models.Comment{}, errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodGet, path: "/comments/{id}/ancestors", handle: (*CommentHandler).GetCommentAncestors,
+			summary: "Get the comments above this one, top-level first, following parent links",
+			data:    []*models.Comment{}, errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodGet, path: "/comments/{id}/children", handle: (*CommentHandler).GetCommentChildren,
+			summary: "Get the subtree under a comment in path order, or with depth=1 a sorted page of immediate replies",
+			query: concatParams([]parameter{
+				{name: "depth", kind: "integer", description: "1 to return immediate replies only"},
+			}, listParams),
+			data: []*models.Comment{}, paginated: true,
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodGet, path: "/comments/{id}/tree", handle: (*CommentHandler).GetCommentSubtree,
+			summary: "Get a comment with its replies nested beneath it, reading only that thread",
+			query: []parameter{
+				{name: "max_depth", kind: "integer", description: "Levels of replies below the comment"},
+				sortParams[0],
+			},
+			data: models.CommentTree{}, errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusGatewayTimeout},
+		},
+		{
+			method: http.MethodPatch, path: "/comments/{id}/parent", handle: (*CommentHandler).MoveComment,
+			summary: "Move a comment and its replies under another comment in the same root", auth: true,
+			body: MoveCommentRequest{}, data: models.Comment{},
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusRequestEntityTooLarge},
+		},
+		{
+			method: http.MethodGet, path: "/comments/{id}/link-preview", handle: (*CommentHandler).GetLinkPreview,
+			summary: "Get the Open Graph preview of a comment's link, fetching it if it isn't stored yet",
+			data:    models.LinkPreview{}, errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusBadGateway},
+		},
+
+		// Moderation
+		{
+			method: http.MethodPost, path: "/comments/{id}/approve", handle: (*CommentHandler).ApproveComment,
+			summary: "Approve a comment awaiting moderation, making it public", auth: true,
+			data:   models.Comment{},
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+		},
+		{
+			method: http.MethodPost, path: "/comments/{id}/reject", handle: (*CommentHandler).RejectComment,
+			summary: "Reject a comment awaiting moderation; only its author still sees it", auth: true,
+			data:   models.Comment{},
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+		},
+		{
+			method: http.MethodPost, path: "/comments/{id}/remove", handle: (*CommentHandler).RemoveComment,
+			summary: "Soft delete anyone's comment as a moderator, recording who removed it and why", auth: true,
+			body:   RemoveCommentRequest{},
+			data:   models.Comment{},
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusRequestEntityTooLarge},
+		},
+		{
+			method: http.MethodGet, path: "/roots/{root_id}/settings", handle: (*CommentHandler).GetRootSettings,
+			summary: "Get a root's overrides of the global configuration (admins only); null fields use the global setting", auth: true,
+			data:   models.RootSettings{},
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+		},
+		{
+			method: http.MethodPut, path: "/roots/{root_id}/settings", handle: (*CommentHandler).UpdateRootSettings,
+			summary: "Replace a root's overrides (admins only): max depth, locked, pre-moderation and anonymous comments", auth: true,
+			body:   models.RootSettings{},
+			data:   models.RootSettings{},
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestEntityTooLarge},
+		},
+		{
+			method: http.MethodPut, path: "/roots/{root_id}/subscription", handle: (*CommentHandler).Subscribe,
+			summary: "Subscribe the user to new comments on a root; subscribing again has no effect", auth: true,
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized},
+		},
+		{
+			method: http.MethodDelete, path: "/roots/{root_id}/subscription", handle: (*CommentHandler).Unsubscribe,
+			summary: "Unsubscribe the user from a root", auth: true,
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized},
+		},
+		{
+			method: http.MethodGet, path: "/roots/{root_id}/moderation-queue", handle: (*CommentHandler).GetModerationQueue,
+			summary: "List a root's comments awaiting moderation, oldest first", auth: true,
+			query: paginationParams, data: []*models.Comment{}, paginated: true,
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized},
+		},
+
+		// Voting operations
+		{
+			method: http.MethodPost, path: "/comments/{id}/vote", handle: (*CommentHandler).VoteComment,
+			summary: "Vote on a comment and get back the updated comment, the stored vote and the score change", auth: true,
+			body: VoteRequest{}, data: VoteResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusRequestEntityTooLarge},
+		},
+		{
+			method: http.MethodPatch, path: "/comments/{id}/vote", handle: (*CommentHandler).VoteComment,
+			summary: "Change the user's vote on a comment; same as POST, with score_delta reporting the net score change", auth: true,
+			body: VoteRequest{}, data: VoteResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusRequestEntityTooLarge},
+		},
+		{
+			method: http.MethodDelete, path: "/comments/{id}/vote", handle: (*CommentHandler).RemoveVote,
+			summary: "Remove the user's vote from a comment", auth: true,
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized},
+		},
+		{
+			method: http.MethodGet, path: "/comments/{id}/vote", handle: (*CommentHandler).GetUserVote,
+			summary: "Get the user's vote type on a comment, null when they haven't voted", auth: true,
+			data:   (*models.VoteType)(nil),
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized},
+		},
+		{
+			method: http.MethodGet, path: "/comments/{id}/votes/summary", handle: (*CommentHandler).GetVoteBreakdown,
+			summary: "Get the up and down vote counts and number of voters for a comment",
+			data:    models.VoteBreakdown{}, errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+
+		// Root-based operations (comments for specific entities)
+		{
+			method: http.MethodGet, path: "/roots/{root_id}/comments", handle: (*CommentHandler).GetCommentsByRoot,
+			summary: "List comments for a root",
+			query:   listParams, data: []*models.Comment{}, paginated: true,
+			errors: []int{http.StatusBadRequest}, conditional: true,
+		},
+		{
+			method: http.MethodGet, path: "/roots/{root_id}/comments/since", handle: (*CommentHandler).GetCommentsSince,
+			summary: "Comments created, edited, voted on or deleted after a time, oldest change first; deleted ones are tombstones with is_deleted set",
+			query: []parameter{
+				{name: "since", kind: "string", description: "RFC 3339 time; pass the updated_at of the last comment from the previous poll", required: true},
+				{name: "after_id", kind: "string", description: "ID of the last comment from the previous poll, to resume among comments updated at since"},
+				{name: "limit", kind: "integer", description: "Most comments to return; a full page means more are waiting"},
+			},
+			data: []*models.Comment{}, errors: []int{http.StatusBadRequest, http.StatusGatewayTimeout},
+		},
+		{
+			method: http.MethodGet, path: "/roots/{root_id}/comments/with-votes", handle: (*CommentHandler).GetCommentsWithVotes,
+			summary: "List comments for a root with the user's votes, both keyed by comment ID and set on each comment", auth: true,
+			query: listParams, data: commentsWithVotes{}, paginated: true,
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized},
+		},
+		{
+			method: http.MethodGet, path: "/roots/{root_id}/tree", handle: (*CommentHandler).GetCommentTree,
+			summary: "Get the hierarchical comment tree for a root",
+			query: []parameter{
+				{name: "max_depth", kind: "integer", description: "Maximum tree depth"},
+				sortParams[0],
+				{name: "min_score", kind: "integer", description: "Flag comments scoring below it as collapsed; they stay in the tree"},
+				{name: "collapse_replies", kind: "boolean", description: "Also collapse every reply under a collapsed comment"},
+				{name: "max_children", kind: "integer", description: "Keep at most this many replies per comment; trimmed comments report has_more_children and remaining_children"},
+			},
+			data: []*models.CommentTree{}, errors: []int{http.StatusBadRequest, http.StatusGatewayTimeout},
+			conditional: true,
+		},
+		{
+			method: http.MethodGet, path: "/roots/{root_id}/tree/with-votes", handle: (*CommentHandler).GetCommentTreeWithVotes,
+			summary: "Get the hierarchical comment tree for a root with the user's vote on each node", auth: true,
+			query: []parameter{
+				{name: "max_depth", kind: "integer", description: "Maximum tree depth"},
+				sortParams[0],
+			},
+			data: []*models.CommentTree{}, errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusGatewayTimeout},
+		},
+		{
+			method: http.MethodGet, path: "/roots/{root_id}/stats", handle: (*CommentHandler).GetCommentStats,
+			summary: "Get comment statistics for a root",
+			data:    models.CommentStats{}, errors: []int{http.StatusBadRequest},
+		},
+		{
+			method: http.MethodGet, path: "/roots/{root_id}/top", handle: (*CommentHandler).GetTopComments,
+			summary: "Get the highest scored comments within a time range",
+			query: []parameter{
+				{name: "limit", kind: "integer", description: "Number of results (default: 10, max: 1000)"},
+				{name: "time_range", kind: "string", description: "hour, day, week, month or all (default: day)"},
+			},
+			data: []*models.Comment{}, errors: []int{http.StatusBadRequest, http.StatusGatewayTimeout},
+		},
+		{
+			method: http.MethodGet, path: "/roots/{root_id}/top-commenters", handle: (*CommentHandler).GetTopCommenters,
+			summary: "Rank the users commenting on a root by their number of live comments, then their combined score",
+			query: []parameter{
+				{name: "limit", kind: "integer", description: "Number of results (default: 10, max: 1000)"},
+			},
+			data: []*models.TopCommenter{}, errors: []int{http.StatusBadRequest, http.StatusGatewayTimeout},
+		},
+		{
+			method: http.MethodGet, path: "/roots/{root_id}/search", handle: (*CommentHandler).SearchComments,
+			summary: "Search comments within a root",
+			query: concatParams([]parameter{
+				{name: "q", kind: "string", description: "Search query", required: true},
+			}, listParams),
+			data: []*models.Comment{}, errors: []int{http.StatusBadRequest, http.StatusGatewayTimeout},
+		},
+		{
+			method: http.MethodGet, path: "/roots/{root_id}/edited", handle: (*CommentHandler).GetEditedComments,
+			summary: "List only edited comments for a root",
+			query:   listParams, data: []*models.Comment{}, paginated: true,
+			errors: []int{http.StatusBadRequest},
+		},
+		{
+			method: http.MethodGet, path: "/roots/{root_id}/export", handle: (*CommentHandler).ExportRoot,
+			summary: "Export every comment in a root as newline-delimited JSON, parents before replies",
+			query: []parameter{
+				{name: "include_deleted", kind: "boolean", description: "Include soft-deleted comments"},
+				{name: "include_votes", kind: "boolean", description: "Attach the votes cast on each comment"},
+			},
+			data: models.ExportedComment{}, stream: "application/x-ndjson",
+			errors: []int{http.StatusBadRequest},
+		},
+		{
+			method: http.MethodGet, path: "/roots/{root_id}/export.csv", handle: (*CommentHandler).ExportRootCSV,
+			summary: "Export a root's comments as CSV with columns " + strings.Join(csvColumns, ", "),
+			stream:  "text/csv", errors: []int{http.StatusBadRequest},
+		},
+		{
+			method: http.MethodGet, path: "/roots/{root_id}/stream", handle: (*CommentHandler).StreamComments,
+			summary: "WebSocket stream of comment.created, comment.updated, comment.deleted and comment.voted events for a root",
+			status:  http.StatusSwitchingProtocols, errors: []int{http.StatusBadRequest},
+		},
+
+		// Cross-root search and trending
+		{
+			method: http.MethodGet, path: "/search", handle: (*CommentHandler).SearchAllComments,
+			summary: "Full-text search across all roots",
+			query: concatParams([]parameter{
+				{name: "q", kind: "string", description: "Search query", required: true},
+				{name: "user_id", kind: "string", description: "Only comments by this user"},
+				{name: "root_id", kind: "string", description: "Only comments on this root"},
+			}, paginationParams, sortParams),
+			data: []*models.Comment{}, paginated: true,
+			errors: []int{http.StatusBadRequest, http.StatusGatewayTimeout},
+		},
+		{
+			method: http.MethodGet, path: "/trending", handle: (*CommentHandler).GetTrendingRoots,
+			summary: "Rank roots by the number of live comments created within a time range",
+			query: []parameter{
+				{name: "limit", kind: "integer", description: "Number of results (default: 10, max: 1000)"},
+				{name: "time_range", kind: "string", description: "hour, day, week, month or all (default: day)"},
+			},
+			data: []*models.TrendingRoot{},
+		},
+
+		// Configuration discovery
+		{
+			method: http.MethodGet, path: "/limits", handle: (*CommentHandler).GetLimits,
+			summary: "Get the content length, depth and page size limits in effect and the accepted sort fields",
+			data:    service.Limits{},
+		},
+
+		// User operations
+		{
+			method: http.MethodGet, path: "/users/{user_id}/comments", handle: (*CommentHandler).GetCommentsByUser,
+			summary: "List a user's comments, optionally on a single root",
+			query: concatParams([]parameter{
+				{name: "root_id", kind: "string", description: "Only comments on this root"},
+			}, listParams),
+			data: []*models.Comment{}, paginated: true,
+			errors: []int{http.StatusBadRequest, http.StatusForbidden},
+		},
+		{
+			method: http.MethodGet, path: "/users/{user_id}/votes", handle: (*CommentHandler).GetUserVotes,
+			summary: "List the comments a user has voted on with their vote, most recent first",
+			query: concatParams([]parameter{
+				{name: "root_id", kind: "string", description: "Only votes on this root"},
+				{name: "vote_type", kind: "string", description: "up or down"},
+				{name: "include_deleted", kind: "boolean", description: "Include votes on soft-deleted comments"},
+			}, paginationParams),
+			data: []*models.VotedComment{}, paginated: true,
+			errors: []int{http.StatusBadRequest, http.StatusForbidden},
+		},
+		{
+			method: http.MethodDelete, path: "/users/{user_id}/votes", handle: (*CommentHandler).RemoveUserVotes,
+			summary: "Remove the user's votes on the listed comments in one request", auth: true,
+			body:   RemoveVotesRequest{},
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestEntityTooLarge},
+		},
+		{
+			method: http.MethodGet, path: "/users/{user_id}/replies", handle: (*CommentHandler).GetRepliesToUser,
+			summary: "List other users' replies to the user's comments, newest first, each with the comment it answers",
+			query: concatParams([]parameter{
+				{name: "root_id", kind: "string", description: "Only replies on this root"},
+			}, paginationParams),
+			data: []*models.Reply{}, paginated: true,
+			errors: []int{http.StatusBadRequest, http.StatusForbidden},
+		},
+		{
+			method: http.MethodGet, path: "/users/{user_id}/count", handle: (*CommentHandler).GetUserCommentCount,
+			summary: "Count a user's comments",
+			data:    userCommentCount{}, errors: []int{http.StatusBadRequest, http.StatusForbidden},
+		},
+		{
+			method: http.MethodGet, path: "/users/{user_id}/blocks", handle: (*CommentHandler).GetBlockedUsers,
+			summary: "List the users the requesting user has blocked", auth: true,
+			data:   []string{},
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+		},
+		{
+			method: http.MethodPut, path: "/users/{user_id}/blocks/{blocked_id}", handle: (*CommentHandler).BlockUser,
+			summary: "Hide a user's comments from the requesting user's lists and trees", auth: true,
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+		},
+		{
+			method: http.MethodDelete, path: "/users/{user_id}/blocks/{blocked_id}", handle: (*CommentHandler).UnblockUser,
+			summary: "Lift a block", auth: true,
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+		},
+		{
+			method: http.MethodGet, path: "/users/{user_id}/reputation", handle: (*CommentHandler).GetUserReputation,
+			summary: "Total the votes a user's live comments have received across all roots, leaving out self-votes",
+			data:    models.UserReputation{}, errors: []int{http.StatusBadRequest, http.StatusGatewayTimeout},
+		},
+		{
+			method: http.MethodGet, path: "/users/{user_id}/export.csv", handle: (*CommentHandler).ExportUserCSV,
+			summary: "Export a user's comments as CSV, oldest first, with the same columns as the root export",
+			stream:  "text/csv", errors: []int{http.StatusBadRequest, http.StatusForbidden},
+		},
+	}
+}
+
+// commentsWithVotes documents the payload of GET /roots/{root_id}/comments/with-votes
+type commentsWithVotes struct {
+	Comments []*models.Comment       `json:"comments"`
+	Votes    map[string]*models.Vote `json:"votes"`
+}
+
+// userCommentCount documents the payload of GET /users/{user_id}/count
+type userCommentCount struct {
+	UserID string `json:"user_id"`
+	Count  int64  `json:"count"`
+}
+
+// concatParams joins parameter lists into a new slice
+func concatParams(lists ...[]parameter) []parameter {
+	var params []parameter
+	for _, list := range lists {
+		params = append(params, list...)
+	}
+	return params
+}
+
+// OpenAPISpec returns the OpenAPI 3 document describing the HTTP API
+func OpenAPISpec() map[string]interface{} {
+	b := &specBuilder{schemas: map[string]interface{}{}}
+	b.schemaOf(reflect.TypeOf(APIResponse{}))
+	b.schemaOf(reflect.TypeOf(Pagination{}))
+
+	b.schemaOf(reflect.TypeOf(HealthReport{}))
+
+	readiness := map[string]interface{}{
+		"get": map[string]interface{}{
+			"summary": "Readiness: checks the database within a short timeout, and that its migrations have been applied",
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{"description": "Healthy", "content": jsonContent(ref("HealthReport"))},
+				"503": map[string]interface{}{"description": "Database unreachable or not yet migrated", "content": jsonContent(ref("HealthReport"))},
+			},
+		},
+	}
+	paths := map[string]interface{}{
+		"/health":       readiness,
+		"/health/ready": readiness,
+		"/health/detailed": map[string]interface{}{
+			"get": map[string]interface{}{
+				"summary": "Readiness plus the database connection pool's stats, under pool",
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{"description": "Healthy", "content": jsonContent(ref("HealthReport"))},
+					"503": map[string]interface{}{"description": "Database unreachable", "content": jsonContent(ref("HealthReport"))},
+				},
+			},
+		},
+		"/health/live": map[string]interface{}{
+			"get": map[string]interface{}{
+				"summary": "Liveness: reports that the process is serving requests",
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{"description": "Alive", "content": jsonContent(ref("HealthReport"))},
+				},
+			},
+		},
+	}
+	for _, rt := range apiRoutes() {
+		path := "/api/v1" + rt.path
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[path] = item
+		}
+		item[strings.ToLower(rt.method)] = b.operation(rt)
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Commentific API",
+			"description": "A production-grade commenting system with infinite hierarchy support.",
+			"version":     serviceVersion,
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": b.schemas},
+	}
+}
+
+// specBuilder accumulates component schemas while operations are described
+type specBuilder struct {
+	schemas map[string]interface{}
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// operation describes a single route
+func (b *specBuilder) operation(rt route) map[string]interface{} {
+	var params []interface{}
+	for _, match := range pathParamPattern.FindAllStringSubmatch(rt.path, -1) {
+		params = append(params, map[string]interface{}{
+			"name": match[1], "in": "path", "required": true,
+			"schema": map[string]interface{}{"type": "string"},
+		})
+	}
+	if rt.auth {
+		params = append(params,
+			map[string]interface{}{
+				"name": "X-User-ID", "in": "header", "description": "Acting user; may be passed as the user_id query parameter instead",
+				"schema": map[string]interface{}{"type": "string"},
+			},
+			map[string]interface{}{
+				"name": "user_id", "in": "query", "description": "Acting user when the X-User-ID header is absent",
+				"schema": map[string]interface{}{"type": "string"},
+			},
+		)
+	}
+	if rt.conditional {
+		params = append(params, map[string]interface{}{
+			"name": "If-None-Match", "in": "header", "description": "ETag from an earlier response; answered with 304 while it is still current",
+			"schema": map[string]interface{}{"type": "string"},
+		})
+	}
+	for _, p := range rt.query {
+		params = append(params, map[string]interface{}{
+			"name": p.name, "in": "query", "description": p.description, "required": p.required,
+			"schema": map[string]interface{}{"type": p.kind},
+		})
+	}
+
+	status := rt.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	responses := map[string]interface{}{
+		strconv.Itoa(status): b.successResponse(rt, status),
+	}
+	if rt.conditional {
+		responses[strconv.Itoa(http.StatusNotModified)] = map[string]interface{}{
+			"description": http.StatusText(http.StatusNotModified),
+		}
+	}
+	for _, code := range append(rt.errors, http.StatusInternalServerError) {
+		responses[strconv.Itoa(code)] = map[string]interface{}{
+			"description": http.StatusText(code),
+			"content":     jsonContent(ref("APIResponse")),
+		}
+	}
+
+	op := map[string]interface{}{
+		"summary":   rt.summary,
+		"responses": responses,
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+	if rt.body != nil {
+		op["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  jsonContent(b.schemaOf(reflect.TypeOf(rt.body))),
+		}
+	}
+	return op
+}
+
+// successResponse wraps the route's payload in the response envelope
+func (b *specBuilder) successResponse(rt route, status int) map[string]interface{} {
+	response := map[string]interface{}{"description": http.StatusText(status)}
+	if status == http.StatusSwitchingProtocols {
+		return response
+	}
+
+	if rt.stream != "" {
+		// NDJSON documents a single line; anything else is opaque text
+		schema := map[string]interface{}{"type": "string"}
+		if rt.data != nil {
+			schema = b.schemaOf(reflect.TypeOf(rt.data))
+		}
+		response["content"] = map[string]interface{}{
+			rt.stream: map[string]interface{}{"schema": schema},
+		}
+		return response
+	}
+
+	envelope := ref("APIResponse")
+	if rt.paginated {
+		envelope = map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"success":    map[string]interface{}{"type": "boolean"},
+				"pagination": ref("Pagination"),
+				"error":      map[string]interface{}{"type": "string"},
+			},
+		}
+	}
+	if rt.data != nil {
+		envelope = map[string]interface{}{
+			"allOf": []interface{}{
+				envelope,
+				map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"data": b.schemaOf(reflect.TypeOf(rt.data))},
+				},
+			},
+		}
+	}
+	response["content"] = jsonContent(envelope)
+	return response
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	voteTypeType = reflect.TypeOf(models.VoteType(0))
+)
+
+// schemaOf returns the JSON schema for t, registering named structs as components
+func (b *specBuilder) schemaOf(t reflect.Type) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		schema := b.schemaOf(t.Elem())
+		if _, isRef := schema["$ref"]; isRef {
+			return map[string]interface{}{"allOf": []interface{}{schema}, "nullable": true}
+		}
+		schema["nullable"] = true
+		return schema
+	}
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	if t == voteTypeType {
+		// Encoded by name; requests may also send 1, -1 or 0
+		return map[string]interface{}{"type": "string", "enum": []string{"up", "down", "none"}}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": b.schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return b.structSchema(t)
+	default:
+		// interface{} and anything else accept any JSON value
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema describes a struct's JSON fields. Exported named structs
+// become components referenced by name; the name is reserved before the
+// fields are walked so self-referencing types like CommentTree terminate.
+func (b *specBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	name := t.Name()
+	exported := name != "" && name[0] >= 'A' && name[0] <= 'Z'
+	if exported {
+		if _, seen := b.schemas[name]; seen {
+			return ref(name)
+		}
+		b.schemas[name] = nil
+	}
+
+	properties := map[string]interface{}{}
+	var required []string
+	var embedded []interface{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		fieldName, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && fieldName == "" {
+			// encoding/json inlines untagged embedded structs
+			embeddedType := field.Type
+			if embeddedType.Kind() == reflect.Ptr {
+				embeddedType = embeddedType.Elem()
+			}
+			embedded = append(embedded, b.schemaOf(embeddedType))
+			continue
+		}
+		if fieldName == "" {
+			fieldName = field.Name
+		}
+		properties[fieldName] = b.schemaOf(field.Type)
+		if slices.Contains(strings.Split(field.Tag.Get("validate"), ","), "required") && !strings.Contains(opts, "omitempty") {
+			required = append(required, fieldName)
+		}
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if len(embedded) > 0 {
+		schema = map[string]interface{}{"allOf": append(embedded, schema)}
+	}
+	if !exported {
+		return schema
+	}
+	b.schemas[name] = schema
+	return ref(name)
+}
+
+func ref(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+func jsonContent(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schema},
+	}
+}
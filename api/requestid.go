@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/christopher18/commentific/v2/service"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the ID that correlates a request across services
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the incoming IDs that are trusted as they are
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware gives every request an ID: the caller's X-Request-ID
+// when it sends a usable one, a new UUID otherwise. The ID is echoed in the
+// response header and stored in the request context, where handlers and the
+// service read it with service.RequestIDFromContext and loggers wrapped with
+// service.NewRequestIDHandler add it to each record. NewRouter installs it.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(service.WithRequestID(r.Context(), requestID)))
+	})
+}
+
+// validRequestID accepts short IDs of visible ASCII, so a caller can't
+// smuggle control characters or huge values into logs and headers
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] < '!' || requestID[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
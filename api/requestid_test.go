@@ -0,0 +1,96 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/api"
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/service"
+	"github.com/google/uuid"
+)
+
+func TestRequestID_RoundTrips(t *testing.T) {
+	// Setup
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	commentService.SetLogger(logger)
+	router := api.NewRouterWithLogger(commentService, logger)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/comments", strings.NewReader(`{"root_id": "post-1", "content": "   "}`))
+	req.Header.Set("X-User-ID", "alice")
+	req.Header.Set("X-Request-ID", "req-42")
+	rec := httptest.NewRecorder()
+
+	// Execute
+	router.ServeHTTP(rec, req)
+
+	// Assert
+	if got := rec.Header().Get("X-Request-ID"); got != "req-42" {
+		t.Fatalf("Expected the request ID to be echoed, got %q", got)
+	}
+	// Both the service's warning and the request log carry the ID
+	var messages []string
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Failed to decode log line %q: %v", line, err)
+		}
+		if record["request_id"] != "req-42" {
+			t.Errorf("Expected request_id req-42 on %q, got %v", record["msg"], record["request_id"])
+		}
+		messages = append(messages, record["msg"].(string))
+	}
+	if strings.Join(messages, ",") != "invalid input rejected,http request" {
+		t.Errorf("Expected the service warning and the request log, got %v", messages)
+	}
+}
+
+func TestRequestID_GeneratedWhenAbsent(t *testing.T) {
+	// Setup
+	router := api.NewRouter(service.NewCommentService(memory.NewMemoryRepository()))
+
+	for name, incoming := range map[string]string{
+		"absent":        "",
+		"too long":      strings.Repeat("a", 200),
+		"control chars": "req\r\nSet-Cookie: x",
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/health/live", nil)
+			if incoming != "" {
+				req.Header.Set("X-Request-ID", incoming)
+			}
+			rec := httptest.NewRecorder()
+
+			// Execute
+			router.ServeHTTP(rec, req)
+
+			// Assert
+			if _, err := uuid.Parse(rec.Header().Get("X-Request-ID")); err != nil {
+				t.Errorf("Expected a generated UUID, got %q", rec.Header().Get("X-Request-ID"))
+			}
+		})
+	}
+}
+
+func TestRequestID_AvailableToHandlers(t *testing.T) {
+	// Setup
+	var seen string
+	handler := api.RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = service.RequestIDFromContext(r.Context())
+	}))
+	rec := httptest.NewRecorder()
+
+	// Execute
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// Assert
+	if seen == "" || seen != rec.Header().Get("X-Request-ID") {
+		t.Errorf("Expected the handler to see the echoed ID %q, got %q", rec.Header().Get("X-Request-ID"), seen)
+	}
+}
@@ -1,6 +1,12 @@
 package api
 
 import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"time"
 
@@ -10,61 +16,121 @@ import (
 
 // Router sets up and returns the HTTP router with all endpoints
 func NewRouter(commentService *service.CommentService) *mux.Router {
+	return NewRouterWithLogger(commentService, nil)
+}
+
+// NewRouterWithLogger is like NewRouter but logs each request to logger.
+// A nil logger logs nothing.
+func NewRouterWithLogger(commentService *service.CommentService, logger *slog.Logger) *mux.Router {
+	return NewRouterWithConfig(commentService, &RouterConfig{Logger: logger})
+}
+
+// RouterConfig holds optional dependencies for NewRouterWithConfig
+type RouterConfig struct {
+	// Logger receives one record per request. Nil logs nothing.
+	Logger *slog.Logger
+	// HealthChecker is consulted by /health and /health/ready, e.g. a
+	// postgres.PostgresProvider. If it is also a SchemaChecker, readiness
+	// stays down until its schema check first passes. Nil reports healthy
+	// without checking.
+	HealthChecker HealthChecker
+	// CompressionMinSize is the smallest response body gzipped for clients
+	// that accept it. Zero uses DefaultCompressionMinSize.
+	CompressionMinSize int
+	// DisableCompression sends every response uncompressed, e.g. when a
+	// proxy in front already compresses
+	DisableCompression bool
+	// IsModerator reports whether a request comes from a moderator, who may
+	// remove anyone's comment and sees who deleted a comment and why. Nil
+	// treats nobody as a moderator.
+	IsModerator func(r *http.Request) bool
+	// IsAdmin reports whether a request comes from an admin, who may read
+	// and change per-root settings. Nil treats nobody as an admin.
+	IsAdmin func(r *http.Request) bool
+	// MaxBodyBytes caps the size of JSON request bodies, answering larger
+	// ones with 413. Zero uses DefaultMaxBodyBytes.
+	MaxBodyBytes int64
+}
+
+// NewRouterWithConfig sets up the HTTP router using the given configuration
+func NewRouterWithConfig(commentService *service.CommentService, config *RouterConfig) *mux.Router {
+	if config == nil {
+		config = &RouterConfig{}
+	}
+
 	router := mux.NewRouter()
 
 	// Add middleware
-	router.Use(corsMiddleware)
-	router.Use(loggingMiddleware)
-	router.Use(contentTypeMiddleware)
+	for _, middleware := range config.middleware() {
+		router.Use(middleware)
+	}
 
 	// Create handler
 	handler := NewCommentHandler(commentService)
+	handler.SetModeratorCheck(config.IsModerator)
+	handler.SetAdminCheck(config.IsAdmin)
+	handler.SetMaxBodyBytes(config.MaxBodyBytes)
 
 	// API routes
 	api := router.PathPrefix("/api/v1").Subrouter()
-
-	// Comment operations
-	api.HandleFunc("/comments", handler.CreateComment).Methods("POST")
-	api.HandleFunc("/comments/{id}", handler.GetComment).Methods("GET")
-	api.HandleFunc("/comments/{id}", handler.UpdateComment).Methods("PUT")
-	api.HandleFunc("/comments/{id}", handler.DeleteComment).Methods("DELETE")
-	api.HandleFunc("/comments/{id}/path", handler.GetCommentPath).Methods("GET")
-	api.HandleFunc("/comments/{id}/children", handler.GetCommentChildren).Methods("GET")
-
-	// Voting operations
-	api.HandleFunc("/comments/{id}/vote", handler.VoteComment).Methods("POST")
-	api.HandleFunc("/comments/{id}/vote", handler.RemoveVote).Methods("DELETE")
-
-	// Root-based operations (comments for specific entities)
-	api.HandleFunc("/roots/{root_id}/comments", handler.GetCommentsByRoot).Methods("GET")
-	api.HandleFunc("/roots/{root_id}/comments/with-votes", handler.GetCommentsWithVotes).Methods("GET")
-	api.HandleFunc("/roots/{root_id}/tree", handler.GetCommentTree).Methods("GET")
-	api.HandleFunc("/roots/{root_id}/stats", handler.GetCommentStats).Methods("GET")
-	api.HandleFunc("/roots/{root_id}/top", handler.GetTopComments).Methods("GET")
-	api.HandleFunc("/roots/{root_id}/search", handler.SearchComments).Methods("GET")
-	api.HandleFunc("/roots/{root_id}/edited", handler.GetEditedComments).Methods("GET")
-
-	// User operations
-	api.HandleFunc("/users/{user_id}/comments", handler.GetCommentsByUser).Methods("GET")
-	api.HandleFunc("/users/{user_id}/count", handler.GetUserCommentCount).Methods("GET")
-
-	// Health check endpoint
-	router.HandleFunc("/health", healthCheckHandler).Methods("GET")
-
-	// API documentation endpoint
+	for _, rt := range apiRoutes() {
+		handle := rt.handle
+		api.HandleFunc(rt.path, func(w http.ResponseWriter, r *http.Request) {
+			handle(handler, w, r)
+		}).Methods(rt.method)
+	}
+
+	// Health check endpoints: /health and /health/ready check the database
+	// and its schema, /health/detailed adds the connection pool's stats,
+	// /health/live only reports that the process is serving requests
+	checks := newHealthChecks(config.HealthChecker)
+	router.HandleFunc("/health", healthCheckHandler(checks)).Methods("GET")
+	router.HandleFunc("/health/ready", healthCheckHandler(checks)).Methods("GET")
+	router.HandleFunc("/health/detailed", detailedHealthHandler(checks)).Methods("GET")
+	router.HandleFunc("/health/live", livenessHandler).Methods("GET")
+
+	// API documentation endpoints
+	router.HandleFunc("/openapi.json", openAPIHandler).Methods("GET")
 	router.HandleFunc("/", apiDocumentationHandler).Methods("GET")
 
 	return router
 }
 
+// middleware returns the middleware every request passes through, outermost
+// first
+func (config *RouterConfig) middleware() []mux.MiddlewareFunc {
+	logger := config.Logger
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	logger = slog.New(service.NewRequestIDHandler(logger.Handler()))
+
+	middleware := []mux.MiddlewareFunc{
+		RequestIDMiddleware,
+		corsMiddleware,
+		loggingMiddleware(logger),
+		contentTypeMiddleware,
+	}
+	if !config.DisableCompression {
+		middleware = append(middleware, CompressionMiddleware(config.CompressionMinSize))
+	}
+	return middleware
+}
+
+// MuxPathParams reads path parameters from routes served by gorilla/mux
+func MuxPathParams(r *http.Request, name string) string {
+	return mux.Vars(r)[name]
+}
+
 // Middleware functions
 
 // corsMiddleware adds CORS headers to responses
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-User-ID, Authorization")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-User-ID, Authorization, X-Request-ID, Idempotency-Key, If-Match, If-None-Match")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, ETag")
 		w.Header().Set("Access-Control-Max-Age", "86400")
 
 		if r.Method == "OPTIONS" {
@@ -76,26 +142,25 @@ func corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// loggingMiddleware logs HTTP requests
-func loggingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-
-		// Create a response writer wrapper to capture status code
-		wrapped := &responseWriterWrapper{ResponseWriter: w, statusCode: http.StatusOK}
-
-		next.ServeHTTP(wrapped, r)
-
-		duration := time.Since(start)
-
-		// Log request details (in production, use a proper logger)
-		// fmt.Printf("[%s] %s %s - %d (%v)\n",
-		//	time.Now().Format("2006-01-02 15:04:05"),
-		//	r.Method, r.URL.Path, wrapped.statusCode, duration)
-
-		// For now, we'll keep it simple to avoid import cycles
-		_ = duration // Suppress unused variable warning
-	})
+// loggingMiddleware logs the method, path, status and duration of each request
+func loggingMiddleware(logger *slog.Logger) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			// Create a response writer wrapper to capture status code
+			wrapped := &responseWriterWrapper{ResponseWriter: w, statusCode: http.StatusOK}
+
+			next.ServeHTTP(wrapped, r)
+
+			logger.InfoContext(r.Context(), "http request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", wrapped.statusCode,
+				"duration", time.Since(start),
+			)
+		})
+	}
 }
 
 // contentTypeMiddleware sets default content type for JSON responses
@@ -120,154 +185,39 @@ func (w *responseWriterWrapper) WriteHeader(statusCode int) {
 	w.ResponseWriter.WriteHeader(statusCode)
 }
 
-// Health check handler
-func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
+// Hijack lets WebSocket upgrades pass through the wrapper
+func (w *responseWriterWrapper) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	w.statusCode = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+// openAPIHandler serves the OpenAPI spec built from the route table
+func openAPIHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{
-		"status": "healthy",
-		"service": "commentific",
-		"version": "1.0.0",
-		"timestamp": "` + time.Now().Format(time.RFC3339) + `"
-	}`))
+	json.NewEncoder(w).Encode(OpenAPISpec())
 }
 
-// API documentation handler
+// apiDocumentationHandler renders Swagger UI for /openapi.json
 func apiDocumentationHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html")
 	w.WriteHeader(http.StatusOK)
-
-	html := `<!DOCTYPE html>
+	w.Write([]byte(`<!DOCTYPE html>
 <html>
 <head>
-    <title>Commentific API</title>
-    <style>
-        body { font-family: Arial, sans-serif; margin: 40px; }
-        h1 { color: #333; }
-        h2 { color: #666; margin-top: 30px; }
-        .endpoint { background-color: #f5f5f5; padding: 10px; margin: 10px 0; border-radius: 5px; }
-        .method { font-weight: bold; color: #2e7d32; }
-        .path { color: #1565c0; }
-        code { background-color: #e8e8e8; padding: 2px 5px; border-radius: 3px; }
-    </style>
+    <title>Commentific API Documentation</title>
+    <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
 </head>
 <body>
-    <h1>Commentific API Documentation</h1>
-    <p>A production-grade commenting system with infinite hierarchy support.</p>
-    
-    <h2>Authentication</h2>
-    <p>Include user identification in requests using either:</p>
-    <ul>
-        <li>Header: <code>X-User-ID: your-user-id</code></li>
-        <li>Query parameter: <code>?user_id=your-user-id</code></li>
-    </ul>
-    
-    <h2>Comment Operations</h2>
-    
-    <div class="endpoint">
-        <span class="method">POST</span> <span class="path">/api/v1/comments</span><br>
-        Create a new comment
-    </div>
-    
-    <div class="endpoint">
-        <span class="method">GET</span> <span class="path">/api/v1/comments/{id}</span><br>
-        Get a specific comment<br>
-        <small>Response includes edit tracking: <code>is_edited</code>, <code>edit_count</code>, <code>original_content</code>, and <code>content_updated_at</code></small>
-    </div>
-    
-    <div class="endpoint">
-        <span class="method">PUT</span> <span class="path">/api/v1/comments/{id}</span><br>
-        Update a comment (requires ownership)
-    </div>
-    
-    <div class="endpoint">
-        <span class="method">DELETE</span> <span class="path">/api/v1/comments/{id}</span><br>
-        Delete a comment (requires ownership)
-    </div>
-    
-    <div class="endpoint">
-        <span class="method">GET</span> <span class="path">/api/v1/comments/{id}/children</span><br>
-        Get child comments (subtree) for a specific comment
-        <br><small>Query params: <code>max_depth</code> (default: 10)</small>
-    </div>
-    
-    <h2>Voting Operations</h2>
-    
-    <div class="endpoint">
-        <span class="method">POST</span> <span class="path">/api/v1/comments/{id}/vote</span><br>
-        Vote on a comment (body: {"vote_type": 1 or -1})
-    </div>
-    
-    <div class="endpoint">
-        <span class="method">DELETE</span> <span class="path">/api/v1/comments/{id}/vote</span><br>
-        Remove vote from a comment
-    </div>
-    
-    <h2>Root-based Operations</h2>
-    
-    <div class="endpoint">
-        <span class="method">GET</span> <span class="path">/api/v1/roots/{root_id}/comments</span><br>
-        Get comments for a specific root (with pagination)
-    </div>
-    
-    <div class="endpoint">
-        <span class="method">GET</span> <span class="path">/api/v1/roots/{root_id}/tree</span><br>
-        Get hierarchical comment tree
-    </div>
-    
-    <div class="endpoint">
-        <span class="method">GET</span> <span class="path">/api/v1/roots/{root_id}/stats</span><br>
-        Get comment statistics for a root
-    </div>
-    
-    <div class="endpoint">
-        <span class="method">GET</span> <span class="path">/api/v1/roots/{root_id}/top</span><br>
-        Get top-rated comments within time range
-    </div>
-    
-    <div class="endpoint">
-        <span class="method">GET</span> <span class="path">/api/v1/roots/{root_id}/search?q=query</span><br>
-        Search comments within a root
-    </div>
-    
-    <div class="endpoint">
-        <span class="method">GET</span> <span class="path">/api/v1/roots/{root_id}/edited</span><br>
-        Get only edited comments for a root<br>
-        <small>Query params: <code>min_edits</code>, <code>max_edits</code>, <code>sort_by=edit_count|content_updated_at</code></small>
-    </div>
-    
-    <h2>User Operations</h2>
-    
-    <div class="endpoint">
-        <span class="method">GET</span> <span class="path">/api/v1/users/{user_id}/comments</span><br>
-        Get comments by a specific user
-    </div>
-    
-    <div class="endpoint">
-        <span class="method">GET</span> <span class="path">/api/v1/users/{user_id}/count</span><br>
-        Get comment count for a user
-    </div>
-    
-    <h2>Query Parameters</h2>
-    <p>Most list endpoints support:</p>
-    <ul>
-        <li><code>limit</code> - Number of results (default: 50, max: 1000)</li>
-        <li><code>offset</code> - Pagination offset</li>
-        <li><code>sort_by</code> - Sort field (score, created_at, updated_at, content_updated_at, edit_count)</li>
-        <li><code>sort_order</code> - Sort direction (asc, desc)</li>
-        <li><code>max_depth</code> - Maximum comment depth for tree operations</li>
-        <li><code>is_edited</code> - Filter by edit status (true/false)</li>
-        <li><code>min_edits</code> - Minimum number of edits</li>
-        <li><code>max_edits</code> - Maximum number of edits</li>
-    </ul>
-    
-    <h2>Health Check</h2>
-    <div class="endpoint">
-        <span class="method">GET</span> <span class="path">/health</span><br>
-        Service health status
-    </div>
+    <div id="swagger-ui"></div>
+    <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+    <script>
+        window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+    </script>
 </body>
-</html>`
-
-	w.Write([]byte(html))
+</html>`))
 }
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/christopher18/commentific/v2/service"
+)
+
+// PathParams reads a named path parameter, such as "id" in /comments/{id},
+// from a routed request. It is how CommentHandler stays independent of the
+// router in front of it.
+type PathParams func(r *http.Request, name string) string
+
+// StdPathParams reads path parameters matched by http.ServeMux patterns
+func StdPathParams(r *http.Request, name string) string {
+	return r.PathValue(name)
+}
+
+// NewStdHandler serves the same API as NewRouterWithConfig, with the same
+// middleware, from a standard http.ServeMux using Go 1.22 path patterns.
+// Mount it wherever the API should live; nil config uses the defaults.
+func NewStdHandler(commentService *service.CommentService, config *RouterConfig) http.Handler {
+	if config == nil {
+		config = &RouterConfig{}
+	}
+
+	serveMux := http.NewServeMux()
+	handler := NewCommentHandlerWithParams(commentService, StdPathParams)
+	handler.SetModeratorCheck(config.IsModerator)
+	handler.SetAdminCheck(config.IsAdmin)
+	handler.SetMaxBodyBytes(config.MaxBodyBytes)
+
+	// API routes
+	for _, rt := range apiRoutes() {
+		handle := rt.handle
+		serveMux.HandleFunc(rt.method+" /api/v1"+rt.path, func(w http.ResponseWriter, r *http.Request) {
+			handle(handler, w, r)
+		})
+	}
+
+	// Health check endpoints: /health and /health/ready check the database
+	// and its schema, /health/detailed adds the connection pool's stats,
+	// /health/live only reports that the process is serving requests
+	checks := newHealthChecks(config.HealthChecker)
+	serveMux.HandleFunc("GET /health", healthCheckHandler(checks))
+	serveMux.HandleFunc("GET /health/ready", healthCheckHandler(checks))
+	serveMux.HandleFunc("GET /health/detailed", detailedHealthHandler(checks))
+	serveMux.HandleFunc("GET /health/live", livenessHandler)
+
+	// API documentation endpoints
+	serveMux.HandleFunc("GET /openapi.json", openAPIHandler)
+	serveMux.HandleFunc("GET /{$}", apiDocumentationHandler)
+
+	// Apply middleware innermost first so the first in the list runs first
+	var h http.Handler = serveMux
+	middleware := config.middleware()
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+	return h
+}
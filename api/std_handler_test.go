@@ -0,0 +1,127 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/api"
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestStdHandler_ServesAPI(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	handler := api.NewStdHandler(commentService, nil)
+	serve := func(method, path, userID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("X-User-ID", userID)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Execute: create a thread and a reply, then read them back through
+	// routes with path parameters
+	created := serve(http.MethodPost, "/api/v1/comments", "alice", `{"root_id": "post-1", "content": "Hello from ServeMux"}`)
+	if created.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", created.Code, created.Body.String())
+	}
+	var comment struct {
+		Data models.Comment `json:"data"`
+	}
+	if err := json.Unmarshal(created.Body.Bytes(), &comment); err != nil {
+		t.Fatalf("Failed to decode comment: %v", err)
+	}
+	reply := serve(http.MethodPost, "/api/v1/comments", "alice", `{"root_id": "post-1", "parent_id": "`+comment.Data.ID+`", "content": "A reply"}`)
+	if reply.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201 for the reply, got %d: %s", reply.Code, reply.Body.String())
+	}
+
+	// Assert
+	if rec := serve(http.MethodGet, "/api/v1/comments/"+comment.Data.ID, "alice", ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Hello from ServeMux") {
+		t.Errorf("Expected the comment by ID, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var tree struct {
+		Data []*models.CommentTree `json:"data"`
+	}
+	rec := serve(http.MethodGet, "/api/v1/roots/post-1/tree", "alice", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &tree); err != nil {
+		t.Fatalf("Failed to decode tree: %v", err)
+	}
+	if len(tree.Data) != 1 || len(tree.Data[0].Children) != 1 {
+		t.Errorf("Expected one thread with one reply, got %+v", tree.Data)
+	}
+	if rec := serve(http.MethodGet, "/api/v1/users/alice/count", "alice", ""); !strings.Contains(rec.Body.String(), `"count":2`) {
+		t.Errorf("Expected alice's two comments, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := serve(http.MethodPost, "/api/v1/comments/"+comment.Data.ID+"/vote", "bob", `{"vote_type": "up"}`); rec.Code != http.StatusOK {
+		t.Errorf("Expected the vote to be recorded, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := serve(http.MethodGet, "/api/v1/comments/missing", "alice", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a missing comment, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodPatch, "/api/v1/roots/post-1/tree", "alice", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405 for an unrouted method, got %d", rec.Code)
+	}
+}
+
+func TestStdHandler_HealthDocsAndMiddleware(t *testing.T) {
+	// Setup
+	handler := api.NewStdHandler(service.NewCommentService(memory.NewMemoryRepository()), &api.RouterConfig{})
+
+	for _, path := range []string{"/health", "/health/live", "/openapi.json", "/"} {
+		t.Run(path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			// Execute
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+			// Assert
+			if rec.Code != http.StatusOK {
+				t.Errorf("Expected status 200, got %d", rec.Code)
+			}
+			if rec.Header().Get("X-Request-ID") == "" {
+				t.Error("Expected the request ID middleware to run")
+			}
+			if rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+				t.Error("Expected the CORS middleware to run")
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nowhere", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 off the documented paths, got %d", rec.Code)
+	}
+}
+
+func TestStdHandler_StreamsWithPathParams(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	server := httptest.NewServer(api.NewStdHandler(commentService, nil))
+	defer server.Close()
+
+	// Execute
+	conn := dialStream(t, server, "post-1")
+	if _, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Live"}); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Assert
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var event models.CommentEvent
+	if err := conn.ReadJSON(&event); err != nil {
+		t.Fatalf("Failed to read event: %v", err)
+	}
+	if event.Comment == nil || event.Comment.RootID != "post-1" {
+		t.Errorf("Expected the event for post-1, got %+v", event)
+	}
+}
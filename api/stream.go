@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// streamBufferSize is how many events a subscriber may fall behind before it is dropped
+	streamBufferSize = 64
+
+	streamWriteWait  = 10 * time.Second
+	streamPongWait   = 60 * time.Second
+	streamPingPeriod = (streamPongWait * 9) / 10
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Matches the permissive CORS policy of the REST API
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// StreamHub fans comment events out to WebSocket subscribers, grouped by root ID.
+// It implements service.EventEmitter.
+type StreamHub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[*streamSubscriber]struct{}
+}
+
+// streamSubscriber is a single WebSocket connection listening to one root
+type streamSubscriber struct {
+	rootID string
+	send   chan []byte
+	once   sync.Once
+}
+
+// close stops the subscriber's writer; safe to call more than once
+func (s *streamSubscriber) close() {
+	s.once.Do(func() { close(s.send) })
+}
+
+// NewStreamHub creates an empty stream hub
+func NewStreamHub() *StreamHub {
+	return &StreamHub{
+		subscribers: make(map[string]map[*streamSubscriber]struct{}),
+	}
+}
+
+// Emit broadcasts an event to every subscriber of its root. Subscribers whose
+// buffer is full are dropped rather than allowed to block the caller.
+func (h *StreamHub) Emit(ctx context.Context, event *models.CommentEvent) {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	var slow []*streamSubscriber
+
+	h.mu.RLock()
+	for sub := range h.subscribers[event.RootID] {
+		select {
+		case sub.send <- message:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range slow {
+		h.unsubscribe(sub)
+	}
+}
+
+// SubscriberCount returns the number of connected subscribers for a root
+func (h *StreamHub) SubscriberCount(rootID string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.subscribers[rootID])
+}
+
+func (h *StreamHub) subscribe(rootID string) *streamSubscriber {
+	sub := &streamSubscriber{
+		rootID: rootID,
+		send:   make(chan []byte, streamBufferSize),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.subscribers[rootID] == nil {
+		h.subscribers[rootID] = make(map[*streamSubscriber]struct{})
+	}
+	h.subscribers[rootID][sub] = struct{}{}
+	return sub
+}
+
+func (h *StreamHub) unsubscribe(sub *streamSubscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if subs, ok := h.subscribers[sub.rootID]; ok {
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(h.subscribers, sub.rootID)
+		}
+	}
+	sub.close()
+}
+
+// ServeRoot handles GET /roots/{root_id}/stream, upgrading the connection to a
+// WebSocket and streaming events for that root until the client disconnects.
+// The root ID comes from a gorilla/mux or http.ServeMux route.
+func (h *StreamHub) ServeRoot(w http.ResponseWriter, r *http.Request) {
+	rootID := StdPathParams(r, "root_id")
+	if rootID == "" {
+		rootID = MuxPathParams(r, "root_id")
+	}
+	h.serveRoot(w, r, rootID)
+}
+
+// serveRoot streams events for rootID over a WebSocket
+func (h *StreamHub) serveRoot(w http.ResponseWriter, r *http.Request, rootID string) {
+	if rootID == "" {
+		http.Error(w, "Root ID is required", http.StatusBadRequest)
+		return
+	}
+
+	// Subscribe before completing the handshake so no event emitted after the
+	// client sees the upgrade is missed
+	sub := h.subscribe(rootID)
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already written an error response
+		h.unsubscribe(sub)
+		return
+	}
+
+	go h.writePump(conn, sub)
+	h.readPump(conn, sub)
+}
+
+// readPump discards client messages and unsubscribes once the client goes away
+func (h *StreamHub) readPump(conn *websocket.Conn, sub *streamSubscriber) {
+	defer h.unsubscribe(sub)
+
+	conn.SetReadLimit(512)
+	conn.SetReadDeadline(time.Now().Add(streamPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(streamPongWait))
+	})
+
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// writePump writes queued events and keepalive pings until the subscriber is closed
+func (h *StreamHub) writePump(conn *websocket.Conn, sub *streamSubscriber) {
+	ticker := time.NewTicker(streamPingPeriod)
+	defer func() {
+		ticker.Stop()
+		conn.Close()
+	}()
+
+	for {
+		select {
+		case message, ok := <-sub.send:
+			conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			if !ok {
+				conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				h.unsubscribe(sub)
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				h.unsubscribe(sub)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/api"
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+	"github.com/gorilla/websocket"
+)
+
+func dialStream(t *testing.T, server *httptest.Server, rootID string) *websocket.Conn {
+	t.Helper()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/roots/" + rootID + "/stream"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to stream: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestStreamComments_FansOutToSubscribers(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	server := httptest.NewServer(api.NewRouter(commentService))
+	defer server.Close()
+
+	first := dialStream(t, server, "product-1")
+	second := dialStream(t, server, "product-1")
+	other := dialStream(t, server, "product-2")
+
+	// Execute
+	comment, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{
+		RootID:  "product-1",
+		UserID:  "user-123",
+		Content: "Live comment",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Assert
+	for i, conn := range []*websocket.Conn{first, second} {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+		var event models.CommentEvent
+		if err := conn.ReadJSON(&event); err != nil {
+			t.Fatalf("Client %d: expected event, got: %v", i+1, err)
+		}
+		if event.Type != models.EventCommentCreated {
+			t.Fatalf("Client %d: expected %s event, got: %s", i+1, models.EventCommentCreated, event.Type)
+		}
+		if event.CommentID != comment.ID || event.Comment == nil || event.Comment.Content != "Live comment" {
+			t.Fatalf("Client %d: expected created comment %s, got: %+v", i+1, comment.ID, event)
+		}
+	}
+
+	other.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	if _, message, err := other.ReadMessage(); err == nil {
+		var event map[string]interface{}
+		json.Unmarshal(message, &event)
+		t.Fatalf("Expected no events for a different root, got: %v", event)
+	}
+}
+
+func TestStreamHub_DropsSlowSubscribers(t *testing.T) {
+	// Setup
+	hub := api.NewStreamHub()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	commentService.AddEventEmitter(hub)
+
+	router := api.NewRouter(commentService)
+	router.HandleFunc("/hub/{root_id}", hub.ServeRoot)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/hub/product-1"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to stream: %v", err)
+	}
+	defer conn.Close()
+
+	// Execute: emit far more events than the buffer holds without reading any
+	event := &models.CommentEvent{Type: models.EventCommentVoted, RootID: "product-1", CommentID: "c1"}
+	for i := 0; i < 100000 && hub.SubscriberCount("product-1") > 0; i++ {
+		hub.Emit(context.Background(), event)
+	}
+
+	// Assert
+	if count := hub.SubscriberCount("product-1"); count != 0 {
+		t.Fatalf("Expected slow subscriber to be dropped, got: %d subscribers", count)
+	}
+}
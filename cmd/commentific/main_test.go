@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/api"
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/repository"
+	"github.com/christopher18/commentific/v2/service"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+func TestLoadConfig_PoolDefaults(t *testing.T) {
+	// Setup
+	for _, key := range []string{"DB_MAX_OPEN_CONNS", "DB_MAX_IDLE_CONNS", "DB_CONN_MAX_LIFETIME", "DB_CONN_MAX_IDLE_TIME"} {
+		t.Setenv(key, "")
+	}
+
+	// Execute
+	config, err := loadConfig()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.DBMaxOpenConns != 25 || config.DBMaxIdleConns != 25 || config.DBConnMaxLifetime != 5*time.Minute || config.DBConnMaxIdleTime != time.Minute {
+		t.Errorf("Expected 25 open, 25 idle, 5m lifetime and 1m idle time, got: %+v", config)
+	}
+}
+
+func TestLoadConfig_PoolFromEnv(t *testing.T) {
+	// Setup
+	t.Setenv("DB_MAX_OPEN_CONNS", "100")
+	t.Setenv("DB_MAX_IDLE_CONNS", "10")
+	t.Setenv("DB_CONN_MAX_LIFETIME", "30m")
+	t.Setenv("DB_CONN_MAX_IDLE_TIME", "90s")
+
+	// Execute
+	config, err := loadConfig()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.DBMaxOpenConns != 100 || config.DBMaxIdleConns != 10 || config.DBConnMaxLifetime != 30*time.Minute || config.DBConnMaxIdleTime != 90*time.Second {
+		t.Errorf("Expected the environment to override the defaults, got: %+v", config)
+	}
+
+	db, err := sqlx.Open("postgres", config.DatabaseURL)
+	if err != nil {
+		t.Fatalf("Failed to open database handle: %v", err)
+	}
+	defer db.Close()
+	configurePool(db, config)
+	if got := db.Stats().MaxOpenConnections; got != 100 {
+		t.Errorf("Expected the pool to allow 100 open connections, got %d", got)
+	}
+}
+
+func TestLoadConfig_RejectsBadPoolSettings(t *testing.T) {
+	cases := map[string]struct {
+		env     map[string]string
+		wantErr string
+	}{
+		"idle above open":       {env: map[string]string{"DB_MAX_OPEN_CONNS": "5", "DB_MAX_IDLE_CONNS": "10"}, wantErr: "DB_MAX_IDLE_CONNS (10) must not exceed DB_MAX_OPEN_CONNS (5)"},
+		"not a number":          {env: map[string]string{"DB_MAX_OPEN_CONNS": "lots"}, wantErr: "DB_MAX_OPEN_CONNS"},
+		"negative":              {env: map[string]string{"DB_MAX_IDLE_CONNS": "-1"}, wantErr: "DB_MAX_IDLE_CONNS"},
+		"duration without unit": {env: map[string]string{"DB_CONN_MAX_LIFETIME": "300"}, wantErr: "DB_CONN_MAX_LIFETIME"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			// Execute
+			_, err := loadConfig()
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Expected an error mentioning %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_ServerSettings(t *testing.T) {
+	// Setup
+	for _, key := range []string{"HTTP_READ_TIMEOUT", "HTTP_WRITE_TIMEOUT", "HTTP_IDLE_TIMEOUT", "MAX_BODY_BYTES"} {
+		t.Setenv(key, "")
+	}
+
+	// Execute
+	defaults, err := loadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	t.Setenv("HTTP_READ_TIMEOUT", "5s")
+	t.Setenv("HTTP_WRITE_TIMEOUT", "2m")
+	t.Setenv("HTTP_IDLE_TIMEOUT", "0")
+	t.Setenv("MAX_BODY_BYTES", "1048576")
+	configured, err := loadConfig()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if defaults.ReadTimeout != 15*time.Second || defaults.WriteTimeout != 15*time.Second || defaults.IdleTimeout != time.Minute || defaults.MaxBodyBytes != 64<<10 {
+		t.Errorf("Expected 15s read and write, 1m idle and a 64KB body limit, got: %+v", defaults)
+	}
+	if configured.ReadTimeout != 5*time.Second || configured.WriteTimeout != 2*time.Minute || configured.IdleTimeout != 0 || configured.MaxBodyBytes != 1<<20 {
+		t.Errorf("Expected the environment to override the defaults, got: %+v", configured)
+	}
+}
+
+func TestLoadConfig_UnlimitedOpenConnsAllowsAnyIdle(t *testing.T) {
+	// Setup
+	t.Setenv("DB_MAX_OPEN_CONNS", "0")
+	t.Setenv("DB_MAX_IDLE_CONNS", "50")
+
+	// Execute
+	_, err := loadConfig()
+
+	// Assert
+	if err != nil {
+		t.Errorf("Expected no error with no open connection limit, got: %v", err)
+	}
+}
+
+// heldRepository holds CreateComment until release is closed, so a write can
+// be caught in flight
+type heldRepository struct {
+	repository.CommentRepository
+	started chan struct{}
+	release chan struct{}
+}
+
+func (r *heldRepository) CreateComment(ctx context.Context, comment *models.Comment) error {
+	close(r.started)
+	<-r.release
+	return r.CommentRepository.CreateComment(ctx, comment)
+}
+
+func TestShutdown_DrainsInFlightWrites(t *testing.T) {
+	// Setup
+	repo := &heldRepository{CommentRepository: memory.NewMemoryRepository(), started: make(chan struct{}), release: make(chan struct{})}
+	commentService := service.NewCommentService(repo)
+	server := &http.Server{Handler: api.NewRouter(commentService)}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go server.Serve(listener)
+	scheduler := service.NewMaintenanceScheduler(commentService, service.MaintenanceConfig{RecalculateInterval: time.Hour})
+	if err := scheduler.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start maintenance: %v", err)
+	}
+	db, err := sqlx.Open("postgres", "postgres://commentific@127.0.0.1:1/commentific?sslmode=disable")
+	if err != nil {
+		t.Fatalf("Failed to open database handle: %v", err)
+	}
+
+	type result struct {
+		status int
+		err    error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		req, _ := http.NewRequest(http.MethodPost, "http://"+listener.Addr().String()+"/api/v1/comments", strings.NewReader(`{"root_id": "post-1", "content": "Written during shutdown"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User-ID", "alice")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			responses <- result{err: err}
+			return
+		}
+		resp.Body.Close()
+		responses <- result{status: resp.StatusCode}
+	}()
+	<-repo.started
+
+	// Execute
+	stopped := make(chan struct{})
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdown(ctx, server, scheduler, commentService, db)
+		close(stopped)
+	}()
+
+	// Assert
+	select {
+	case <-stopped:
+		t.Fatal("Expected shutdown to wait for the write in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(repo.release)
+	got := <-responses
+	if got.err != nil || got.status != http.StatusCreated {
+		t.Fatalf("Expected the in-flight write to complete with 201, got %d: %v", got.status, got.err)
+	}
+	<-stopped
+	comments, err := commentService.GetCommentsByRoot(context.Background(), "post-1", nil)
+	if err != nil {
+		t.Fatalf("Failed to list comments: %v", err)
+	}
+	if len(comments) != 1 {
+		t.Errorf("Expected the comment to be stored, got %d comments", len(comments))
+	}
+}
@@ -0,0 +1,38 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/christopher18/commentific/v2/service"
+)
+
+type contextKey int
+
+const (
+	userIDKey contextKey = iota
+	voteLoaderKey
+)
+
+// WithUserID returns a context carrying the ID of the user making the request.
+// Mutations act as this user, userVote fields are resolved for them and
+// queries include their own comments awaiting moderation.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	ctx = service.WithViewer(ctx, userID)
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserIDFromContext returns the requesting user's ID, or "" if none was set
+func UserIDFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDKey).(string)
+	return userID
+}
+
+// withVoteLoader attaches a fresh request-scoped vote loader to the context
+func withVoteLoader(ctx context.Context, commentService *service.CommentService) context.Context {
+	return context.WithValue(ctx, voteLoaderKey, newVoteLoader(commentService, UserIDFromContext(ctx)))
+}
+
+func voteLoaderFromContext(ctx context.Context) *voteLoader {
+	loader, _ := ctx.Value(voteLoaderKey).(*voteLoader)
+	return loader
+}
@@ -0,0 +1,60 @@
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/christopher18/commentific/v2/service"
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+// Handler serves GraphQL requests over HTTP
+type Handler struct {
+	schema         *gql.Schema
+	commentService *service.CommentService
+}
+
+// NewHandler creates a GraphQL HTTP handler for the comment service
+func NewHandler(commentService *service.CommentService) *Handler {
+	return &Handler{
+		schema:         MustNewSchema(commentService),
+		commentService: commentService,
+	}
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// ServeHTTP executes a GraphQL POST request. The requesting user is taken from
+// the X-User-ID header or user_id query parameter, matching the REST API.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if UserIDFromContext(ctx) == "" {
+		userID := r.Header.Get("X-User-ID")
+		if userID == "" {
+			userID = r.URL.Query().Get("user_id")
+		}
+		ctx = WithUserID(ctx, userID)
+	}
+	ctx = withVoteLoader(ctx, h.commentService)
+
+	resp := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,215 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/graphql"
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+// countingRepository counts batched vote lookups on top of the memory repository
+type countingRepository struct {
+	*memory.MemoryRepository
+	voteBatches int
+}
+
+func (r *countingRepository) GetUserVotesForComments(ctx context.Context, commentIDs []string, userID string) (map[string]*models.Vote, error) {
+	r.voteBatches++
+	return r.MemoryRepository.GetUserVotesForComments(ctx, commentIDs, userID)
+}
+
+type treeNode struct {
+	Comment struct {
+		ID       string  `json:"id"`
+		Depth    int     `json:"depth"`
+		Score    int     `json:"score"`
+		UserVote *string `json:"userVote"`
+	} `json:"comment"`
+	Children []treeNode `json:"children"`
+}
+
+// seedThread creates root -> reply -> nested reply and has voter upvote the root and reply
+func seedThread(t *testing.T, commentService *service.CommentService) (rootID, replyID, nestedID string) {
+	t.Helper()
+	ctx := context.Background()
+
+	root, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Root"})
+	if err != nil {
+		t.Fatalf("Failed to create root comment: %v", err)
+	}
+	reply, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", ParentID: &root.ID, UserID: "author", Content: "Reply"})
+	if err != nil {
+		t.Fatalf("Failed to create reply: %v", err)
+	}
+	nested, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", ParentID: &reply.ID, UserID: "author", Content: "Nested"})
+	if err != nil {
+		t.Fatalf("Failed to create nested reply: %v", err)
+	}
+
+	for _, id := range []string{root.ID, reply.ID} {
+		if _, _, _, err := commentService.VoteComment(ctx, id, "voter", models.VoteTypeUp); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+	}
+
+	return root.ID, reply.ID, nested.ID
+}
+
+func execute(t *testing.T, handler http.Handler, userID, query string, variables map[string]interface{}) map[string]json.RawMessage {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	if userID != "" {
+		req.Header.Set("X-User-ID", userID)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var resp struct {
+		Data   map[string]json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Expected no errors, got: %v", resp.Errors)
+	}
+	return resp.Data
+}
+
+const treeQuery = `query($rootId: ID!, $depth: Int) {
+	commentTree(rootId: $rootId, depth: $depth) {
+		comment { id depth score userVote }
+		children {
+			comment { id depth score userVote }
+			children { comment { id depth score userVote } children { comment { id } } }
+		}
+	}
+}`
+
+func TestCommentTree_BatchesUserVotes(t *testing.T) {
+	// Setup
+	repo := &countingRepository{MemoryRepository: memory.NewMemoryRepository()}
+	commentService := service.NewCommentService(repo)
+	rootID, replyID, nestedID := seedThread(t, commentService)
+	handler := graphql.NewHandler(commentService)
+
+	// Execute
+	data := execute(t, handler, "voter", treeQuery, map[string]interface{}{"rootId": "product-1"})
+
+	// Assert
+	var tree []treeNode
+	if err := json.Unmarshal(data["commentTree"], &tree); err != nil {
+		t.Fatalf("Failed to decode tree: %v", err)
+	}
+	if len(tree) != 1 || tree[0].Comment.ID != rootID {
+		t.Fatalf("Expected single root %s, got: %+v", rootID, tree)
+	}
+	if tree[0].Comment.Score != 1 || tree[0].Comment.UserVote == nil || *tree[0].Comment.UserVote != "UP" {
+		t.Fatalf("Expected root score 1 with UP vote, got: %+v", tree[0].Comment)
+	}
+
+	reply := tree[0].Children
+	if len(reply) != 1 || reply[0].Comment.ID != replyID || reply[0].Comment.UserVote == nil {
+		t.Fatalf("Expected voted reply %s, got: %+v", replyID, reply)
+	}
+
+	nested := reply[0].Children
+	if len(nested) != 1 || nested[0].Comment.ID != nestedID || nested[0].Comment.UserVote != nil {
+		t.Fatalf("Expected unvoted nested reply %s, got: %+v", nestedID, nested)
+	}
+
+	if repo.voteBatches != 1 {
+		t.Fatalf("Expected vote lookups to be batched into 1 call, got: %d", repo.voteBatches)
+	}
+}
+
+func TestCommentTree_DepthArgument(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	_, replyID, _ := seedThread(t, commentService)
+	handler := graphql.NewHandler(commentService)
+
+	// Execute
+	data := execute(t, handler, "", treeQuery, map[string]interface{}{"rootId": "product-1", "depth": 1})
+
+	// Assert
+	var tree []treeNode
+	if err := json.Unmarshal(data["commentTree"], &tree); err != nil {
+		t.Fatalf("Failed to decode tree: %v", err)
+	}
+	if len(tree) != 1 || len(tree[0].Children) != 1 {
+		t.Fatalf("Expected root with one reply, got: %+v", tree)
+	}
+
+	reply := tree[0].Children[0]
+	if reply.Comment.ID != replyID {
+		t.Fatalf("Expected reply %s, got: %s", replyID, reply.Comment.ID)
+	}
+	if len(reply.Children) != 0 {
+		t.Fatalf("Expected depth 1 to exclude nested replies, got: %d children", len(reply.Children))
+	}
+	if tree[0].Comment.UserVote != nil {
+		t.Fatalf("Expected no user vote for anonymous request, got: %v", *tree[0].Comment.UserVote)
+	}
+}
+
+func TestMutations_CreateVoteDelete(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	handler := graphql.NewHandler(commentService)
+
+	// Execute: create as author
+	data := execute(t, handler, "author", `mutation { createComment(input: {rootId: "product-1", content: "Hello"}) { id userId } }`, nil)
+	var created struct {
+		ID     string `json:"id"`
+		UserID string `json:"userId"`
+	}
+	if err := json.Unmarshal(data["createComment"], &created); err != nil {
+		t.Fatalf("Failed to decode created comment: %v", err)
+	}
+	if created.UserID != "author" {
+		t.Fatalf("Expected comment owned by author, got: %s", created.UserID)
+	}
+
+	// Execute: vote as another user
+	data = execute(t, handler, "voter", `mutation($id: ID!) { vote(commentId: $id, voteType: DOWN) { score userVote } }`, map[string]interface{}{"id": created.ID})
+	var voted struct {
+		Score    int     `json:"score"`
+		UserVote *string `json:"userVote"`
+	}
+	if err := json.Unmarshal(data["vote"], &voted); err != nil {
+		t.Fatalf("Failed to decode vote result: %v", err)
+	}
+	if voted.Score != -1 || voted.UserVote == nil || *voted.UserVote != "DOWN" {
+		t.Fatalf("Expected score -1 with DOWN vote, got: %+v", voted)
+	}
+
+	// Execute: delete as author
+	data = execute(t, handler, "author", `mutation($id: ID!) { deleteComment(id: $id) }`, map[string]interface{}{"id": created.ID})
+	if string(data["deleteComment"]) != "true" {
+		t.Fatalf("Expected deleteComment to return true, got: %s", data["deleteComment"])
+	}
+
+	// Assert
+	data = execute(t, handler, "", `query($id: ID!) { comment(id: $id) { id } }`, map[string]interface{}{"id": created.ID})
+	if string(data["comment"]) != "null" {
+		t.Fatalf("Expected deleted comment to resolve to null, got: %s", data["comment"])
+	}
+}
@@ -0,0 +1,73 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+// voteLoader batches the requesting user's vote lookups for a single GraphQL
+// request. Resolvers that return lists of comments prime the loader with every
+// comment ID, so the first userVote field resolved loads all of them in one
+// repository call instead of one call per comment.
+type voteLoader struct {
+	commentService *service.CommentService
+	userID         string
+
+	mu      sync.Mutex
+	pending map[string]struct{}
+	loaded  map[string]*models.Vote
+}
+
+func newVoteLoader(commentService *service.CommentService, userID string) *voteLoader {
+	return &voteLoader{
+		commentService: commentService,
+		userID:         userID,
+		pending:        make(map[string]struct{}),
+		loaded:         make(map[string]*models.Vote),
+	}
+}
+
+// prime queues comment IDs for the next batch
+func (l *voteLoader) prime(commentIDs ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, id := range commentIDs {
+		if _, done := l.loaded[id]; !done {
+			l.pending[id] = struct{}{}
+		}
+	}
+}
+
+// load returns the user's vote for a comment, or nil if they have not voted.
+// The lock is held across the fetch so concurrent field resolvers wait for the
+// in-flight batch rather than issuing their own.
+func (l *voteLoader) load(ctx context.Context, commentID string) (*models.Vote, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if vote, done := l.loaded[commentID]; done {
+		return vote, nil
+	}
+
+	l.pending[commentID] = struct{}{}
+	ids := make([]string, 0, len(l.pending))
+	for id := range l.pending {
+		ids = append(ids, id)
+	}
+
+	votes, err := l.commentService.GetUserVotesForComments(ctx, ids, l.userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		l.loaded[id] = votes[id]
+	}
+	l.pending = make(map[string]struct{})
+
+	return l.loaded[commentID], nil
+}
@@ -0,0 +1,242 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+// Resolver is the root resolver for queries and mutations
+type Resolver struct {
+	commentService *service.CommentService
+}
+
+// loader returns the request-scoped vote loader, falling back to an unbatched
+// one when the schema is executed without going through Handler
+func (r *Resolver) loader(ctx context.Context) *voteLoader {
+	if loader := voteLoaderFromContext(ctx); loader != nil {
+		return loader
+	}
+	return newVoteLoader(r.commentService, UserIDFromContext(ctx))
+}
+
+// Comment resolves the comment query
+func (r *Resolver) Comment(ctx context.Context, args struct{ ID gql.ID }) (*commentResolver, error) {
+	comment, err := r.commentService.GetComment(ctx, string(args.ID))
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &commentResolver{comment: comment, votes: r.loader(ctx)}, nil
+}
+
+// CommentsByRoot resolves the commentsByRoot query
+func (r *Resolver) CommentsByRoot(ctx context.Context, args struct {
+	RootID    gql.ID
+	Limit     *int32
+	Offset    *int32
+	SortBy    *string
+	SortOrder *string
+}) ([]*commentResolver, error) {
+	filter := &models.CommentFilter{}
+	if args.Limit != nil {
+		limit := int(*args.Limit)
+		filter.Limit = &limit
+	}
+	if args.Offset != nil {
+		offset := int(*args.Offset)
+		filter.Offset = &offset
+	}
+	if args.SortBy != nil {
+		filter.SortBy = *args.SortBy
+	}
+	if args.SortOrder != nil {
+		filter.SortOrder = *args.SortOrder
+	}
+
+	comments, err := r.commentService.GetCommentsByRoot(ctx, string(args.RootID), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	loader := r.loader(ctx)
+	resolvers := make([]*commentResolver, 0, len(comments))
+	for _, comment := range comments {
+		loader.prime(comment.ID)
+		resolvers = append(resolvers, &commentResolver{comment: comment, votes: loader})
+	}
+	return resolvers, nil
+}
+
+// CommentTree resolves the commentTree query
+func (r *Resolver) CommentTree(ctx context.Context, args struct {
+	RootID gql.ID
+	Depth  *int32
+	SortBy *string
+}) ([]*commentTreeResolver, error) {
+	maxDepth, sortBy := 0, "score"
+	if args.Depth != nil {
+		maxDepth = int(*args.Depth)
+	}
+	if args.SortBy != nil {
+		sortBy = *args.SortBy
+	}
+
+	tree, err := r.commentService.GetCommentTree(ctx, string(args.RootID), maxDepth, sortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCommentTreeResolvers(tree, r.loader(ctx)), nil
+}
+
+// CreateComment resolves the createComment mutation
+func (r *Resolver) CreateComment(ctx context.Context, args struct {
+	Input struct {
+		RootID   gql.ID
+		ParentID *gql.ID
+		Content  string
+		MediaURL *string
+		LinkURL  *string
+	}
+}) (*commentResolver, error) {
+	req := &models.CreateCommentRequest{
+		RootID:   string(args.Input.RootID),
+		UserID:   UserIDFromContext(ctx),
+		Content:  args.Input.Content,
+		MediaURL: args.Input.MediaURL,
+		LinkURL:  args.Input.LinkURL,
+	}
+	if args.Input.ParentID != nil {
+		parentID := string(*args.Input.ParentID)
+		req.ParentID = &parentID
+	}
+
+	comment, err := r.commentService.CreateComment(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &commentResolver{comment: comment, votes: r.loader(ctx)}, nil
+}
+
+// Vote resolves the vote mutation and returns the comment with its updated score
+func (r *Resolver) Vote(ctx context.Context, args struct {
+	CommentID gql.ID
+	VoteType  string
+}) (*commentResolver, error) {
+	voteType := models.VoteTypeUp
+	if args.VoteType == "DOWN" {
+		voteType = models.VoteTypeDown
+	}
+
+	commentID := string(args.CommentID)
+	comment, _, _, err := r.commentService.VoteComment(ctx, commentID, UserIDFromContext(ctx), voteType)
+	if err != nil {
+		return nil, err
+	}
+
+	// The vote just cast would be stale in a loader that already fetched it
+	return &commentResolver{comment: comment, votes: newVoteLoader(r.commentService, UserIDFromContext(ctx))}, nil
+}
+
+// DeleteComment resolves the deleteComment mutation
+func (r *Resolver) DeleteComment(ctx context.Context, args struct{ ID gql.ID }) (bool, error) {
+	if err := r.commentService.DeleteComment(ctx, string(args.ID), UserIDFromContext(ctx)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// commentResolver resolves fields of the Comment type
+type commentResolver struct {
+	comment *models.Comment
+	votes   *voteLoader
+}
+
+func (c *commentResolver) ID() gql.ID     { return gql.ID(c.comment.ID) }
+func (c *commentResolver) RootID() gql.ID { return gql.ID(c.comment.RootID) }
+func (c *commentResolver) ParentID() *gql.ID {
+	if c.comment.ParentID == nil {
+		return nil
+	}
+	id := gql.ID(*c.comment.ParentID)
+	return &id
+}
+func (c *commentResolver) UserID() string         { return c.comment.UserID }
+func (c *commentResolver) Content() string        { return c.comment.Content }
+func (c *commentResolver) ContentHTML() *string   { return c.comment.ContentHTML }
+func (c *commentResolver) MediaURL() *string      { return c.comment.MediaURL }
+func (c *commentResolver) LinkURL() *string       { return c.comment.LinkURL }
+func (c *commentResolver) Upvotes() int32         { return int32(c.comment.Upvotes) }
+func (c *commentResolver) Downvotes() int32       { return int32(c.comment.Downvotes) }
+func (c *commentResolver) Score() int32           { return int32(c.comment.Score) }
+func (c *commentResolver) Depth() int32           { return int32(c.comment.Depth) }
+func (c *commentResolver) Path() string           { return c.comment.Path }
+func (c *commentResolver) IsEdited() bool         { return c.comment.IsEdited }
+func (c *commentResolver) EditCount() int32       { return int32(c.comment.EditCount) }
+func (c *commentResolver) ReplyCount() int32      { return int32(c.comment.ReplyCount) }
+func (c *commentResolver) DescendantCount() int32 { return int32(c.comment.DescendantCount) }
+func (c *commentResolver) IsAnonymous() bool      { return c.comment.IsAnonymous }
+func (c *commentResolver) DisplayName() *string   { return c.comment.DisplayName }
+func (c *commentResolver) Status() string         { return string(c.comment.Status) }
+func (c *commentResolver) ContentUpdatedAt() *gql.Time {
+	if c.comment.ContentUpdatedAt == nil {
+		return nil
+	}
+	return &gql.Time{Time: *c.comment.ContentUpdatedAt}
+}
+func (c *commentResolver) CreatedAt() gql.Time { return gql.Time{Time: c.comment.CreatedAt} }
+func (c *commentResolver) UpdatedAt() gql.Time { return gql.Time{Time: c.comment.UpdatedAt} }
+
+// UserVote returns the requesting user's vote, batched through the vote loader
+func (c *commentResolver) UserVote(ctx context.Context) (*string, error) {
+	if c.votes.userID == "" {
+		return nil, nil
+	}
+
+	vote, err := c.votes.load(ctx, c.comment.ID)
+	if err != nil || vote == nil {
+		return nil, err
+	}
+
+	var voteType string
+	switch vote.VoteType {
+	case models.VoteTypeUp:
+		voteType = "UP"
+	case models.VoteTypeDown:
+		voteType = "DOWN"
+	default:
+		return nil, nil
+	}
+	return &voteType, nil
+}
+
+// commentTreeResolver resolves fields of the CommentTreeNode type
+type commentTreeResolver struct {
+	comment  *commentResolver
+	children []*commentTreeResolver
+}
+
+func (t *commentTreeResolver) Comment() *commentResolver        { return t.comment }
+func (t *commentTreeResolver) Children() []*commentTreeResolver { return t.children }
+
+// newCommentTreeResolvers wraps tree nodes recursively, priming the vote loader
+// with every comment in the tree
+func newCommentTreeResolvers(nodes []*models.CommentTree, loader *voteLoader) []*commentTreeResolver {
+	resolvers := make([]*commentTreeResolver, 0, len(nodes))
+	for _, node := range nodes {
+		loader.prime(node.Comment.ID)
+		resolvers = append(resolvers, &commentTreeResolver{
+			comment:  &commentResolver{comment: node.Comment, votes: loader},
+			children: newCommentTreeResolvers(node.Children, loader),
+		})
+	}
+	return resolvers
+}
@@ -0,0 +1,26 @@
+// Package graphql exposes the comment service over GraphQL
+package graphql
+
+import (
+	_ "embed"
+
+	"github.com/christopher18/commentific/v2/service"
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// NewSchema parses the comment schema and binds it to resolvers backed by the comment service
+func NewSchema(commentService *service.CommentService) (*gql.Schema, error) {
+	return gql.ParseSchema(schemaSDL, &Resolver{commentService: commentService})
+}
+
+// MustNewSchema is like NewSchema but panics if the schema cannot be parsed
+func MustNewSchema(commentService *service.CommentService) *gql.Schema {
+	schema, err := NewSchema(commentService)
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
@@ -0,0 +1,232 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/christopher18/commentific/v2/models"
+	commentificv1 "github.com/christopher18/commentific/v2/proto/commentific/v1"
+	"github.com/christopher18/commentific/v2/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements the commentific.v1.CommentService gRPC service by
+// delegating to the CommentService business logic
+type Server struct {
+	commentificv1.UnimplementedCommentServiceServer
+	commentService *service.CommentService
+}
+
+// NewServer creates a new gRPC comment server
+func NewServer(commentService *service.CommentService) *Server {
+	return &Server{
+		commentService: commentService,
+	}
+}
+
+// Register registers the comment service with a gRPC server
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	commentificv1.RegisterCommentServiceServer(registrar, s)
+}
+
+// CreateComment creates a new comment
+func (s *Server) CreateComment(ctx context.Context, req *commentificv1.CreateCommentRequest) (*commentificv1.Comment, error) {
+	comment, err := s.commentService.CreateComment(ctx, &models.CreateCommentRequest{
+		RootID:   req.GetRootId(),
+		ParentID: req.ParentId,
+		UserID:   req.GetUserId(),
+		Content:  req.GetContent(),
+		MediaURL: req.MediaUrl,
+		LinkURL:  req.LinkUrl,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return toProtoComment(comment), nil
+}
+
+// GetComment retrieves a comment by ID
+func (s *Server) GetComment(ctx context.Context, req *commentificv1.GetCommentRequest) (*commentificv1.Comment, error) {
+	comment, err := s.commentService.GetComment(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return toProtoComment(comment), nil
+}
+
+// UpdateComment updates a comment and returns its new state
+func (s *Server) UpdateComment(ctx context.Context, req *commentificv1.UpdateCommentRequest) (*commentificv1.Comment, error) {
+	err := s.commentService.UpdateComment(ctx, req.GetId(), req.GetUserId(), &models.UpdateCommentRequest{
+		Content:  req.Content,
+		MediaURL: req.MediaUrl,
+		LinkURL:  req.LinkUrl,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return s.GetComment(ctx, &commentificv1.GetCommentRequest{Id: req.GetId()})
+}
+
+// DeleteComment soft deletes a comment
+func (s *Server) DeleteComment(ctx context.Context, req *commentificv1.DeleteCommentRequest) (*emptypb.Empty, error) {
+	if err := s.commentService.DeleteComment(ctx, req.GetId(), req.GetUserId()); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ListCommentsByRoot retrieves a page of comments for a root
+func (s *Server) ListCommentsByRoot(ctx context.Context, req *commentificv1.ListCommentsByRootRequest) (*commentificv1.ListCommentsResponse, error) {
+	filter := &models.CommentFilter{
+		SortBy:    req.GetSortBy(),
+		SortOrder: req.GetSortOrder(),
+	}
+	if req.Limit != nil {
+		limit := int(req.GetLimit())
+		filter.Limit = &limit
+	}
+	if req.Offset != nil {
+		offset := int(req.GetOffset())
+		filter.Offset = &offset
+	}
+	if req.MaxDepth != nil {
+		maxDepth := int(req.GetMaxDepth())
+		filter.MaxDepth = &maxDepth
+	}
+
+	comments, err := s.commentService.GetCommentsByRoot(ctx, req.GetRootId(), filter)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	resp := &commentificv1.ListCommentsResponse{
+		Comments: make([]*commentificv1.Comment, 0, len(comments)),
+	}
+	for _, comment := range comments {
+		resp.Comments = append(resp.Comments, toProtoComment(comment))
+	}
+
+	return resp, nil
+}
+
+// GetCommentTree retrieves the hierarchical comment tree for a root
+func (s *Server) GetCommentTree(ctx context.Context, req *commentificv1.GetCommentTreeRequest) (*commentificv1.GetCommentTreeResponse, error) {
+	tree, err := s.commentService.GetCommentTree(ctx, req.GetRootId(), int(req.GetMaxDepth()), req.GetSortBy())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &commentificv1.GetCommentTreeResponse{Roots: toProtoTrees(tree)}, nil
+}
+
+// VoteComment records a vote and returns the comment with its updated score
+func (s *Server) VoteComment(ctx context.Context, req *commentificv1.VoteCommentRequest) (*commentificv1.Comment, error) {
+	var voteType models.VoteType
+	switch req.GetVoteType() {
+	case commentificv1.VoteType_VOTE_TYPE_UP:
+		voteType = models.VoteTypeUp
+	case commentificv1.VoteType_VOTE_TYPE_DOWN:
+		voteType = models.VoteTypeDown
+	default:
+		return nil, status.Error(codes.InvalidArgument, "vote_type must be VOTE_TYPE_UP or VOTE_TYPE_DOWN")
+	}
+
+	comment, _, _, err := s.commentService.VoteComment(ctx, req.GetCommentId(), req.GetUserId(), voteType)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return toProtoComment(comment), nil
+}
+
+// GetCommentStats retrieves statistics for a root
+func (s *Server) GetCommentStats(ctx context.Context, req *commentificv1.GetCommentStatsRequest) (*commentificv1.CommentStats, error) {
+	stats, err := s.commentService.GetCommentStats(ctx, req.GetRootId())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &commentificv1.CommentStats{
+		RootId:             stats.RootID,
+		TotalCount:         stats.TotalCount,
+		TotalScore:         stats.TotalScore,
+		MaxDepth:           int32(stats.MaxDepth),
+		RecentCount:        stats.RecentCount,
+		EditedCount:        stats.EditedCount,
+		TotalEdits:         stats.TotalEdits,
+		EditRate:           stats.EditRate,
+		AvgEditsPerComment: stats.AvgEditsPerComment,
+	}, nil
+}
+
+// toStatusError maps typed service errors onto gRPC status codes
+func toStatusError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrNotAuthorized), errors.Is(err, service.ErrSelfVote), errors.Is(err, service.ErrRootLocked):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, service.ErrHasReplies):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrVersionConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, service.ErrInvalidInput):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// toProtoComment converts a comment model to its protobuf representation
+func toProtoComment(comment *models.Comment) *commentificv1.Comment {
+	pb := &commentificv1.Comment{
+		Id:              comment.ID,
+		RootId:          comment.RootID,
+		ParentId:        comment.ParentID,
+		UserId:          comment.UserID,
+		Content:         comment.Content,
+		MediaUrl:        comment.MediaURL,
+		LinkUrl:         comment.LinkURL,
+		Upvotes:         comment.Upvotes,
+		Downvotes:       comment.Downvotes,
+		Score:           comment.Score,
+		Depth:           int32(comment.Depth),
+		Path:            comment.Path,
+		IsDeleted:       comment.IsDeleted,
+		IsEdited:        comment.IsEdited,
+		EditCount:       int32(comment.EditCount),
+		OriginalContent: comment.OriginalContent,
+		CreatedAt:       timestamppb.New(comment.CreatedAt),
+		UpdatedAt:       timestamppb.New(comment.UpdatedAt),
+		ReplyCount:      int32(comment.ReplyCount),
+		DescendantCount: int32(comment.DescendantCount),
+	}
+	if comment.ContentUpdatedAt != nil {
+		pb.ContentUpdatedAt = timestamppb.New(*comment.ContentUpdatedAt)
+	}
+	return pb
+}
+
+// toProtoTrees converts comment tree nodes recursively
+func toProtoTrees(nodes []*models.CommentTree) []*commentificv1.CommentTree {
+	trees := make([]*commentificv1.CommentTree, 0, len(nodes))
+	for _, node := range nodes {
+		trees = append(trees, &commentificv1.CommentTree{
+			Comment:  toProtoComment(node.Comment),
+			Children: toProtoTrees(node.Children),
+		})
+	}
+	return trees
+}
@@ -0,0 +1,150 @@
+package grpcapi_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/grpcapi"
+	"github.com/christopher18/commentific/v2/memory"
+	commentificv1 "github.com/christopher18/commentific/v2/proto/commentific/v1"
+	"github.com/christopher18/commentific/v2/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient starts an in-process gRPC server backed by the memory repository
+func newTestClient(t *testing.T) commentificv1.CommentServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	grpcapi.NewServer(service.NewCommentService(memory.NewMemoryRepository())).Register(server)
+
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial bufconn: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return commentificv1.NewCommentServiceClient(conn)
+}
+
+func TestServer_CreateAndGetComment(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateComment(ctx, &commentificv1.CreateCommentRequest{
+		RootId:  "product-1",
+		UserId:  "user-123",
+		Content: "Hello over gRPC",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if created.GetId() == "" {
+		t.Fatal("Expected comment ID to be generated")
+	}
+
+	fetched, err := client.GetComment(ctx, &commentificv1.GetCommentRequest{Id: created.GetId()})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if fetched.GetContent() != "Hello over gRPC" {
+		t.Errorf("Expected content to round-trip, got %q", fetched.GetContent())
+	}
+	if fetched.GetRootId() != "product-1" {
+		t.Errorf("Expected root_id product-1, got %s", fetched.GetRootId())
+	}
+	if fetched.GetCreatedAt() == nil {
+		t.Error("Expected created_at to be set")
+	}
+}
+
+func TestServer_ErrorCodes(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	// Missing comment maps to NotFound
+	_, err := client.GetComment(ctx, &commentificv1.GetCommentRequest{Id: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+
+	// Empty content maps to InvalidArgument
+	_, err = client.CreateComment(ctx, &commentificv1.CreateCommentRequest{
+		RootId: "product-1",
+		UserId: "user-123",
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+
+	// Self-vote maps to PermissionDenied
+	created, err := client.CreateComment(ctx, &commentificv1.CreateCommentRequest{
+		RootId:  "product-1",
+		UserId:  "user-123",
+		Content: "My own comment",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	_, err = client.VoteComment(ctx, &commentificv1.VoteCommentRequest{
+		CommentId: created.GetId(),
+		UserId:    "user-123",
+		VoteType:  commentificv1.VoteType_VOTE_TYPE_UP,
+	})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied, got %v", err)
+	}
+
+	// Editing someone else's comment maps to PermissionDenied
+	content := "Hijacked"
+	_, err = client.UpdateComment(ctx, &commentificv1.UpdateCommentRequest{
+		Id:      created.GetId(),
+		UserId:  "user-456",
+		Content: &content,
+	})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied, got %v", err)
+	}
+}
+
+func TestServer_VoteReturnsUpdatedScore(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateComment(ctx, &commentificv1.CreateCommentRequest{
+		RootId:  "product-1",
+		UserId:  "user-123",
+		Content: "Vote on me",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	voted, err := client.VoteComment(ctx, &commentificv1.VoteCommentRequest{
+		CommentId: created.GetId(),
+		UserId:    "user-456",
+		VoteType:  commentificv1.VoteType_VOTE_TYPE_UP,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if voted.GetScore() != 1 {
+		t.Errorf("Expected score 1, got %d", voted.GetScore())
+	}
+}
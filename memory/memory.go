@@ -0,0 +1,1759 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/ranking"
+	"github.com/christopher18/commentific/v2/repository"
+	"github.com/google/uuid"
+)
+
+// MemoryRepository implements the CommentRepository interface using in-process maps.
+// It mirrors the behavior of the PostgreSQL implementation (including the score and
+// edit tracking triggers) and is intended for tests, prototyping and embedding
+// Commentific without a database. Transactions are accepted but not isolated.
+type MemoryRepository struct {
+	store *store
+}
+
+// Ensure MemoryRepository satisfies the repository interface
+var _ repository.CommentRepository = (*MemoryRepository)(nil)
+
+// store holds the shared state so transaction handles see the same data
+type store struct {
+	mu       sync.RWMutex
+	comments map[string]*models.Comment
+	order    []string                // comment IDs in insertion order
+	votes    map[string]*models.Vote // keyed by commentID + ":" + userID
+	settings map[string]*models.RootSettings
+	blocks   map[string]map[string]bool // blocker ID -> blocked user IDs
+	subs     map[string]map[string]bool // root ID -> subscribed user IDs
+}
+
+// ordered returns the stored comments in insertion order so ties sort deterministically
+func (s *store) ordered() []*models.Comment {
+	comments := make([]*models.Comment, 0, len(s.order))
+	for _, id := range s.order {
+		if comment, exists := s.comments[id]; exists {
+			comments = append(comments, comment)
+		}
+	}
+	return comments
+}
+
+// NewMemoryRepository creates a new, empty in-memory comment repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		store: &store{
+			comments: make(map[string]*models.Comment),
+			votes:    make(map[string]*models.Vote),
+			settings: make(map[string]*models.RootSettings),
+			blocks:   make(map[string]map[string]bool),
+			subs:     make(map[string]map[string]bool),
+		},
+	}
+}
+
+// voteKey builds the map key for a user's vote on a comment
+func voteKey(commentID, userID string) string {
+	return commentID + ":" + userID
+}
+
+// copyComment returns a shallow copy so callers can't mutate stored state
+func copyComment(comment *models.Comment) *models.Comment {
+	c := *comment
+	return &c
+}
+
+// CreateComment creates a new comment
+func (r *MemoryRepository) CreateComment(ctx context.Context, comment *models.Comment) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Generate ID if not provided
+	if comment.ID == "" {
+		comment.ID = uuid.New().String()
+	}
+	if err := repository.ValidateCommentID(comment.ID); err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+	if _, exists := r.store.comments[comment.ID]; exists {
+		return fmt.Errorf("failed to create comment: duplicate id %s", comment.ID)
+	}
+
+	// Calculate path and depth
+	if comment.ParentID != nil {
+		parent, exists := r.store.comments[*comment.ParentID]
+		if !exists || parent.IsDeleted {
+			return fmt.Errorf("failed to get parent comment: %w", repository.ErrNotFound)
+		}
+		if parent.RootID != comment.RootID {
+			return fmt.Errorf("parent comment belongs to different root")
+		}
+		comment.Depth = parent.Depth + 1
+		comment.Path = parent.Path + "." + comment.ID
+	} else {
+		comment.Depth = 0
+		comment.Path = comment.ID
+	}
+
+	comment.CreatedAt = time.Now()
+	comment.UpdatedAt = comment.CreatedAt
+	comment.ReplyCount = 0
+	comment.DescendantCount = 0
+	comment.Version = 1
+	if comment.Status == "" {
+		comment.Status = models.CommentStatusApproved
+	}
+
+	r.store.comments[comment.ID] = copyComment(comment)
+	r.store.order = append(r.store.order, comment.ID)
+	if counted(comment) {
+		r.adjustReplyCountsLocked(comment, 1)
+	}
+	return nil
+}
+
+// GetCommentByID retrieves a comment by its ID
+func (r *MemoryRepository) GetCommentByID(ctx context.Context, id string) (*models.Comment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	comment, exists := r.store.comments[id]
+	if !exists || comment.IsDeleted {
+		return nil, repository.ErrNotFound
+	}
+	return copyComment(comment), nil
+}
+
+// GetCommentsByIDs retrieves many comments in the order of ids, skipping missing ones
+func (r *MemoryRepository) GetCommentsByIDs(ctx context.Context, ids []string, includeDeleted bool) ([]*models.Comment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	comments := make([]*models.Comment, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		comment, exists := r.store.comments[id]
+		if !exists || seen[id] || (comment.IsDeleted && !includeDeleted) {
+			continue
+		}
+		seen[id] = true
+		comments = append(comments, copyComment(comment))
+	}
+	return comments, nil
+}
+
+// UpdateComment updates a comment's content, emulating the edit tracking trigger
+func (r *MemoryRepository) UpdateComment(ctx context.Context, id string, updates *models.UpdateCommentRequest) error {
+	if updates.Content == nil && updates.MediaURL == nil && updates.LinkURL == nil {
+		return nil // Nothing to update
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	comment, exists := r.store.comments[id]
+	if !exists || comment.IsDeleted {
+		return fmt.Errorf("%w or already deleted", repository.ErrNotFound)
+	}
+	if updates.Version != nil && *updates.Version != comment.Version {
+		return repository.ErrVersionConflict
+	}
+
+	old := *comment
+	if updates.Content != nil {
+		comment.Content = *updates.Content
+	}
+	if updates.MediaURL != nil {
+		mediaURL := *updates.MediaURL
+		comment.MediaURL = &mediaURL
+	}
+	if updates.LinkURL != nil {
+		linkURL := *updates.LinkURL
+		comment.LinkURL = &linkURL
+		if !equalStringPtr(old.LinkURL, comment.LinkURL) {
+			comment.LinkPreview = nil // The preview belonged to the old link
+		}
+	}
+
+	// Only content changes are edits; media and link changes just bump UpdatedAt
+	now := time.Now()
+	if old.Content != comment.Content {
+		if !old.IsEdited {
+			original := old.Content
+			comment.OriginalContent = &original
+		}
+		comment.IsEdited = true
+		comment.ContentUpdatedAt = &now
+		comment.EditCount = old.EditCount + 1
+	}
+	comment.UpdatedAt = now
+	comment.Version++
+
+	return nil
+}
+
+// DeleteComment soft deletes a comment on behalf of its author
+func (r *MemoryRepository) DeleteComment(ctx context.Context, id string, userID string, reason *string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	comment, exists := r.store.comments[id]
+	if !exists || comment.IsDeleted || comment.UserID != userID {
+		return fmt.Errorf("%w, already deleted, or user not authorized", repository.ErrNotFound)
+	}
+
+	r.softDeleteLocked(comment, userID, reason)
+	return nil
+}
+
+// RemoveComment soft deletes a comment whoever wrote it, recording the
+// moderator who removed it
+func (r *MemoryRepository) RemoveComment(ctx context.Context, id string, moderatorID string, reason *string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	comment, exists := r.store.comments[id]
+	if !exists || comment.IsDeleted {
+		return fmt.Errorf("%w or already deleted", repository.ErrNotFound)
+	}
+
+	r.softDeleteLocked(comment, moderatorID, reason)
+	return nil
+}
+
+// softDeleteLocked marks comment deleted by deletedBy. The caller must hold
+// the write lock.
+func (r *MemoryRepository) softDeleteLocked(comment *models.Comment, deletedBy string, reason *string) {
+	wasCounted := counted(comment)
+	comment.IsDeleted = true
+	comment.UpdatedAt = time.Now()
+	comment.DeletedBy = &deletedBy
+	comment.DeleteReason = reason
+	if wasCounted {
+		r.adjustReplyCountsLocked(comment, -1)
+	}
+}
+
+// HardDeleteComment permanently removes a comment and its votes, refusing
+// comments that still have replies with ErrHasReplies
+func (r *MemoryRepository) HardDeleteComment(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	comment, exists := r.store.comments[id]
+	if !exists {
+		return repository.ErrNotFound
+	}
+	for _, other := range r.store.comments {
+		if other.ParentID != nil && *other.ParentID == id {
+			return repository.ErrHasReplies
+		}
+	}
+
+	if counted(comment) {
+		r.adjustReplyCountsLocked(comment, -1)
+	}
+	delete(r.store.comments, id)
+	for i, orderedID := range r.store.order {
+		if orderedID == id {
+			r.store.order = append(r.store.order[:i], r.store.order[i+1:]...)
+			break
+		}
+	}
+
+	// Emulate ON DELETE CASCADE on votes.comment_id
+	for key, vote := range r.store.votes {
+		if vote.CommentID == id {
+			delete(r.store.votes, key)
+		}
+	}
+	return nil
+}
+
+// GetComments retrieves comments based on filter
+func (r *MemoryRepository) GetComments(ctx context.Context, filter *models.CommentFilter) ([]*models.Comment, error) {
+	if filter == nil {
+		filter = &models.CommentFilter{}
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	blocked := r.store.blockedBy(filter.ViewerID)
+	comments := []*models.Comment{}
+	for _, comment := range r.store.ordered() {
+		if comment.IsDeleted || blocked[comment.UserID] || !matchesFilter(comment, filter) {
+			continue
+		}
+		comments = append(comments, copyComment(comment))
+	}
+
+	sortComments(comments, filter.SortBy, filter.SortOrder)
+	return paginate(comments, filter.Limit, filter.Offset), nil
+}
+
+// GetCommentsByRootID retrieves comments for a specific root
+func (r *MemoryRepository) GetCommentsByRootID(ctx context.Context, rootID string, filter *models.CommentFilter) ([]*models.Comment, error) {
+	if filter == nil {
+		filter = &models.CommentFilter{}
+	}
+	filter.RootID = &rootID
+	return r.GetComments(ctx, filter)
+}
+
+// GetCommentsSince retrieves up to limit of the root's approved comments,
+// soft-deleted ones included, updated after since in (updated_at, id) order.
+// With afterID set, comments updated at since itself with a greater ID come
+// first, so a page cut in the middle of a tie resumes where it stopped.
+func (r *MemoryRepository) GetCommentsSince(ctx context.Context, rootID string, since time.Time, afterID string, limit int) ([]*models.Comment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	comments := []*models.Comment{}
+	for _, comment := range r.store.ordered() {
+		if comment.RootID != rootID || !visibleTo(comment, nil) {
+			continue
+		}
+		if !comment.UpdatedAt.After(since) && !(afterID != "" && comment.UpdatedAt.Equal(since) && comment.ID > afterID) {
+			continue
+		}
+		comments = append(comments, copyComment(comment))
+	}
+	sort.SliceStable(comments, func(i, j int) bool {
+		if !comments[i].UpdatedAt.Equal(comments[j].UpdatedAt) {
+			return comments[i].UpdatedAt.Before(comments[j].UpdatedAt)
+		}
+		return comments[i].ID < comments[j].ID
+	})
+	if limit > 0 && len(comments) > limit {
+		comments = comments[:limit]
+	}
+	return comments, nil
+}
+
+// GetCommentsByUserID retrieves comments by a specific user
+func (r *MemoryRepository) GetCommentsByUserID(ctx context.Context, userID string, filter *models.CommentFilter) ([]*models.Comment, error) {
+	if filter == nil {
+		filter = &models.CommentFilter{}
+	}
+	filter.UserID = &userID
+	return r.GetComments(ctx, filter)
+}
+
+// GetDirectChildren retrieves the immediate replies to a comment, without
+// descending further into the subtree
+func (r *MemoryRepository) GetDirectChildren(ctx context.Context, parentID string, filter *models.CommentFilter) ([]*models.Comment, error) {
+	if filter == nil {
+		filter = &models.CommentFilter{}
+	}
+	filter.ParentID = &parentID
+	return r.GetComments(ctx, filter)
+}
+
+// GetCommentChildren retrieves child comments up to maxDepth in path order,
+// paginated by filter.Limit and filter.Offset
+func (r *MemoryRepository) GetCommentChildren(ctx context.Context, parentID string, maxDepth int, filter *models.CommentFilter) ([]*models.Comment, error) {
+	parent, err := r.GetCommentByID(ctx, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent comment: %w", err)
+	}
+
+	prefix := parent.Path + "."
+	maxAllowedDepth := parent.Depth + maxDepth
+	var viewerID *string
+	if filter != nil {
+		viewerID = filter.ViewerID
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	blocked := r.store.blockedBy(viewerID)
+	comments := []*models.Comment{}
+	for _, comment := range r.store.ordered() {
+		if comment.IsDeleted || comment.Depth > maxAllowedDepth || !strings.HasPrefix(comment.Path, prefix) {
+			continue
+		}
+		if blocked[comment.UserID] {
+			continue
+		}
+		if !visibleTo(comment, viewerID) {
+			continue
+		}
+		comments = append(comments, copyComment(comment))
+	}
+
+	sort.SliceStable(comments, func(i, j int) bool {
+		if comments[i].Path != comments[j].Path {
+			return comments[i].Path < comments[j].Path
+		}
+		return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+	})
+
+	if filter == nil {
+		return comments, nil
+	}
+	return paginate(comments, filter.Limit, filter.Offset), nil
+}
+
+// ExportComments pages through every comment in a root in byte-wise path
+// order, so a parent always precedes its replies
+func (r *MemoryRepository) ExportComments(ctx context.Context, rootID, afterPath string, limit int, includeDeleted bool) ([]*models.Comment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	comments := []*models.Comment{}
+	for _, comment := range r.store.comments {
+		if comment.RootID != rootID || comment.Path <= afterPath || (comment.IsDeleted && !includeDeleted) {
+			continue
+		}
+		comments = append(comments, copyComment(comment))
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		return comments[i].Path < comments[j].Path
+	})
+
+	if limit > 0 && len(comments) > limit {
+		comments = comments[:limit]
+	}
+	return comments, nil
+}
+
+// GetCommentTree builds a hierarchical tree structure
+func (r *MemoryRepository) GetCommentTree(ctx context.Context, rootID string, maxDepth int, sortBy string) ([]*models.CommentTree, error) {
+	filter := &models.CommentFilter{
+		RootID:   &rootID,
+		MaxDepth: &maxDepth,
+		SortBy:   sortBy,
+	}
+
+	comments, err := r.GetComments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCommentTree(comments), nil
+}
+
+// GetCommentSubtree builds the tree under one comment, up to maxDepth levels
+// below it
+func (r *MemoryRepository) GetCommentSubtree(ctx context.Context, commentID string, maxDepth int, sortBy string) (*models.CommentTree, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	root, exists := r.store.comments[commentID]
+	if !exists || root.IsDeleted || !visibleTo(root, nil) {
+		return nil, repository.ErrNotFound
+	}
+
+	prefix := root.Path + "."
+	maxAllowedDepth := root.Depth + maxDepth
+	comments := []*models.Comment{copyComment(root)}
+	for _, comment := range r.store.ordered() {
+		if comment.IsDeleted || comment.Depth > maxAllowedDepth || !strings.HasPrefix(comment.Path, prefix) {
+			continue
+		}
+		if !visibleTo(comment, nil) {
+			continue
+		}
+		comments = append(comments, copyComment(comment))
+	}
+	sortComments(comments, sortBy, "")
+
+	nodes, _ := linkCommentTree(comments)
+	return nodes[commentID], nil
+}
+
+// GetCommentPath retrieves the path from root to a specific comment, falling
+// back to parent links when the comment's path doesn't match its depth
+func (r *MemoryRepository) GetCommentPath(ctx context.Context, commentID string) ([]*models.Comment, error) {
+	comment, err := r.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ids := strings.Split(comment.Path, ".")
+	if len(ids) != comment.Depth+1 || ids[len(ids)-1] != comment.ID {
+		return append(r.ancestorsLocked(comment), comment), nil
+	}
+
+	comments := []*models.Comment{}
+	for _, id := range ids {
+		if ancestor, exists := r.store.comments[id]; exists && !ancestor.IsDeleted {
+			comments = append(comments, copyComment(ancestor))
+		}
+	}
+
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].Depth < comments[j].Depth
+	})
+
+	return comments, nil
+}
+
+// GetCommentAncestors returns the comments above commentID, top-level first,
+// following parent links instead of the path. Deleted ancestors are left out.
+func (r *MemoryRepository) GetCommentAncestors(ctx context.Context, commentID string) ([]*models.Comment, error) {
+	comment, err := r.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.ancestorsLocked(comment), nil
+}
+
+// ancestorsLocked walks up from comment's parent, stopping after
+// comment.Depth steps so a cycle can't loop forever. Callers must hold
+// r.store.mu.
+func (r *MemoryRepository) ancestorsLocked(comment *models.Comment) []*models.Comment {
+	ancestors := []*models.Comment{}
+	parentID := comment.ParentID
+	for hops := 0; parentID != nil && hops < comment.Depth; hops++ {
+		parent, exists := r.store.comments[*parentID]
+		if !exists {
+			break
+		}
+		if !parent.IsDeleted {
+			ancestors = append(ancestors, copyComment(parent))
+		}
+		parentID = parent.ParentID
+	}
+	slices.Reverse(ancestors)
+	return ancestors
+}
+
+// MoveComment re-parents a comment under newParentID in the same root,
+// rewriting path and depth for the whole subtree and moving reply counts from
+// the old ancestors to the new ones
+func (r *MemoryRepository) MoveComment(ctx context.Context, commentID, newParentID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	comment, exists := r.store.comments[commentID]
+	if !exists || comment.IsDeleted {
+		return fmt.Errorf("failed to get comment: %w", repository.ErrNotFound)
+	}
+	parent, exists := r.store.comments[newParentID]
+	if !exists || parent.IsDeleted {
+		return fmt.Errorf("failed to get new parent comment: %w", repository.ErrNotFound)
+	}
+	if parent.RootID != comment.RootID {
+		return fmt.Errorf("new parent comment belongs to different root")
+	}
+	if parent.ID == comment.ID || strings.HasPrefix(parent.Path, comment.Path+".") {
+		return fmt.Errorf("cannot move a comment under itself or its descendants")
+	}
+
+	// The comment plus its live descendants leave the old ancestors
+	moved := 1 + comment.DescendantCount
+	if comment.ParentID != nil {
+		if oldParent, exists := r.store.comments[*comment.ParentID]; exists {
+			oldParent.ReplyCount--
+		}
+		for _, ancestorID := range strings.Split(comment.Path, ".") {
+			if ancestor, exists := r.store.comments[ancestorID]; exists && ancestorID != comment.ID {
+				ancestor.DescendantCount -= moved
+			}
+		}
+	}
+
+	oldPath := comment.Path
+	newPath := parent.Path + "." + comment.ID
+	depthDelta := parent.Depth + 1 - comment.Depth
+	for _, c := range r.store.comments {
+		if c.Path == oldPath || strings.HasPrefix(c.Path, oldPath+".") {
+			c.Path = newPath + strings.TrimPrefix(c.Path, oldPath)
+			c.Depth += depthDelta
+		}
+	}
+
+	parentID := parent.ID
+	comment.ParentID = &parentID
+	comment.UpdatedAt = time.Now()
+
+	parent.ReplyCount++
+	for _, ancestorID := range strings.Split(parent.Path, ".") {
+		if ancestor, exists := r.store.comments[ancestorID]; exists {
+			ancestor.DescendantCount += moved
+		}
+	}
+
+	return nil
+}
+
+// CreateVote creates or replaces a user's vote on a comment
+func (r *MemoryRepository) CreateVote(ctx context.Context, vote *models.Vote) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.comments[vote.CommentID]; !exists {
+		return fmt.Errorf("failed to create vote: %w", repository.ErrNotFound)
+	}
+
+	now := time.Now()
+	if vote.Weight < 1 {
+		vote.Weight = 1
+	}
+	key := voteKey(vote.CommentID, vote.UserID)
+	if existing, exists := r.store.votes[key]; exists {
+		existing.VoteType = vote.VoteType
+		existing.Weight = vote.Weight
+		existing.UpdatedAt = now
+		*vote = *existing
+	} else {
+		if vote.ID == "" {
+			vote.ID = uuid.New().String()
+		}
+		vote.CreatedAt = now
+		vote.UpdatedAt = now
+		stored := *vote
+		r.store.votes[key] = &stored
+	}
+
+	r.recalculateLocked(vote.CommentID)
+	return nil
+}
+
+// UpdateVote updates or creates a vote. Only the vote type changes: an
+// existing vote keeps its weight, and a new one weighs 1.
+func (r *MemoryRepository) UpdateVote(ctx context.Context, commentID, userID string, voteType models.VoteType) error {
+	r.store.mu.Lock()
+	existing, exists := r.store.votes[voteKey(commentID, userID)]
+	if exists {
+		existing.VoteType = voteType
+		existing.UpdatedAt = time.Now()
+		r.recalculateLocked(commentID)
+	}
+	r.store.mu.Unlock()
+	if exists {
+		return nil
+	}
+	return r.CreateVote(ctx, &models.Vote{CommentID: commentID, UserID: userID, VoteType: voteType})
+}
+
+// DeleteVote removes a user's vote
+func (r *MemoryRepository) DeleteVote(ctx context.Context, commentID, userID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.votes, voteKey(commentID, userID))
+	r.recalculateLocked(commentID)
+	return nil
+}
+
+// DeleteVotes removes a user's votes on many comments
+func (r *MemoryRepository) DeleteVotes(ctx context.Context, commentIDs []string, userID string) ([]string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	removed := []string{}
+	for _, commentID := range commentIDs {
+		key := voteKey(commentID, userID)
+		if _, exists := r.store.votes[key]; !exists {
+			continue
+		}
+		delete(r.store.votes, key)
+		r.recalculateLocked(commentID)
+		removed = append(removed, commentID)
+	}
+	return removed, nil
+}
+
+// GetUserVote retrieves a user's vote for a comment
+func (r *MemoryRepository) GetUserVote(ctx context.Context, commentID, userID string) (*models.Vote, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	vote, exists := r.store.votes[voteKey(commentID, userID)]
+	if !exists {
+		return nil, nil // No vote found
+	}
+	v := *vote
+	return &v, nil
+}
+
+// GetCommentVotes retrieves all votes for a comment
+func (r *MemoryRepository) GetCommentVotes(ctx context.Context, commentID string) ([]*models.Vote, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	votes := []*models.Vote{}
+	for _, vote := range r.store.votes {
+		if vote.CommentID == commentID {
+			v := *vote
+			votes = append(votes, &v)
+		}
+	}
+	return votes, nil
+}
+
+// GetVotesForComments retrieves every vote on the given comments, oldest first
+func (r *MemoryRepository) GetVotesForComments(ctx context.Context, commentIDs []string) (map[string][]*models.Vote, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(commentIDs))
+	for _, id := range commentIDs {
+		wanted[id] = true
+	}
+
+	votes := make(map[string][]*models.Vote)
+	for _, vote := range r.store.votes {
+		if wanted[vote.CommentID] {
+			v := *vote
+			votes[vote.CommentID] = append(votes[vote.CommentID], &v)
+		}
+	}
+	for _, list := range votes {
+		sort.Slice(list, func(i, j int) bool {
+			if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+				return list[i].CreatedAt.Before(list[j].CreatedAt)
+			}
+			return list[i].ID < list[j].ID
+		})
+	}
+	return votes, nil
+}
+
+// GetUserVotes retrieves the comments a user has voted on with their vote,
+// most recently voted first
+func (r *MemoryRepository) GetUserVotes(ctx context.Context, userID string, filter *models.VoteFilter) ([]*models.VotedComment, error) {
+	if filter == nil {
+		filter = &models.VoteFilter{}
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var votes []*models.Vote
+	for _, vote := range r.store.votes {
+		if vote.UserID != userID || (filter.VoteType != nil && vote.VoteType != *filter.VoteType) {
+			continue
+		}
+		comment, ok := r.store.comments[vote.CommentID]
+		if !ok || (comment.IsDeleted && !filter.IncludeDeleted) || (filter.RootID != nil && comment.RootID != *filter.RootID) {
+			continue
+		}
+		votes = append(votes, vote)
+	}
+	sort.Slice(votes, func(i, j int) bool {
+		if !votes[i].UpdatedAt.Equal(votes[j].UpdatedAt) {
+			return votes[i].UpdatedAt.After(votes[j].UpdatedAt)
+		}
+		return votes[i].ID < votes[j].ID
+	})
+
+	if filter.Offset != nil && *filter.Offset > 0 {
+		votes = votes[min(*filter.Offset, len(votes)):]
+	}
+	if filter.Limit != nil && *filter.Limit >= 0 && *filter.Limit < len(votes) {
+		votes = votes[:*filter.Limit]
+	}
+
+	voted := make([]*models.VotedComment, 0, len(votes))
+	for _, vote := range votes {
+		voted = append(voted, &models.VotedComment{
+			Comment:  copyComment(r.store.comments[vote.CommentID]),
+			VoteType: vote.VoteType,
+			VotedAt:  vote.UpdatedAt,
+		})
+	}
+	return voted, nil
+}
+
+// GetRepliesToUser retrieves other users' replies to userID's comments, newest
+// first, each with the comment it answers
+func (r *MemoryRepository) GetRepliesToUser(ctx context.Context, userID string, filter *models.CommentFilter) ([]*models.Reply, error) {
+	if filter == nil {
+		filter = &models.CommentFilter{}
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	blocked := r.store.blocks[userID]
+	replies := []*models.Comment{}
+	for _, comment := range r.store.ordered() {
+		if comment.ParentID == nil || comment.UserID == userID || blocked[comment.UserID] || !counted(comment) {
+			continue
+		}
+		if filter.RootID != nil && comment.RootID != *filter.RootID {
+			continue
+		}
+		parent, ok := r.store.comments[*comment.ParentID]
+		if !ok || parent.UserID != userID || parent.IsDeleted {
+			continue
+		}
+		replies = append(replies, comment)
+	}
+	sort.SliceStable(replies, func(i, j int) bool {
+		if !replies[i].CreatedAt.Equal(replies[j].CreatedAt) {
+			return replies[i].CreatedAt.After(replies[j].CreatedAt)
+		}
+		return replies[i].ID < replies[j].ID
+	})
+
+	result := []*models.Reply{}
+	for _, comment := range paginate(replies, filter.Limit, filter.Offset) {
+		result = append(result, &models.Reply{
+			Comment: copyComment(comment),
+			Parent:  copyComment(r.store.comments[*comment.ParentID]),
+		})
+	}
+	return result, nil
+}
+
+// GetVoteBreakdown counts the up and down votes on a comment and its
+// distinct voters
+func (r *MemoryRepository) GetVoteBreakdown(ctx context.Context, commentID string) (*models.VoteBreakdown, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	comment, exists := r.store.comments[commentID]
+	if !exists || comment.IsDeleted {
+		return nil, repository.ErrNotFound
+	}
+
+	breakdown := &models.VoteBreakdown{CommentID: commentID}
+	voters := make(map[string]bool)
+	for _, vote := range r.store.votes {
+		if vote.CommentID != commentID {
+			continue
+		}
+		switch vote.VoteType {
+		case models.VoteTypeUp:
+			breakdown.Upvotes++
+		case models.VoteTypeDown:
+			breakdown.Downvotes++
+		}
+		voters[vote.UserID] = true
+	}
+	breakdown.Voters = int64(len(voters))
+	return breakdown, nil
+}
+
+// GetCommentsWithUserVotes retrieves comments along with the user's votes
+func (r *MemoryRepository) GetCommentsWithUserVotes(ctx context.Context, rootID, userID string, filter *models.CommentFilter) ([]*models.Comment, map[string]*models.Vote, error) {
+	scoped := &models.CommentFilter{}
+	if filter != nil {
+		scoped.ParentID = filter.ParentID
+		scoped.MaxDepth = filter.MaxDepth
+		scoped.TopLevelOnly = filter.TopLevelOnly
+		scoped.CreatedAfter = filter.CreatedAfter
+		scoped.CreatedBefore = filter.CreatedBefore
+		scoped.IsEdited = filter.IsEdited
+		scoped.MinEdits = filter.MinEdits
+		scoped.MaxEdits = filter.MaxEdits
+		scoped.SortBy = filter.SortBy
+		scoped.SortOrder = filter.SortOrder
+		scoped.Limit = filter.Limit
+		scoped.Offset = filter.Offset
+	}
+	scoped.RootID = &rootID
+	scoped.ViewerID = &userID
+
+	comments, err := r.GetComments(ctx, scoped)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	votes := make(map[string]*models.Vote)
+	for _, comment := range comments {
+		if vote, exists := r.store.votes[voteKey(comment.ID, userID)]; exists {
+			v := *vote
+			votes[comment.ID] = &v
+			comment.CurrentUserVote = &v.VoteType
+		}
+	}
+
+	return comments, votes, nil
+}
+
+// GetCommentTreeWithUserVotes builds the tree userID sees with their vote set
+// on each comment
+func (r *MemoryRepository) GetCommentTreeWithUserVotes(ctx context.Context, rootID, userID string, maxDepth int, sortBy string) ([]*models.CommentTree, error) {
+	filter := &models.CommentFilter{
+		MaxDepth: &maxDepth,
+		SortBy:   sortBy,
+	}
+	comments, _, err := r.GetCommentsWithUserVotes(ctx, rootID, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCommentTree(comments), nil
+}
+
+// GetUserVotesForComments retrieves a user's votes for a set of comments
+func (r *MemoryRepository) GetUserVotesForComments(ctx context.Context, commentIDs []string, userID string) (map[string]*models.Vote, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	votes := make(map[string]*models.Vote)
+	for _, id := range commentIDs {
+		if vote, exists := r.store.votes[voteKey(id, userID)]; exists {
+			v := *vote
+			votes[id] = &v
+		}
+	}
+	return votes, nil
+}
+
+// ImportComments inserts comments exactly as given, keeping their IDs, paths,
+// depths, timestamps and vote counts. Parents must exist already or precede
+// their children in comments. Nothing is inserted if any comment is rejected.
+func (r *MemoryRepository) ImportComments(ctx context.Context, comments []*models.Comment) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	pending := make(map[string]bool, len(comments))
+	for _, comment := range comments {
+		if _, exists := r.store.comments[comment.ID]; exists || pending[comment.ID] {
+			return fmt.Errorf("failed to import comments: duplicate id %s", comment.ID)
+		}
+		if comment.ParentID != nil {
+			if _, exists := r.store.comments[*comment.ParentID]; !exists && !pending[*comment.ParentID] {
+				return fmt.Errorf("failed to import comment %s: parent %s: %w", comment.ID, *comment.ParentID, repository.ErrNotFound)
+			}
+		}
+		pending[comment.ID] = true
+	}
+
+	for _, comment := range comments {
+		stored := copyComment(comment)
+		stored.ReplyCount = 0
+		stored.DescendantCount = 0
+		stored.Version = 1
+		if stored.Status == "" {
+			stored.Status = models.CommentStatusApproved
+		}
+		r.store.comments[stored.ID] = stored
+		r.store.order = append(r.store.order, stored.ID)
+		if counted(stored) {
+			r.adjustReplyCountsLocked(stored, 1)
+		}
+	}
+	return nil
+}
+
+// UpdateCommentScores recalculates scores for specified comments
+func (r *MemoryRepository) UpdateCommentScores(ctx context.Context, commentIDs []string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, id := range commentIDs {
+		r.recalculateLocked(id)
+	}
+	return nil
+}
+
+// GetCommentStats retrieves statistics for a root
+func (r *MemoryRepository) GetCommentStats(ctx context.Context, rootID string) (*models.CommentStats, error) {
+	return r.statsFor([]string{rootID})[rootID], nil
+}
+
+// GetCommentStatsBatch retrieves statistics for many roots at once
+func (r *MemoryRepository) GetCommentStatsBatch(ctx context.Context, rootIDs []string) (map[string]*models.CommentStats, error) {
+	return r.statsFor(rootIDs), nil
+}
+
+// GetRootVersion reports how many comments the root has, deleted and
+// unapproved included, and when the latest of them last changed
+func (r *MemoryRepository) GetRootVersion(ctx context.Context, rootID string) (*models.RootVersion, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	version := &models.RootVersion{RootID: rootID}
+	for _, comment := range r.store.comments {
+		if comment.RootID != rootID {
+			continue
+		}
+		version.CommentCount++
+		if version.LastUpdatedAt == nil || comment.UpdatedAt.After(*version.LastUpdatedAt) {
+			updatedAt := comment.UpdatedAt
+			version.LastUpdatedAt = &updatedAt
+		}
+	}
+	return version, nil
+}
+
+// statsFor aggregates statistics for the given roots in one pass over the store
+func (r *MemoryRepository) statsFor(rootIDs []string) map[string]*models.CommentStats {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	result := make(map[string]*models.CommentStats, len(rootIDs))
+	for _, rootID := range rootIDs {
+		result[rootID] = &models.CommentStats{RootID: rootID}
+	}
+	recentCutoff := time.Now().Add(-24 * time.Hour)
+	totalDepth := make(map[string]int64, len(rootIDs))
+	participants := make(map[string]map[string]bool, len(rootIDs))
+
+	for _, comment := range r.store.ordered() {
+		stats, requested := result[comment.RootID]
+		if !requested || !counted(comment) {
+			continue
+		}
+		stats.TotalCount++
+		stats.TotalScore += comment.Score
+		if comment.Depth > stats.MaxDepth {
+			stats.MaxDepth = comment.Depth
+		}
+		if comment.CreatedAt.After(recentCutoff) {
+			stats.RecentCount++
+		}
+		if comment.IsEdited {
+			stats.EditedCount++
+		}
+		stats.TotalEdits += int64(comment.EditCount)
+		totalDepth[comment.RootID] += int64(comment.Depth)
+		if participants[comment.RootID] == nil {
+			participants[comment.RootID] = make(map[string]bool)
+		}
+		participants[comment.RootID][comment.UserID] = true
+		if stats.LastCommentAt == nil || comment.CreatedAt.After(*stats.LastCommentAt) {
+			createdAt := comment.CreatedAt
+			stats.LastCommentAt = &createdAt
+		}
+	}
+
+	for rootID, stats := range result {
+		stats.ParticipantCount = int64(len(participants[rootID]))
+		if stats.TotalCount > 0 {
+			stats.EditRate = float64(stats.EditedCount) / float64(stats.TotalCount) * 100
+			stats.AvgDepth = float64(totalDepth[rootID]) / float64(stats.TotalCount)
+		}
+		if stats.EditedCount > 0 {
+			stats.AvgEditsPerComment = float64(stats.TotalEdits) / float64(stats.EditedCount)
+		}
+	}
+
+	return result
+}
+
+// GetUserCommentCount retrieves the number of comments by a user
+func (r *MemoryRepository) GetUserCommentCount(ctx context.Context, userID string) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, comment := range r.store.ordered() {
+		if comment.UserID == userID && !comment.IsDeleted {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetTopComments retrieves top comments based on score within time range
+func (r *MemoryRepository) GetTopComments(ctx context.Context, rootID string, limit int, timeRange string) ([]*models.Comment, error) {
+	cutoff := timeRangeCutoff(timeRange)
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	comments := []*models.Comment{}
+	for _, comment := range r.store.ordered() {
+		if comment.RootID != rootID || !counted(comment) || comment.CreatedAt.Before(cutoff) {
+			continue
+		}
+		comments = append(comments, copyComment(comment))
+	}
+
+	sort.SliceStable(comments, func(i, j int) bool {
+		if comments[i].Score != comments[j].Score {
+			return comments[i].Score > comments[j].Score
+		}
+		return comments[i].CreatedAt.After(comments[j].CreatedAt)
+	})
+
+	return paginate(comments, &limit, nil), nil
+}
+
+// GetTrendingRoots ranks roots by how many live comments they gained within
+// the time range, breaking ties by the combined score of those comments
+func (r *MemoryRepository) GetTrendingRoots(ctx context.Context, timeRange string, limit int) ([]*models.TrendingRoot, error) {
+	cutoff := timeRangeCutoff(timeRange)
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byRoot := make(map[string]*models.TrendingRoot)
+	for _, comment := range r.store.comments {
+		if !counted(comment) || !comment.CreatedAt.After(cutoff) {
+			continue
+		}
+		root, ok := byRoot[comment.RootID]
+		if !ok {
+			root = &models.TrendingRoot{RootID: comment.RootID}
+			byRoot[comment.RootID] = root
+		}
+		root.CommentCount++
+		root.TotalScore += comment.Score
+		if comment.CreatedAt.After(root.LatestCommentAt) {
+			root.LatestCommentAt = comment.CreatedAt
+		}
+	}
+
+	roots := make([]*models.TrendingRoot, 0, len(byRoot))
+	for _, root := range byRoot {
+		roots = append(roots, root)
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		if roots[i].CommentCount != roots[j].CommentCount {
+			return roots[i].CommentCount > roots[j].CommentCount
+		}
+		if roots[i].TotalScore != roots[j].TotalScore {
+			return roots[i].TotalScore > roots[j].TotalScore
+		}
+		return roots[i].LatestCommentAt.After(roots[j].LatestCommentAt)
+	})
+
+	if limit > 0 && len(roots) > limit {
+		roots = roots[:limit]
+	}
+	return roots, nil
+}
+
+// GetUserReputation totals the votes on a user's live comments across all
+// roots, counting vote records so self-votes left by imports can be skipped
+func (r *MemoryRepository) GetUserReputation(ctx context.Context, userID string) (*models.UserReputation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	reputation := &models.UserReputation{UserID: userID}
+	authored := make(map[string]bool)
+	for _, comment := range r.store.comments {
+		if comment.UserID == userID && counted(comment) {
+			authored[comment.ID] = true
+			reputation.CommentCount++
+		}
+	}
+	for _, vote := range r.store.votes {
+		if !authored[vote.CommentID] || vote.UserID == userID {
+			continue
+		}
+		switch vote.VoteType {
+		case models.VoteTypeUp:
+			reputation.Upvotes++
+		case models.VoteTypeDown:
+			reputation.Downvotes++
+		}
+		reputation.TotalScore += int64(vote.VoteType) * int64(vote.Weight)
+	}
+	return reputation, nil
+}
+
+// GetTopCommenters ranks the users commenting on a root by how many live
+// comments they have there, breaking ties by the combined score of those
+// comments
+func (r *MemoryRepository) GetTopCommenters(ctx context.Context, rootID string, limit int) ([]*models.TopCommenter, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byUser := make(map[string]*models.TopCommenter)
+	for _, comment := range r.store.comments {
+		if comment.RootID != rootID || !counted(comment) {
+			continue
+		}
+		commenter, ok := byUser[comment.UserID]
+		if !ok {
+			commenter = &models.TopCommenter{UserID: comment.UserID}
+			byUser[comment.UserID] = commenter
+		}
+		commenter.CommentCount++
+		commenter.TotalScore += comment.Score
+	}
+
+	commenters := make([]*models.TopCommenter, 0, len(byUser))
+	for _, commenter := range byUser {
+		commenters = append(commenters, commenter)
+	}
+	sort.Slice(commenters, func(i, j int) bool {
+		if commenters[i].CommentCount != commenters[j].CommentCount {
+			return commenters[i].CommentCount > commenters[j].CommentCount
+		}
+		if commenters[i].TotalScore != commenters[j].TotalScore {
+			return commenters[i].TotalScore > commenters[j].TotalScore
+		}
+		return commenters[i].UserID < commenters[j].UserID
+	})
+
+	if limit > 0 && len(commenters) > limit {
+		commenters = commenters[:limit]
+	}
+	return commenters, nil
+}
+
+// timeRangeCutoff returns the start of an "hour", "day", "week" or "month"
+// window ending now; any other range means all time
+func timeRangeCutoff(timeRange string) time.Time {
+	switch timeRange {
+	case "hour":
+		return time.Now().Add(-time.Hour)
+	case "day":
+		return time.Now().AddDate(0, 0, -1)
+	case "week":
+		return time.Now().AddDate(0, 0, -7)
+	case "month":
+		return time.Now().AddDate(0, -1, 0)
+	default:
+		return time.Time{}
+	}
+}
+
+// SetCommentStatus moves a comment to a new moderation status, adding it to or
+// removing it from its ancestors' reply counts
+func (r *MemoryRepository) SetCommentStatus(ctx context.Context, id string, status models.CommentStatus) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	comment, exists := r.store.comments[id]
+	if !exists || comment.IsDeleted {
+		return repository.ErrNotFound
+	}
+
+	wasCounted := counted(comment)
+	comment.Status = status
+	comment.UpdatedAt = time.Now()
+	switch isCounted := counted(comment); {
+	case isCounted && !wasCounted:
+		r.adjustReplyCountsLocked(comment, 1)
+	case !isCounted && wasCounted:
+		r.adjustReplyCountsLocked(comment, -1)
+	}
+	return nil
+}
+
+// GetRootSettings retrieves the overrides stored for a root
+func (r *MemoryRepository) GetRootSettings(ctx context.Context, rootID string) (*models.RootSettings, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	settings, exists := r.store.settings[rootID]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+	stored := *settings
+	return &stored, nil
+}
+
+// SetRootSettings replaces the overrides stored for a root
+func (r *MemoryRepository) SetRootSettings(ctx context.Context, settings *models.RootSettings) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	settings.UpdatedAt = time.Now()
+	stored := *settings
+	r.store.settings[settings.RootID] = &stored
+	return nil
+}
+
+// AddBlock stops blockerID seeing comments by blockedID
+func (r *MemoryRepository) AddBlock(ctx context.Context, blockerID, blockedID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.blocks[blockerID] == nil {
+		r.store.blocks[blockerID] = make(map[string]bool)
+	}
+	r.store.blocks[blockerID][blockedID] = true
+	return nil
+}
+
+// RemoveBlock lifts a block added with AddBlock
+func (r *MemoryRepository) RemoveBlock(ctx context.Context, blockerID, blockedID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.blocks[blockerID], blockedID)
+	return nil
+}
+
+// GetBlockedUsers lists the users blockerID has blocked, sorted
+func (r *MemoryRepository) GetBlockedUsers(ctx context.Context, blockerID string) ([]string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	blocked := make([]string, 0, len(r.store.blocks[blockerID]))
+	for userID := range r.store.blocks[blockerID] {
+		blocked = append(blocked, userID)
+	}
+	sort.Strings(blocked)
+	return blocked, nil
+}
+
+// blockedBy returns the users the viewer has blocked; the caller holds the lock
+func (s *store) blockedBy(viewerID *string) map[string]bool {
+	if viewerID == nil {
+		return nil
+	}
+	return s.blocks[*viewerID]
+}
+
+// Subscribe makes userID a subscriber of rootID
+func (r *MemoryRepository) Subscribe(ctx context.Context, userID, rootID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.subs[rootID] == nil {
+		r.store.subs[rootID] = make(map[string]bool)
+	}
+	r.store.subs[rootID][userID] = true
+	return nil
+}
+
+// Unsubscribe drops userID from the subscribers of rootID
+func (r *MemoryRepository) Unsubscribe(ctx context.Context, userID, rootID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.subs[rootID], userID)
+	return nil
+}
+
+// GetSubscriberUserIDs lists the users subscribed to rootID, sorted
+func (r *MemoryRepository) GetSubscriberUserIDs(ctx context.Context, rootID string) ([]string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	subscribers := make([]string, 0, len(r.store.subs[rootID]))
+	for userID := range r.store.subs[rootID] {
+		subscribers = append(subscribers, userID)
+	}
+	sort.Strings(subscribers)
+	return subscribers, nil
+}
+
+// SetLinkPreview stores the preview fetched for a comment's link, provided
+// the comment still links to preview.URL
+func (r *MemoryRepository) SetLinkPreview(ctx context.Context, id string, preview *models.LinkPreview) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	comment, exists := r.store.comments[id]
+	if !exists || comment.IsDeleted || comment.LinkURL == nil || *comment.LinkURL != preview.URL {
+		return repository.ErrNotFound
+	}
+
+	stored := *preview
+	comment.LinkPreview = &stored
+	return nil
+}
+
+// GetModerationQueue retrieves a root's comments awaiting moderation, pending
+// or quarantined, oldest first
+func (r *MemoryRepository) GetModerationQueue(ctx context.Context, rootID string, filter *models.CommentFilter) ([]*models.Comment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	comments := []*models.Comment{}
+	for _, comment := range r.store.ordered() {
+		if comment.RootID != rootID || comment.IsDeleted {
+			continue
+		}
+		if comment.Status != models.CommentStatusPending && comment.Status != models.CommentStatusQuarantined {
+			continue
+		}
+		comments = append(comments, copyComment(comment))
+	}
+
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+	})
+
+	if filter == nil {
+		return comments, nil
+	}
+	return paginate(comments, filter.Limit, filter.Offset), nil
+}
+
+// PurgeDeletedComments permanently deletes soft-deleted comments older than specified days
+func (r *MemoryRepository) PurgeDeletedComments(ctx context.Context, olderThan int) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	cutoff := time.Now().AddDate(0, 0, -olderThan)
+	var purged int64
+	remaining := r.store.order[:0]
+	for _, id := range r.store.order {
+		comment := r.store.comments[id]
+		if comment.IsDeleted && comment.UpdatedAt.Before(cutoff) {
+			delete(r.store.comments, id)
+			purged++
+			continue
+		}
+		remaining = append(remaining, id)
+	}
+	r.store.order = remaining
+
+	// Emulate ON DELETE CASCADE on votes.comment_id
+	for key, vote := range r.store.votes {
+		if _, exists := r.store.comments[vote.CommentID]; !exists {
+			delete(r.store.votes, key)
+		}
+	}
+
+	return purged, nil
+}
+
+// RecalculateCommentScores recalculates all comment scores
+func (r *MemoryRepository) RecalculateCommentScores(ctx context.Context) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id := range r.store.comments {
+		r.recalculateLocked(id)
+	}
+	return nil
+}
+
+// RepairCommentPaths rebuilds path and depth for every comment in a root from
+// the parent chain and returns the number of comments corrected
+func (r *MemoryRepository) RepairCommentPaths(ctx context.Context, rootID string) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	type position struct {
+		path  string
+		depth int
+	}
+	resolved := make(map[string]position)
+
+	var resolve func(comment *models.Comment, seen map[string]bool) (position, bool)
+	resolve = func(comment *models.Comment, seen map[string]bool) (position, bool) {
+		if pos, ok := resolved[comment.ID]; ok {
+			return pos, true
+		}
+		if seen[comment.ID] {
+			return position{}, false // parent_id cycle
+		}
+		seen[comment.ID] = true
+
+		pos := position{path: comment.ID}
+		if comment.ParentID != nil {
+			parent, exists := r.store.comments[*comment.ParentID]
+			if !exists || parent.RootID != rootID {
+				return position{}, false
+			}
+			parentPos, ok := resolve(parent, seen)
+			if !ok {
+				return position{}, false
+			}
+			pos = position{path: parentPos.path + "." + comment.ID, depth: parentPos.depth + 1}
+		}
+		resolved[comment.ID] = pos
+		return pos, true
+	}
+
+	var repaired int64
+	for _, comment := range r.store.comments {
+		if comment.RootID != rootID {
+			continue
+		}
+		pos, ok := resolve(comment, make(map[string]bool))
+		if !ok {
+			continue
+		}
+		if comment.Path != pos.path || comment.Depth != pos.depth {
+			comment.Path = pos.path
+			comment.Depth = pos.depth
+			repaired++
+		}
+	}
+	return repaired, nil
+}
+
+// RecalculateReplyCounts recomputes reply and descendant counts for all comments
+func (r *MemoryRepository) RecalculateReplyCounts(ctx context.Context) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, comment := range r.store.comments {
+		comment.ReplyCount = 0
+		comment.DescendantCount = 0
+	}
+	for _, comment := range r.store.comments {
+		if counted(comment) {
+			r.adjustReplyCountsLocked(comment, 1)
+		}
+	}
+	return nil
+}
+
+// RecalculateDecayedScores recomputes decayed scores for all comments
+func (r *MemoryRepository) RecalculateDecayedScores(ctx context.Context, halfLife time.Duration, now time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	decayed := make(map[string]float64)
+	for _, vote := range r.store.votes {
+		decayed[vote.CommentID] += float64(vote.VoteType) * float64(vote.Weight) * ranking.DecayWeight(now.Sub(vote.CreatedAt), halfLife)
+	}
+
+	for id, comment := range r.store.comments {
+		if comment.IsDeleted {
+			continue
+		}
+		score := decayed[id]
+		comment.DecayedScore = &score
+	}
+	return nil
+}
+
+// GetCommentForUpdate is GetCommentByID: every write takes the store lock, so
+// there are no rows to lock
+func (r *MemoryRepository) GetCommentForUpdate(ctx context.Context, id string) (*models.Comment, error) {
+	return r.GetCommentByID(ctx, id)
+}
+
+// BeginTx returns a repository handle sharing the same store
+func (r *MemoryRepository) BeginTx(ctx context.Context) (repository.Repository, error) {
+	return &MemoryRepository{store: r.store}, nil
+}
+
+// CommitTx is a no-op; writes are applied immediately
+func (r *MemoryRepository) CommitTx(ctx context.Context) error {
+	return nil
+}
+
+// RollbackTx is a no-op; writes are applied immediately
+func (r *MemoryRepository) RollbackTx(ctx context.Context) error {
+	return nil
+}
+
+// adjustReplyCountsLocked emulates the reply count trigger: it adds delta to the
+// parent's reply count and every ancestor's descendant count. Callers must hold
+// the write lock.
+func (r *MemoryRepository) adjustReplyCountsLocked(comment *models.Comment, delta int) {
+	if comment.ParentID == nil {
+		return
+	}
+
+	if parent, exists := r.store.comments[*comment.ParentID]; exists {
+		parent.ReplyCount += delta
+	}
+	for _, ancestorID := range strings.Split(comment.Path, ".") {
+		if ancestorID == comment.ID {
+			continue
+		}
+		if ancestor, exists := r.store.comments[ancestorID]; exists {
+			ancestor.DescendantCount += delta
+		}
+	}
+}
+
+// recalculateLocked recomputes vote counts and the weighted score for a
+// comment; callers must hold the write lock
+func (r *MemoryRepository) recalculateLocked(commentID string) {
+	comment, exists := r.store.comments[commentID]
+	if !exists {
+		return
+	}
+
+	var upvotes, downvotes, score int64
+	for _, vote := range r.store.votes {
+		if vote.CommentID != commentID {
+			continue
+		}
+		switch vote.VoteType {
+		case models.VoteTypeUp:
+			upvotes++
+		case models.VoteTypeDown:
+			downvotes++
+		}
+		score += int64(vote.VoteType) * int64(vote.Weight)
+	}
+
+	comment.Upvotes = upvotes
+	comment.Downvotes = downvotes
+	comment.Score = score
+	comment.UpdatedAt = time.Now()
+}
+
+// matchesFilter reports whether a comment satisfies the non-pagination parts of a filter
+func matchesFilter(comment *models.Comment, filter *models.CommentFilter) bool {
+	if filter.RootID != nil && comment.RootID != *filter.RootID {
+		return false
+	}
+	if filter.UserID != nil && comment.UserID != *filter.UserID {
+		return false
+	}
+	if filter.ParentID != nil && (comment.ParentID == nil || *comment.ParentID != *filter.ParentID) {
+		return false
+	}
+	if filter.MaxDepth != nil && comment.Depth > *filter.MaxDepth {
+		return false
+	}
+	if filter.TopLevelOnly && comment.Depth != 0 {
+		return false
+	}
+	if filter.CreatedAfter != nil && comment.CreatedAt.Before(*filter.CreatedAfter) {
+		return false
+	}
+	if filter.CreatedBefore != nil && !comment.CreatedAt.Before(*filter.CreatedBefore) {
+		return false
+	}
+	if filter.IsEdited != nil && comment.IsEdited != *filter.IsEdited {
+		return false
+	}
+	if filter.MinEdits != nil && comment.EditCount < *filter.MinEdits {
+		return false
+	}
+	if filter.MaxEdits != nil && comment.EditCount > *filter.MaxEdits {
+		return false
+	}
+	if filter.Search != nil && !matchesSearch(comment.Content, *filter.Search) {
+		return false
+	}
+	return visibleTo(comment, filter.ViewerID)
+}
+
+// counted reports whether a comment is included in its ancestors' reply counts
+// and in statistics: it must be live and approved
+func counted(comment *models.Comment) bool {
+	return !comment.IsDeleted && comment.Status == models.CommentStatusApproved
+}
+
+// visibleTo reports whether a comment may be listed for viewerID. Comments
+// awaiting moderation are only shown to their author.
+func visibleTo(comment *models.Comment, viewerID *string) bool {
+	return comment.Status == models.CommentStatusApproved || viewerID != nil && comment.UserID == *viewerID
+}
+
+// matchesSearch approximates plainto_tsquery matching: every search term must
+// appear in the content, ignoring case. There is no stemming.
+func matchesSearch(content, search string) bool {
+	content = strings.ToLower(content)
+	terms := strings.Fields(strings.ToLower(search))
+	if len(terms) == 0 {
+		return false
+	}
+	for _, term := range terms {
+		if !strings.Contains(content, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortComments orders comments the same way the SQL ORDER BY clause would
+func sortComments(comments []*models.Comment, sortBy, sortOrder string) {
+	less := func(a, b *models.Comment) bool {
+		switch sortBy {
+		case "score":
+			return a.Score < b.Score
+		case "updated_at":
+			return a.UpdatedAt.Before(b.UpdatedAt)
+		case "content_updated_at":
+			return timeOrZero(a.ContentUpdatedAt).Before(timeOrZero(b.ContentUpdatedAt))
+		case "edit_count":
+			return a.EditCount < b.EditCount
+		case "hot":
+			return ranking.Hot(a.Score, a.CreatedAt) < ranking.Hot(b.Score, b.CreatedAt)
+		case "best":
+			return ranking.Best(a.Upvotes, a.Downvotes) < ranking.Best(b.Upvotes, b.Downvotes)
+		case "decayed":
+			return decayedOrScore(a) < decayedOrScore(b)
+		case "controversial":
+			return ranking.Controversial(a.Upvotes, a.Downvotes) < ranking.Controversial(b.Upvotes, b.Downvotes)
+		default:
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+	}
+
+	sort.SliceStable(comments, func(i, j int) bool {
+		if sortOrder == "asc" {
+			return less(comments[i], comments[j])
+		}
+		return less(comments[j], comments[i])
+	})
+}
+
+// decayedOrScore mirrors COALESCE(decayed_score, score)
+func decayedOrScore(comment *models.Comment) float64 {
+	if comment.DecayedScore != nil {
+		return *comment.DecayedScore
+	}
+	return float64(comment.Score)
+}
+
+// paginate applies limit and offset to an already sorted slice
+func paginate(comments []*models.Comment, limit, offset *int) []*models.Comment {
+	if offset != nil && *offset > 0 {
+		if *offset >= len(comments) {
+			return []*models.Comment{}
+		}
+		comments = comments[*offset:]
+	}
+	if limit != nil && *limit >= 0 && *limit < len(comments) {
+		comments = comments[:*limit]
+	}
+	return comments
+}
+
+// buildCommentTree converts flat comments to tree structure
+func buildCommentTree(comments []*models.Comment) []*models.CommentTree {
+	_, roots := linkCommentTree(comments)
+	return roots
+}
+
+// linkCommentTree makes a node for each comment and attaches it to its
+// parent's node, returning the nodes by ID and those of top-level comments
+func linkCommentTree(comments []*models.Comment) (map[string]*models.CommentTree, []*models.CommentTree) {
+	commentMap := make(map[string]*models.CommentTree)
+	var roots []*models.CommentTree
+
+	for _, comment := range comments {
+		commentMap[comment.ID] = &models.CommentTree{
+			Comment:  comment,
+			Children: []*models.CommentTree{},
+		}
+	}
+
+	for _, comment := range comments {
+		node := commentMap[comment.ID]
+		if comment.ParentID != nil {
+			if parent, exists := commentMap[*comment.ParentID]; exists {
+				parent.Children = append(parent.Children, node)
+			}
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	return commentMap, roots
+}
+
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,76 @@
+// Package metrics exposes Prometheus metrics for Commentific. It is opt-in:
+// nothing is recorded unless a Metrics is registered as an event emitter on the
+// CommentService and/or wraps the repository.
+//
+//	m := metrics.New(nil)
+//	commentService := service.NewCommentService(m.WrapRepository(repo))
+//	commentService.AddEventEmitter(m)
+//	http.Handle("/metrics", m.Handler())
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the Commentific collectors and the registry they live in
+type Metrics struct {
+	registry *prometheus.Registry
+
+	commentOperations *prometheus.CounterVec
+	queryDuration     *prometheus.HistogramVec
+	queryErrors       *prometheus.CounterVec
+}
+
+// New creates the collectors and registers them with registry. A nil registry
+// creates a fresh one, so several services in one process don't collide.
+func New(registry *prometheus.Registry) *Metrics {
+	if registry == nil {
+		registry = prometheus.NewRegistry()
+	}
+
+	m := &Metrics{
+		registry: registry,
+		commentOperations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "commentific",
+			Name:      "comment_operations_total",
+			Help:      "Comment operations by kind (created, updated, deleted, voted).",
+		}, []string{"operation"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "commentific",
+			Name:      "repository_query_duration_seconds",
+			Help:      "Latency of repository calls by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+		queryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "commentific",
+			Name:      "repository_errors_total",
+			Help:      "Repository calls that returned an error, by method.",
+		}, []string{"operation"}),
+	}
+
+	registry.MustRegister(m.commentOperations, m.queryDuration, m.queryErrors)
+	return m
+}
+
+// Registry returns the registry the collectors are registered with
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Emit implements service.EventEmitter, counting each comment event by kind
+func (m *Metrics) Emit(ctx context.Context, event *models.CommentEvent) {
+	// "comment.created" -> "created"
+	operation := strings.TrimPrefix(string(event.Type), "comment.")
+	m.commentOperations.WithLabelValues(operation).Inc()
+}
@@ -0,0 +1,64 @@
+package metrics_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/metrics"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics_CreateIncrementsCreatedCounter(t *testing.T) {
+	// Setup
+	m := metrics.New(nil)
+	commentService := service.NewCommentService(m.WrapRepository(memory.NewMemoryRepository()))
+	commentService.AddEventEmitter(m)
+
+	// Execute
+	_, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Counted"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Assert
+	expected := `
+# HELP commentific_comment_operations_total Comment operations by kind (created, updated, deleted, voted).
+# TYPE commentific_comment_operations_total counter
+commentific_comment_operations_total{operation="created"} 1
+`
+	if err := testutil.GatherAndCompare(m.Registry(), strings.NewReader(expected), "commentific_comment_operations_total"); err != nil {
+		t.Fatalf("Unexpected counter value: %v", err)
+	}
+
+	observed, err := testutil.GatherAndCount(m.Registry(), "commentific_repository_query_duration_seconds")
+	if err != nil {
+		t.Fatalf("Failed to gather histogram: %v", err)
+	}
+	if observed == 0 {
+		t.Fatal("Expected repository latency to be recorded")
+	}
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	// Setup
+	m := metrics.New(nil)
+	m.Emit(context.Background(), &models.CommentEvent{Type: models.EventCommentVoted})
+
+	// Execute
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// Assert
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `commentific_comment_operations_total{operation="voted"} 1`) {
+		t.Fatalf("Expected voted counter in output, got: %s", rec.Body.String())
+	}
+}
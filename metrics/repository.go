@@ -0,0 +1,343 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/repository"
+)
+
+// WrapRepository returns a repository that records the latency and errors of
+// every call to repo, labelled by method name
+func (m *Metrics) WrapRepository(repo repository.CommentRepository) repository.CommentRepository {
+	return &instrumentedRepository{repo: repo, metrics: m}
+}
+
+// observe records one repository call; err points at the call's named result
+func (m *Metrics) observe(operation string, start time.Time, err *error) {
+	m.queryDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if *err != nil {
+		m.queryErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+// instrumentedRepository decorates a CommentRepository with metrics
+type instrumentedRepository struct {
+	repo    repository.CommentRepository
+	metrics *Metrics
+}
+
+var _ repository.CommentRepository = (*instrumentedRepository)(nil)
+
+func (r *instrumentedRepository) CreateComment(ctx context.Context, comment *models.Comment) (err error) {
+	defer r.metrics.observe("CreateComment", time.Now(), &err)
+	return r.repo.CreateComment(ctx, comment)
+}
+
+func (r *instrumentedRepository) GetCommentByID(ctx context.Context, id string) (comment *models.Comment, err error) {
+	defer r.metrics.observe("GetCommentByID", time.Now(), &err)
+	return r.repo.GetCommentByID(ctx, id)
+}
+
+func (r *instrumentedRepository) GetCommentForUpdate(ctx context.Context, id string) (comment *models.Comment, err error) {
+	defer r.metrics.observe("GetCommentForUpdate", time.Now(), &err)
+	return r.repo.GetCommentForUpdate(ctx, id)
+}
+
+func (r *instrumentedRepository) GetCommentsByIDs(ctx context.Context, ids []string, includeDeleted bool) (comments []*models.Comment, err error) {
+	defer r.metrics.observe("GetCommentsByIDs", time.Now(), &err)
+	return r.repo.GetCommentsByIDs(ctx, ids, includeDeleted)
+}
+
+func (r *instrumentedRepository) UpdateComment(ctx context.Context, id string, updates *models.UpdateCommentRequest) (err error) {
+	defer r.metrics.observe("UpdateComment", time.Now(), &err)
+	return r.repo.UpdateComment(ctx, id, updates)
+}
+
+func (r *instrumentedRepository) DeleteComment(ctx context.Context, id string, userID string, reason *string) (err error) {
+	defer r.metrics.observe("DeleteComment", time.Now(), &err)
+	return r.repo.DeleteComment(ctx, id, userID, reason)
+}
+
+func (r *instrumentedRepository) RemoveComment(ctx context.Context, id string, moderatorID string, reason *string) (err error) {
+	defer r.metrics.observe("RemoveComment", time.Now(), &err)
+	return r.repo.RemoveComment(ctx, id, moderatorID, reason)
+}
+
+func (r *instrumentedRepository) HardDeleteComment(ctx context.Context, id string) (err error) {
+	defer r.metrics.observe("HardDeleteComment", time.Now(), &err)
+	return r.repo.HardDeleteComment(ctx, id)
+}
+
+func (r *instrumentedRepository) GetComments(ctx context.Context, filter *models.CommentFilter) (comments []*models.Comment, err error) {
+	defer r.metrics.observe("GetComments", time.Now(), &err)
+	return r.repo.GetComments(ctx, filter)
+}
+
+func (r *instrumentedRepository) GetCommentsByRootID(ctx context.Context, rootID string, filter *models.CommentFilter) (comments []*models.Comment, err error) {
+	defer r.metrics.observe("GetCommentsByRootID", time.Now(), &err)
+	return r.repo.GetCommentsByRootID(ctx, rootID, filter)
+}
+
+func (r *instrumentedRepository) GetCommentsSince(ctx context.Context, rootID string, since time.Time, afterID string, limit int) (comments []*models.Comment, err error) {
+	defer r.metrics.observe("GetCommentsSince", time.Now(), &err)
+	return r.repo.GetCommentsSince(ctx, rootID, since, afterID, limit)
+}
+
+func (r *instrumentedRepository) GetCommentsByUserID(ctx context.Context, userID string, filter *models.CommentFilter) (comments []*models.Comment, err error) {
+	defer r.metrics.observe("GetCommentsByUserID", time.Now(), &err)
+	return r.repo.GetCommentsByUserID(ctx, userID, filter)
+}
+
+func (r *instrumentedRepository) GetCommentChildren(ctx context.Context, parentID string, maxDepth int, filter *models.CommentFilter) (comments []*models.Comment, err error) {
+	defer r.metrics.observe("GetCommentChildren", time.Now(), &err)
+	return r.repo.GetCommentChildren(ctx, parentID, maxDepth, filter)
+}
+
+func (r *instrumentedRepository) GetDirectChildren(ctx context.Context, parentID string, filter *models.CommentFilter) (comments []*models.Comment, err error) {
+	defer r.metrics.observe("GetDirectChildren", time.Now(), &err)
+	return r.repo.GetDirectChildren(ctx, parentID, filter)
+}
+
+func (r *instrumentedRepository) ExportComments(ctx context.Context, rootID, afterPath string, limit int, includeDeleted bool) (comments []*models.Comment, err error) {
+	defer r.metrics.observe("ExportComments", time.Now(), &err)
+	return r.repo.ExportComments(ctx, rootID, afterPath, limit, includeDeleted)
+}
+
+func (r *instrumentedRepository) GetCommentTree(ctx context.Context, rootID string, maxDepth int, sortBy string) (tree []*models.CommentTree, err error) {
+	defer r.metrics.observe("GetCommentTree", time.Now(), &err)
+	return r.repo.GetCommentTree(ctx, rootID, maxDepth, sortBy)
+}
+
+func (r *instrumentedRepository) GetCommentSubtree(ctx context.Context, commentID string, maxDepth int, sortBy string) (tree *models.CommentTree, err error) {
+	defer r.metrics.observe("GetCommentSubtree", time.Now(), &err)
+	return r.repo.GetCommentSubtree(ctx, commentID, maxDepth, sortBy)
+}
+
+func (r *instrumentedRepository) GetCommentPath(ctx context.Context, commentID string) (comments []*models.Comment, err error) {
+	defer r.metrics.observe("GetCommentPath", time.Now(), &err)
+	return r.repo.GetCommentPath(ctx, commentID)
+}
+
+func (r *instrumentedRepository) GetCommentAncestors(ctx context.Context, commentID string) (comments []*models.Comment, err error) {
+	defer r.metrics.observe("GetCommentAncestors", time.Now(), &err)
+	return r.repo.GetCommentAncestors(ctx, commentID)
+}
+
+func (r *instrumentedRepository) MoveComment(ctx context.Context, commentID, newParentID string) (err error) {
+	defer r.metrics.observe("MoveComment", time.Now(), &err)
+	return r.repo.MoveComment(ctx, commentID, newParentID)
+}
+
+func (r *instrumentedRepository) CreateVote(ctx context.Context, vote *models.Vote) (err error) {
+	defer r.metrics.observe("CreateVote", time.Now(), &err)
+	return r.repo.CreateVote(ctx, vote)
+}
+
+func (r *instrumentedRepository) UpdateVote(ctx context.Context, commentID, userID string, voteType models.VoteType) (err error) {
+	defer r.metrics.observe("UpdateVote", time.Now(), &err)
+	return r.repo.UpdateVote(ctx, commentID, userID, voteType)
+}
+
+func (r *instrumentedRepository) DeleteVote(ctx context.Context, commentID, userID string) (err error) {
+	defer r.metrics.observe("DeleteVote", time.Now(), &err)
+	return r.repo.DeleteVote(ctx, commentID, userID)
+}
+
+func (r *instrumentedRepository) DeleteVotes(ctx context.Context, commentIDs []string, userID string) (removed []string, err error) {
+	defer r.metrics.observe("DeleteVotes", time.Now(), &err)
+	return r.repo.DeleteVotes(ctx, commentIDs, userID)
+}
+
+func (r *instrumentedRepository) GetUserVote(ctx context.Context, commentID, userID string) (vote *models.Vote, err error) {
+	defer r.metrics.observe("GetUserVote", time.Now(), &err)
+	return r.repo.GetUserVote(ctx, commentID, userID)
+}
+
+func (r *instrumentedRepository) GetCommentVotes(ctx context.Context, commentID string) (votes []*models.Vote, err error) {
+	defer r.metrics.observe("GetCommentVotes", time.Now(), &err)
+	return r.repo.GetCommentVotes(ctx, commentID)
+}
+
+func (r *instrumentedRepository) GetVotesForComments(ctx context.Context, commentIDs []string) (votes map[string][]*models.Vote, err error) {
+	defer r.metrics.observe("GetVotesForComments", time.Now(), &err)
+	return r.repo.GetVotesForComments(ctx, commentIDs)
+}
+
+func (r *instrumentedRepository) GetUserVotes(ctx context.Context, userID string, filter *models.VoteFilter) (voted []*models.VotedComment, err error) {
+	defer r.metrics.observe("GetUserVotes", time.Now(), &err)
+	return r.repo.GetUserVotes(ctx, userID, filter)
+}
+
+func (r *instrumentedRepository) GetVoteBreakdown(ctx context.Context, commentID string) (breakdown *models.VoteBreakdown, err error) {
+	defer r.metrics.observe("GetVoteBreakdown", time.Now(), &err)
+	return r.repo.GetVoteBreakdown(ctx, commentID)
+}
+
+func (r *instrumentedRepository) GetCommentsWithUserVotes(ctx context.Context, rootID, userID string, filter *models.CommentFilter) (comments []*models.Comment, votes map[string]*models.Vote, err error) {
+	defer r.metrics.observe("GetCommentsWithUserVotes", time.Now(), &err)
+	return r.repo.GetCommentsWithUserVotes(ctx, rootID, userID, filter)
+}
+
+func (r *instrumentedRepository) GetUserVotesForComments(ctx context.Context, commentIDs []string, userID string) (votes map[string]*models.Vote, err error) {
+	defer r.metrics.observe("GetUserVotesForComments", time.Now(), &err)
+	return r.repo.GetUserVotesForComments(ctx, commentIDs, userID)
+}
+
+func (r *instrumentedRepository) GetCommentTreeWithUserVotes(ctx context.Context, rootID, userID string, maxDepth int, sortBy string) (tree []*models.CommentTree, err error) {
+	defer r.metrics.observe("GetCommentTreeWithUserVotes", time.Now(), &err)
+	return r.repo.GetCommentTreeWithUserVotes(ctx, rootID, userID, maxDepth, sortBy)
+}
+
+func (r *instrumentedRepository) UpdateCommentScores(ctx context.Context, commentIDs []string) (err error) {
+	defer r.metrics.observe("UpdateCommentScores", time.Now(), &err)
+	return r.repo.UpdateCommentScores(ctx, commentIDs)
+}
+
+func (r *instrumentedRepository) ImportComments(ctx context.Context, comments []*models.Comment) (err error) {
+	defer r.metrics.observe("ImportComments", time.Now(), &err)
+	return r.repo.ImportComments(ctx, comments)
+}
+
+func (r *instrumentedRepository) GetCommentStats(ctx context.Context, rootID string) (stats *models.CommentStats, err error) {
+	defer r.metrics.observe("GetCommentStats", time.Now(), &err)
+	return r.repo.GetCommentStats(ctx, rootID)
+}
+
+func (r *instrumentedRepository) GetCommentStatsBatch(ctx context.Context, rootIDs []string) (stats map[string]*models.CommentStats, err error) {
+	defer r.metrics.observe("GetCommentStatsBatch", time.Now(), &err)
+	return r.repo.GetCommentStatsBatch(ctx, rootIDs)
+}
+
+func (r *instrumentedRepository) GetRootVersion(ctx context.Context, rootID string) (version *models.RootVersion, err error) {
+	defer r.metrics.observe("GetRootVersion", time.Now(), &err)
+	return r.repo.GetRootVersion(ctx, rootID)
+}
+
+func (r *instrumentedRepository) GetUserCommentCount(ctx context.Context, userID string) (count int64, err error) {
+	defer r.metrics.observe("GetUserCommentCount", time.Now(), &err)
+	return r.repo.GetUserCommentCount(ctx, userID)
+}
+
+func (r *instrumentedRepository) GetTopComments(ctx context.Context, rootID string, limit int, timeRange string) (comments []*models.Comment, err error) {
+	defer r.metrics.observe("GetTopComments", time.Now(), &err)
+	return r.repo.GetTopComments(ctx, rootID, limit, timeRange)
+}
+
+func (r *instrumentedRepository) GetTrendingRoots(ctx context.Context, timeRange string, limit int) (roots []*models.TrendingRoot, err error) {
+	defer r.metrics.observe("GetTrendingRoots", time.Now(), &err)
+	return r.repo.GetTrendingRoots(ctx, timeRange, limit)
+}
+
+func (r *instrumentedRepository) GetUserReputation(ctx context.Context, userID string) (reputation *models.UserReputation, err error) {
+	defer r.metrics.observe("GetUserReputation", time.Now(), &err)
+	return r.repo.GetUserReputation(ctx, userID)
+}
+
+func (r *instrumentedRepository) AddBlock(ctx context.Context, blockerID, blockedID string) (err error) {
+	defer r.metrics.observe("AddBlock", time.Now(), &err)
+	return r.repo.AddBlock(ctx, blockerID, blockedID)
+}
+
+func (r *instrumentedRepository) RemoveBlock(ctx context.Context, blockerID, blockedID string) (err error) {
+	defer r.metrics.observe("RemoveBlock", time.Now(), &err)
+	return r.repo.RemoveBlock(ctx, blockerID, blockedID)
+}
+
+func (r *instrumentedRepository) GetBlockedUsers(ctx context.Context, blockerID string) (blocked []string, err error) {
+	defer r.metrics.observe("GetBlockedUsers", time.Now(), &err)
+	return r.repo.GetBlockedUsers(ctx, blockerID)
+}
+
+func (r *instrumentedRepository) Subscribe(ctx context.Context, userID, rootID string) (err error) {
+	defer r.metrics.observe("Subscribe", time.Now(), &err)
+	return r.repo.Subscribe(ctx, userID, rootID)
+}
+
+func (r *instrumentedRepository) Unsubscribe(ctx context.Context, userID, rootID string) (err error) {
+	defer r.metrics.observe("Unsubscribe", time.Now(), &err)
+	return r.repo.Unsubscribe(ctx, userID, rootID)
+}
+
+func (r *instrumentedRepository) GetSubscriberUserIDs(ctx context.Context, rootID string) (subscribers []string, err error) {
+	defer r.metrics.observe("GetSubscriberUserIDs", time.Now(), &err)
+	return r.repo.GetSubscriberUserIDs(ctx, rootID)
+}
+
+func (r *instrumentedRepository) GetRepliesToUser(ctx context.Context, userID string, filter *models.CommentFilter) (replies []*models.Reply, err error) {
+	defer r.metrics.observe("GetRepliesToUser", time.Now(), &err)
+	return r.repo.GetRepliesToUser(ctx, userID, filter)
+}
+
+func (r *instrumentedRepository) GetTopCommenters(ctx context.Context, rootID string, limit int) (commenters []*models.TopCommenter, err error) {
+	defer r.metrics.observe("GetTopCommenters", time.Now(), &err)
+	return r.repo.GetTopCommenters(ctx, rootID, limit)
+}
+
+func (r *instrumentedRepository) SetCommentStatus(ctx context.Context, id string, status models.CommentStatus) (err error) {
+	defer r.metrics.observe("SetCommentStatus", time.Now(), &err)
+	return r.repo.SetCommentStatus(ctx, id, status)
+}
+
+func (r *instrumentedRepository) GetModerationQueue(ctx context.Context, rootID string, filter *models.CommentFilter) (comments []*models.Comment, err error) {
+	defer r.metrics.observe("GetModerationQueue", time.Now(), &err)
+	return r.repo.GetModerationQueue(ctx, rootID, filter)
+}
+
+func (r *instrumentedRepository) GetRootSettings(ctx context.Context, rootID string) (_ *models.RootSettings, err error) {
+	defer r.metrics.observe("GetRootSettings", time.Now(), &err)
+	return r.repo.GetRootSettings(ctx, rootID)
+}
+
+func (r *instrumentedRepository) SetRootSettings(ctx context.Context, settings *models.RootSettings) (err error) {
+	defer r.metrics.observe("SetRootSettings", time.Now(), &err)
+	return r.repo.SetRootSettings(ctx, settings)
+}
+
+func (r *instrumentedRepository) SetLinkPreview(ctx context.Context, id string, preview *models.LinkPreview) (err error) {
+	defer r.metrics.observe("SetLinkPreview", time.Now(), &err)
+	return r.repo.SetLinkPreview(ctx, id, preview)
+}
+
+func (r *instrumentedRepository) PurgeDeletedComments(ctx context.Context, olderThan int) (count int64, err error) {
+	defer r.metrics.observe("PurgeDeletedComments", time.Now(), &err)
+	return r.repo.PurgeDeletedComments(ctx, olderThan)
+}
+
+func (r *instrumentedRepository) RecalculateCommentScores(ctx context.Context) (err error) {
+	defer r.metrics.observe("RecalculateCommentScores", time.Now(), &err)
+	return r.repo.RecalculateCommentScores(ctx)
+}
+
+func (r *instrumentedRepository) RepairCommentPaths(ctx context.Context, rootID string) (count int64, err error) {
+	defer r.metrics.observe("RepairCommentPaths", time.Now(), &err)
+	return r.repo.RepairCommentPaths(ctx, rootID)
+}
+
+func (r *instrumentedRepository) RecalculateReplyCounts(ctx context.Context) (err error) {
+	defer r.metrics.observe("RecalculateReplyCounts", time.Now(), &err)
+	return r.repo.RecalculateReplyCounts(ctx)
+}
+
+func (r *instrumentedRepository) RecalculateDecayedScores(ctx context.Context, halfLife time.Duration, now time.Time) (err error) {
+	defer r.metrics.observe("RecalculateDecayedScores", time.Now(), &err)
+	return r.repo.RecalculateDecayedScores(ctx, halfLife, now)
+}
+
+// BeginTx wraps the transaction handle so calls made inside it are recorded too
+func (r *instrumentedRepository) BeginTx(ctx context.Context) (repository.Repository, error) {
+	tx, err := r.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedRepository{repo: tx, metrics: r.metrics}, nil
+}
+
+func (r *instrumentedRepository) CommitTx(ctx context.Context) error {
+	return r.repo.CommitTx(ctx)
+}
+
+func (r *instrumentedRepository) RollbackTx(ctx context.Context) error {
+	return r.repo.RollbackTx(ctx)
+}
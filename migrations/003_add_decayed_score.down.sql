@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_comments_root_decayed_score;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS decayed_score;
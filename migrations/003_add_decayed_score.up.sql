@@ -0,0 +1,6 @@
+-- Time-decayed score: each vote's contribution halves every half-life since it
+-- was cast. NULL until the recompute job has run for the comment.
+ALTER TABLE comments ADD COLUMN decayed_score DOUBLE PRECISION;
+
+-- Sorting by decayed score within a root
+CREATE INDEX idx_comments_root_decayed_score ON comments(root_id, decayed_score DESC) WHERE NOT is_deleted;
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS trigger_comments_reply_counts ON comments;
+
+DROP FUNCTION IF EXISTS update_comment_reply_counts();
+
+ALTER TABLE comments DROP COLUMN IF EXISTS descendant_count;
+ALTER TABLE comments DROP COLUMN IF EXISTS reply_count;
@@ -0,0 +1,46 @@
+-- Denormalized reply counts so clients can show "42 replies" without loading the subtree.
+-- Both count only comments that are not soft deleted.
+ALTER TABLE comments ADD COLUMN reply_count INTEGER NOT NULL DEFAULT 0;      -- Direct children
+ALTER TABLE comments ADD COLUMN descendant_count INTEGER NOT NULL DEFAULT 0; -- Whole subtree, excluding the comment itself
+
+-- Keep counts current as replies are created, soft deleted or restored
+CREATE OR REPLACE FUNCTION update_comment_reply_counts()
+RETURNS TRIGGER AS $$
+DECLARE
+    delta INTEGER;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        IF NEW.is_deleted THEN
+            RETURN NEW;
+        END IF;
+        delta := 1;
+    ELSIF OLD.is_deleted = NEW.is_deleted THEN
+        RETURN NEW;
+    ELSIF NEW.is_deleted THEN
+        delta := -1;
+    ELSE
+        delta := 1;
+    END IF;
+
+    IF NEW.parent_id IS NOT NULL THEN
+        UPDATE comments SET reply_count = reply_count + delta WHERE id = NEW.parent_id;
+
+        -- Every ancestor appears in the materialized path
+        UPDATE comments SET descendant_count = descendant_count + delta
+        WHERE id = ANY(string_to_array(NEW.path, '.')::uuid[]) AND id <> NEW.id;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_comments_reply_counts
+    AFTER INSERT OR UPDATE OF is_deleted ON comments
+    FOR EACH ROW
+    EXECUTE FUNCTION update_comment_reply_counts();
+
+-- Backfill existing comments
+UPDATE comments c
+SET
+    reply_count = (SELECT COUNT(*) FROM comments r WHERE r.parent_id = c.id AND NOT r.is_deleted),
+    descendant_count = (SELECT COUNT(*) FROM comments d WHERE d.path LIKE c.path || '.%' AND NOT d.is_deleted);
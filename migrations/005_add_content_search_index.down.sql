@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_comments_content_fts;
@@ -0,0 +1,2 @@
+-- Full-text search over comment content across all roots
+CREATE INDEX idx_comments_content_fts ON comments USING GIN (to_tsvector('english', content)) WHERE NOT is_deleted;
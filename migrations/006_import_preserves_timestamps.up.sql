@@ -0,0 +1,32 @@
+-- Bulk imports keep their original timestamps. Inserting a reply bumps the
+-- parent's reply counts, which would otherwise stamp the parent with NOW().
+-- ImportComments sets commentific.importing for its own transaction only.
+CREATE OR REPLACE FUNCTION update_comment_edit_tracking()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF current_setting('commentific.importing', true) = 'on' THEN
+        RETURN NEW;
+    END IF;
+
+    -- Check if content, media_url, or link_url changed
+    IF (OLD.content IS DISTINCT FROM NEW.content) OR 
+       (OLD.media_url IS DISTINCT FROM NEW.media_url) OR 
+       (OLD.link_url IS DISTINCT FROM NEW.link_url) THEN
+        
+        -- Store original content if this is the first edit
+        IF OLD.is_edited = FALSE THEN
+            NEW.original_content = OLD.content;
+        END IF;
+        
+        -- Update edit tracking fields
+        NEW.is_edited = TRUE;
+        NEW.content_updated_at = NOW();
+        NEW.edit_count = OLD.edit_count + 1;
+    END IF;
+    
+    -- Always update the general updated_at timestamp
+    NEW.updated_at = NOW();
+    
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
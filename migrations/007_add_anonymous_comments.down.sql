@@ -0,0 +1,2 @@
+ALTER TABLE comments DROP COLUMN IF EXISTS display_name;
+ALTER TABLE comments DROP COLUMN IF EXISTS is_anonymous;
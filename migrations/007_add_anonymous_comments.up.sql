@@ -0,0 +1,4 @@
+-- Guest comments: user_id holds a generated guest token and the author may
+-- leave an optional display name
+ALTER TABLE comments ADD COLUMN is_anonymous BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE comments ADD COLUMN display_name VARCHAR(64);
@@ -0,0 +1,39 @@
+-- Restore the reply count trigger from migration 004
+CREATE OR REPLACE FUNCTION update_comment_reply_counts()
+RETURNS TRIGGER AS $$
+DECLARE
+    delta INTEGER;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        IF NEW.is_deleted THEN
+            RETURN NEW;
+        END IF;
+        delta := 1;
+    ELSIF OLD.is_deleted = NEW.is_deleted THEN
+        RETURN NEW;
+    ELSIF NEW.is_deleted THEN
+        delta := -1;
+    ELSE
+        delta := 1;
+    END IF;
+
+    IF NEW.parent_id IS NOT NULL THEN
+        UPDATE comments SET reply_count = reply_count + delta WHERE id = NEW.parent_id;
+
+        -- Every ancestor appears in the materialized path
+        UPDATE comments SET descendant_count = descendant_count + delta
+        WHERE id = ANY(string_to_array(NEW.path, '.')::uuid[]) AND id <> NEW.id;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_comments_reply_counts ON comments;
+CREATE TRIGGER trigger_comments_reply_counts
+    AFTER INSERT OR UPDATE OF is_deleted ON comments
+    FOR EACH ROW
+    EXECUTE FUNCTION update_comment_reply_counts();
+
+DROP INDEX IF EXISTS idx_comments_root_pending;
+ALTER TABLE comments DROP COLUMN IF EXISTS status;
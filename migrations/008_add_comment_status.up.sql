@@ -0,0 +1,47 @@
+-- Pre-moderation: only approved comments are shown publicly. Existing
+-- comments are approved.
+ALTER TABLE comments ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'approved'
+    CONSTRAINT comments_status_check CHECK (status IN ('pending', 'approved', 'rejected'));
+
+-- The moderation queue of a root, oldest first
+CREATE INDEX idx_comments_root_pending ON comments(root_id, created_at) WHERE status = 'pending' AND NOT is_deleted;
+
+-- Reply counts now include approved comments only, so pending replies don't
+-- show up in "42 replies" before a moderator has seen them
+CREATE OR REPLACE FUNCTION update_comment_reply_counts()
+RETURNS TRIGGER AS $$
+DECLARE
+    was_counted BOOLEAN := FALSE;
+    is_counted BOOLEAN;
+    delta INTEGER;
+BEGIN
+    is_counted := NOT NEW.is_deleted AND NEW.status = 'approved';
+    IF TG_OP = 'UPDATE' THEN
+        was_counted := NOT OLD.is_deleted AND OLD.status = 'approved';
+    END IF;
+
+    IF was_counted = is_counted THEN
+        RETURN NEW;
+    ELSIF is_counted THEN
+        delta := 1;
+    ELSE
+        delta := -1;
+    END IF;
+
+    IF NEW.parent_id IS NOT NULL THEN
+        UPDATE comments SET reply_count = reply_count + delta WHERE id = NEW.parent_id;
+
+        -- Every ancestor appears in the materialized path
+        UPDATE comments SET descendant_count = descendant_count + delta
+        WHERE id = ANY(string_to_array(NEW.path, '.')::uuid[]) AND id <> NEW.id;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_comments_reply_counts ON comments;
+CREATE TRIGGER trigger_comments_reply_counts
+    AFTER INSERT OR UPDATE OF is_deleted, status ON comments
+    FOR EACH ROW
+    EXECUTE FUNCTION update_comment_reply_counts();
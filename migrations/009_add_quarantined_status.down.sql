@@ -0,0 +1,9 @@
+-- Quarantined comments go back to the plain moderation queue
+UPDATE comments SET status = 'pending' WHERE status = 'quarantined';
+
+DROP INDEX IF EXISTS idx_comments_root_pending;
+CREATE INDEX idx_comments_root_pending ON comments(root_id, created_at) WHERE status = 'pending' AND NOT is_deleted;
+
+ALTER TABLE comments DROP CONSTRAINT comments_status_check;
+ALTER TABLE comments ADD CONSTRAINT comments_status_check
+    CHECK (status IN ('pending', 'approved', 'rejected'));
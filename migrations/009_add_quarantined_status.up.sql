@@ -0,0 +1,10 @@
+-- Comments held back by the spam scorer are quarantined rather than pending,
+-- so moderators can tell automatic holds from manual pre-moderation
+ALTER TABLE comments DROP CONSTRAINT comments_status_check;
+ALTER TABLE comments ADD CONSTRAINT comments_status_check
+    CHECK (status IN ('pending', 'approved', 'rejected', 'quarantined'));
+
+-- The moderation queue now covers both
+DROP INDEX IF EXISTS idx_comments_root_pending;
+CREATE INDEX idx_comments_root_pending ON comments(root_id, created_at)
+    WHERE status IN ('pending', 'quarantined') AND NOT is_deleted;
@@ -0,0 +1,3 @@
+-- Open Graph metadata fetched for link_url, stored as JSON; cleared whenever
+-- link_url changes
+ALTER TABLE comments ADD COLUMN link_preview JSONB;
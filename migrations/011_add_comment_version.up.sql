@@ -0,0 +1,3 @@
+-- Optimistic locking: every update bumps version, and an update may name the
+-- version it was based on so stale edits are refused
+ALTER TABLE comments ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+-- The cascade matches the original schema, so there is nothing to undo
+SELECT 1;
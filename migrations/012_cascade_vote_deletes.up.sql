@@ -0,0 +1,8 @@
+-- Votes must never outlive their comment. Databases whose votes foreign key
+-- lost its ON DELETE CASCADE (or never had one) may hold orphaned votes;
+-- remove them and restore the cascade.
+DELETE FROM votes v WHERE NOT EXISTS (SELECT 1 FROM comments c WHERE c.id = v.comment_id);
+
+ALTER TABLE votes DROP CONSTRAINT IF EXISTS votes_comment_id_fkey;
+ALTER TABLE votes ADD CONSTRAINT votes_comment_id_fkey
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE;
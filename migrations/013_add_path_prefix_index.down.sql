@@ -0,0 +1,2 @@
+-- The other indexes in the up migration belong to 001
+DROP INDEX IF EXISTS idx_comments_path_prefix;
@@ -0,0 +1,12 @@
+-- Indexes behind the list, tree and children queries. 001 creates the
+-- root, user and parent indexes; IF NOT EXISTS restores any that a schema
+-- built by hand is missing, so those installs stop scanning the table.
+CREATE INDEX IF NOT EXISTS idx_comments_root_created ON comments(root_id, created_at DESC) WHERE NOT is_deleted;
+CREATE INDEX IF NOT EXISTS idx_comments_root_score_created ON comments(root_id, score DESC, created_at DESC) WHERE NOT is_deleted;
+CREATE INDEX IF NOT EXISTS idx_comments_user_id ON comments(user_id) WHERE NOT is_deleted;
+CREATE INDEX IF NOT EXISTS idx_comments_parent_id ON comments(parent_id) WHERE NOT is_deleted;
+
+-- The trigram index on path serves substring matches. Subtree reads match a
+-- prefix (path LIKE 'a.b.%'), which a B-tree answers with a range scan, but
+-- only with text_pattern_ops unless the database collation is C.
+CREATE INDEX IF NOT EXISTS idx_comments_path_prefix ON comments(path text_pattern_ops) WHERE NOT is_deleted;
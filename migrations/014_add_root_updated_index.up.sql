@@ -0,0 +1,4 @@
+-- Polling clients ask for a root's comments changed after a time. Deleted
+-- rows are included so pollers learn of deletions, hence no partial index.
+-- 001's idx_comments_root_updated skips them, so this one needs its own name.
+CREATE INDEX IF NOT EXISTS idx_comments_root_updated_all ON comments(root_id, updated_at);
@@ -0,0 +1,2 @@
+ALTER TABLE comments DROP COLUMN IF EXISTS delete_reason;
+ALTER TABLE comments DROP COLUMN IF EXISTS deleted_by;
@@ -0,0 +1,3 @@
+-- Who removed a comment and why, kept for appeals; set by soft deletes only
+ALTER TABLE comments ADD COLUMN deleted_by TEXT;
+ALTER TABLE comments ADD COLUMN delete_reason TEXT;
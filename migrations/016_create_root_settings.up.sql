@@ -0,0 +1,10 @@
+-- Per-root overrides of the service configuration; a NULL column falls back
+-- to the global setting
+CREATE TABLE IF NOT EXISTS root_settings (
+    root_id VARCHAR(255) PRIMARY KEY,
+    max_depth INTEGER CHECK (max_depth >= 0),
+    locked BOOLEAN,
+    pre_moderation BOOLEAN,
+    allow_anonymous BOOLEAN,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
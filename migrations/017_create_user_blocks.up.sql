@@ -0,0 +1,8 @@
+-- Directional blocks: blocker_id no longer sees comments by blocked_id
+CREATE TABLE IF NOT EXISTS user_blocks (
+    blocker_id VARCHAR(255) NOT NULL,
+    blocked_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id)
+);
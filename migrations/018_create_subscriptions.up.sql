@@ -0,0 +1,7 @@
+-- Users following a root to hear about new comments on it
+CREATE TABLE IF NOT EXISTS subscriptions (
+    root_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (root_id, user_id)
+);
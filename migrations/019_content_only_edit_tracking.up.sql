@@ -0,0 +1,28 @@
+-- Only content changes count as edits. Changing the media or link still
+-- bumps updated_at, but leaves is_edited, edit_count, content_updated_at and
+-- original_content alone.
+CREATE OR REPLACE FUNCTION update_comment_edit_tracking()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF current_setting('commentific.importing', true) = 'on' THEN
+        RETURN NEW;
+    END IF;
+
+    IF OLD.content IS DISTINCT FROM NEW.content THEN
+        -- Store original content if this is the first edit
+        IF OLD.is_edited = FALSE THEN
+            NEW.original_content = OLD.content;
+        END IF;
+
+        -- Update edit tracking fields
+        NEW.is_edited = TRUE;
+        NEW.content_updated_at = NOW();
+        NEW.edit_count = OLD.edit_count + 1;
+    END IF;
+
+    -- Always update the general updated_at timestamp
+    NEW.updated_at = NOW();
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
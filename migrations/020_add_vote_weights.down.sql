@@ -0,0 +1,44 @@
+-- Every vote counts once again
+CREATE OR REPLACE FUNCTION update_comment_score()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- Update the comment's vote counts and score
+    UPDATE comments 
+    SET 
+        upvotes = (SELECT COUNT(*) FROM votes WHERE comment_id = NEW.comment_id AND vote_type = 1),
+        downvotes = (SELECT COUNT(*) FROM votes WHERE comment_id = NEW.comment_id AND vote_type = -1),
+        updated_at = NOW()
+    WHERE id = NEW.comment_id;
+    
+    -- Update the calculated score
+    UPDATE comments 
+    SET score = upvotes - downvotes 
+    WHERE id = NEW.comment_id;
+    
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION update_comment_score_on_delete()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- Update the comment's vote counts and score
+    UPDATE comments 
+    SET 
+        upvotes = (SELECT COUNT(*) FROM votes WHERE comment_id = OLD.comment_id AND vote_type = 1),
+        downvotes = (SELECT COUNT(*) FROM votes WHERE comment_id = OLD.comment_id AND vote_type = -1),
+        updated_at = NOW()
+    WHERE id = OLD.comment_id;
+    
+    -- Update the calculated score
+    UPDATE comments 
+    SET score = upvotes - downvotes 
+    WHERE id = OLD.comment_id;
+    
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+UPDATE comments SET score = upvotes - downvotes;
+
+ALTER TABLE votes DROP COLUMN IF EXISTS weight;
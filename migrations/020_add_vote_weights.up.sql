@@ -0,0 +1,33 @@
+-- Weighted voting: each vote counts weight times toward the comment's score,
+-- so a weight-3 upvote adds 3. upvotes and downvotes still count voters.
+ALTER TABLE votes ADD COLUMN weight INTEGER NOT NULL DEFAULT 1 CHECK (weight >= 1);
+
+CREATE OR REPLACE FUNCTION update_comment_score()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE comments
+    SET
+        upvotes = (SELECT COUNT(*) FROM votes WHERE comment_id = NEW.comment_id AND vote_type = 1),
+        downvotes = (SELECT COUNT(*) FROM votes WHERE comment_id = NEW.comment_id AND vote_type = -1),
+        score = COALESCE((SELECT SUM(vote_type * weight) FROM votes WHERE comment_id = NEW.comment_id), 0),
+        updated_at = NOW()
+    WHERE id = NEW.comment_id;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION update_comment_score_on_delete()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE comments
+    SET
+        upvotes = (SELECT COUNT(*) FROM votes WHERE comment_id = OLD.comment_id AND vote_type = 1),
+        downvotes = (SELECT COUNT(*) FROM votes WHERE comment_id = OLD.comment_id AND vote_type = -1),
+        score = COALESCE((SELECT SUM(vote_type * weight) FROM votes WHERE comment_id = OLD.comment_id), 0),
+        updated_at = NOW()
+    WHERE id = OLD.comment_id;
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
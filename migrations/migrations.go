@@ -0,0 +1,12 @@
+// Package migrations holds the SQL migrations that create and upgrade the
+// Postgres schema. Apply the files in order with psql, or let
+// PostgresProvider.Migrate apply them, which also honors a table prefix.
+package migrations
+
+import "embed"
+
+// FS holds the migration files, named NNN_description.up.sql and
+// NNN_description.down.sql
+//
+//go:embed *.sql
+var FS embed.FS
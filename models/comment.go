@@ -1,43 +1,107 @@
 package models
 
 import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
 // Comment represents a comment in the system with support for infinite hierarchy
 type Comment struct {
-	ID               string     `json:"id" db:"id"`
-	RootID           string     `json:"root_id" db:"root_id"`                             // The entity this comment belongs to (post, product, etc.)
-	ParentID         *string    `json:"parent_id" db:"parent_id"`                         // Parent comment ID for threading
-	UserID           string     `json:"user_id" db:"user_id"`                             // External user ID
-	Content          string     `json:"content" db:"content"`                             // The comment text
-	MediaURL         *string    `json:"media_url" db:"media_url"`                         // Optional media attachment
-	LinkURL          *string    `json:"link_url" db:"link_url"`                           // Optional link
-	Upvotes          int64      `json:"upvotes" db:"upvotes"`                             // Number of upvotes
-	Downvotes        int64      `json:"downvotes" db:"downvotes"`                         // Number of downvotes
-	Score            int64      `json:"score" db:"score"`                                 // Calculated score (upvotes - downvotes)
-	Depth            int        `json:"depth" db:"depth"`                                 // Depth in the comment tree
-	Path             string     `json:"path" db:"path"`                                   // Materialized path for efficient queries
-	IsDeleted        bool       `json:"is_deleted" db:"is_deleted"`                       // Soft delete flag
-	IsEdited         bool       `json:"is_edited" db:"is_edited"`                         // Whether comment has been edited
-	EditCount        int        `json:"edit_count" db:"edit_count"`                       // Number of times comment has been edited
-	OriginalContent  *string    `json:"original_content,omitempty" db:"original_content"` // Original content before first edit
-	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
-	ContentUpdatedAt *time.Time `json:"content_updated_at,omitempty" db:"content_updated_at"` // When content was last edited
+	ID               string        `json:"id" db:"id"`
+	RootID           string        `json:"root_id" db:"root_id"`                             // The entity this comment belongs to (post, product, etc.)
+	ParentID         *string       `json:"parent_id" db:"parent_id"`                         // Parent comment ID for threading
+	UserID           string        `json:"user_id" db:"user_id"`                             // External user ID
+	Content          string        `json:"content" db:"content"`                             // The comment text
+	MediaURL         *string       `json:"media_url" db:"media_url"`                         // Optional media attachment
+	LinkURL          *string       `json:"link_url" db:"link_url"`                           // Optional link
+	Upvotes          int64         `json:"upvotes" db:"upvotes"`                             // Number of upvotes
+	Downvotes        int64         `json:"downvotes" db:"downvotes"`                         // Number of downvotes
+	Score            int64         `json:"score" db:"score"`                                 // Calculated score (upvotes - downvotes)
+	Depth            int           `json:"depth" db:"depth"`                                 // Depth in the comment tree
+	Path             string        `json:"path" db:"path"`                                   // Materialized path for efficient queries
+	IsDeleted        bool          `json:"is_deleted" db:"is_deleted"`                       // Soft delete flag
+	IsEdited         bool          `json:"is_edited" db:"is_edited"`                         // Whether comment has been edited
+	EditCount        int           `json:"edit_count" db:"edit_count"`                       // Number of times comment has been edited
+	OriginalContent  *string       `json:"original_content,omitempty" db:"original_content"` // Original content before first edit
+	CreatedAt        time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at" db:"updated_at"`
+	ContentUpdatedAt *time.Time    `json:"content_updated_at,omitempty" db:"content_updated_at"` // When content was last edited
+	DecayedScore     *float64      `json:"decayed_score,omitempty" db:"decayed_score"`           // Score with older votes weighted less (set by the decay recompute job)
+	ReplyCount       int           `json:"reply_count" db:"reply_count"`                         // Number of direct replies (excluding deleted)
+	DescendantCount  int           `json:"descendant_count" db:"descendant_count"`               // Number of replies in the whole subtree (excluding deleted)
+	IsAnonymous      bool          `json:"is_anonymous" db:"is_anonymous"`                       // Posted by a guest; UserID is a guest token
+	DisplayName      *string       `json:"display_name,omitempty" db:"display_name"`             // Name a guest chose to show, if any
+	Status           CommentStatus `json:"status" db:"status"`                                   // Moderation state; only approved comments are public
+	ContentHTML      *string       `json:"content_html,omitempty" db:"-"`                        // Content rendered from Markdown to sanitized HTML on read, when enabled; never stored
+	LinkPreview      *LinkPreview  `json:"link_preview,omitempty" db:"link_preview"`             // Open Graph card for LinkURL, once fetched
+	Version          int64         `json:"version" db:"version"`                                 // Starts at 1 and goes up with every update, for optimistic locking
+	DeletedBy        *string       `json:"deleted_by,omitempty" db:"deleted_by"`                 // Who soft deleted the comment: its author or a moderator
+	DeleteReason     *string       `json:"delete_reason,omitempty" db:"delete_reason"`           // Why it was deleted, if given
+	CurrentUserVote  *VoteType     `json:"current_user_vote,omitempty" db:"-"`                   // The requesting user's vote, set by the with-votes reads; nil if they haven't voted
 }
 
+// LinkPreview is the Open Graph metadata of a comment's LinkURL. It is stored
+// as JSON.
+type LinkPreview struct {
+	URL         string    `json:"url"` // The LinkURL it was fetched for
+	Title       string    `json:"title,omitempty"`
+	Description string    `json:"description,omitempty"`
+	ImageURL    string    `json:"image_url,omitempty"`
+	SiteName    string    `json:"site_name,omitempty"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+// Value encodes the preview as JSON for storage
+func (p LinkPreview) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// Scan decodes a preview stored as JSON
+func (p *LinkPreview) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, p)
+	case string:
+		return json.Unmarshal([]byte(src), p)
+	default:
+		return fmt.Errorf("cannot scan %T into LinkPreview", src)
+	}
+}
+
+// CommentStatus is where a comment stands in moderation
+type CommentStatus string
+
+const (
+	CommentStatusApproved    CommentStatus = "approved"    // Publicly visible
+	CommentStatusPending     CommentStatus = "pending"     // Awaiting a moderator; visible only to its author
+	CommentStatusRejected    CommentStatus = "rejected"    // Turned down by a moderator; visible only to its author
+	CommentStatusQuarantined CommentStatus = "quarantined" // Held back by the spam scorer; awaits a moderator like pending
+)
+
+// GuestUserIDPrefix starts every guest token used as the UserID of an
+// anonymous comment
+const GuestUserIDPrefix = "guest-"
+
 // Vote represents a user's vote on a comment
 type Vote struct {
 	ID        string    `json:"id" db:"id"`
 	CommentID string    `json:"comment_id" db:"comment_id"`
 	UserID    string    `json:"user_id" db:"user_id"`
-	VoteType  VoteType  `json:"vote_type" db:"vote_type"` // 1 for upvote, -1 for downvote
+	VoteType  VoteType  `json:"vote_type" db:"vote_type"` // "up" (stored as 1) or "down" (stored as -1)
+	Weight    int       `json:"weight" db:"weight"`       // How many points the vote moves the score; 0 is stored as 1
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
-// VoteType represents the type of vote
+// VoteType represents the type of vote. It is stored as a smallint and
+// encoded in JSON as "up", "down" or "none"; the numeric forms 1, -1 and 0
+// are accepted on input too.
 type VoteType int
 
 const (
@@ -46,20 +110,147 @@ const (
 	VoteTypeDown VoteType = -1
 )
 
+// ErrInvalidVoteType is returned when parsing a vote type that isn't one of
+// the names or numbers below
+var ErrInvalidVoteType = errors.New("invalid vote type")
+
+// String returns "up", "down" or "none"
+func (v VoteType) String() string {
+	switch v {
+	case VoteTypeUp:
+		return "up"
+	case VoteTypeDown:
+		return "down"
+	case VoteTypeNone:
+		return "none"
+	default:
+		return fmt.Sprintf("VoteType(%d)", int(v))
+	}
+}
+
+// MarshalJSON encodes known vote types by name and anything else as a number
+func (v VoteType) MarshalJSON() ([]byte, error) {
+	switch v {
+	case VoteTypeUp, VoteTypeDown, VoteTypeNone:
+		return json.Marshal(v.String())
+	default:
+		return json.Marshal(int(v))
+	}
+}
+
+// ParseVoteType converts "up", "down" or "none", in any case, to a VoteType
+func ParseVoteType(name string) (VoteType, error) {
+	switch strings.ToLower(name) {
+	case "up":
+		return VoteTypeUp, nil
+	case "down":
+		return VoteTypeDown, nil
+	case "none":
+		return VoteTypeNone, nil
+	default:
+		return 0, fmt.Errorf("%w %q: must be \"up\", \"down\" or \"none\"", ErrInvalidVoteType, name)
+	}
+}
+
+// UnmarshalJSON accepts "up", "down" and "none" (in any case) as well as the
+// numbers 1, -1 and 0
+func (v *VoteType) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var name string
+		if err := json.Unmarshal(data, &name); err != nil {
+			return err
+		}
+		parsed, err := ParseVoteType(name)
+		if err != nil {
+			return err
+		}
+		*v = parsed
+		return nil
+	}
+
+	var n int
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("%w %s: must be a string or a number", ErrInvalidVoteType, data)
+	}
+	switch VoteType(n) {
+	case VoteTypeUp, VoteTypeDown, VoteTypeNone:
+		*v = VoteType(n)
+		return nil
+	default:
+		return fmt.Errorf("%w %d: must be 1, -1 or 0", ErrInvalidVoteType, n)
+	}
+}
+
+// VotedComment is a comment from a user's vote history along with how they
+// voted. The comment's fields are inlined in JSON.
+type VotedComment struct {
+	*Comment
+	VoteType VoteType  `json:"vote_type" db:"vote_type"`
+	VotedAt  time.Time `json:"voted_at" db:"voted_at"` // When the vote was cast or last changed
+}
+
+// Reply is a comment answering one of a user's comments, along with the
+// comment it answers. The reply's fields are inlined in JSON.
+type Reply struct {
+	*Comment
+	Parent *Comment `json:"parent" db:"parent"`
+}
+
 // CommentTree represents a comment with its children for hierarchical display
 type CommentTree struct {
-	Comment  *Comment       `json:"comment"`
-	Children []*CommentTree `json:"children,omitempty"`
+	Comment   *Comment       `json:"comment"`
+	Children  []*CommentTree `json:"children,omitempty"`
+	Collapsed bool           `json:"collapsed,omitempty"` // Below the requested minimum score, or under a comment that is; shown folded
+
+	// Set when Children was cut short; fetch the rest with the direct children endpoint
+	HasMoreChildren   bool `json:"has_more_children,omitempty"`
+	RemainingChildren int  `json:"remaining_children,omitempty"`
+}
+
+// ExportedComment is a comment as written by a thread export, optionally
+// carrying the votes cast on it. The comment's fields are inlined in JSON.
+type ExportedComment struct {
+	*Comment
+	Votes []*Vote `json:"votes,omitempty"`
+}
+
+// RootExport is a complete snapshot of a root's comments, parents before
+// replies, so the list can be passed straight back to an import
+type RootExport struct {
+	RootID     string             `json:"root_id"`
+	ExportedAt time.Time          `json:"exported_at"`
+	Comments   []*ExportedComment `json:"comments"`
+}
+
+// TrendingRoot summarizes recent comment activity on a root
+type TrendingRoot struct {
+	RootID          string    `json:"root_id" db:"root_id"`
+	CommentCount    int64     `json:"comment_count" db:"comment_count"`         // Live comments created within the window
+	TotalScore      int64     `json:"total_score" db:"total_score"`             // Combined score of those comments
+	LatestCommentAt time.Time `json:"latest_comment_at" db:"latest_comment_at"` // Newest comment within the window
+}
+
+// TopCommenter summarizes one user's live comments on a root
+type TopCommenter struct {
+	UserID       string `json:"user_id" db:"user_id"`
+	CommentCount int64  `json:"comment_count" db:"comment_count"`
+	TotalScore   int64  `json:"total_score" db:"total_score"` // Combined score of those comments
 }
 
 // CreateCommentRequest represents the request to create a new comment
 type CreateCommentRequest struct {
-	RootID   string  `json:"root_id" validate:"required"`
-	ParentID *string `json:"parent_id"`
-	UserID   string  `json:"user_id" validate:"required"`
-	Content  string  `json:"content" validate:"required,min=1,max=10000"`
-	MediaURL *string `json:"media_url"`
-	LinkURL  *string `json:"link_url"`
+	RootID      string  `json:"root_id" validate:"required"`
+	ParentID    *string `json:"parent_id"`
+	UserID      string  `json:"user_id" validate:"required_unless=Anonymous true"` // A guest token, or empty to issue one, when Anonymous
+	Content     string  `json:"content" validate:"required,min=1"`                 // At most CommentServiceConfig.MaxCommentLength characters
+	MediaURL    *string `json:"media_url"`
+	LinkURL     *string `json:"link_url"`
+	Anonymous   bool    `json:"anonymous"`                                // Post as a guest; needs CommentServiceConfig.AllowAnonymous
+	DisplayName *string `json:"display_name" validate:"omitempty,max=64"` // Shown for anonymous comments; ignored otherwise
 }
 
 // UpdateCommentRequest represents the request to update a comment
@@ -67,38 +258,104 @@ type UpdateCommentRequest struct {
 	Content  *string `json:"content,omitempty"`
 	MediaURL *string `json:"media_url,omitempty"`
 	LinkURL  *string `json:"link_url,omitempty"`
+	Version  *int64  `json:"version,omitempty"` // Refuse the update with ErrVersionConflict unless the stored version matches
 }
 
 // VoteRequest represents a vote request
 type VoteRequest struct {
-	UserID   string   `json:"user_id" validate:"required"`
-	VoteType VoteType `json:"vote_type" validate:"required,oneof=1 -1"`
+	CommentID string   `json:"comment_id,omitempty"` // The comment voted on, for BatchVoteComments
+	UserID    string   `json:"user_id" validate:"required"`
+	VoteType  VoteType `json:"vote_type" validate:"required,oneof=1 -1"` // "up"/"down" or 1/-1
+}
+
+// SortFields lists the values CommentFilter.SortBy accepts
+var SortFields = []string{"score", "created_at", "updated_at", "content_updated_at", "edit_count", "hot", "best", "controversial", "decayed"}
+
+// ValidSortField reports whether sortBy is one of SortFields
+func ValidSortField(sortBy string) bool {
+	return slices.Contains(SortFields, sortBy)
 }
 
 // CommentFilter represents filters for querying comments
 type CommentFilter struct {
-	RootID    *string `json:"root_id,omitempty"`
-	UserID    *string `json:"user_id,omitempty"`
-	ParentID  *string `json:"parent_id,omitempty"`
-	MaxDepth  *int    `json:"max_depth,omitempty"`
-	SortBy    string  `json:"sort_by,omitempty"`    // "score", "created_at", "updated_at", "content_updated_at", "edit_count"
-	SortOrder string  `json:"sort_order,omitempty"` // "asc", "desc"
-	Limit     *int    `json:"limit,omitempty"`
-	Offset    *int    `json:"offset,omitempty"`
-	IsEdited  *bool   `json:"is_edited,omitempty"` // Filter by edited status
-	MinEdits  *int    `json:"min_edits,omitempty"` // Minimum number of edits
-	MaxEdits  *int    `json:"max_edits,omitempty"` // Maximum number of edits
+	RootID        *string    `json:"root_id,omitempty"`
+	UserID        *string    `json:"user_id,omitempty"`
+	ParentID      *string    `json:"parent_id,omitempty"`
+	MaxDepth      *int       `json:"max_depth,omitempty"`
+	TopLevelOnly  bool       `json:"top_level_only,omitempty"` // Only comments at depth 0, leaving out replies
+	CreatedAfter  *time.Time `json:"created_after,omitempty"`  // Only comments created at or after this time
+	CreatedBefore *time.Time `json:"created_before,omitempty"` // Only comments created before this time
+	SortBy        string     `json:"sort_by,omitempty"`        // "score", "created_at", "updated_at", "content_updated_at", "edit_count", "hot", "best", "controversial", "decayed"
+	SortOrder     string     `json:"sort_order,omitempty"`     // "asc", "desc"
+	Limit         *int       `json:"limit,omitempty"`
+	Offset        *int       `json:"offset,omitempty"`
+	IsEdited      *bool      `json:"is_edited,omitempty"` // Filter by edited status
+	MinEdits      *int       `json:"min_edits,omitempty"` // Minimum number of edits
+	MaxEdits      *int       `json:"max_edits,omitempty"` // Maximum number of edits
+	Search        *string    `json:"search,omitempty"`    // Full-text search terms matched against content
+	ViewerID      *string    `json:"viewer_id,omitempty"` // Also return this user's own comments that are not approved, and leave out those by users they blocked
+}
+
+// VoteBreakdown is the up/down split of the votes on a comment
+type VoteBreakdown struct {
+	CommentID string `json:"comment_id" db:"comment_id"`
+	Upvotes   int64  `json:"upvotes" db:"upvotes"`
+	Downvotes int64  `json:"downvotes" db:"downvotes"`
+	Voters    int64  `json:"voters" db:"voters"` // Distinct users with a vote on the comment
+}
+
+// UserReputation sums up the votes a user's live comments have received
+// across every root. Votes users cast on their own comments don't count.
+type UserReputation struct {
+	UserID       string `json:"user_id" db:"user_id"`
+	CommentCount int64  `json:"comment_count" db:"comment_count"`
+	TotalScore   int64  `json:"total_score" db:"total_score"` // Sum of the votes, each counted weight times
+	Upvotes      int64  `json:"upvotes" db:"upvotes"`
+	Downvotes    int64  `json:"downvotes" db:"downvotes"`
+}
+
+// VoteFilter represents filters for querying a user's vote history
+type VoteFilter struct {
+	RootID         *string   `json:"root_id,omitempty"`
+	VoteType       *VoteType `json:"vote_type,omitempty"`       // Only up or only down votes
+	IncludeDeleted bool      `json:"include_deleted,omitempty"` // Include votes on soft-deleted comments
+	Limit          *int      `json:"limit,omitempty"`
+	Offset         *int      `json:"offset,omitempty"`
+}
+
+// RootVersion summarizes how a root's comments last changed. Any create,
+// edit, vote, delete or purge on the root changes the count or the latest
+// update time, so it is a cheap stand-in for the root's contents when
+// validating caches.
+type RootVersion struct {
+	RootID        string     `json:"root_id" db:"root_id"`
+	CommentCount  int64      `json:"comment_count" db:"comment_count"`
+	LastUpdatedAt *time.Time `json:"last_updated_at,omitempty" db:"last_updated_at"`
+}
+
+// RootSettings overrides the service configuration for one root. A nil field
+// falls back to the global setting.
+type RootSettings struct {
+	RootID         string    `json:"root_id" db:"root_id"`
+	MaxDepth       *int      `json:"max_depth" db:"max_depth"`             // Deepest depth a reply may have, and the deepest level tree reads return
+	Locked         *bool     `json:"locked" db:"locked"`                   // Refuse new comments; existing ones stay readable
+	PreModeration  *bool     `json:"pre_moderation" db:"pre_moderation"`   // Hold new comments as pending until a moderator approves them
+	AllowAnonymous *bool     `json:"allow_anonymous" db:"allow_anonymous"` // Accept guest comments
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // CommentStats represents statistics for a comment thread
 type CommentStats struct {
-	RootID             string  `json:"root_id"`
-	TotalCount         int64   `json:"total_count"`
-	TotalScore         int64   `json:"total_score"`
-	MaxDepth           int     `json:"max_depth"`
-	RecentCount        int64   `json:"recent_count"`          // Comments in last 24 hours
-	EditedCount        int64   `json:"edited_count"`          // Number of edited comments
-	TotalEdits         int64   `json:"total_edits"`           // Total number of edits across all comments
-	EditRate           float64 `json:"edit_rate"`             // Percentage of comments that have been edited
-	AvgEditsPerComment float64 `json:"avg_edits_per_comment"` // Average edits per edited comment
+	RootID             string     `json:"root_id"`
+	TotalCount         int64      `json:"total_count"`
+	TotalScore         int64      `json:"total_score"`
+	MaxDepth           int        `json:"max_depth"`
+	RecentCount        int64      `json:"recent_count"`              // Comments in last 24 hours
+	EditedCount        int64      `json:"edited_count"`              // Number of edited comments
+	TotalEdits         int64      `json:"total_edits"`               // Total number of edits across all comments
+	EditRate           float64    `json:"edit_rate"`                 // Percentage of comments that have been edited
+	AvgEditsPerComment float64    `json:"avg_edits_per_comment"`     // Average edits per edited comment
+	AvgDepth           float64    `json:"avg_depth"`                 // Average nesting depth of the comments
+	ParticipantCount   int64      `json:"participant_count"`         // Number of distinct commenters
+	LastCommentAt      *time.Time `json:"last_comment_at,omitempty"` // When the newest comment was posted; nil if there are none
 }
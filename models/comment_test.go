@@ -0,0 +1,74 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+func TestVoteType_UnmarshalJSON(t *testing.T) {
+	cases := map[string]models.VoteType{
+		`"up"`:   models.VoteTypeUp,
+		`"down"`: models.VoteTypeDown,
+		`"none"`: models.VoteTypeNone,
+		`"UP"`:   models.VoteTypeUp,
+		`1`:      models.VoteTypeUp,
+		`-1`:     models.VoteTypeDown,
+		`0`:      models.VoteTypeNone,
+	}
+
+	for input, expected := range cases {
+		// Execute
+		var req models.VoteRequest
+		err := json.Unmarshal([]byte(`{"user_id": "voter", "vote_type": `+input+`}`), &req)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Expected %s to unmarshal, got: %v", input, err)
+		}
+		if req.VoteType != expected {
+			t.Fatalf("Expected %s to unmarshal to %d, got: %d", input, expected, req.VoteType)
+		}
+	}
+}
+
+func TestVoteType_UnmarshalJSONRejectsUnknown(t *testing.T) {
+	for _, input := range []string{`"sideways"`, `2`, `1.5`, `true`} {
+		// Execute
+		var vote models.VoteType
+		err := json.Unmarshal([]byte(input), &vote)
+
+		// Assert
+		if err == nil {
+			t.Fatalf("Expected %s to be rejected, got vote type %d", input, vote)
+		}
+	}
+}
+
+func TestVoteType_RoundTrip(t *testing.T) {
+	// Setup
+	vote := models.Vote{ID: "v1", CommentID: "c1", UserID: "voter", VoteType: models.VoteTypeDown}
+
+	// Execute
+	data, err := json.Marshal(vote)
+	if err != nil {
+		t.Fatalf("Failed to marshal vote: %v", err)
+	}
+	var decoded models.Vote
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal vote: %v", err)
+	}
+
+	// Assert
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Failed to decode raw JSON: %v", err)
+	}
+	if raw["vote_type"] != "down" {
+		t.Fatalf("Expected vote_type to be encoded as \"down\", got: %v", raw["vote_type"])
+	}
+	if decoded.VoteType != models.VoteTypeDown {
+		t.Fatalf("Expected round-tripped vote type to be down, got: %d", decoded.VoteType)
+	}
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// EventType identifies what happened to a comment
+type EventType string
+
+const (
+	EventCommentCreated EventType = "comment.created"
+	EventCommentUpdated EventType = "comment.updated"
+	EventCommentDeleted EventType = "comment.deleted"
+	EventCommentVoted   EventType = "comment.voted"
+)
+
+// CommentEvent describes a change to a comment, emitted after the change is stored
+type CommentEvent struct {
+	Type       EventType `json:"type"`
+	RootID     string    `json:"root_id"`
+	CommentID  string    `json:"comment_id"`
+	UserID     string    `json:"user_id"`             // User who made the change
+	Comment    *Comment  `json:"comment,omitempty"`   // Comment state after the change (omitted for deletes)
+	VoteType   *VoteType `json:"vote_type,omitempty"` // Vote cast, for voted events ("none" when a vote is removed)
+	OccurredAt time.Time `json:"occurred_at"`
+}
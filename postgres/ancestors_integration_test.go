@@ -0,0 +1,52 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+func TestGetCommentAncestors_IgnoresMalformedPath(t *testing.T) {
+	// Setup: a reply three levels down whose stored path has been mangled
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	var chain []*models.Comment
+	var parentID *string
+	for _, content := range []string{"Top", "Reply", "Reply to reply", "Deepest"} {
+		comment := &models.Comment{RootID: "ancestors-1", ParentID: parentID, UserID: "alice", Content: content}
+		if err := repo.CreateComment(ctx, comment); err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		chain = append(chain, comment)
+		parentID = &comment.ID
+	}
+	deepest := chain[3]
+	db.MustExecContext(ctx, `UPDATE comments SET path = $1 WHERE id = $2`, "not-a-uuid.."+deepest.ID, deepest.ID)
+
+	// Execute
+	ancestors, err := repo.GetCommentAncestors(ctx, deepest.ID)
+	if err != nil {
+		t.Fatalf("Failed to get ancestors: %v", err)
+	}
+	path, err := repo.GetCommentPath(ctx, deepest.ID)
+	if err != nil {
+		t.Fatalf("Failed to get path: %v", err)
+	}
+
+	// Assert
+	if len(ancestors) != 3 {
+		t.Fatalf("Expected 3 ancestors, got %d", len(ancestors))
+	}
+	for i, ancestor := range ancestors {
+		if ancestor.ID != chain[i].ID {
+			t.Errorf("Expected ancestor %d to be %q, got %q", i, chain[i].Content, ancestor.Content)
+		}
+	}
+	if len(path) != 4 || path[0].ID != chain[0].ID || path[3].ID != deepest.ID {
+		t.Errorf("Expected the path to fall back to parent links, got %d comments", len(path))
+	}
+}
@@ -0,0 +1,82 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+func TestBlocks_HideCommentsFromBlocker(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	top := &models.Comment{RootID: "product-1", UserID: "alice", Content: "Hello"}
+	if err := repo.CreateComment(ctx, top); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	reply := &models.Comment{RootID: "product-1", ParentID: &top.ID, UserID: "bob", Content: "Hi"}
+	if err := repo.CreateComment(ctx, reply); err != nil {
+		t.Fatalf("Failed to create reply: %v", err)
+	}
+	// Blocking twice is harmless
+	for i := 0; i < 2; i++ {
+		if err := repo.AddBlock(ctx, "alice", "bob"); err != nil {
+			t.Fatalf("Failed to block: %v", err)
+		}
+	}
+	alice, carol := "alice", "carol"
+
+	// Execute
+	blocked, err := repo.GetBlockedUsers(ctx, "alice")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	aliceList, err := repo.GetCommentsByRootID(ctx, "product-1", &models.CommentFilter{ViewerID: &alice})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	carolList, err := repo.GetCommentsByRootID(ctx, "product-1", &models.CommentFilter{ViewerID: &carol})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	aliceChildren, err := repo.GetCommentChildren(ctx, top.ID, 5, &models.CommentFilter{ViewerID: &alice})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	aliceVoted, _, err := repo.GetCommentsWithUserVotes(ctx, "product-1", "alice", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if len(blocked) != 1 || blocked[0] != "bob" {
+		t.Errorf("Expected alice to have blocked bob, got: %v", blocked)
+	}
+	if len(aliceList) != 1 || aliceList[0].ID != top.ID {
+		t.Errorf("Expected alice to see only her comment, got: %v", commentIDs(aliceList))
+	}
+	if len(carolList) != 2 {
+		t.Errorf("Expected carol to see both comments, got: %v", commentIDs(carolList))
+	}
+	if len(aliceChildren) != 0 {
+		t.Errorf("Expected alice to see no replies, got: %v", commentIDs(aliceChildren))
+	}
+	if len(aliceVoted) != 1 {
+		t.Errorf("Expected alice to see one comment with votes, got: %v", commentIDs(aliceVoted))
+	}
+
+	// Lifting the block shows bob again
+	if err := repo.RemoveBlock(ctx, "alice", "bob"); err != nil {
+		t.Fatalf("Failed to unblock: %v", err)
+	}
+	aliceList, err = repo.GetCommentsByRootID(ctx, "product-1", &models.CommentFilter{ViewerID: &alice})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(aliceList) != 2 {
+		t.Errorf("Expected alice to see both comments after unblocking, got: %v", commentIDs(aliceList))
+	}
+}
@@ -0,0 +1,31 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestGetCommentsByIDs(t *testing.T) {
+	// Setup
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	first := seedComment(t, repo, db, "product-1", 0, time.Now())
+	second := seedComment(t, repo, db, "product-2", 0, time.Now())
+	missing := uuid.New().String()
+
+	// Execute
+	comments, err := repo.GetCommentsByIDs(ctx, []string{second.ID, missing, "not-a-uuid", first.ID}, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if ids := commentIDs(comments); len(ids) != 2 || ids[0] != second.ID || ids[1] != first.ID {
+		t.Fatalf("Expected [second, first], got: %v", ids)
+	}
+}
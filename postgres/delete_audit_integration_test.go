@@ -0,0 +1,53 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/repository"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestDeleteAudit(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	commentService := service.NewCommentService(repo)
+	var ids []string
+	for _, author := range []string{"alice", "bob"} {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "audit-1", UserID: author, Content: "Hello"})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		ids = append(ids, comment.ID)
+	}
+	reason := "Spam"
+
+	// Execute
+	if err := repo.DeleteComment(ctx, ids[0], "alice", nil); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+	if err := repo.RemoveComment(ctx, ids[1], "mod-1", &reason); err != nil {
+		t.Fatalf("Failed to remove comment: %v", err)
+	}
+	againErr := repo.RemoveComment(ctx, ids[1], "mod-1", &reason)
+
+	// Assert
+	comments, err := repo.GetCommentsByIDs(ctx, ids, true)
+	if err != nil || len(comments) != 2 {
+		t.Fatalf("Expected both deleted comments, got %d (err %v)", len(comments), err)
+	}
+	if by := comments[0].DeletedBy; by == nil || *by != "alice" || comments[0].DeleteReason != nil {
+		t.Errorf("Expected the author delete to record alice and no reason, got %v and %v", by, comments[0].DeleteReason)
+	}
+	if by := comments[1].DeletedBy; by == nil || *by != "mod-1" || comments[1].DeleteReason == nil || *comments[1].DeleteReason != "Spam" {
+		t.Errorf("Expected the removal to record mod-1 and Spam, got %v and %v", by, comments[1].DeleteReason)
+	}
+	if !errors.Is(againErr, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound removing a deleted comment, got %v", againErr)
+	}
+}
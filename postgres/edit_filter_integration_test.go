@@ -0,0 +1,114 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestEditSortAndFilters(t *testing.T) {
+	// Setup: comments edited zero, two and one times
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	commentService := service.NewCommentService(repo)
+
+	var seeded []*models.Comment
+	for i, edits := range []int{0, 2, 1} {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "edit-filters-1", UserID: "alice", Content: fmt.Sprintf("Comment %d", i)})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		for edit := 1; edit <= edits; edit++ {
+			content := fmt.Sprintf("Comment %d, edit %d", i, edit)
+			if err := commentService.UpdateComment(ctx, comment.ID, "alice", &models.UpdateCommentRequest{Content: &content}); err != nil {
+				t.Fatalf("Failed to edit comment: %v", err)
+			}
+		}
+		seeded = append(seeded, comment)
+	}
+	edited, two := true, 2
+
+	// Execute
+	sorted, err := commentService.GetCommentsByRoot(ctx, "edit-filters-1", &models.CommentFilter{SortBy: "edit_count", SortOrder: "desc"})
+	if err != nil {
+		t.Fatalf("Failed to sort by edit count: %v", err)
+	}
+	editedOnly, err := commentService.GetCommentsByRoot(ctx, "edit-filters-1", &models.CommentFilter{IsEdited: &edited})
+	if err != nil {
+		t.Fatalf("Failed to filter by is_edited: %v", err)
+	}
+	withVotes, _, err := commentService.GetCommentsWithUserVotes(ctx, "edit-filters-1", "bob", &models.CommentFilter{MinEdits: &two})
+	if err != nil {
+		t.Fatalf("Failed to filter comments with votes by min_edits: %v", err)
+	}
+
+	// Assert
+	want := []string{seeded[1].ID, seeded[2].ID, seeded[0].ID}
+	if len(sorted) != len(want) {
+		t.Fatalf("Expected %d comments, got %d", len(want), len(sorted))
+	}
+	for i, comment := range sorted {
+		if comment.ID != want[i] {
+			t.Errorf("Expected comment %d to have %d edits, got %d", i, 2-i, comment.EditCount)
+		}
+	}
+	if len(editedOnly) != 2 {
+		t.Errorf("Expected 2 edited comments, got %d", len(editedOnly))
+	}
+	if len(withVotes) != 1 || withVotes[0].ID != seeded[1].ID {
+		t.Errorf("Expected only the comment edited twice, got %d comments", len(withVotes))
+	}
+}
+
+func TestSortByContentUpdatedAt_NeverEditedSortOldest(t *testing.T) {
+	// Setup: one edited comment and two never edited, whose content_updated_at is NULL
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	commentService := service.NewCommentService(repo)
+
+	var seeded []*models.Comment
+	for i := 0; i < 3; i++ {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "edit-sort-1", UserID: "alice", Content: fmt.Sprintf("Comment %d", i)})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		seeded = append(seeded, comment)
+	}
+	content := "Comment 1, edited"
+	if err := commentService.UpdateComment(ctx, seeded[1].ID, "alice", &models.UpdateCommentRequest{Content: &content}); err != nil {
+		t.Fatalf("Failed to edit comment: %v", err)
+	}
+	unedited := []string{seeded[0].ID, seeded[2].ID}
+	if unedited[0] > unedited[1] {
+		unedited[0], unedited[1] = unedited[1], unedited[0]
+	}
+
+	for _, tc := range []struct {
+		order string
+		want  []string
+	}{
+		{order: "desc", want: []string{seeded[1].ID, unedited[1], unedited[0]}},
+		{order: "asc", want: []string{unedited[0], unedited[1], seeded[1].ID}},
+	} {
+		// Execute
+		sorted, err := commentService.GetCommentsByRoot(ctx, "edit-sort-1", &models.CommentFilter{SortBy: "content_updated_at", SortOrder: tc.order})
+		if err != nil {
+			t.Fatalf("Failed to sort by content_updated_at %s: %v", tc.order, err)
+		}
+
+		// Assert: NULLs sort as the oldest, with ties broken by ID
+		if len(sorted) != len(tc.want) {
+			t.Fatalf("Expected %d comments sorting %s, got %d", len(tc.want), tc.order, len(sorted))
+		}
+		for i, comment := range sorted {
+			if comment.ID != tc.want[i] {
+				t.Errorf("Sorting %s: expected comment %d to be %s, got %s", tc.order, i, tc.want[i], comment.ID)
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestUpdateComment_EditTracking(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	commentService := service.NewCommentService(repo)
+
+	media := "https://example.com/old.png"
+	created, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "edits-1", UserID: "alice", Content: "Original text", MediaURL: &media})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Execute: change the media, then the content and media together
+	newMedia := "https://example.com/new.png"
+	if err := repo.UpdateComment(ctx, created.ID, &models.UpdateCommentRequest{MediaURL: &newMedia}); err != nil {
+		t.Fatalf("Failed to update media: %v", err)
+	}
+	mediaOnly, err := repo.GetCommentByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	content := "Edited text"
+	otherMedia := "https://example.com/other.png"
+	if err := repo.UpdateComment(ctx, created.ID, &models.UpdateCommentRequest{Content: &content, MediaURL: &otherMedia}); err != nil {
+		t.Fatalf("Failed to update content: %v", err)
+	}
+	both, err := repo.GetCommentByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+
+	// Assert
+	if mediaOnly.IsEdited || mediaOnly.EditCount != 0 || mediaOnly.ContentUpdatedAt != nil {
+		t.Errorf("Expected a media-only change not to count as an edit, got is_edited=%v edit_count=%d", mediaOnly.IsEdited, mediaOnly.EditCount)
+	}
+	if !mediaOnly.UpdatedAt.After(created.UpdatedAt) || mediaOnly.MediaURL == nil || *mediaOnly.MediaURL != newMedia {
+		t.Errorf("Expected the new media and a later updated_at, got %v at %v", mediaOnly.MediaURL, mediaOnly.UpdatedAt)
+	}
+	if !both.IsEdited || both.EditCount != 1 || both.ContentUpdatedAt == nil {
+		t.Errorf("Expected one tracked edit, got is_edited=%v edit_count=%d", both.IsEdited, both.EditCount)
+	}
+	if both.OriginalContent == nil || *both.OriginalContent != "Original text" {
+		t.Errorf("Expected the original content kept, got: %v", both.OriginalContent)
+	}
+}
@@ -0,0 +1,58 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestExportComments_PathOrderAcrossPages(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	commentService := service.NewCommentService(repo)
+
+	parent, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "export-1", UserID: "alice", Content: "Top"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "export-1", ParentID: &parent.ID, UserID: "bob", Content: "Reply"}); err != nil {
+			t.Fatalf("Failed to create reply: %v", err)
+		}
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, parent.ID, "bob", models.VoteTypeUp); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	// Execute: page two at a time
+	var exported []*models.Comment
+	afterPath := ""
+	for {
+		page, err := repo.ExportComments(ctx, "export-1", afterPath, 2, false)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		exported = append(exported, page...)
+		if len(page) < 2 {
+			break
+		}
+		afterPath = page[len(page)-1].Path
+	}
+
+	// Assert
+	if len(exported) != 4 || exported[0].ID != parent.ID {
+		t.Fatalf("Expected the parent followed by 3 replies, got: %d comments", len(exported))
+	}
+	votes, err := repo.GetVotesForComments(ctx, []string{parent.ID})
+	if err != nil {
+		t.Fatalf("Failed to get votes: %v", err)
+	}
+	if len(votes[parent.ID]) != 1 || votes[parent.ID][0].UserID != "bob" {
+		t.Fatalf("Expected bob's vote, got: %+v", votes[parent.ID])
+	}
+}
@@ -0,0 +1,54 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/repository"
+)
+
+func TestHardDeleteComment(t *testing.T) {
+	// Setup
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	parent := &models.Comment{RootID: "product-1", UserID: "author", Content: "Parent"}
+	if err := repo.CreateComment(ctx, parent); err != nil {
+		t.Fatalf("Failed to create parent: %v", err)
+	}
+	leaf := &models.Comment{RootID: "product-1", ParentID: &parent.ID, UserID: "replier", Content: "Leaf"}
+	if err := repo.CreateComment(ctx, leaf); err != nil {
+		t.Fatalf("Failed to create leaf: %v", err)
+	}
+	if err := repo.UpdateVote(ctx, leaf.ID, "voter", models.VoteTypeUp); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	// Execute & Assert: the parent is blocked while the leaf exists
+	if err := repo.HardDeleteComment(ctx, parent.ID); !errors.Is(err, repository.ErrHasReplies) {
+		t.Fatalf("Expected ErrHasReplies, got: %v", err)
+	}
+
+	if err := repo.HardDeleteComment(ctx, leaf.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var rows int
+	if err := db.Get(&rows, `SELECT COUNT(*) FROM comments WHERE id = $1`, leaf.ID); err != nil || rows != 0 {
+		t.Fatalf("Expected the leaf row to be removed, got: %d (%v)", rows, err)
+	}
+	if err := db.Get(&rows, `SELECT COUNT(*) FROM votes WHERE comment_id = $1`, leaf.ID); err != nil || rows != 0 {
+		t.Fatalf("Expected the leaf's votes to be removed, got: %d (%v)", rows, err)
+	}
+	updated, err := repo.GetCommentByID(ctx, parent.ID)
+	if err != nil {
+		t.Fatalf("Failed to get parent: %v", err)
+	}
+	if updated.ReplyCount != 0 || updated.DescendantCount != 0 {
+		t.Fatalf("Expected parent counts to drop, got: %d replies, %d descendants", updated.ReplyCount, updated.DescendantCount)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/christopher18/commentific/v2/postgres"
+)
+
+func TestHealth_Reachable(t *testing.T) {
+	// Setup
+	_, db := newTestRepository(t)
+	provider := postgres.NewPostgresProvider(db)
+
+	// Execute
+	err := provider.Health()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected reachable database to be healthy, got: %v", err)
+	}
+}
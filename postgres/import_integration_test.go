@@ -0,0 +1,50 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+	"github.com/google/uuid"
+)
+
+func TestImportComments(t *testing.T) {
+	// Setup: a -> b -> c, listed children first
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	commentService := service.NewCommentService(repo)
+
+	created := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	a := &models.Comment{ID: uuid.New().String(), RootID: "legacy-1", UserID: "alice", Content: "Top", CreatedAt: created, UpdatedAt: created}
+	b := &models.Comment{ID: uuid.New().String(), RootID: "legacy-1", ParentID: &a.ID, UserID: "bob", Content: "Reply", CreatedAt: created.Add(time.Hour)}
+	c := &models.Comment{ID: uuid.New().String(), RootID: "legacy-1", ParentID: &b.ID, UserID: "carol", Content: "Nested", CreatedAt: created.Add(2 * time.Hour)}
+
+	// Execute
+	if err := commentService.ImportComments(ctx, []*models.Comment{c, b, a}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	nested, err := repo.GetCommentByID(ctx, c.ID)
+	if err != nil {
+		t.Fatalf("Failed to get nested comment: %v", err)
+	}
+	if nested.Path != a.ID+"."+b.ID+"."+c.ID || nested.Depth != 2 {
+		t.Fatalf("Expected path %s at depth 2, got: %s at depth %d", a.ID+"."+b.ID+"."+c.ID, nested.Path, nested.Depth)
+	}
+
+	top, err := repo.GetCommentByID(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("Failed to get top-level comment: %v", err)
+	}
+	if top.ReplyCount != 1 || top.DescendantCount != 2 {
+		t.Fatalf("Expected 1 reply and 2 descendants, got: %d, %d", top.ReplyCount, top.DescendantCount)
+	}
+	if !top.CreatedAt.Equal(created) || !top.UpdatedAt.Equal(created) {
+		t.Fatalf("Expected timestamps to be preserved, got: created %v, updated %v", top.CreatedAt, top.UpdatedAt)
+	}
+}
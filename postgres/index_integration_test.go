@@ -0,0 +1,112 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// explain returns the plan for query with sequential scans priced out, so the
+// planner shows which index it would use on a table bigger than the fixture's
+func explain(t *testing.T, db *sqlx.DB, query string) string {
+	t.Helper()
+	ctx := context.Background()
+
+	tx := db.MustBeginTx(ctx, nil)
+	defer tx.Rollback()
+	tx.MustExecContext(ctx, `SET LOCAL enable_seqscan = off`)
+
+	var plan []string
+	if err := tx.SelectContext(ctx, &plan, "EXPLAIN "+query); err != nil {
+		t.Fatalf("Failed to explain query: %v", err)
+	}
+	return strings.Join(plan, "\n")
+}
+
+func TestTreeQueriesUseIndexes(t *testing.T) {
+	// Setup
+	repo, db := newTestRepository(t)
+	target := seedWideThread(t, repo, "post-1", 20, 3)
+	db.MustExec(`ANALYZE comments`)
+
+	tests := []struct {
+		name  string
+		query string
+		index string
+	}{
+		{
+			name:  "tree by score",
+			query: `SELECT id FROM comments WHERE NOT is_deleted AND root_id = 'post-1' AND depth <= 10 AND status = 'approved' ORDER BY score DESC`,
+			index: "idx_comments_root_score_created",
+		},
+		{
+			name:  "list by creation time",
+			query: `SELECT id FROM comments WHERE NOT is_deleted AND root_id = 'post-1' ORDER BY created_at DESC LIMIT 50`,
+			index: "idx_comments_root_created",
+		},
+		{
+			name:  "subtree by path prefix",
+			query: `SELECT id FROM comments WHERE path LIKE '` + target.Path + `.%' AND NOT is_deleted`,
+			index: "idx_comments_path",
+		},
+		{
+			name:  "direct replies",
+			query: `SELECT id FROM comments WHERE NOT is_deleted AND parent_id = '` + target.ID + `'`,
+			index: "idx_comments_parent",
+		},
+		{
+			name:  "comments by user",
+			query: `SELECT id FROM comments WHERE NOT is_deleted AND user_id = 'author'`,
+			index: "idx_comments_user_id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Execute
+			plan := explain(t, db, tt.query)
+
+			// Assert
+			if !strings.Contains(plan, tt.index) {
+				t.Errorf("Expected the plan to use %s, got:\n%s", tt.index, plan)
+			}
+		})
+	}
+}
+
+func TestPollIndexCoversDeletedRows(t *testing.T) {
+	// Setup
+	_, db := newTestRepository(t)
+
+	// Execute
+	var indexes []struct {
+		Name       string `db:"indexname"`
+		Definition string `db:"indexdef"`
+	}
+	err := db.Select(&indexes, `
+		SELECT indexname, indexdef FROM pg_indexes
+		WHERE schemaname = current_schema() AND indexname IN ('idx_comments_root_updated', 'idx_comments_root_updated_all')`)
+	if err != nil {
+		t.Fatalf("Failed to list indexes: %v", err)
+	}
+
+	// Assert: 014 adds a full index beside 001's partial one
+	found := make(map[string]string)
+	for _, index := range indexes {
+		found[index.Name] = index.Definition
+	}
+	if def, ok := found["idx_comments_root_updated_all"]; !ok || strings.Contains(def, "WHERE") {
+		t.Errorf("Expected idx_comments_root_updated_all without a WHERE clause, got %q", def)
+	}
+	if def, ok := found["idx_comments_root_updated"]; !ok || !strings.Contains(def, "WHERE") {
+		t.Errorf("Expected 001's partial idx_comments_root_updated to remain, got %q", def)
+	}
+	plan := explain(t, db, `SELECT id FROM comments WHERE root_id = 'post-1' AND updated_at > NOW() - INTERVAL '1 hour' ORDER BY updated_at, id`)
+	if !strings.Contains(plan, "idx_comments_root_updated_all") {
+		t.Errorf("Expected polls to use idx_comments_root_updated_all, got:\n%s", plan)
+	}
+}
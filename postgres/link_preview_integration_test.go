@@ -0,0 +1,59 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/repository"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestSetLinkPreview_StoredAndClearedWithLink(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	link := "https://example.com/article"
+	comment, err := service.NewCommentService(repo).CreateComment(ctx, &models.CreateCommentRequest{RootID: "preview-1", UserID: "alice", Content: "Worth a read", LinkURL: &link})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	preview := &models.LinkPreview{URL: link, Title: "Gophers", ImageURL: "https://example.com/gopher.png", FetchedAt: time.Now().UTC().Truncate(time.Second)}
+
+	// Execute
+	if err := repo.SetLinkPreview(ctx, comment.ID, preview); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	stored, err := repo.GetCommentByID(ctx, comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	if stored.LinkPreview == nil || stored.LinkPreview.Title != "Gophers" || !stored.LinkPreview.FetchedAt.Equal(preview.FetchedAt) {
+		t.Fatalf("Expected the stored preview, got %+v", stored.LinkPreview)
+	}
+	withVotes, _, err := repo.GetCommentsWithUserVotes(ctx, "preview-1", "bob", nil)
+	if err != nil || len(withVotes) != 1 || withVotes[0].LinkPreview == nil {
+		t.Fatalf("Expected the preview alongside votes, got %+v (err %v)", withVotes, err)
+	}
+
+	// A preview for a link the comment no longer has is refused, and editing
+	// the link drops the old preview
+	stale := &models.LinkPreview{URL: "https://example.com/other", Title: "Other"}
+	if err := repo.SetLinkPreview(ctx, comment.ID, stale); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound for a preview of another link, got: %v", err)
+	}
+	newLink := "https://example.com/follow-up"
+	if err := repo.UpdateComment(ctx, comment.ID, &models.UpdateCommentRequest{LinkURL: &newLink}); err != nil {
+		t.Fatalf("Failed to update comment: %v", err)
+	}
+	stored, _ = repo.GetCommentByID(ctx, comment.ID)
+	if stored.LinkPreview != nil {
+		t.Fatalf("Expected the preview to be cleared with the link, got %+v", stored.LinkPreview)
+	}
+}
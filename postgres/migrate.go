@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/christopher18/commentific/v2/migrations"
+	"github.com/lib/pq"
+)
+
+// migrationNames matches the names in the migrations that must carry the
+// table prefix: the tables themselves, and the indexes, trigger functions and
+// constraints, whose names would otherwise collide between prefixes sharing a
+// schema
+var migrationNames = regexp.MustCompile(`\b(?:comments|votes|root_settings|user_blocks|subscriptions|idx_\w+|update_\w+|\w+_fkey|\w+_check)\b`)
+
+// templateMigration rewrites the names in a migration into the placeholders
+// tables.expand fills in: {comments} for a table and {prefix}idx_... for the
+// rest
+func templateMigration(sql string) string {
+	return migrationNames.ReplaceAllStringFunc(sql, func(name string) string {
+		if slices.Contains(tableNames, name) {
+			return "{" + name + "}"
+		}
+		return "{prefix}" + name
+	})
+}
+
+// migration is one of the up migrations in the migrations package
+type migration struct {
+	version int64
+	name    string
+	sql     string
+}
+
+// loadMigrations returns the up migrations in version order
+func loadMigrations() ([]migration, error) {
+	names, err := fs.Glob(migrations.FS, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+	loaded := make([]migration, 0, len(names))
+	for _, name := range names {
+		number, _, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseInt(number, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has no version number", name)
+		}
+		sql, err := fs.ReadFile(migrations.FS, name)
+		if err != nil {
+			return nil, err
+		}
+		loaded = append(loaded, migration{version: version, name: strings.TrimSuffix(name, ".up.sql"), sql: string(sql)})
+	}
+	slices.SortFunc(loaded, func(a, b migration) int { return cmp.Compare(a.version, b.version) })
+	return loaded, nil
+}
+
+// Migrate applies the migrations that haven't been applied yet
+func (p *PostgresProvider) Migrate() error {
+	return p.MigrateContext(context.Background())
+}
+
+// MigrateContext applies the migrations that haven't been applied yet, in
+// order and in a single transaction. Tables, indexes, trigger functions and
+// constraints are named with the provider's table prefix, so deployments with
+// different prefixes can share a schema. Applied versions are recorded in the
+// prefixed commentific_migrations table. A schema migrated by hand is adopted
+// when no versions are recorded yet but CheckSchema finds it complete.
+func (p *PostgresProvider) MigrateContext(ctx context.Context) (err error) {
+	pending, err := loadMigrations()
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	q := tableQueryer{queryer: tx, tables: p.tables}
+
+	// Instances starting together take turns; the later ones find nothing to do
+	if _, err := q.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('{prefix}commentific_migrations'))`); err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	_, err = q.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS {prefix}commentific_migrations (
+			version BIGINT PRIMARY KEY,
+			applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+	var applied []int64
+	if err := q.SelectContext(ctx, &applied, `SELECT version FROM {prefix}commentific_migrations`); err != nil {
+		return fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	if len(applied) == 0 {
+		var exists bool
+		if err := q.GetContext(ctx, &exists, `SELECT to_regclass('{comments}') IS NOT NULL`); err != nil {
+			return fmt.Errorf("failed to check for existing tables: %w", err)
+		}
+		if exists {
+			if err := p.checkSchema(ctx, q); err != nil {
+				return fmt.Errorf("%s exists but no migrations are recorded; apply the rest by hand before running Migrate: %w", p.tables.name("comments"), err)
+			}
+			for _, m := range pending {
+				applied = append(applied, m.version)
+			}
+			pending = nil
+		}
+	}
+
+	for _, m := range pending {
+		if slices.Contains(applied, m.version) {
+			continue
+		}
+		if _, err := q.ExecContext(ctx, templateMigration(m.sql)); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", m.name, err)
+		}
+		applied = append(applied, m.version)
+	}
+	_, err = q.ExecContext(ctx, `
+		INSERT INTO {prefix}commentific_migrations (version)
+		SELECT unnest($1::bigint[])
+		ON CONFLICT (version) DO NOTHING`, pq.Array(applied))
+	if err != nil {
+		return fmt.Errorf("failed to record migrations: %w", err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,92 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestCommentStatus_VisibilityAndReplyCounts(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	parent, err := service.NewCommentService(repo).CreateComment(ctx, &models.CreateCommentRequest{RootID: "moderated-1", UserID: "alice", Content: "Top"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	commentService := service.NewCommentServiceWithConfig(repo, &service.CommentServiceConfig{PreModeration: true})
+	reply, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "moderated-1", ParentID: &parent.ID, UserID: "bob", Content: "Held"})
+	if err != nil {
+		t.Fatalf("Failed to create reply: %v", err)
+	}
+
+	// Execute
+	rootID := "moderated-1"
+	public, err := repo.GetComments(ctx, &models.CommentFilter{RootID: &rootID})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	viewer := "bob"
+	own, err := repo.GetComments(ctx, &models.CommentFilter{RootID: &rootID, ViewerID: &viewer})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if len(public) != 1 || len(own) != 2 {
+		t.Fatalf("Expected 1 public comment and 2 for the author, got %d and %d", len(public), len(own))
+	}
+	queue, err := repo.GetModerationQueue(ctx, rootID, nil)
+	if err != nil || len(queue) != 1 || queue[0].ID != reply.ID || queue[0].Status != models.CommentStatusPending {
+		t.Fatalf("Expected the pending reply in the queue, got: %+v (err %v)", queue, err)
+	}
+	stored, _ := repo.GetCommentByID(ctx, parent.ID)
+	if stored.ReplyCount != 0 {
+		t.Fatalf("Expected pending replies to be left out of reply counts, got %d", stored.ReplyCount)
+	}
+
+	if err := repo.SetCommentStatus(ctx, reply.ID, models.CommentStatusApproved); err != nil {
+		t.Fatalf("Failed to approve reply: %v", err)
+	}
+	stored, _ = repo.GetCommentByID(ctx, parent.ID)
+	if stored.ReplyCount != 1 || stored.DescendantCount != 1 {
+		t.Fatalf("Expected approval to count the reply, got reply_count %d descendant_count %d", stored.ReplyCount, stored.DescendantCount)
+	}
+	if err := repo.RecalculateReplyCounts(ctx); err != nil {
+		t.Fatalf("Failed to recalculate reply counts: %v", err)
+	}
+	stored, _ = repo.GetCommentByID(ctx, parent.ID)
+	if stored.ReplyCount != 1 {
+		t.Fatalf("Expected the backfill to agree with the trigger, got reply_count %d", stored.ReplyCount)
+	}
+}
+
+func TestGetModerationQueue_IncludesQuarantined(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	for _, comment := range []*models.Comment{
+		{RootID: "moderated-2", UserID: "alice", Content: "Held", Status: models.CommentStatusPending},
+		{RootID: "moderated-2", UserID: "bob", Content: "Spam", Status: models.CommentStatusQuarantined},
+		{RootID: "moderated-2", UserID: "carol", Content: "Published"},
+	} {
+		if err := repo.CreateComment(ctx, comment); err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+	}
+
+	// Execute
+	queue, err := repo.GetModerationQueue(ctx, "moderated-2", nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(queue) != 2 || queue[0].Status != models.CommentStatusPending || queue[1].Status != models.CommentStatusQuarantined {
+		t.Fatalf("Expected the pending then the quarantined comment, got: %+v", queue)
+	}
+}
@@ -0,0 +1,55 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+func TestMoveComment(t *testing.T) {
+	// Setup: a -> b -> c, and a separate top-level comment d
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+
+	create := func(parentID *string) *models.Comment {
+		comment := &models.Comment{RootID: "product-1", ParentID: parentID, UserID: "author", Content: "Comment"}
+		if err := repo.CreateComment(ctx, comment); err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		return comment
+	}
+	a := create(nil)
+	b := create(&a.ID)
+	c := create(&b.ID)
+	d := create(nil)
+
+	// Execute
+	if err := repo.MoveComment(ctx, b.ID, d.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	child, err := repo.GetCommentByID(ctx, c.ID)
+	if err != nil {
+		t.Fatalf("Failed to get descendant: %v", err)
+	}
+	if child.Path != d.ID+"."+b.ID+"."+c.ID || child.Depth != 2 {
+		t.Fatalf("Expected descendant path rewritten, got: %s at depth %d", child.Path, child.Depth)
+	}
+
+	oldParent, _ := repo.GetCommentByID(ctx, a.ID)
+	newParent, _ := repo.GetCommentByID(ctx, d.ID)
+	if oldParent.ReplyCount != 0 || oldParent.DescendantCount != 0 {
+		t.Fatalf("Expected old parent to lose the subtree, got: %d replies, %d descendants", oldParent.ReplyCount, oldParent.DescendantCount)
+	}
+	if newParent.ReplyCount != 1 || newParent.DescendantCount != 2 {
+		t.Fatalf("Expected new parent to gain the subtree, got: %d replies, %d descendants", newParent.ReplyCount, newParent.DescendantCount)
+	}
+
+	if err := repo.MoveComment(ctx, d.ID, c.ID); err == nil {
+		t.Fatal("Expected error moving a comment under its own descendant, got nil")
+	}
+}
@@ -0,0 +1,23 @@
+package postgres
+
+import "testing"
+
+func TestDescendantPattern(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "8c1f.0d2e", want: `8c1f.0d2e.%`},
+		{path: "thread_1", want: `thread\_1.%`},
+		{path: "50%.off", want: `50\%.off.%`},
+		{path: `back\slash`, want: `back\\slash.%`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := descendantPattern(tt.path); got != tt.want {
+				t.Errorf("descendantPattern(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
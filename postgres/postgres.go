@@ -13,27 +13,32 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	_ "github.com/lib/pq"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // PostgresRepository implements the CommentRepository interface for PostgreSQL
 type PostgresRepository struct {
-	db *sqlx.DB
-	tx *sqlx.Tx
+	db     *sqlx.DB
+	tx     *sqlx.Tx
+	tracer trace.Tracer
+	tables *tables
 }
 
 // PostgresProvider implements the RepositoryProvider interface
 type PostgresProvider struct {
-	db *sqlx.DB
+	db     *sqlx.DB
+	tracer trace.Tracer
+	tables *tables
 }
 
 // NewPostgresProvider creates a new PostgreSQL repository provider
 func NewPostgresProvider(db *sqlx.DB) *PostgresProvider {
-	return &PostgresProvider{db: db}
+	return &PostgresProvider{db: db, tables: defaultTables}
 }
 
 // GetCommentRepository returns a PostgreSQL comment repository
 func (p *PostgresProvider) GetCommentRepository() repository.CommentRepository {
-	return &PostgresRepository{db: p.db}
+	return &PostgresRepository{db: p.db, tracer: p.tracer, tables: p.tables}
 }
 
 // Close closes the database connection
@@ -46,50 +51,45 @@ func (p *PostgresProvider) Health() error {
 	return p.db.Ping()
 }
 
-// Migrate runs database migrations
-func (p *PostgresProvider) Migrate() error {
-	// This would integrate with a migration tool like golang-migrate
-	// For now, we'll implement a simple check
-	var exists bool
-	err := p.db.QueryRow("SELECT EXISTS (SELECT FROM information_schema.tables WHERE table_name = 'comments')").Scan(&exists)
-	if err != nil {
-		return fmt.Errorf("failed to check if tables exist: %w", err)
-	}
-
-	if !exists {
-		return fmt.Errorf("database not migrated: comments table does not exist")
-	}
+// HealthContext is Health, giving up when ctx is done
+func (p *PostgresProvider) HealthContext(ctx context.Context) error {
+	return p.db.PingContext(ctx)
+}
 
-	return nil
+// Stats reports the state of the connection pool: its configured limit and
+// the connections open, in use and idle, along with how often callers have
+// had to wait for one
+func (p *PostgresProvider) Stats() sql.DBStats {
+	return p.db.Stats()
 }
 
-// getDB returns the appropriate database connection (transaction or regular)
-func (r *PostgresRepository) getDB() sqlx.ExtContext {
+// getDB returns the appropriate database connection (transaction or
+// regular). Queries name tables with placeholders such as {comments}, which
+// it fills in with the provider's table prefix.
+func (r *PostgresRepository) getDB() queryer {
 	if r.tx != nil {
-		return r.tx
+		return tableQueryer{queryer: r.tx, tables: r.tables}
 	}
-	return r.db
+	return tableQueryer{queryer: r.db, tables: r.tables}
 }
 
 // getQueryable returns a queryable interface that supports Get and Select methods
-func (r *PostgresRepository) getQueryable() interface {
-	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
-	QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row
-	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
-	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
-} {
-	if r.tx != nil {
-		return r.tx
-	}
-	return r.db
+func (r *PostgresRepository) getQueryable() queryer {
+	return r.getDB()
 }
 
 // CreateComment creates a new comment
-func (r *PostgresRepository) CreateComment(ctx context.Context, comment *models.Comment) error {
+func (r *PostgresRepository) CreateComment(ctx context.Context, comment *models.Comment) (err error) {
+	ctx, span := r.startSpan(ctx, "CreateComment", attrRootID.String(comment.RootID))
+	defer func() { endSpan(span, err) }()
+
 	// Generate ID if not provided
 	if comment.ID == "" {
 		comment.ID = uuid.New().String()
 	}
+	if err := repository.ValidateCommentID(comment.ID); err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
 
 	// Calculate path and depth
 	if comment.ParentID != nil {
@@ -111,38 +111,48 @@ func (r *PostgresRepository) CreateComment(ctx context.Context, comment *models.
 	}
 
 	query := `
-		INSERT INTO comments (id, root_id, parent_id, user_id, content, media_url, link_url, depth, path, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+		INSERT INTO {comments} (id, root_id, parent_id, user_id, content, media_url, link_url, depth, path, created_at, updated_at,
+		                      is_anonymous, display_name, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
 
 	comment.CreatedAt = time.Now()
 	comment.UpdatedAt = time.Now()
+	if comment.Status == "" {
+		comment.Status = models.CommentStatusApproved
+	}
 
-	_, err := r.getDB().ExecContext(ctx, query,
+	_, err = r.getDB().ExecContext(ctx, query,
 		comment.ID, comment.RootID, comment.ParentID, comment.UserID,
 		comment.Content, comment.MediaURL, comment.LinkURL, comment.Depth,
-		comment.Path, comment.CreatedAt, comment.UpdatedAt)
+		comment.Path, comment.CreatedAt, comment.UpdatedAt,
+		comment.IsAnonymous, comment.DisplayName, comment.Status)
 
 	if err != nil {
 		return fmt.Errorf("failed to create comment: %w", err)
 	}
+	comment.Version = 1
 
 	return nil
 }
 
 // GetCommentByID retrieves a comment by its ID
-func (r *PostgresRepository) GetCommentByID(ctx context.Context, id string) (*models.Comment, error) {
+func (r *PostgresRepository) GetCommentByID(ctx context.Context, id string) (_ *models.Comment, err error) {
+	ctx, span := r.startSpan(ctx, "GetCommentByID", attrCommentID.String(id))
+	defer func() { endSpan(span, err) }()
+
 	query := `
 		SELECT id, root_id, parent_id, user_id, content, media_url, link_url, 
 		       upvotes, downvotes, score, depth, path, is_deleted, is_edited,
-		       edit_count, original_content, created_at, updated_at, content_updated_at
-		FROM comments 
+		       edit_count, original_content, created_at, updated_at, content_updated_at, decayed_score,
+		       reply_count, descendant_count, is_anonymous, display_name, status, link_preview, version, deleted_by, delete_reason
+		FROM {comments} 
 		WHERE id = $1 AND NOT is_deleted`
 
 	comment := &models.Comment{}
-	err := r.getQueryable().GetContext(ctx, comment, query, id)
+	err = r.getQueryable().GetContext(ctx, comment, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("comment not found")
+			return nil, repository.ErrNotFound
 		}
 		return nil, fmt.Errorf("failed to get comment: %w", err)
 	}
@@ -150,8 +160,93 @@ func (r *PostgresRepository) GetCommentByID(ctx context.Context, id string) (*mo
 	return comment, nil
 }
 
+// GetCommentForUpdate reads a comment like GetCommentByID and locks its row
+// FOR UPDATE, so inside a transaction nobody else can change or delete it
+// until the transaction ends. Outside one the lock is released at once.
+func (r *PostgresRepository) GetCommentForUpdate(ctx context.Context, id string) (_ *models.Comment, err error) {
+	ctx, span := r.startSpan(ctx, "GetCommentForUpdate", attrCommentID.String(id))
+	defer func() { endSpan(span, err) }()
+
+	query := `
+		SELECT id, root_id, parent_id, user_id, content, media_url, link_url,
+		       upvotes, downvotes, score, depth, path, is_deleted, is_edited,
+		       edit_count, original_content, created_at, updated_at, content_updated_at, decayed_score,
+		       reply_count, descendant_count, is_anonymous, display_name, status, link_preview, version, deleted_by, delete_reason
+		FROM {comments}
+		WHERE id = $1 AND NOT is_deleted
+		FOR UPDATE`
+
+	comment := &models.Comment{}
+	err = r.getQueryable().GetContext(ctx, comment, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to lock comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// GetCommentsByIDs retrieves many comments in a single query, returned in the
+// order of ids. IDs that do not exist (or are deleted, unless includeDeleted) are skipped.
+func (r *PostgresRepository) GetCommentsByIDs(ctx context.Context, ids []string, includeDeleted bool) (_ []*models.Comment, err error) {
+	ctx, span := r.startSpan(ctx, "GetCommentsByIDs")
+	defer func() { endSpan(span, err) }()
+
+	// Comment IDs are UUIDs; anything else cannot match and would fail the cast
+	valid := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err == nil {
+			valid = append(valid, id)
+		}
+	}
+	if len(valid) == 0 {
+		return []*models.Comment{}, nil
+	}
+
+	query := `
+		SELECT id, root_id, parent_id, user_id, content, media_url, link_url,
+		       upvotes, downvotes, score, depth, path, is_deleted, is_edited,
+		       edit_count, original_content, created_at, updated_at, content_updated_at, decayed_score,
+		       reply_count, descendant_count, is_anonymous, display_name, status, link_preview, version, deleted_by, delete_reason
+		FROM {comments} 
+		WHERE id = ANY($1::uuid[])`
+	if !includeDeleted {
+		query += " AND NOT is_deleted"
+	}
+
+	found := []*models.Comment{}
+	err = r.getQueryable().SelectContext(ctx, &found, query, pq.Array(valid))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	return orderByIDs(found, ids), nil
+}
+
+// orderByIDs arranges comments in the order of ids, skipping IDs with no match
+func orderByIDs(comments []*models.Comment, ids []string) []*models.Comment {
+	byID := make(map[string]*models.Comment, len(comments))
+	for _, comment := range comments {
+		byID[comment.ID] = comment
+	}
+
+	ordered := make([]*models.Comment, 0, len(comments))
+	for _, id := range ids {
+		if comment, exists := byID[id]; exists {
+			ordered = append(ordered, comment)
+			delete(byID, id) // Return duplicates once
+		}
+	}
+	return ordered
+}
+
 // UpdateComment updates a comment's content
-func (r *PostgresRepository) UpdateComment(ctx context.Context, id string, updates *models.UpdateCommentRequest) error {
+func (r *PostgresRepository) UpdateComment(ctx context.Context, id string, updates *models.UpdateCommentRequest) (err error) {
+	ctx, span := r.startSpan(ctx, "UpdateComment", attrCommentID.String(id))
+	defer func() { endSpan(span, err) }()
+
 	setParts := []string{}
 	args := []interface{}{}
 	argIndex := 1
@@ -169,7 +264,8 @@ func (r *PostgresRepository) UpdateComment(ctx context.Context, id string, updat
 	}
 
 	if updates.LinkURL != nil {
-		setParts = append(setParts, fmt.Sprintf("link_url = $%d", argIndex))
+		// The preview belonged to the old link
+		setParts = append(setParts, fmt.Sprintf("link_url = $%d", argIndex), fmt.Sprintf("link_preview = CASE WHEN link_url IS DISTINCT FROM $%d THEN NULL ELSE link_preview END", argIndex))
 		args = append(args, *updates.LinkURL)
 		argIndex++
 	}
@@ -178,13 +274,19 @@ func (r *PostgresRepository) UpdateComment(ctx context.Context, id string, updat
 		return nil // Nothing to update
 	}
 
-	setParts = append(setParts, fmt.Sprintf("updated_at = $%d", argIndex))
+	setParts = append(setParts, fmt.Sprintf("updated_at = $%d", argIndex), "version = version + 1")
 	args = append(args, time.Now())
 	argIndex++
 
-	query := fmt.Sprintf("UPDATE comments SET %s WHERE id = $%d AND NOT is_deleted",
+	query := fmt.Sprintf("UPDATE {comments} SET %s WHERE id = $%d AND NOT is_deleted",
 		strings.Join(setParts, ", "), argIndex)
 	args = append(args, id)
+	argIndex++
+
+	if updates.Version != nil {
+		query += fmt.Sprintf(" AND version = $%d", argIndex)
+		args = append(args, *updates.Version)
+	}
 
 	result, err := r.getDB().ExecContext(ctx, query, args...)
 	if err != nil {
@@ -197,17 +299,33 @@ func (r *PostgresRepository) UpdateComment(ctx context.Context, id string, updat
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("comment not found or already deleted")
+		if updates.Version != nil {
+			// Tell a stale version apart from a missing comment
+			var exists bool
+			err = r.getQueryable().GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM {comments} WHERE id = $1 AND NOT is_deleted)`, id)
+			if err != nil {
+				return fmt.Errorf("failed to check comment: %w", err)
+			}
+			if exists {
+				return repository.ErrVersionConflict
+			}
+		}
+		return fmt.Errorf("%w or already deleted", repository.ErrNotFound)
 	}
 
 	return nil
 }
 
-// DeleteComment soft deletes a comment
-func (r *PostgresRepository) DeleteComment(ctx context.Context, id string, userID string) error {
-	query := `UPDATE comments SET is_deleted = true, updated_at = $1 WHERE id = $2 AND user_id = $3 AND NOT is_deleted`
+// DeleteComment soft deletes a comment on behalf of its author
+func (r *PostgresRepository) DeleteComment(ctx context.Context, id string, userID string, reason *string) (err error) {
+	ctx, span := r.startSpan(ctx, "DeleteComment", attrCommentID.String(id))
+	defer func() { endSpan(span, err) }()
+
+	query := `
+		UPDATE {comments} SET is_deleted = true, updated_at = $1, deleted_by = $3, delete_reason = $4
+		WHERE id = $2 AND user_id = $3 AND NOT is_deleted`
 
-	result, err := r.getDB().ExecContext(ctx, query, time.Now(), id, userID)
+	result, err := r.getDB().ExecContext(ctx, query, time.Now(), id, userID, reason)
 	if err != nil {
 		return fmt.Errorf("failed to delete comment: %w", err)
 	}
@@ -218,19 +336,109 @@ func (r *PostgresRepository) DeleteComment(ctx context.Context, id string, userI
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("comment not found, already deleted, or user not authorized")
+		return fmt.Errorf("%w, already deleted, or user not authorized", repository.ErrNotFound)
+	}
+
+	return nil
+}
+
+// RemoveComment soft deletes a comment whoever wrote it, recording the
+// moderator who removed it
+func (r *PostgresRepository) RemoveComment(ctx context.Context, id string, moderatorID string, reason *string) (err error) {
+	ctx, span := r.startSpan(ctx, "RemoveComment", attrCommentID.String(id))
+	defer func() { endSpan(span, err) }()
+
+	query := `
+		UPDATE {comments} SET is_deleted = true, updated_at = $1, deleted_by = $3, delete_reason = $4
+		WHERE id = $2 AND NOT is_deleted`
+
+	result, err := r.getDB().ExecContext(ctx, query, time.Now(), id, moderatorID, reason)
+	if err != nil {
+		return fmt.Errorf("failed to remove comment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w or already deleted", repository.ErrNotFound)
+	}
+
+	return nil
+}
+
+// HardDeleteComment permanently removes a comment, soft deleted or not, along
+// with its votes. Comments that still have replies (including soft-deleted
+// ones) are refused with ErrHasReplies. Run it inside a transaction so the
+// reply count adjustment and the delete land together.
+func (r *PostgresRepository) HardDeleteComment(ctx context.Context, id string) (err error) {
+	ctx, span := r.startSpan(ctx, "HardDeleteComment", attrCommentID.String(id))
+	defer func() { endSpan(span, err) }()
+
+	var target struct {
+		ParentID  *string              `db:"parent_id"`
+		Path      string               `db:"path"`
+		IsDeleted bool                 `db:"is_deleted"`
+		Status    models.CommentStatus `db:"status"`
+	}
+	err = r.getQueryable().GetContext(ctx, &target, `SELECT parent_id, path, is_deleted, status FROM {comments} WHERE id = $1 FOR UPDATE`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return repository.ErrNotFound
+		}
+		return fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	var hasReplies bool
+	err = r.getQueryable().GetContext(ctx, &hasReplies, `SELECT EXISTS (SELECT 1 FROM {comments} WHERE parent_id = $1)`, id)
+	if err != nil {
+		return fmt.Errorf("failed to check for replies: %w", err)
+	}
+	if hasReplies {
+		return repository.ErrHasReplies
+	}
+
+	// The reply count trigger only fires on updates, so undo a counted comment's counts here
+	if !target.IsDeleted && target.Status == models.CommentStatusApproved && target.ParentID != nil {
+		_, err = r.getDB().ExecContext(ctx, `UPDATE {comments} SET reply_count = reply_count - 1 WHERE id = $1`, *target.ParentID)
+		if err != nil {
+			return fmt.Errorf("failed to update parent reply count: %w", err)
+		}
+		_, err = r.getDB().ExecContext(ctx, `
+			UPDATE {comments} SET descendant_count = descendant_count - 1
+			WHERE id = ANY(string_to_array($1, '.')::uuid[]) AND id <> $2`,
+			target.Path, id)
+		if err != nil {
+			return fmt.Errorf("failed to update ancestor counts: %w", err)
+		}
+	}
+
+	// Votes are removed in the same statement, as in PurgeDeletedComments
+	_, err = r.getDB().ExecContext(ctx, `
+		WITH removed_votes AS (
+			DELETE FROM {votes} WHERE comment_id = $1
+		)
+		DELETE FROM {comments} WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to hard delete comment: %w", err)
 	}
 
 	return nil
 }
 
 // GetComments retrieves comments based on filter
-func (r *PostgresRepository) GetComments(ctx context.Context, filter *models.CommentFilter) ([]*models.Comment, error) {
+func (r *PostgresRepository) GetComments(ctx context.Context, filter *models.CommentFilter) (_ []*models.Comment, err error) {
+	ctx, span := r.startSpan(ctx, "GetComments")
+	defer func() { endSpan(span, err) }()
+
 	query := `
 		SELECT id, root_id, parent_id, user_id, content, media_url, link_url,
 		       upvotes, downvotes, score, depth, path, is_deleted, is_edited,
-		       edit_count, original_content, created_at, updated_at, content_updated_at
-		FROM comments 
+		       edit_count, original_content, created_at, updated_at, content_updated_at, decayed_score,
+		       reply_count, descendant_count, is_anonymous, display_name, status, link_preview, version, deleted_by, delete_reason
+		FROM {comments} 
 		WHERE NOT is_deleted`
 
 	args := []interface{}{}
@@ -260,6 +468,22 @@ func (r *PostgresRepository) GetComments(ctx context.Context, filter *models.Com
 		argIndex++
 	}
 
+	if filter.TopLevelOnly {
+		query += " AND depth = 0"
+	}
+
+	if filter.CreatedAfter != nil {
+		query += fmt.Sprintf(" AND created_at >= $%d", argIndex)
+		args = append(args, *filter.CreatedAfter)
+		argIndex++
+	}
+
+	if filter.CreatedBefore != nil {
+		query += fmt.Sprintf(" AND created_at < $%d", argIndex)
+		args = append(args, *filter.CreatedBefore)
+		argIndex++
+	}
+
 	if filter.IsEdited != nil {
 		query += fmt.Sprintf(" AND is_edited = $%d", argIndex)
 		args = append(args, *filter.IsEdited)
@@ -278,21 +502,25 @@ func (r *PostgresRepository) GetComments(ctx context.Context, filter *models.Com
 		argIndex++
 	}
 
-	// Add sorting
-	sortBy := "created_at"
-	if filter.SortBy != "" {
-		switch filter.SortBy {
-		case "score", "created_at", "updated_at", "content_updated_at", "edit_count":
-			sortBy = filter.SortBy
-		}
+	if filter.Search != nil {
+		query += fmt.Sprintf(" AND to_tsvector('english', content) @@ plainto_tsquery('english', $%d)", argIndex)
+		args = append(args, *filter.Search)
+		argIndex++
 	}
 
-	sortOrder := "DESC"
-	if filter.SortOrder == "asc" {
-		sortOrder = "ASC"
+	// Comments awaiting moderation are only shown to their author, and
+	// comments by users the viewer blocked not at all
+	if filter.ViewerID != nil {
+		query += fmt.Sprintf(" AND (status = 'approved' OR user_id = $%d)", argIndex)
+		query += fmt.Sprintf(" AND %s", notBlockedClause("user_id", argIndex))
+		args = append(args, *filter.ViewerID)
+		argIndex++
+	} else {
+		query += " AND status = 'approved'"
 	}
 
-	query += fmt.Sprintf(" ORDER BY %s %s", sortBy, sortOrder)
+	// Add sorting
+	query += orderByClause(filter.SortBy, filter.SortOrder, "")
 
 	// Add pagination
 	if filter.Limit != nil {
@@ -308,7 +536,7 @@ func (r *PostgresRepository) GetComments(ctx context.Context, filter *models.Com
 	}
 
 	comments := []*models.Comment{}
-	err := r.getQueryable().SelectContext(ctx, &comments, query, args...)
+	err = r.getQueryable().SelectContext(ctx, &comments, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get comments: %w", err)
 	}
@@ -316,6 +544,60 @@ func (r *PostgresRepository) GetComments(ctx context.Context, filter *models.Com
 	return comments, nil
 }
 
+// sortColumns are the stored columns comments can be sorted by
+var sortColumns = map[string]bool{
+	"score":              true,
+	"created_at":         true,
+	"updated_at":         true,
+	"content_updated_at": true,
+	"edit_count":         true,
+}
+
+// rankingExpressions maps computed sort options to SQL expressions, matching
+// the formulas in the ranking package. %[1]s is replaced with the comments
+// table alias. They only read stored columns, never NOW(), so ordering is
+// stable across the pages of a request.
+var rankingExpressions = map[string]string{
+	"hot": "(SIGN(%[1]sscore) * LOG(GREATEST(ABS(%[1]sscore), 1)) + (EXTRACT(EPOCH FROM %[1]screated_at) - 1134028003) / 45000)",
+	// Wilson lower bound with z = 1.96, written in terms of counts: n = up + down
+	"best": `(CASE WHEN %[1]supvotes + %[1]sdownvotes = 0 THEN 0 ELSE
+		(%[1]supvotes::float8 / (%[1]supvotes + %[1]sdownvotes) + 1.9208 / (%[1]supvotes + %[1]sdownvotes)
+		 - 1.96 * SQRT(%[1]supvotes::float8 * %[1]sdownvotes / (%[1]supvotes + %[1]sdownvotes) + 0.9604) / (%[1]supvotes + %[1]sdownvotes))
+		/ (1 + 3.8416 / (%[1]supvotes + %[1]sdownvotes)) END)`,
+	// Comments the decay job has not reached yet fall back to their raw score
+	"decayed": "COALESCE(%[1]sdecayed_score, %[1]sscore)",
+	"controversial": `(CASE WHEN %[1]supvotes = 0 OR %[1]sdownvotes = 0 THEN 0 ELSE
+		POWER((%[1]supvotes + %[1]sdownvotes)::float8,
+		      LEAST(%[1]supvotes, %[1]sdownvotes)::float8 / GREATEST(%[1]supvotes, %[1]sdownvotes)) END)`,
+}
+
+// orderByClause builds a safe ORDER BY clause from user-supplied sort options,
+// falling back to created_at for unknown fields. Ties are broken by ID so
+// pages are deterministic.
+func orderByClause(sortBy, sortOrder, alias string) string {
+	direction := "DESC"
+	if sortOrder == "asc" {
+		direction = "ASC"
+	}
+
+	if expr, ok := rankingExpressions[sortBy]; ok {
+		return fmt.Sprintf(" ORDER BY %s %s, %sid", fmt.Sprintf(expr, alias), direction, alias)
+	}
+
+	if !sortColumns[sortBy] {
+		sortBy = "created_at"
+	}
+	nulls := ""
+	if sortBy == "content_updated_at" {
+		// Never-edited comments sort as the oldest, as in the memory repository
+		nulls = " NULLS LAST"
+		if direction == "ASC" {
+			nulls = " NULLS FIRST"
+		}
+	}
+	return fmt.Sprintf(" ORDER BY %s%s %s%s, %sid", alias, sortBy, direction, nulls, alias)
+}
+
 // GetCommentsByRootID retrieves comments for a specific root
 func (r *PostgresRepository) GetCommentsByRootID(ctx context.Context, rootID string, filter *models.CommentFilter) ([]*models.Comment, error) {
 	if filter == nil {
@@ -325,6 +607,34 @@ func (r *PostgresRepository) GetCommentsByRootID(ctx context.Context, rootID str
 	return r.GetComments(ctx, filter)
 }
 
+// GetCommentsSince retrieves up to limit of the root's comments created or
+// updated after since, in (updated_at, id) order. With afterID set, comments
+// updated at since itself with a greater ID are included too, so a page cut
+// in the middle of a tie resumes where it stopped. Soft-deleted comments are
+// included so callers learn of deletions, but only approved ones: a comment
+// deleted before it was ever approved was never shown to anyone.
+func (r *PostgresRepository) GetCommentsSince(ctx context.Context, rootID string, since time.Time, afterID string, limit int) (_ []*models.Comment, err error) {
+	ctx, span := r.startSpan(ctx, "GetCommentsSince", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	query := `
+		SELECT id, root_id, parent_id, user_id, content, media_url, link_url,
+		       upvotes, downvotes, score, depth, path, is_deleted, is_edited,
+		       edit_count, original_content, created_at, updated_at, content_updated_at, decayed_score,
+		       reply_count, descendant_count, is_anonymous, display_name, status, link_preview, version, deleted_by, delete_reason
+		FROM {comments}
+		WHERE root_id = $1 AND status = 'approved' AND updated_at >= $2
+		  AND (updated_at > $2 OR ($3 <> '' AND updated_at = $2 AND id::text > $3))
+		ORDER BY updated_at, id
+		LIMIT NULLIF($4, 0)`
+
+	var comments []*models.Comment
+	if err = r.getQueryable().SelectContext(ctx, &comments, query, rootID, since, afterID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get comments since %s: %w", since.Format(time.RFC3339), err)
+	}
+	return comments, nil
+}
+
 // GetCommentsByUserID retrieves comments by a specific user
 func (r *PostgresRepository) GetCommentsByUserID(ctx context.Context, userID string, filter *models.CommentFilter) ([]*models.Comment, error) {
 	if filter == nil {
@@ -334,15 +644,38 @@ func (r *PostgresRepository) GetCommentsByUserID(ctx context.Context, userID str
 	return r.GetComments(ctx, filter)
 }
 
-// GetCommentChildren retrieves child comments up to maxDepth
-func (r *PostgresRepository) GetCommentChildren(ctx context.Context, parentID string, maxDepth int) ([]*models.Comment, error) {
+// GetDirectChildren retrieves the immediate replies to a comment, without
+// descending further into the subtree
+func (r *PostgresRepository) GetDirectChildren(ctx context.Context, parentID string, filter *models.CommentFilter) ([]*models.Comment, error) {
+	if filter == nil {
+		filter = &models.CommentFilter{}
+	}
+	filter.ParentID = &parentID
+	return r.GetComments(ctx, filter)
+}
+
+// descendantPattern returns a LIKE pattern matching the paths below path.
+// Paths are IDs joined by dots, so any LIKE wildcard or escape character in
+// an ID is escaped to keep it from matching another comment's subtree.
+func descendantPattern(path string) string {
+	return likeEscaper.Replace(path) + ".%"
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// GetCommentChildren retrieves child comments up to maxDepth in path order,
+// paginated by filter.Limit and filter.Offset
+func (r *PostgresRepository) GetCommentChildren(ctx context.Context, parentID string, maxDepth int, filter *models.CommentFilter) (_ []*models.Comment, err error) {
+	ctx, span := r.startSpan(ctx, "GetCommentChildren", attrCommentID.String(parentID))
+	defer func() { endSpan(span, err) }()
+
 	query := `
 		SELECT id, root_id, parent_id, user_id, content, media_url, link_url,
 		       upvotes, downvotes, score, depth, path, is_deleted, is_edited,
-		       edit_count, original_content, created_at, updated_at, content_updated_at
-		FROM comments 
-		WHERE path LIKE $1 AND NOT is_deleted AND depth <= $2
-		ORDER BY path, created_at`
+		       edit_count, original_content, created_at, updated_at, content_updated_at, decayed_score,
+		       reply_count, descendant_count, is_anonymous, display_name, status, link_preview, version, deleted_by, delete_reason
+		FROM {comments} 
+		WHERE path LIKE $1 AND NOT is_deleted AND depth <= $2`
 
 	// Get parent path first
 	parent, err := r.GetCommentByID(ctx, parentID)
@@ -350,11 +683,34 @@ func (r *PostgresRepository) GetCommentChildren(ctx context.Context, parentID st
 		return nil, fmt.Errorf("failed to get parent comment: %w", err)
 	}
 
-	pathPattern := parent.Path + ".%"
+	pathPattern := descendantPattern(parent.Path)
 	maxAllowedDepth := parent.Depth + maxDepth
+	args := []interface{}{pathPattern, maxAllowedDepth}
+
+	// Comments awaiting moderation are only shown to their author, and
+	// comments by users the viewer blocked not at all
+	if filter != nil && filter.ViewerID != nil {
+		args = append(args, *filter.ViewerID)
+		query += fmt.Sprintf(" AND (status = 'approved' OR user_id = $%d)", len(args))
+		query += fmt.Sprintf(" AND %s", notBlockedClause("user_id", len(args)))
+	} else {
+		query += " AND status = 'approved'"
+	}
+	query += " ORDER BY path, created_at"
+
+	if filter != nil {
+		if filter.Limit != nil {
+			args = append(args, *filter.Limit)
+			query += fmt.Sprintf(" LIMIT $%d", len(args))
+		}
+		if filter.Offset != nil {
+			args = append(args, *filter.Offset)
+			query += fmt.Sprintf(" OFFSET $%d", len(args))
+		}
+	}
 
 	comments := []*models.Comment{}
-	err = r.getQueryable().SelectContext(ctx, &comments, query, pathPattern, maxAllowedDepth)
+	err = r.getQueryable().SelectContext(ctx, &comments, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get comment children: %w", err)
 	}
@@ -362,8 +718,39 @@ func (r *PostgresRepository) GetCommentChildren(ctx context.Context, parentID st
 	return comments, nil
 }
 
+// ExportComments pages through every comment in a root ordered by path,
+// compared byte-wise so a parent always precedes its replies. Pass the last
+// path of the previous page as afterPath, or "" to start.
+func (r *PostgresRepository) ExportComments(ctx context.Context, rootID, afterPath string, limit int, includeDeleted bool) (_ []*models.Comment, err error) {
+	ctx, span := r.startSpan(ctx, "ExportComments", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	query := `
+		SELECT id, root_id, parent_id, user_id, content, media_url, link_url,
+		       upvotes, downvotes, score, depth, path, is_deleted, is_edited,
+		       edit_count, original_content, created_at, updated_at, content_updated_at, decayed_score,
+		       reply_count, descendant_count, is_anonymous, display_name, status, link_preview, version, deleted_by, delete_reason
+		FROM {comments}
+		WHERE root_id = $1 AND path COLLATE "C" > $2`
+	if !includeDeleted {
+		query += " AND NOT is_deleted"
+	}
+	query += ` ORDER BY path COLLATE "C" LIMIT $3`
+
+	comments := []*models.Comment{}
+	err = r.getQueryable().SelectContext(ctx, &comments, query, rootID, afterPath, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export comments: %w", err)
+	}
+
+	return comments, nil
+}
+
 // GetCommentTree builds a hierarchical tree structure
-func (r *PostgresRepository) GetCommentTree(ctx context.Context, rootID string, maxDepth int, sortBy string) ([]*models.CommentTree, error) {
+func (r *PostgresRepository) GetCommentTree(ctx context.Context, rootID string, maxDepth int, sortBy string) (_ []*models.CommentTree, err error) {
+	ctx, span := r.startSpan(ctx, "GetCommentTree", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
 	// Get all comments for the root up to maxDepth
 	filter := &models.CommentFilter{
 		RootID:   &rootID,
@@ -380,8 +767,60 @@ func (r *PostgresRepository) GetCommentTree(ctx context.Context, rootID string,
 	return r.buildCommentTree(comments), nil
 }
 
+// GetCommentSubtree fetches one comment and its replies up to maxDepth levels
+// below it. Where GetCommentTree loads the whole root with a flat query, this
+// walks parent_id with a recursive CTE starting at the comment, so a deep
+// subtree of a wide thread never reads the comment's unrelated siblings.
+func (r *PostgresRepository) GetCommentSubtree(ctx context.Context, commentID string, maxDepth int, sortBy string) (_ *models.CommentTree, err error) {
+	ctx, span := r.startSpan(ctx, "GetCommentSubtree", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
+
+	// Comment IDs are UUIDs; anything else cannot match and would fail the cast
+	if _, err := uuid.Parse(commentID); err != nil {
+		return nil, repository.ErrNotFound
+	}
+
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT c.*, 0 AS level
+			FROM {comments} c
+			WHERE c.id = $1 AND NOT c.is_deleted AND c.status = 'approved'
+			UNION ALL
+			SELECT c.*, s.level + 1
+			FROM {comments} c
+			JOIN subtree s ON c.parent_id = s.id
+			WHERE s.level < $2 AND NOT c.is_deleted AND c.status = 'approved'
+		)
+		SELECT id, root_id, parent_id, user_id, content, media_url, link_url,
+		       upvotes, downvotes, score, depth, path, is_deleted, is_edited,
+		       edit_count, original_content, created_at, updated_at, content_updated_at, decayed_score,
+		       reply_count, descendant_count, is_anonymous, display_name, status, link_preview, version, deleted_by, delete_reason
+		FROM subtree`
+	query += orderByClause(sortBy, "", "")
+
+	comments := []*models.Comment{}
+	err = r.getQueryable().SelectContext(ctx, &comments, query, commentID, maxDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment subtree: %w", err)
+	}
+
+	nodes, _ := r.linkCommentTree(comments)
+	node, ok := nodes[commentID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return node, nil
+}
+
 // buildCommentTree converts flat comments to tree structure
 func (r *PostgresRepository) buildCommentTree(comments []*models.Comment) []*models.CommentTree {
+	_, roots := r.linkCommentTree(comments)
+	return roots
+}
+
+// linkCommentTree makes a node for each comment and attaches it to its
+// parent's node, returning the nodes by ID and those of top-level comments
+func (r *PostgresRepository) linkCommentTree(comments []*models.Comment) (map[string]*models.CommentTree, []*models.CommentTree) {
 	commentMap := make(map[string]*models.CommentTree)
 	var roots []*models.CommentTree
 
@@ -406,28 +845,37 @@ func (r *PostgresRepository) buildCommentTree(comments []*models.Comment) []*mod
 		}
 	}
 
-	return roots
+	return commentMap, roots
 }
 
-// GetCommentPath retrieves the path from root to a specific comment
-func (r *PostgresRepository) GetCommentPath(ctx context.Context, commentID string) ([]*models.Comment, error) {
+// GetCommentPath retrieves the path from root to a specific comment. The IDs
+// come from the comment's materialized path; a path that doesn't match the
+// comment's depth falls back to following parent links.
+func (r *PostgresRepository) GetCommentPath(ctx context.Context, commentID string) (_ []*models.Comment, err error) {
+	ctx, span := r.startSpan(ctx, "GetCommentPath", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
+
 	comment, err := r.GetCommentByID(ctx, commentID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse path and get all comments in the path
-	pathParts := strings.Split(comment.Path, ".")
-	if len(pathParts) == 0 {
-		return []*models.Comment{comment}, nil
+	pathParts, ok := pathIDs(comment)
+	if !ok {
+		ancestors, err := r.ancestorsOf(ctx, comment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get comment path: %w", err)
+		}
+		return append(ancestors, comment), nil
 	}
 
 	query := `
 		SELECT id, root_id, parent_id, user_id, content, media_url, link_url,
 		       upvotes, downvotes, score, depth, path, is_deleted, is_edited,
-		       edit_count, original_content, created_at, updated_at, content_updated_at
-		FROM comments 
-		WHERE id = ANY($1) AND NOT is_deleted
+		       edit_count, original_content, created_at, updated_at, content_updated_at, decayed_score,
+		       reply_count, descendant_count, is_anonymous, display_name, status, link_preview, version, deleted_by, delete_reason
+		FROM {comments} 
+		WHERE id = ANY($1::uuid[]) AND NOT is_deleted
 		ORDER BY depth`
 
 	comments := []*models.Comment{}
@@ -439,6 +887,147 @@ func (r *PostgresRepository) GetCommentPath(ctx context.Context, commentID strin
 	return comments, nil
 }
 
+// pathIDs splits a comment's path into the IDs from the top-level ancestor
+// down to the comment. ok is false when the path can't be trusted: it has the
+// wrong number of segments for the comment's depth, doesn't end at the
+// comment, or holds something other than UUIDs.
+func pathIDs(comment *models.Comment) (_ []string, ok bool) {
+	parts := strings.Split(comment.Path, ".")
+	if len(parts) != comment.Depth+1 || parts[len(parts)-1] != comment.ID {
+		return nil, false
+	}
+	for _, part := range parts {
+		if _, err := uuid.Parse(part); err != nil {
+			return nil, false
+		}
+	}
+	return parts, true
+}
+
+// GetCommentAncestors returns the comments above commentID, top-level first,
+// by walking parent_id with a recursive CTE. Unlike GetCommentPath it never
+// reads the materialized path, so it holds up when a path is malformed.
+// Deleted ancestors are left out; ErrNotFound if the comment itself is missing
+// or deleted.
+func (r *PostgresRepository) GetCommentAncestors(ctx context.Context, commentID string) (_ []*models.Comment, err error) {
+	ctx, span := r.startSpan(ctx, "GetCommentAncestors", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
+
+	comment, err := r.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	ancestors, err := r.ancestorsOf(ctx, comment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment ancestors: %w", err)
+	}
+	return ancestors, nil
+}
+
+// ancestorsOf walks up from comment's parent. The walk stops after
+// comment.Depth steps, so a cycle in corrupt data can't run away.
+func (r *PostgresRepository) ancestorsOf(ctx context.Context, comment *models.Comment) ([]*models.Comment, error) {
+	ancestors := []*models.Comment{}
+	if comment.ParentID == nil {
+		return ancestors, nil
+	}
+
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT c.*, 1 AS hops
+			FROM {comments} c
+			WHERE c.id = $1
+			UNION ALL
+			SELECT c.*, a.hops + 1
+			FROM {comments} c
+			JOIN ancestors a ON c.id = a.parent_id
+			WHERE a.hops < $2
+		)
+		SELECT id, root_id, parent_id, user_id, content, media_url, link_url,
+		       upvotes, downvotes, score, depth, path, is_deleted, is_edited,
+		       edit_count, original_content, created_at, updated_at, content_updated_at, decayed_score,
+		       reply_count, descendant_count, is_anonymous, display_name, status, link_preview, version, deleted_by, delete_reason
+		FROM ancestors
+		WHERE NOT is_deleted
+		ORDER BY hops DESC`
+
+	if err := r.getQueryable().SelectContext(ctx, &ancestors, query, *comment.ParentID, comment.Depth); err != nil {
+		return nil, err
+	}
+	return ancestors, nil
+}
+
+// MoveComment re-parents a comment under newParentID in the same root. The
+// comment's path and depth are rewritten along with every descendant's, and
+// reply counts move from the old ancestors to the new ones. Run it inside a
+// transaction so the subtree is never seen half-moved.
+func (r *PostgresRepository) MoveComment(ctx context.Context, commentID, newParentID string) (err error) {
+	ctx, span := r.startSpan(ctx, "MoveComment", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
+
+	comment, err := r.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return fmt.Errorf("failed to get comment: %w", err)
+	}
+	parent, err := r.GetCommentByID(ctx, newParentID)
+	if err != nil {
+		return fmt.Errorf("failed to get new parent comment: %w", err)
+	}
+	if parent.RootID != comment.RootID {
+		return fmt.Errorf("new parent comment belongs to different root")
+	}
+	if parent.ID == comment.ID || strings.HasPrefix(parent.Path, comment.Path+".") {
+		return fmt.Errorf("cannot move a comment under itself or its descendants")
+	}
+
+	// The comment plus its live descendants leave the old ancestors
+	moved := 1 + comment.DescendantCount
+	if comment.ParentID != nil {
+		_, err = r.getDB().ExecContext(ctx, `UPDATE {comments} SET reply_count = reply_count - 1 WHERE id = $1`, *comment.ParentID)
+		if err != nil {
+			return fmt.Errorf("failed to update old parent reply count: %w", err)
+		}
+		_, err = r.getDB().ExecContext(ctx, `
+			UPDATE {comments} SET descendant_count = descendant_count - $1
+			WHERE id = ANY(string_to_array($2, '.')::uuid[]) AND id <> $3`,
+			moved, comment.Path, comment.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update old ancestor counts: %w", err)
+		}
+	}
+
+	newPath := parent.Path + "." + comment.ID
+	_, err = r.getDB().ExecContext(ctx, `
+		UPDATE {comments}
+		SET path = $1 || substr(path, length($2) + 1),
+		    depth = depth + $3
+		WHERE path = $2 OR path LIKE $4`,
+		newPath, comment.Path, parent.Depth+1-comment.Depth, descendantPattern(comment.Path))
+	if err != nil {
+		return fmt.Errorf("failed to rewrite subtree paths: %w", err)
+	}
+
+	_, err = r.getDB().ExecContext(ctx, `UPDATE {comments} SET parent_id = $1, updated_at = NOW() WHERE id = $2`, parent.ID, comment.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update parent: %w", err)
+	}
+
+	_, err = r.getDB().ExecContext(ctx, `UPDATE {comments} SET reply_count = reply_count + 1 WHERE id = $1`, parent.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update new parent reply count: %w", err)
+	}
+	_, err = r.getDB().ExecContext(ctx, `
+		UPDATE {comments} SET descendant_count = descendant_count + $1
+		WHERE id = ANY(string_to_array($2, '.')::uuid[])`,
+		moved, parent.Path)
+	if err != nil {
+		return fmt.Errorf("failed to update new ancestor counts: %w", err)
+	}
+
+	return nil
+}
+
 // Vote operations
 func (r *PostgresRepository) CreateVote(ctx context.Context, vote *models.Vote) error {
 	if vote.ID == "" {
@@ -446,16 +1035,19 @@ func (r *PostgresRepository) CreateVote(ctx context.Context, vote *models.Vote)
 	}
 
 	query := `
-		INSERT INTO votes (id, comment_id, user_id, vote_type, created_at, updated_at)
-		VALUES ($1::uuid, $2::uuid, $3::varchar, $4::smallint, $5::timestamptz, $6::timestamptz)
+		INSERT INTO {votes} (id, comment_id, user_id, vote_type, weight, created_at, updated_at)
+		VALUES ($1::uuid, $2::uuid, $3::varchar, $4::smallint, $5::integer, $6::timestamptz, $7::timestamptz)
 		ON CONFLICT (comment_id, user_id) 
-		DO UPDATE SET vote_type = $4::smallint, updated_at = $6::timestamptz`
+		DO UPDATE SET vote_type = $4::smallint, weight = $5::integer, updated_at = $7::timestamptz`
 
 	vote.CreatedAt = time.Now()
 	vote.UpdatedAt = time.Now()
+	if vote.Weight < 1 {
+		vote.Weight = 1
+	}
 
 	_, err := r.getDB().ExecContext(ctx, query,
-		vote.ID, vote.CommentID, vote.UserID, vote.VoteType,
+		vote.ID, vote.CommentID, vote.UserID, vote.VoteType, vote.Weight,
 		vote.CreatedAt, vote.UpdatedAt)
 
 	if err != nil {
@@ -465,21 +1057,33 @@ func (r *PostgresRepository) CreateVote(ctx context.Context, vote *models.Vote)
 	return nil
 }
 
-// UpdateVote updates or creates a vote
-func (r *PostgresRepository) UpdateVote(ctx context.Context, commentID, userID string, voteType models.VoteType) error {
-	vote := &models.Vote{
-		CommentID: commentID,
-		UserID:    userID,
-		VoteType:  voteType,
+// UpdateVote updates or creates a vote. Only the vote type changes: an
+// existing vote keeps its weight, and a new one weighs 1.
+func (r *PostgresRepository) UpdateVote(ctx context.Context, commentID, userID string, voteType models.VoteType) (err error) {
+	ctx, span := r.startSpan(ctx, "UpdateVote", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
+
+	query := `
+		INSERT INTO {votes} (id, comment_id, user_id, vote_type, weight, created_at, updated_at)
+		VALUES ($1::uuid, $2::uuid, $3::varchar, $4::smallint, 1, NOW(), NOW())
+		ON CONFLICT (comment_id, user_id)
+		DO UPDATE SET vote_type = $4::smallint, updated_at = NOW()`
+
+	_, err = r.getDB().ExecContext(ctx, query, uuid.New().String(), commentID, userID, voteType)
+	if err != nil {
+		return fmt.Errorf("failed to update vote: %w", err)
 	}
-	return r.CreateVote(ctx, vote)
+	return nil
 }
 
 // DeleteVote removes a user's vote
-func (r *PostgresRepository) DeleteVote(ctx context.Context, commentID, userID string) error {
-	query := `DELETE FROM votes WHERE comment_id = $1 AND user_id = $2`
+func (r *PostgresRepository) DeleteVote(ctx context.Context, commentID, userID string) (err error) {
+	ctx, span := r.startSpan(ctx, "DeleteVote", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
 
-	_, err := r.getDB().ExecContext(ctx, query, commentID, userID)
+	query := `DELETE FROM {votes} WHERE comment_id = $1 AND user_id = $2`
+
+	_, err = r.getDB().ExecContext(ctx, query, commentID, userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete vote: %w", err)
 	}
@@ -487,15 +1091,46 @@ func (r *PostgresRepository) DeleteVote(ctx context.Context, commentID, userID s
 	return nil
 }
 
-// GetUserVote retrieves a user's vote for a comment
-func (r *PostgresRepository) GetUserVote(ctx context.Context, commentID, userID string) (*models.Vote, error) {
-	query := `
-		SELECT id, comment_id, user_id, vote_type, created_at, updated_at
-		FROM votes 
-		WHERE comment_id = $1 AND user_id = $2`
+// DeleteVotes removes a user's votes on many comments in one statement. The
+// vote triggers recompute each affected comment's counts and score.
+func (r *PostgresRepository) DeleteVotes(ctx context.Context, commentIDs []string, userID string) (_ []string, err error) {
+	ctx, span := r.startSpan(ctx, "DeleteVotes")
+	defer func() { endSpan(span, err) }()
+
+	// Comment IDs are UUIDs; anything else cannot match and would fail the cast
+	valid := make([]string, 0, len(commentIDs))
+	for _, id := range commentIDs {
+		if _, err := uuid.Parse(id); err == nil {
+			valid = append(valid, id)
+		}
+	}
+	removed := []string{}
+	if len(valid) == 0 {
+		return removed, nil
+	}
+
+	query := `DELETE FROM {votes} WHERE comment_id = ANY($1::uuid[]) AND user_id = $2 RETURNING comment_id`
+
+	err = r.getQueryable().SelectContext(ctx, &removed, query, pq.Array(valid), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete votes: %w", err)
+	}
+
+	return removed, nil
+}
+
+// GetUserVote retrieves a user's vote for a comment
+func (r *PostgresRepository) GetUserVote(ctx context.Context, commentID, userID string) (_ *models.Vote, err error) {
+	ctx, span := r.startSpan(ctx, "GetUserVote", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
+
+	query := `
+		SELECT id, comment_id, user_id, vote_type, weight, created_at, updated_at
+		FROM {votes} 
+		WHERE comment_id = $1 AND user_id = $2`
 
 	vote := &models.Vote{}
-	err := r.getQueryable().GetContext(ctx, vote, query, commentID, userID)
+	err = r.getQueryable().GetContext(ctx, vote, query, commentID, userID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // No vote found
@@ -509,8 +1144,8 @@ func (r *PostgresRepository) GetUserVote(ctx context.Context, commentID, userID
 // GetCommentVotes retrieves all votes for a comment
 func (r *PostgresRepository) GetCommentVotes(ctx context.Context, commentID string) ([]*models.Vote, error) {
 	query := `
-		SELECT id, comment_id, user_id, vote_type, created_at, updated_at
-		FROM votes 
+		SELECT id, comment_id, user_id, vote_type, weight, created_at, updated_at
+		FROM {votes} 
 		WHERE comment_id = $1`
 
 	votes := []*models.Vote{}
@@ -522,42 +1157,250 @@ func (r *PostgresRepository) GetCommentVotes(ctx context.Context, commentID stri
 	return votes, nil
 }
 
+// GetVotesForComments retrieves every vote on the given comments, oldest first
+func (r *PostgresRepository) GetVotesForComments(ctx context.Context, commentIDs []string) (_ map[string][]*models.Vote, err error) {
+	ctx, span := r.startSpan(ctx, "GetVotesForComments")
+	defer func() { endSpan(span, err) }()
+
+	votes := make(map[string][]*models.Vote)
+	if len(commentIDs) == 0 {
+		return votes, nil
+	}
+
+	query := `
+		SELECT id, comment_id, user_id, vote_type, weight, created_at, updated_at
+		FROM {votes}
+		WHERE comment_id = ANY($1)
+		ORDER BY created_at, id`
+
+	rows := []*models.Vote{}
+	err = r.getQueryable().SelectContext(ctx, &rows, query, pq.Array(commentIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment votes: %w", err)
+	}
+
+	for _, vote := range rows {
+		votes[vote.CommentID] = append(votes[vote.CommentID], vote)
+	}
+
+	return votes, nil
+}
+
+// GetUserVotes retrieves the comments a user has voted on with their vote,
+// most recently voted first
+func (r *PostgresRepository) GetUserVotes(ctx context.Context, userID string, filter *models.VoteFilter) (_ []*models.VotedComment, err error) {
+	ctx, span := r.startSpan(ctx, "GetUserVotes")
+	defer func() { endSpan(span, err) }()
+
+	if filter == nil {
+		filter = &models.VoteFilter{}
+	}
+
+	query := `
+		SELECT c.id, c.root_id, c.parent_id, c.user_id, c.content, c.media_url, c.link_url,
+		       c.upvotes, c.downvotes, c.score, c.depth, c.path, c.is_deleted, c.is_edited,
+		       c.edit_count, c.original_content, c.created_at, c.updated_at, c.content_updated_at, c.decayed_score,
+		       c.reply_count, c.descendant_count, c.is_anonymous, c.display_name, c.status, c.link_preview, c.version, c.deleted_by, c.delete_reason,
+		       v.vote_type, v.updated_at AS voted_at
+		FROM {votes} v
+		JOIN {comments} c ON c.id = v.comment_id
+		WHERE v.user_id = $1`
+
+	args := []interface{}{userID}
+	argIndex := 2
+
+	if !filter.IncludeDeleted {
+		query += " AND NOT c.is_deleted"
+	}
+
+	if filter.RootID != nil {
+		query += fmt.Sprintf(" AND c.root_id = $%d", argIndex)
+		args = append(args, *filter.RootID)
+		argIndex++
+	}
+
+	if filter.VoteType != nil {
+		query += fmt.Sprintf(" AND v.vote_type = $%d", argIndex)
+		args = append(args, *filter.VoteType)
+		argIndex++
+	}
+
+	query += " ORDER BY v.updated_at DESC, v.id"
+
+	if filter.Limit != nil {
+		query += fmt.Sprintf(" LIMIT $%d", argIndex)
+		args = append(args, *filter.Limit)
+		argIndex++
+	}
+
+	if filter.Offset != nil {
+		query += fmt.Sprintf(" OFFSET $%d", argIndex)
+		args = append(args, *filter.Offset)
+	}
+
+	voted := []*models.VotedComment{}
+	err = r.getQueryable().SelectContext(ctx, &voted, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user votes: %w", err)
+	}
+
+	return voted, nil
+}
+
+// GetRepliesToUser retrieves other users' replies to userID's comments, newest
+// first, joining each to the comment it answers
+func (r *PostgresRepository) GetRepliesToUser(ctx context.Context, userID string, filter *models.CommentFilter) (_ []*models.Reply, err error) {
+	ctx, span := r.startSpan(ctx, "GetRepliesToUser")
+	defer func() { endSpan(span, err) }()
+
+	if filter == nil {
+		filter = &models.CommentFilter{}
+	}
+
+	query := `
+		SELECT c.id, c.root_id, c.parent_id, c.user_id, c.content, c.media_url, c.link_url,
+		       c.upvotes, c.downvotes, c.score, c.depth, c.path, c.is_deleted, c.is_edited,
+		       c.edit_count, c.original_content, c.created_at, c.updated_at, c.content_updated_at, c.decayed_score,
+		       c.reply_count, c.descendant_count, c.is_anonymous, c.display_name, c.status, c.link_preview, c.version, c.deleted_by, c.delete_reason,
+		       p.id AS "parent.id", p.root_id AS "parent.root_id", p.parent_id AS "parent.parent_id",
+		       p.user_id AS "parent.user_id", p.content AS "parent.content", p.media_url AS "parent.media_url",
+		       p.link_url AS "parent.link_url", p.upvotes AS "parent.upvotes", p.downvotes AS "parent.downvotes",
+		       p.score AS "parent.score", p.depth AS "parent.depth", p.path AS "parent.path",
+		       p.is_deleted AS "parent.is_deleted", p.is_edited AS "parent.is_edited", p.edit_count AS "parent.edit_count",
+		       p.original_content AS "parent.original_content", p.created_at AS "parent.created_at",
+		       p.updated_at AS "parent.updated_at", p.content_updated_at AS "parent.content_updated_at",
+		       p.decayed_score AS "parent.decayed_score", p.reply_count AS "parent.reply_count",
+		       p.descendant_count AS "parent.descendant_count", p.is_anonymous AS "parent.is_anonymous",
+		       p.display_name AS "parent.display_name", p.status AS "parent.status",
+		       p.link_preview AS "parent.link_preview", p.version AS "parent.version",
+		       p.deleted_by AS "parent.deleted_by", p.delete_reason AS "parent.delete_reason"
+		FROM {comments} c
+		JOIN {comments} p ON p.id = c.parent_id
+		WHERE p.user_id = $1 AND c.user_id <> $1
+		  AND NOT c.is_deleted AND c.status = 'approved'
+		  AND NOT p.is_deleted
+		  AND ` + notBlockedClause("c.user_id", 1)
+
+	args := []interface{}{userID}
+	argIndex := 2
+
+	if filter.RootID != nil {
+		query += fmt.Sprintf(" AND c.root_id = $%d", argIndex)
+		args = append(args, *filter.RootID)
+		argIndex++
+	}
+
+	query += " ORDER BY c.created_at DESC, c.id"
+
+	if filter.Limit != nil {
+		query += fmt.Sprintf(" LIMIT $%d", argIndex)
+		args = append(args, *filter.Limit)
+		argIndex++
+	}
+
+	if filter.Offset != nil {
+		query += fmt.Sprintf(" OFFSET $%d", argIndex)
+		args = append(args, *filter.Offset)
+	}
+
+	replies := []*models.Reply{}
+	err = r.getQueryable().SelectContext(ctx, &replies, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replies to user: %w", err)
+	}
+
+	return replies, nil
+}
+
+// GetVoteBreakdown counts the up and down votes on a comment and its distinct
+// voters in a single aggregate query
+func (r *PostgresRepository) GetVoteBreakdown(ctx context.Context, commentID string) (_ *models.VoteBreakdown, err error) {
+	ctx, span := r.startSpan(ctx, "GetVoteBreakdown", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
+
+	query := `
+		SELECT c.id AS comment_id,
+		       COUNT(v.id) FILTER (WHERE v.vote_type = 1) AS upvotes,
+		       COUNT(v.id) FILTER (WHERE v.vote_type = -1) AS downvotes,
+		       COUNT(DISTINCT v.user_id) AS voters
+		FROM {comments} c
+		LEFT JOIN {votes} v ON v.comment_id = c.id
+		WHERE c.id = $1 AND NOT c.is_deleted
+		GROUP BY c.id`
+
+	breakdown := &models.VoteBreakdown{}
+	err = r.getQueryable().GetContext(ctx, breakdown, query, commentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get vote breakdown: %w", err)
+	}
+
+	return breakdown, nil
+}
+
 // GetCommentsWithUserVotes retrieves comments with user's votes in a single query
-func (r *PostgresRepository) GetCommentsWithUserVotes(ctx context.Context, rootID, userID string, filter *models.CommentFilter) ([]*models.Comment, map[string]*models.Vote, error) {
+func (r *PostgresRepository) GetCommentsWithUserVotes(ctx context.Context, rootID, userID string, filter *models.CommentFilter) (_ []*models.Comment, _ map[string]*models.Vote, err error) {
+	ctx, span := r.startSpan(ctx, "GetCommentsWithUserVotes", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
 	query := `
 		SELECT c.id, c.root_id, c.parent_id, c.user_id, c.content, c.media_url, c.link_url,
 		       c.upvotes, c.downvotes, c.score, c.depth, c.path, c.is_deleted, c.is_edited,
-		       c.edit_count, c.original_content, c.created_at, c.updated_at, c.content_updated_at,
+		       c.edit_count, c.original_content, c.created_at, c.updated_at, c.content_updated_at, c.decayed_score,
+		       c.reply_count, c.descendant_count, c.is_anonymous, c.display_name, c.status, c.link_preview, c.version, c.deleted_by, c.delete_reason,
 		       v.id as vote_id, v.vote_type
-		FROM comments c
-		LEFT JOIN votes v ON c.id = v.comment_id AND v.user_id = $2
-		WHERE c.root_id = $1 AND NOT c.is_deleted`
+		FROM {comments} c
+		LEFT JOIN {votes} v ON c.id = v.comment_id AND v.user_id = $2
+		WHERE c.root_id = $1 AND NOT c.is_deleted AND (c.status = 'approved' OR c.user_id = $2)
+		  AND ` + notBlockedClause("c.user_id", 2)
 
 	args := []interface{}{rootID, userID}
 	argIndex := 3
 
 	if filter != nil {
+		if filter.ParentID != nil {
+			query += fmt.Sprintf(" AND c.parent_id = $%d", argIndex)
+			args = append(args, *filter.ParentID)
+			argIndex++
+		}
 		if filter.MaxDepth != nil {
 			query += fmt.Sprintf(" AND c.depth <= $%d", argIndex)
 			args = append(args, *filter.MaxDepth)
 			argIndex++
 		}
-
-		// Add sorting
-		sortBy := "c.created_at"
-		if filter.SortBy != "" {
-			switch filter.SortBy {
-			case "score", "created_at", "updated_at":
-				sortBy = "c." + filter.SortBy
-			}
+		if filter.TopLevelOnly {
+			query += " AND c.depth = 0"
 		}
-
-		sortOrder := "DESC"
-		if filter.SortOrder == "asc" {
-			sortOrder = "ASC"
+		if filter.CreatedAfter != nil {
+			query += fmt.Sprintf(" AND c.created_at >= $%d", argIndex)
+			args = append(args, *filter.CreatedAfter)
+			argIndex++
+		}
+		if filter.CreatedBefore != nil {
+			query += fmt.Sprintf(" AND c.created_at < $%d", argIndex)
+			args = append(args, *filter.CreatedBefore)
+			argIndex++
+		}
+		if filter.IsEdited != nil {
+			query += fmt.Sprintf(" AND c.is_edited = $%d", argIndex)
+			args = append(args, *filter.IsEdited)
+			argIndex++
+		}
+		if filter.MinEdits != nil {
+			query += fmt.Sprintf(" AND c.edit_count >= $%d", argIndex)
+			args = append(args, *filter.MinEdits)
+			argIndex++
+		}
+		if filter.MaxEdits != nil {
+			query += fmt.Sprintf(" AND c.edit_count <= $%d", argIndex)
+			args = append(args, *filter.MaxEdits)
+			argIndex++
 		}
 
-		query += fmt.Sprintf(" ORDER BY %s %s", sortBy, sortOrder)
+		// Add sorting
+		query += orderByClause(filter.SortBy, filter.SortOrder, "c.")
 
 		// Add pagination
 		if filter.Limit != nil {
@@ -592,7 +1435,8 @@ func (r *PostgresRepository) GetCommentsWithUserVotes(ctx context.Context, rootI
 			&comment.Content, &comment.MediaURL, &comment.LinkURL,
 			&comment.Upvotes, &comment.Downvotes, &comment.Score,
 			&comment.Depth, &comment.Path, &comment.IsDeleted, &comment.IsEdited,
-			&comment.EditCount, &comment.OriginalContent, &comment.CreatedAt, &comment.UpdatedAt, &comment.ContentUpdatedAt,
+			&comment.EditCount, &comment.OriginalContent, &comment.CreatedAt, &comment.UpdatedAt, &comment.ContentUpdatedAt, &comment.DecayedScore,
+			&comment.ReplyCount, &comment.DescendantCount, &comment.IsAnonymous, &comment.DisplayName, &comment.Status, &comment.LinkPreview, &comment.Version, &comment.DeletedBy, &comment.DeleteReason,
 			&voteID, &voteType,
 		)
 		if err != nil {
@@ -602,29 +1446,142 @@ func (r *PostgresRepository) GetCommentsWithUserVotes(ctx context.Context, rootI
 		comments = append(comments, comment)
 
 		if voteID.Valid {
-			votes[comment.ID] = &models.Vote{
+			vote := &models.Vote{
 				ID:        voteID.String,
 				CommentID: comment.ID,
 				UserID:    userID,
 				VoteType:  models.VoteType(voteType.Int32),
 			}
+			votes[comment.ID] = vote
+			comment.CurrentUserVote = &vote.VoteType
 		}
 	}
 
 	return comments, votes, nil
 }
 
-// UpdateCommentScores recalculates scores for specified comments
+// GetCommentTreeWithUserVotes builds the tree userID sees, reading each
+// comment with their vote in the same query GetCommentsWithUserVotes makes,
+// which sets CurrentUserVote
+func (r *PostgresRepository) GetCommentTreeWithUserVotes(ctx context.Context, rootID, userID string, maxDepth int, sortBy string) (_ []*models.CommentTree, err error) {
+	ctx, span := r.startSpan(ctx, "GetCommentTreeWithUserVotes", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	filter := &models.CommentFilter{
+		MaxDepth: &maxDepth,
+		SortBy:   sortBy,
+	}
+	comments, _, err := r.GetCommentsWithUserVotes(ctx, rootID, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.buildCommentTree(comments), nil
+}
+
+// GetUserVotesForComments retrieves a user's votes for a set of comments in a single query
+func (r *PostgresRepository) GetUserVotesForComments(ctx context.Context, commentIDs []string, userID string) (_ map[string]*models.Vote, err error) {
+	ctx, span := r.startSpan(ctx, "GetUserVotesForComments")
+	defer func() { endSpan(span, err) }()
+
+	votes := make(map[string]*models.Vote)
+	if len(commentIDs) == 0 {
+		return votes, nil
+	}
+
+	query := `
+		SELECT id, comment_id, user_id, vote_type, weight, created_at, updated_at
+		FROM {votes} 
+		WHERE user_id = $1 AND comment_id = ANY($2)`
+
+	rows := []*models.Vote{}
+	err = r.getQueryable().SelectContext(ctx, &rows, query, userID, pq.Array(commentIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user votes: %w", err)
+	}
+
+	for _, vote := range rows {
+		votes[vote.CommentID] = vote
+	}
+
+	return votes, nil
+}
+
+// importBatchSize is the number of rows per INSERT in ImportComments
+const importBatchSize = 500
+
+// ImportComments inserts comments exactly as given, keeping their IDs, paths,
+// depths, timestamps, edit tracking and vote counts, in multi-row INSERTs of
+// importBatchSize. Parents must exist already or precede their children in
+// comments. The reply count trigger still maintains reply_count and
+// descendant_count; edit tracking is suspended so a parent's updated_at is not
+// reset as its replies arrive. Run it inside a transaction.
+func (r *PostgresRepository) ImportComments(ctx context.Context, comments []*models.Comment) (err error) {
+	ctx, span := r.startSpan(ctx, "ImportComments")
+	defer func() { endSpan(span, err) }()
+
+	if len(comments) == 0 {
+		return nil
+	}
+
+	// Read by the edit tracking trigger (migration 006); local to the transaction
+	if _, err = r.getDB().ExecContext(ctx, `SELECT set_config('commentific.importing', 'on', true)`); err != nil {
+		return fmt.Errorf("failed to suspend edit tracking: %w", err)
+	}
+
+	const columns = 22
+	for start := 0; start < len(comments); start += importBatchSize {
+		batch := comments[start:min(start+importBatchSize, len(comments))]
+
+		placeholders := make([]string, 0, len(batch))
+		args := make([]interface{}, 0, len(batch)*columns)
+		for i, c := range batch {
+			if c.Status == "" {
+				c.Status = models.CommentStatusApproved
+			}
+			row := make([]string, columns)
+			for j := range row {
+				row[j] = fmt.Sprintf("$%d", i*columns+j+1)
+			}
+			placeholders = append(placeholders, "("+strings.Join(row, ", ")+")")
+			args = append(args,
+				c.ID, c.RootID, c.ParentID, c.UserID, c.Content, c.MediaURL, c.LinkURL,
+				c.Upvotes, c.Downvotes, c.Score, c.Depth, c.Path, c.IsDeleted,
+				c.IsEdited, c.EditCount, c.OriginalContent, c.CreatedAt, c.UpdatedAt, c.ContentUpdatedAt,
+				c.IsAnonymous, c.DisplayName, c.Status)
+		}
+
+		query := `
+			INSERT INTO {comments} (id, root_id, parent_id, user_id, content, media_url, link_url,
+			                      upvotes, downvotes, score, depth, path, is_deleted,
+			                      is_edited, edit_count, original_content, created_at, updated_at, content_updated_at,
+			                      is_anonymous, display_name, status)
+			VALUES ` + strings.Join(placeholders, ", ")
+
+		if _, err = r.getDB().ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to import comments: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// weightedScore sums the votes on a {comments} row, each counting its weight
+const weightedScore = `COALESCE((SELECT SUM(vote_type * weight) FROM {votes} WHERE comment_id = {comments}.id), 0)`
+
+// UpdateCommentScores recalculates vote counts and weighted scores for
+// specified comments
 func (r *PostgresRepository) UpdateCommentScores(ctx context.Context, commentIDs []string) error {
 	if len(commentIDs) == 0 {
 		return nil
 	}
 
 	query := `
-		UPDATE comments 
+		UPDATE {comments} 
 		SET 
-			upvotes = (SELECT COUNT(*) FROM votes WHERE comment_id = comments.id AND vote_type = 1),
-			downvotes = (SELECT COUNT(*) FROM votes WHERE comment_id = comments.id AND vote_type = -1),
+			upvotes = (SELECT COUNT(*) FROM {votes} WHERE comment_id = {comments}.id AND vote_type = 1),
+			downvotes = (SELECT COUNT(*) FROM {votes} WHERE comment_id = {comments}.id AND vote_type = -1),
+			score = ` + weightedScore + `,
 			updated_at = NOW()
 		WHERE id = ANY($1)`
 
@@ -633,18 +1590,14 @@ func (r *PostgresRepository) UpdateCommentScores(ctx context.Context, commentIDs
 		return fmt.Errorf("failed to update comment scores: %w", err)
 	}
 
-	// Update calculated score
-	scoreQuery := `UPDATE comments SET score = upvotes - downvotes WHERE id = ANY($1)`
-	_, err = r.getDB().ExecContext(ctx, scoreQuery, pq.Array(commentIDs))
-	if err != nil {
-		return fmt.Errorf("failed to update calculated scores: %w", err)
-	}
-
 	return nil
 }
 
 // GetCommentStats retrieves enhanced statistics for a root including edit tracking
-func (r *PostgresRepository) GetCommentStats(ctx context.Context, rootID string) (*models.CommentStats, error) {
+func (r *PostgresRepository) GetCommentStats(ctx context.Context, rootID string) (_ *models.CommentStats, err error) {
+	ctx, span := r.startSpan(ctx, "GetCommentStats", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
 	query := `
 		SELECT 
 			COUNT(*) as total_count,
@@ -652,32 +1605,109 @@ func (r *PostgresRepository) GetCommentStats(ctx context.Context, rootID string)
 			COALESCE(MAX(depth), 0) as max_depth,
 			COUNT(CASE WHEN created_at > NOW() - INTERVAL '24 hours' THEN 1 END) as recent_count,
 			COUNT(CASE WHEN is_edited = true THEN 1 END) as edited_count,
-			COALESCE(SUM(edit_count), 0) as total_edits
-		FROM comments 
-		WHERE root_id = $1 AND NOT is_deleted`
+			COALESCE(SUM(edit_count), 0) as total_edits,
+			COALESCE(AVG(depth), 0) as avg_depth,
+			COUNT(DISTINCT user_id) as participant_count,
+			MAX(created_at) as last_comment_at
+		FROM {comments} 
+		WHERE root_id = $1 AND NOT is_deleted AND status = 'approved'`
 
 	stats := &models.CommentStats{RootID: rootID}
-	err := r.getQueryable().QueryRowxContext(ctx, query, rootID).Scan(
+	err = r.getQueryable().QueryRowxContext(ctx, query, rootID).Scan(
 		&stats.TotalCount, &stats.TotalScore, &stats.MaxDepth, &stats.RecentCount,
-		&stats.EditedCount, &stats.TotalEdits)
+		&stats.EditedCount, &stats.TotalEdits, &stats.AvgDepth, &stats.ParticipantCount, &stats.LastCommentAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get comment stats: %w", err)
 	}
 
-	// Calculate derived statistics
+	fillDerivedStats(stats)
+	return stats, nil
+}
+
+// GetCommentStatsBatch retrieves statistics for many roots with a single grouped
+// query. Every requested root is present in the result, with zero values if it
+// has no comments.
+func (r *PostgresRepository) GetCommentStatsBatch(ctx context.Context, rootIDs []string) (_ map[string]*models.CommentStats, err error) {
+	ctx, span := r.startSpan(ctx, "GetCommentStatsBatch")
+	defer func() { endSpan(span, err) }()
+
+	result := make(map[string]*models.CommentStats, len(rootIDs))
+	for _, rootID := range rootIDs {
+		result[rootID] = &models.CommentStats{RootID: rootID}
+	}
+	if len(rootIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT 
+			root_id,
+			COUNT(*) as total_count,
+			COALESCE(SUM(score), 0) as total_score,
+			COALESCE(MAX(depth), 0) as max_depth,
+			COUNT(CASE WHEN created_at > NOW() - INTERVAL '24 hours' THEN 1 END) as recent_count,
+			COUNT(CASE WHEN is_edited = true THEN 1 END) as edited_count,
+			COALESCE(SUM(edit_count), 0) as total_edits,
+			COALESCE(AVG(depth), 0) as avg_depth,
+			COUNT(DISTINCT user_id) as participant_count,
+			MAX(created_at) as last_comment_at
+		FROM {comments} 
+		WHERE root_id = ANY($1) AND NOT is_deleted AND status = 'approved'
+		GROUP BY root_id`
+
+	rows, err := r.getQueryable().QueryContext(ctx, query, pq.Array(rootIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment stats: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		stats := &models.CommentStats{}
+		err := rows.Scan(&stats.RootID, &stats.TotalCount, &stats.TotalScore, &stats.MaxDepth,
+			&stats.RecentCount, &stats.EditedCount, &stats.TotalEdits, &stats.AvgDepth, &stats.ParticipantCount,
+			&stats.LastCommentAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment stats: %w", err)
+		}
+		fillDerivedStats(stats)
+		result[stats.RootID] = stats
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read comment stats: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetRootVersion reports how many rows the root has and when the latest of
+// them last changed. Deleted and unapproved rows count too, since their
+// changes can alter what a caller sees.
+func (r *PostgresRepository) GetRootVersion(ctx context.Context, rootID string) (_ *models.RootVersion, err error) {
+	ctx, span := r.startSpan(ctx, "GetRootVersion", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	query := `SELECT COUNT(*) AS comment_count, MAX(updated_at) AS last_updated_at FROM {comments} WHERE root_id = $1`
+
+	version := &models.RootVersion{RootID: rootID}
+	if err = r.getQueryable().QueryRowxContext(ctx, query, rootID).Scan(&version.CommentCount, &version.LastUpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to get root version: %w", err)
+	}
+	return version, nil
+}
+
+// fillDerivedStats calculates the statistics derived from the aggregated counts
+func fillDerivedStats(stats *models.CommentStats) {
 	if stats.TotalCount > 0 {
 		stats.EditRate = float64(stats.EditedCount) / float64(stats.TotalCount) * 100
 	}
 	if stats.EditedCount > 0 {
 		stats.AvgEditsPerComment = float64(stats.TotalEdits) / float64(stats.EditedCount)
 	}
-
-	return stats, nil
 }
 
 // GetUserCommentCount retrieves the number of comments by a user
 func (r *PostgresRepository) GetUserCommentCount(ctx context.Context, userID string) (int64, error) {
-	query := `SELECT COUNT(*) FROM comments WHERE user_id = $1 AND NOT is_deleted`
+	query := `SELECT COUNT(*) FROM {comments} WHERE user_id = $1 AND NOT is_deleted`
 
 	var count int64
 	err := r.getQueryable().QueryRowxContext(ctx, query, userID).Scan(&count)
@@ -690,43 +1720,354 @@ func (r *PostgresRepository) GetUserCommentCount(ctx context.Context, userID str
 
 // GetTopComments retrieves top comments based on score within time range
 func (r *PostgresRepository) GetTopComments(ctx context.Context, rootID string, limit int, timeRange string) ([]*models.Comment, error) {
-	var timeClause string
+	query := fmt.Sprintf(`
+		SELECT id, root_id, parent_id, user_id, content, media_url, link_url,
+		       upvotes, downvotes, score, depth, path, is_deleted, is_edited,
+		       edit_count, original_content, created_at, updated_at, content_updated_at, decayed_score,
+		       reply_count, descendant_count, is_anonymous, display_name, status, link_preview, version, deleted_by, delete_reason
+		FROM {comments} 
+		WHERE root_id = $1 AND NOT is_deleted AND status = 'approved' %s
+		ORDER BY score DESC, created_at DESC
+		LIMIT $2`, timeRangeClause(timeRange))
+
+	comments := []*models.Comment{}
+	err := r.getQueryable().SelectContext(ctx, &comments, query, rootID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top comments: %w", err)
+	}
+
+	return comments, nil
+}
+
+// GetTrendingRoots ranks roots by how many live comments they gained within
+// the time range, breaking ties by the combined score of those comments
+func (r *PostgresRepository) GetTrendingRoots(ctx context.Context, timeRange string, limit int) (_ []*models.TrendingRoot, err error) {
+	ctx, span := r.startSpan(ctx, "GetTrendingRoots")
+	defer func() { endSpan(span, err) }()
+
+	query := fmt.Sprintf(`
+		SELECT root_id, COUNT(*) AS comment_count, COALESCE(SUM(score), 0) AS total_score,
+		       MAX(created_at) AS latest_comment_at
+		FROM {comments}
+		WHERE NOT is_deleted AND status = 'approved' %s
+		GROUP BY root_id
+		ORDER BY comment_count DESC, total_score DESC, latest_comment_at DESC
+		LIMIT $1`, timeRangeClause(timeRange))
+
+	roots := []*models.TrendingRoot{}
+	err = r.getQueryable().SelectContext(ctx, &roots, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trending roots: %w", err)
+	}
+
+	return roots, nil
+}
+
+// GetUserReputation totals the votes on a user's live comments across all
+// roots, counting vote rows so self-votes left by imports can be skipped
+func (r *PostgresRepository) GetUserReputation(ctx context.Context, userID string) (_ *models.UserReputation, err error) {
+	ctx, span := r.startSpan(ctx, "GetUserReputation")
+	defer func() { endSpan(span, err) }()
+
+	query := `
+		SELECT COUNT(DISTINCT c.id) AS comment_count,
+		       COUNT(v.id) FILTER (WHERE v.vote_type = 1) AS upvotes,
+		       COUNT(v.id) FILTER (WHERE v.vote_type = -1) AS downvotes,
+		       COALESCE(SUM(v.vote_type * v.weight), 0) AS total_score
+		FROM {comments} c
+		LEFT JOIN {votes} v ON v.comment_id = c.id AND v.user_id <> c.user_id
+		WHERE c.user_id = $1 AND NOT c.is_deleted AND c.status = 'approved'`
+
+	reputation := &models.UserReputation{UserID: userID}
+	err = r.getQueryable().QueryRowxContext(ctx, query, userID).Scan(
+		&reputation.CommentCount, &reputation.Upvotes, &reputation.Downvotes, &reputation.TotalScore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user reputation: %w", err)
+	}
+	return reputation, nil
+}
+
+// GetTopCommenters ranks the users commenting on a root by how many live
+// comments they have there, breaking ties by the combined score of those
+// comments
+func (r *PostgresRepository) GetTopCommenters(ctx context.Context, rootID string, limit int) (_ []*models.TopCommenter, err error) {
+	ctx, span := r.startSpan(ctx, "GetTopCommenters", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	query := `
+		SELECT user_id, COUNT(*) AS comment_count, COALESCE(SUM(score), 0) AS total_score
+		FROM {comments}
+		WHERE root_id = $1 AND NOT is_deleted AND status = 'approved'
+		GROUP BY user_id
+		ORDER BY comment_count DESC, total_score DESC, user_id
+		LIMIT $2`
+
+	commenters := []*models.TopCommenter{}
+	err = r.getQueryable().SelectContext(ctx, &commenters, query, rootID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top commenters: %w", err)
+	}
+
+	return commenters, nil
+}
+
+// timeRangeClause restricts created_at to an "hour", "day", "week" or
+// "month" window; any other range means all time
+func timeRangeClause(timeRange string) string {
 	switch timeRange {
 	case "hour":
-		timeClause = "AND created_at > NOW() - INTERVAL '1 hour'"
+		return "AND created_at > NOW() - INTERVAL '1 hour'"
 	case "day":
-		timeClause = "AND created_at > NOW() - INTERVAL '1 day'"
+		return "AND created_at > NOW() - INTERVAL '1 day'"
 	case "week":
-		timeClause = "AND created_at > NOW() - INTERVAL '1 week'"
+		return "AND created_at > NOW() - INTERVAL '1 week'"
 	case "month":
-		timeClause = "AND created_at > NOW() - INTERVAL '1 month'"
+		return "AND created_at > NOW() - INTERVAL '1 month'"
 	default:
-		timeClause = "" // All time
+		return ""
 	}
+}
 
-	query := fmt.Sprintf(`
+// SetCommentStatus moves a comment to a new moderation status. The reply count
+// trigger adds it to or removes it from its ancestors' counts.
+func (r *PostgresRepository) SetCommentStatus(ctx context.Context, id string, status models.CommentStatus) (err error) {
+	ctx, span := r.startSpan(ctx, "SetCommentStatus", attrCommentID.String(id))
+	defer func() { endSpan(span, err) }()
+
+	result, err := r.getDB().ExecContext(ctx,
+		`UPDATE {comments} SET status = $2 WHERE id = $1 AND NOT is_deleted`, id, status)
+	if err != nil {
+		return fmt.Errorf("failed to set comment status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetModerationQueue retrieves a root's comments awaiting moderation, pending
+// or quarantined, oldest first, paginated by filter.Limit and filter.Offset
+func (r *PostgresRepository) GetModerationQueue(ctx context.Context, rootID string, filter *models.CommentFilter) (_ []*models.Comment, err error) {
+	ctx, span := r.startSpan(ctx, "GetModerationQueue", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	query := `
 		SELECT id, root_id, parent_id, user_id, content, media_url, link_url,
 		       upvotes, downvotes, score, depth, path, is_deleted, is_edited,
-		       edit_count, original_content, created_at, updated_at, content_updated_at
-		FROM comments 
-		WHERE root_id = $1 AND NOT is_deleted %s
-		ORDER BY score DESC, created_at DESC
-		LIMIT $2`, timeClause)
+		       edit_count, original_content, created_at, updated_at, content_updated_at, decayed_score,
+		       reply_count, descendant_count, is_anonymous, display_name, status, link_preview, version, deleted_by, delete_reason
+		FROM {comments}
+		WHERE root_id = $1 AND status IN ('pending', 'quarantined') AND NOT is_deleted
+		ORDER BY created_at, id`
+	args := []interface{}{rootID}
+
+	if filter != nil {
+		if filter.Limit != nil {
+			args = append(args, *filter.Limit)
+			query += fmt.Sprintf(" LIMIT $%d", len(args))
+		}
+		if filter.Offset != nil {
+			args = append(args, *filter.Offset)
+			query += fmt.Sprintf(" OFFSET $%d", len(args))
+		}
+	}
 
 	comments := []*models.Comment{}
-	err := r.getQueryable().SelectContext(ctx, &comments, query, rootID, limit)
+	err = r.getQueryable().SelectContext(ctx, &comments, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get top comments: %w", err)
+		return nil, fmt.Errorf("failed to get moderation queue: %w", err)
 	}
 
 	return comments, nil
 }
 
-// PurgeDeletedComments permanently deletes soft-deleted comments older than specified days
-func (r *PostgresRepository) PurgeDeletedComments(ctx context.Context, olderThan int) (int64, error) {
-	query := `DELETE FROM comments WHERE is_deleted = true AND updated_at < NOW() - INTERVAL '%d days'`
+// GetRootSettings retrieves the overrides stored for a root
+func (r *PostgresRepository) GetRootSettings(ctx context.Context, rootID string) (_ *models.RootSettings, err error) {
+	ctx, span := r.startSpan(ctx, "GetRootSettings", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	query := `SELECT root_id, max_depth, locked, pre_moderation, allow_anonymous, updated_at FROM {root_settings} WHERE root_id = $1`
+
+	settings := &models.RootSettings{}
+	if err = r.getQueryable().GetContext(ctx, settings, query, rootID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get root settings: %w", err)
+	}
+	return settings, nil
+}
+
+// SetRootSettings replaces the overrides stored for a root
+func (r *PostgresRepository) SetRootSettings(ctx context.Context, settings *models.RootSettings) (err error) {
+	ctx, span := r.startSpan(ctx, "SetRootSettings", attrRootID.String(settings.RootID))
+	defer func() { endSpan(span, err) }()
+
+	query := `
+		INSERT INTO {root_settings} (root_id, max_depth, locked, pre_moderation, allow_anonymous, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (root_id) DO UPDATE SET
+			max_depth = EXCLUDED.max_depth,
+			locked = EXCLUDED.locked,
+			pre_moderation = EXCLUDED.pre_moderation,
+			allow_anonymous = EXCLUDED.allow_anonymous,
+			updated_at = EXCLUDED.updated_at`
+
+	settings.UpdatedAt = time.Now()
+	_, err = r.getDB().ExecContext(ctx, query,
+		settings.RootID, settings.MaxDepth, settings.Locked, settings.PreModeration, settings.AllowAnonymous, settings.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to set root settings: %w", err)
+	}
+	return nil
+}
+
+// AddBlock stops blockerID seeing comments by blockedID
+func (r *PostgresRepository) AddBlock(ctx context.Context, blockerID, blockedID string) (err error) {
+	ctx, span := r.startSpan(ctx, "AddBlock")
+	defer func() { endSpan(span, err) }()
+
+	query := `
+		INSERT INTO {user_blocks} (blocker_id, blocked_id)
+		VALUES ($1, $2)
+		ON CONFLICT (blocker_id, blocked_id) DO NOTHING`
+
+	_, err = r.getDB().ExecContext(ctx, query, blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("failed to add block: %w", err)
+	}
+	return nil
+}
+
+// RemoveBlock lifts a block added with AddBlock
+func (r *PostgresRepository) RemoveBlock(ctx context.Context, blockerID, blockedID string) (err error) {
+	ctx, span := r.startSpan(ctx, "RemoveBlock")
+	defer func() { endSpan(span, err) }()
+
+	query := `DELETE FROM {user_blocks} WHERE blocker_id = $1 AND blocked_id = $2`
+
+	_, err = r.getDB().ExecContext(ctx, query, blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("failed to remove block: %w", err)
+	}
+	return nil
+}
+
+// GetBlockedUsers lists the users blockerID has blocked, sorted
+func (r *PostgresRepository) GetBlockedUsers(ctx context.Context, blockerID string) (_ []string, err error) {
+	ctx, span := r.startSpan(ctx, "GetBlockedUsers")
+	defer func() { endSpan(span, err) }()
+
+	query := `SELECT blocked_id FROM {user_blocks} WHERE blocker_id = $1 ORDER BY blocked_id`
+
+	blocked := []string{}
+	err = r.getQueryable().SelectContext(ctx, &blocked, query, blockerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked users: %w", err)
+	}
+	return blocked, nil
+}
+
+// Subscribe makes userID a subscriber of rootID
+func (r *PostgresRepository) Subscribe(ctx context.Context, userID, rootID string) (err error) {
+	ctx, span := r.startSpan(ctx, "Subscribe", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	query := `
+		INSERT INTO {subscriptions} (root_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (root_id, user_id) DO NOTHING`
+
+	_, err = r.getDB().ExecContext(ctx, query, rootID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+	return nil
+}
+
+// Unsubscribe drops userID from the subscribers of rootID
+func (r *PostgresRepository) Unsubscribe(ctx context.Context, userID, rootID string) (err error) {
+	ctx, span := r.startSpan(ctx, "Unsubscribe", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	query := `DELETE FROM {subscriptions} WHERE root_id = $1 AND user_id = $2`
+
+	_, err = r.getDB().ExecContext(ctx, query, rootID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to unsubscribe: %w", err)
+	}
+	return nil
+}
+
+// GetSubscriberUserIDs lists the users subscribed to rootID, sorted
+func (r *PostgresRepository) GetSubscriberUserIDs(ctx context.Context, rootID string) (_ []string, err error) {
+	ctx, span := r.startSpan(ctx, "GetSubscriberUserIDs", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	query := `SELECT user_id FROM {subscriptions} WHERE root_id = $1 ORDER BY user_id`
+
+	subscribers := []string{}
+	err = r.getQueryable().SelectContext(ctx, &subscribers, query, rootID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscribers: %w", err)
+	}
+	return subscribers, nil
+}
+
+// notBlockedClause keeps rows whose author column the viewer bound to
+// $viewerArg has not blocked
+func notBlockedClause(column string, viewerArg int) string {
+	return fmt.Sprintf("%s NOT IN (SELECT blocked_id FROM {user_blocks} WHERE blocker_id = $%d)", column, viewerArg)
+}
+
+// SetLinkPreview stores the preview fetched for a comment's link. It only
+// applies while link_url still matches preview.URL, so a fetch that finishes
+// after the link was edited is dropped.
+func (r *PostgresRepository) SetLinkPreview(ctx context.Context, id string, preview *models.LinkPreview) (err error) {
+	ctx, span := r.startSpan(ctx, "SetLinkPreview", attrCommentID.String(id))
+	defer func() { endSpan(span, err) }()
+
+	result, err := r.getDB().ExecContext(ctx,
+		`UPDATE {comments} SET link_preview = $2 WHERE id = $1 AND link_url = $3 AND NOT is_deleted`, id, preview, preview.URL)
+	if err != nil {
+		return fmt.Errorf("failed to set link preview: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// PurgeDeletedComments permanently deletes soft-deleted comments older than
+// the given number of days, along with their votes
+func (r *PostgresRepository) PurgeDeletedComments(ctx context.Context, olderThan int) (_ int64, err error) {
+	ctx, span := r.startSpan(ctx, "PurgeDeletedComments")
+	defer func() { endSpan(span, err) }()
+
+	// Votes are removed explicitly rather than left to ON DELETE CASCADE, so
+	// schemas created without the cascade don't accumulate orphans
+	query := `
+		WITH purged AS (
+			SELECT id FROM {comments}
+			WHERE is_deleted AND updated_at < NOW() - make_interval(days => $1)
+		), purged_votes AS (
+			DELETE FROM {votes} WHERE comment_id IN (SELECT id FROM purged)
+		)
+		DELETE FROM {comments} WHERE id IN (SELECT id FROM purged)`
 
-	result, err := r.getDB().ExecContext(ctx, fmt.Sprintf(query, olderThan))
+	result, err := r.getDB().ExecContext(ctx, query, olderThan)
 	if err != nil {
 		return 0, fmt.Errorf("failed to purge deleted comments: %w", err)
 	}
@@ -742,10 +2083,11 @@ func (r *PostgresRepository) PurgeDeletedComments(ctx context.Context, olderThan
 // RecalculateCommentScores recalculates all comment scores
 func (r *PostgresRepository) RecalculateCommentScores(ctx context.Context) error {
 	query := `
-		UPDATE comments 
+		UPDATE {comments} 
 		SET 
-			upvotes = (SELECT COUNT(*) FROM votes WHERE comment_id = comments.id AND vote_type = 1),
-			downvotes = (SELECT COUNT(*) FROM votes WHERE comment_id = comments.id AND vote_type = -1),
+			upvotes = (SELECT COUNT(*) FROM {votes} WHERE comment_id = {comments}.id AND vote_type = 1),
+			downvotes = (SELECT COUNT(*) FROM {votes} WHERE comment_id = {comments}.id AND vote_type = -1),
+			score = ` + weightedScore + `,
 			updated_at = NOW()`
 
 	_, err := r.getDB().ExecContext(ctx, query)
@@ -753,11 +2095,70 @@ func (r *PostgresRepository) RecalculateCommentScores(ctx context.Context) error
 		return fmt.Errorf("failed to recalculate vote counts: %w", err)
 	}
 
-	// Update calculated scores
-	scoreQuery := `UPDATE comments SET score = upvotes - downvotes`
-	_, err = r.getDB().ExecContext(ctx, scoreQuery)
+	return nil
+}
+
+// RecalculateDecayedScores recomputes decayed scores for all comments, halving
+// each vote's weight for every halfLife between its creation and now
+func (r *PostgresRepository) RecalculateDecayedScores(ctx context.Context, halfLife time.Duration, now time.Time) error {
+	query := `
+		UPDATE {comments}
+		SET decayed_score = COALESCE((
+			SELECT SUM(v.vote_type * v.weight * POWER(0.5, GREATEST(EXTRACT(EPOCH FROM ($1::timestamptz - v.created_at)), 0) / $2::float8))
+			FROM {votes} v
+			WHERE v.comment_id = {comments}.id
+		), 0)
+		WHERE NOT is_deleted`
+
+	_, err := r.getDB().ExecContext(ctx, query, now, halfLife.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to recalculate decayed scores: %w", err)
+	}
+
+	return nil
+}
+
+// RepairCommentPaths rebuilds path and depth for every comment in a root by
+// walking parent_id down from the top-level comments, and returns the number of
+// rows that had drifted
+func (r *PostgresRepository) RepairCommentPaths(ctx context.Context, rootID string) (int64, error) {
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT id, id::text AS path, 0 AS depth
+			FROM {comments}
+			WHERE root_id = $1 AND parent_id IS NULL
+			UNION ALL
+			SELECT c.id, t.path || '.' || c.id::text, t.depth + 1
+			FROM {comments} c
+			JOIN tree t ON c.parent_id = t.id
+			WHERE c.root_id = $1
+		)
+		UPDATE {comments} c
+		SET path = t.path, depth = t.depth
+		FROM tree t
+		WHERE c.id = t.id AND (c.path <> t.path OR c.depth IS DISTINCT FROM t.depth)`
+
+	result, err := r.getDB().ExecContext(ctx, query, rootID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to repair comment paths: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// RecalculateReplyCounts recomputes reply and descendant counts for all comments
+func (r *PostgresRepository) RecalculateReplyCounts(ctx context.Context) error {
+	query := `
+		UPDATE {comments} c
+		SET
+			reply_count = (SELECT COUNT(*) FROM {comments} r
+			               WHERE r.parent_id = c.id AND NOT r.is_deleted AND r.status = 'approved'),
+			descendant_count = (SELECT COUNT(*) FROM {comments} d
+			                    WHERE left(d.path, length(c.path) + 1) = c.path || '.' AND NOT d.is_deleted AND d.status = 'approved')`
+
+	_, err := r.getDB().ExecContext(ctx, query)
 	if err != nil {
-		return fmt.Errorf("failed to recalculate scores: %w", err)
+		return fmt.Errorf("failed to recalculate reply counts: %w", err)
 	}
 
 	return nil
@@ -770,7 +2171,7 @@ func (r *PostgresRepository) BeginTx(ctx context.Context) (repository.Repository
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
-	return &PostgresRepository{db: r.db, tx: tx}, nil
+	return &PostgresRepository{db: r.db, tx: tx, tracer: r.tracer, tables: r.tables}, nil
 }
 
 func (r *PostgresRepository) CommitTx(ctx context.Context) error {
@@ -0,0 +1,103 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+func TestPurgeDeletedComments_RemovesVotes(t *testing.T) {
+	// Setup
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	old := &models.Comment{RootID: "purge-1", UserID: "alice", Content: "Deleted long ago"}
+	recent := &models.Comment{RootID: "purge-1", UserID: "alice", Content: "Deleted today"}
+	for _, comment := range []*models.Comment{old, recent} {
+		if err := repo.CreateComment(ctx, comment); err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		if err := repo.UpdateVote(ctx, comment.ID, "voter", models.VoteTypeUp); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+		if err := repo.DeleteComment(ctx, comment.ID, "alice", nil); err != nil {
+			t.Fatalf("Failed to delete comment: %v", err)
+		}
+	}
+
+	// Backdate the deletion; the edit tracking trigger would otherwise reset updated_at
+	tx := db.MustBeginTx(ctx, nil)
+	tx.MustExecContext(ctx, `SET LOCAL commentific.importing = 'on'`)
+	tx.MustExecContext(ctx, `UPDATE comments SET updated_at = NOW() - INTERVAL '60 days' WHERE id = $1`, old.ID)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to backdate comment: %v", err)
+	}
+
+	// Execute
+	purged, err := repo.PurgeDeletedComments(ctx, 30)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if purged != 1 {
+		t.Fatalf("Expected 1 comment purged, got %d", purged)
+	}
+	var votes int
+	if err := db.GetContext(ctx, &votes, `SELECT COUNT(*) FROM votes WHERE comment_id = $1`, old.ID); err != nil {
+		t.Fatalf("Failed to count votes: %v", err)
+	}
+	if votes != 0 {
+		t.Fatalf("Expected the purged comment's votes to be gone, got %d", votes)
+	}
+	if err := db.GetContext(ctx, &votes, `SELECT COUNT(*) FROM votes WHERE comment_id = $1`, recent.ID); err != nil {
+		t.Fatalf("Failed to count votes: %v", err)
+	}
+	if votes != 1 {
+		t.Fatalf("Expected the recently deleted comment to keep its vote, got %d", votes)
+	}
+}
+
+func TestCascadeVoteDeletesMigration(t *testing.T) {
+	// Setup: a schema that lost the cascade and collected an orphaned vote
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	comment := &models.Comment{RootID: "purge-2", UserID: "alice", Content: "Voted on"}
+	if err := repo.CreateComment(ctx, comment); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	db.MustExecContext(ctx, `ALTER TABLE votes DROP CONSTRAINT votes_comment_id_fkey`)
+	db.MustExecContext(ctx, `INSERT INTO votes (comment_id, user_id, vote_type) VALUES (uuid_generate_v4(), 'voter', 1)`)
+	migration, err := os.ReadFile("../migrations/012_cascade_vote_deletes.up.sql")
+	if err != nil {
+		t.Fatalf("Failed to read migration: %v", err)
+	}
+
+	// Execute
+	if _, err := db.ExecContext(ctx, string(migration)); err != nil {
+		t.Fatalf("Failed to apply migration: %v", err)
+	}
+
+	// Assert
+	var orphans int
+	if err := db.GetContext(ctx, &orphans, `SELECT COUNT(*) FROM votes v WHERE NOT EXISTS (SELECT 1 FROM comments c WHERE c.id = v.comment_id)`); err != nil {
+		t.Fatalf("Failed to count orphaned votes: %v", err)
+	}
+	if orphans != 0 {
+		t.Fatalf("Expected orphaned votes to be removed, got %d", orphans)
+	}
+	if err := repo.UpdateVote(ctx, comment.ID, "voter", models.VoteTypeUp); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+	db.MustExecContext(ctx, `DELETE FROM comments WHERE id = $1`, comment.ID)
+	var votes int
+	if err := db.GetContext(ctx, &votes, `SELECT COUNT(*) FROM votes WHERE comment_id = $1`, comment.ID); err != nil {
+		t.Fatalf("Failed to count votes: %v", err)
+	}
+	if votes != 0 {
+		t.Fatalf("Expected the restored cascade to remove the comment's votes, got %d", votes)
+	}
+}
@@ -0,0 +1,148 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+func TestGetComments_HotSort(t *testing.T) {
+	// Setup
+	repo, db := newTestRepository(t)
+	now := time.Now()
+	old := seedComment(t, repo, db, "product-1", 50, now.Add(-3*24*time.Hour))
+	fresh := seedComment(t, repo, db, "product-1", 5, now.Add(-time.Hour))
+
+	// Execute
+	hot, err := repo.GetCommentsByRootID(context.Background(), "product-1", &models.CommentFilter{SortBy: "hot"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	byScore, err := repo.GetCommentsByRootID(context.Background(), "product-1", &models.CommentFilter{SortBy: "score"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if ids := commentIDs(hot); len(ids) != 2 || ids[0] != fresh.ID {
+		t.Fatalf("Expected fresh comment first under hot, got: %v", ids)
+	}
+	if ids := commentIDs(byScore); len(ids) != 2 || ids[0] != old.ID {
+		t.Fatalf("Expected old comment first under score, got: %v", ids)
+	}
+}
+
+func TestGetCommentTree_HotSort(t *testing.T) {
+	// Setup
+	repo, db := newTestRepository(t)
+	now := time.Now()
+	seedComment(t, repo, db, "product-1", 50, now.Add(-3*24*time.Hour))
+	fresh := seedComment(t, repo, db, "product-1", 5, now.Add(-time.Hour))
+
+	// Execute
+	tree, err := repo.GetCommentTree(context.Background(), "product-1", 10, "hot")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if len(tree) != 2 || tree[0].Comment.ID != fresh.ID {
+		t.Fatalf("Expected fresh comment first in hot tree, got %d roots", len(tree))
+	}
+}
+
+func TestGetComments_BestSort(t *testing.T) {
+	// Setup
+	repo, db := newTestRepository(t)
+	now := time.Now()
+	noisy := seedComment(t, repo, db, "product-1", 0, now)
+	unanimous := seedComment(t, repo, db, "product-1", 0, now)
+	if _, err := db.Exec(`UPDATE comments SET upvotes = 200, downvotes = 150, score = 50 WHERE id = $1`, noisy.ID); err != nil {
+		t.Fatalf("Failed to seed votes: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE comments SET upvotes = 50, downvotes = 0, score = 50 WHERE id = $1`, unanimous.ID); err != nil {
+		t.Fatalf("Failed to seed votes: %v", err)
+	}
+
+	// Execute
+	best, err := repo.GetCommentsByRootID(context.Background(), "product-1", &models.CommentFilter{SortBy: "best"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if ids := commentIDs(best); len(ids) != 2 || ids[0] != unanimous.ID {
+		t.Fatalf("Expected 50/0 comment first under best, got: %v", ids)
+	}
+}
+
+func TestGetComments_ControversialSortPaginates(t *testing.T) {
+	// Setup
+	repo, db := newTestRepository(t)
+	now := time.Now()
+	seeded := map[string][2]int{}
+	var contestedID string
+	for _, votes := range [][2]int{{200, 0}, {100, 100}, {2, 2}} {
+		comment := seedComment(t, repo, db, "product-1", 0, now)
+		if _, err := db.Exec(`UPDATE comments SET upvotes = $1, downvotes = $2, score = $1 - $2 WHERE id = $3`, votes[0], votes[1], comment.ID); err != nil {
+			t.Fatalf("Failed to seed votes: %v", err)
+		}
+		seeded[comment.ID] = votes
+		if votes == [2]int{100, 100} {
+			contestedID = comment.ID
+		}
+	}
+
+	// Execute
+	limit, offset := 1, 0
+	first, err := repo.GetCommentsByRootID(context.Background(), "product-1", &models.CommentFilter{SortBy: "controversial", Limit: &limit, Offset: &offset})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	offset = 1
+	second, err := repo.GetCommentsByRootID(context.Background(), "product-1", &models.CommentFilter{SortBy: "controversial", Limit: &limit, Offset: &offset})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if ids := commentIDs(first); len(ids) != 1 || ids[0] != contestedID {
+		t.Fatalf("Expected 100/100 comment on the first page, got: %v", ids)
+	}
+	if ids := commentIDs(second); len(ids) != 1 || seeded[ids[0]] != [2]int{2, 2} {
+		t.Fatalf("Expected 2/2 comment on the second page, got: %v", ids)
+	}
+}
+
+func TestRecalculateDecayedScores(t *testing.T) {
+	// Setup
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	now := time.Now()
+	comment := seedComment(t, repo, db, "product-1", 0, now)
+	if err := repo.UpdateVote(ctx, comment.ID, "voter-1", models.VoteTypeUp); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+	halfLife := 24 * time.Hour
+	if _, err := db.Exec(`UPDATE votes SET created_at = $1 WHERE comment_id = $2`, now.Add(-halfLife), comment.ID); err != nil {
+		t.Fatalf("Failed to backdate vote: %v", err)
+	}
+
+	// Execute
+	if err := repo.RecalculateDecayedScores(ctx, halfLife, now); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	updated, err := repo.GetCommentByID(ctx, comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+
+	// Assert
+	if updated.DecayedScore == nil || *updated.DecayedScore < 0.49 || *updated.DecayedScore > 0.51 {
+		t.Fatalf("Expected decayed score of 0.5 after one half-life, got: %v", updated.DecayedScore)
+	}
+}
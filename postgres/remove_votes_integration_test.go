@@ -0,0 +1,61 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestDeleteVotes(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	commentService := service.NewCommentService(repo)
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "remove-votes-1", UserID: "alice", Content: "Vote on me"})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		ids = append(ids, comment.ID)
+	}
+	for _, commentID := range ids[:2] {
+		if _, _, _, err := commentService.VoteComment(ctx, commentID, "carol", models.VoteTypeUp); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, ids[0], "dave", models.VoteTypeDown); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	// Execute
+	removed, err := repo.DeleteVotes(ctx, ids, "carol")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	want := append([]string{}, ids[:2]...)
+	sort.Strings(want)
+	sort.Strings(removed)
+	if strings.Join(removed, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected votes removed from %v, got %v", want, removed)
+	}
+	comments, err := repo.GetCommentsByIDs(ctx, ids[:2], false)
+	if err != nil {
+		t.Fatalf("Failed to get comments: %v", err)
+	}
+	if comments[0].Upvotes != 0 || comments[0].Downvotes != 1 || comments[0].Score != -1 {
+		t.Errorf("Expected only dave's downvote left on the first comment, got %+v", comments[0])
+	}
+	if comments[1].Upvotes != 0 || comments[1].Score != 0 {
+		t.Errorf("Expected no votes left on the second comment, got %+v", comments[1])
+	}
+}
@@ -0,0 +1,52 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+func TestRepairCommentPaths(t *testing.T) {
+	// Setup
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	parent := &models.Comment{RootID: "product-1", UserID: "author", Content: "Parent"}
+	if err := repo.CreateComment(ctx, parent); err != nil {
+		t.Fatalf("Failed to create parent: %v", err)
+	}
+	child := &models.Comment{RootID: "product-1", ParentID: &parent.ID, UserID: "author", Content: "Child"}
+	if err := repo.CreateComment(ctx, child); err != nil {
+		t.Fatalf("Failed to create child: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE comments SET path = 'corrupted', depth = 7 WHERE id = $1`, child.ID); err != nil {
+		t.Fatalf("Failed to corrupt path: %v", err)
+	}
+
+	// Execute
+	repaired, err := repo.RepairCommentPaths(ctx, "product-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if repaired != 1 {
+		t.Fatalf("Expected 1 repaired row, got: %d", repaired)
+	}
+	restored, err := repo.GetCommentByID(ctx, child.ID)
+	if err != nil {
+		t.Fatalf("Failed to get child: %v", err)
+	}
+	if restored.Path != parent.ID+"."+child.ID || restored.Depth != 1 {
+		t.Fatalf("Expected path %s at depth 1, got: %s at depth %d", parent.ID+"."+child.ID, restored.Path, restored.Depth)
+	}
+
+	// A consistent root needs no changes
+	repaired, err = repo.RepairCommentPaths(ctx, "product-1")
+	if err != nil || repaired != 0 {
+		t.Fatalf("Expected no further repairs, got: %d (%v)", repaired, err)
+	}
+}
@@ -0,0 +1,48 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+func TestGetRepliesToUser(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	create := func(userID string, parentID *string) *models.Comment {
+		t.Helper()
+		comment := &models.Comment{RootID: "product-1", ParentID: parentID, UserID: userID, Content: "Comment by " + userID}
+		if err := repo.CreateComment(ctx, comment); err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		return comment
+	}
+	question := create("alice", nil)
+	fromBob := create("bob", &question.ID)
+	fromCarol := create("carol", &question.ID)
+	create("alice", &question.ID)
+	create("dave", &fromBob.ID)
+
+	// Execute
+	replies, err := repo.GetRepliesToUser(ctx, "alice", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if len(replies) != 2 {
+		t.Fatalf("Expected 2 replies, got: %d", len(replies))
+	}
+	if replies[0].ID != fromCarol.ID || replies[1].ID != fromBob.ID {
+		t.Errorf("Expected carol's reply then bob's, got: %s, %s", replies[0].UserID, replies[1].UserID)
+	}
+	for _, reply := range replies {
+		if reply.Parent == nil || reply.Parent.ID != question.ID || reply.Parent.UserID != "alice" {
+			t.Errorf("Expected each reply to carry alice's comment, got: %+v", reply.Parent)
+		}
+	}
+}
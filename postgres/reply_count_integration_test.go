@@ -0,0 +1,61 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+func TestReplyCounts_MaintainedByTrigger(t *testing.T) {
+	// Setup
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	root := &models.Comment{RootID: "product-1", UserID: "author", Content: "Root"}
+	if err := repo.CreateComment(ctx, root); err != nil {
+		t.Fatalf("Failed to create root: %v", err)
+	}
+	reply := &models.Comment{RootID: "product-1", ParentID: &root.ID, UserID: "replier", Content: "Reply"}
+	if err := repo.CreateComment(ctx, reply); err != nil {
+		t.Fatalf("Failed to create reply: %v", err)
+	}
+	nested := &models.Comment{RootID: "product-1", ParentID: &reply.ID, UserID: "nester", Content: "Nested"}
+	if err := repo.CreateComment(ctx, nested); err != nil {
+		t.Fatalf("Failed to create nested reply: %v", err)
+	}
+
+	// Assert
+	got, err := repo.GetCommentByID(ctx, root.ID)
+	if err != nil {
+		t.Fatalf("Failed to get root: %v", err)
+	}
+	if got.ReplyCount != 1 || got.DescendantCount != 2 {
+		t.Fatalf("Expected 1 reply and 2 descendants, got %d and %d", got.ReplyCount, got.DescendantCount)
+	}
+
+	// Execute: delete the nested reply
+	if err := repo.DeleteComment(ctx, nested.ID, "nester", nil); err != nil {
+		t.Fatalf("Failed to delete: %v", err)
+	}
+
+	// Assert
+	got, _ = repo.GetCommentByID(ctx, root.ID)
+	if got.ReplyCount != 1 || got.DescendantCount != 1 {
+		t.Fatalf("Expected 1 reply and 1 descendant after delete, got %d and %d", got.ReplyCount, got.DescendantCount)
+	}
+
+	// Backfill from scratch matches the trigger-maintained values
+	if _, err := db.Exec(`UPDATE comments SET reply_count = 0, descendant_count = 0`); err != nil {
+		t.Fatalf("Failed to reset counts: %v", err)
+	}
+	if err := repo.RecalculateReplyCounts(ctx); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	got, _ = repo.GetCommentByID(ctx, root.ID)
+	if got.ReplyCount != 1 || got.DescendantCount != 1 {
+		t.Fatalf("Expected backfill to restore 1 reply and 1 descendant, got %d and %d", got.ReplyCount, got.DescendantCount)
+	}
+}
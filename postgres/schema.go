@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSchemaNotReady is returned by CheckSchema while the migrations have not
+// all been applied
+var ErrSchemaNotReady = errors.New("database schema not ready")
+
+// schemaColumns are the columns CheckSchema looks for: on each table, one
+// added by the newest migration that touches it, standing in for the
+// migrations before. A migration that adds a column should replace its
+// table's entry.
+var schemaColumns = []struct{ table, column string }{
+	{"comments", "deleted_by"}, // 015
+	{"votes", "weight"},        // 020
+	{"root_settings", "root_id"},
+	{"user_blocks", "blocker_id"},
+	{"subscriptions", "root_id"},
+}
+
+// CheckSchema reports whether the migrations have created the tables the
+// repository queries, under the provider's table prefix, returning an error
+// matching ErrSchemaNotReady that names what is missing if not. Tables are
+// looked up on the search_path, as the queries find them.
+func (p *PostgresProvider) CheckSchema(ctx context.Context) error {
+	return p.checkSchema(ctx, p.db)
+}
+
+// checkSchema is CheckSchema run through q, which may be a transaction
+func (p *PostgresProvider) checkSchema(ctx context.Context, q queryer) error {
+	var missing []string
+	for _, want := range schemaColumns {
+		table := p.tables.name(want.table)
+		var exists bool
+		err := q.QueryRowxContext(ctx, `
+			SELECT EXISTS (
+				SELECT FROM pg_attribute
+				WHERE attrelid = to_regclass($1) AND attname = $2 AND NOT attisdropped
+			)`, table, want.column).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to check schema: %w", err)
+		}
+		if !exists {
+			missing = append(missing, table+"."+want.column)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", ErrSchemaNotReady, strings.Join(missing, ", "))
+	}
+	return nil
+}
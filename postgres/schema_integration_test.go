@@ -0,0 +1,45 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/postgres"
+)
+
+func TestCheckSchema(t *testing.T) {
+	// Setup
+	_, db := newPrefixedTestRepository(t, "cmt_")
+	ctx := context.Background()
+	migrated := postgres.NewPostgresProvider(db)
+	if err := migrated.SetTablePrefix("cmt_"); err != nil {
+		t.Fatalf("Failed to set table prefix: %v", err)
+	}
+	unmigrated := postgres.NewPostgresProvider(db)
+	if err := unmigrated.SetTablePrefix("unmigrated_"); err != nil {
+		t.Fatalf("Failed to set table prefix: %v", err)
+	}
+
+	// Execute
+	migratedErr := migrated.CheckSchema(ctx)
+	unmigratedErr := unmigrated.CheckSchema(ctx)
+	if _, err := db.Exec(`ALTER TABLE cmt_votes DROP COLUMN weight CASCADE`); err != nil {
+		t.Fatalf("Failed to undo migration 020: %v", err)
+	}
+	partialErr := migrated.CheckSchema(ctx)
+
+	// Assert
+	if migratedErr != nil {
+		t.Errorf("Expected the migrated schema to pass, got: %v", migratedErr)
+	}
+	if !errors.Is(unmigratedErr, postgres.ErrSchemaNotReady) || !strings.Contains(unmigratedErr.Error(), "unmigrated_comments.deleted_by") {
+		t.Errorf("Expected ErrSchemaNotReady naming the missing comments table, got: %v", unmigratedErr)
+	}
+	if !errors.Is(partialErr, postgres.ErrSchemaNotReady) || !strings.HasSuffix(partialErr.Error(), "missing cmt_votes.weight") {
+		t.Errorf("Expected ErrSchemaNotReady naming only the weight column, got: %v", partialErr)
+	}
+}
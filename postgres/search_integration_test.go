@@ -0,0 +1,42 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+func TestGetComments_Search(t *testing.T) {
+	// Setup
+	repo, db := newTestRepository(t)
+	now := time.Now()
+	first := seedComment(t, repo, db, "product-1", 0, now)
+	second := seedComment(t, repo, db, "product-2", 0, now.Add(-time.Minute))
+	other := seedComment(t, repo, db, "product-3", 0, now)
+	for id, content := range map[string]string{
+		first.ID:  "Great battery life",
+		second.ID: "The batteries died quickly",
+		other.ID:  "Nice screen",
+	} {
+		if _, err := db.Exec(`UPDATE comments SET content = $1 WHERE id = $2`, content, id); err != nil {
+			t.Fatalf("Failed to seed content: %v", err)
+		}
+	}
+
+	// Execute
+	search := "battery"
+	results, err := repo.GetComments(context.Background(), &models.CommentFilter{Search: &search})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert: stemming matches "batteries" in a different root
+	got := commentIDs(results)
+	if len(got) != 2 || got[0] != first.ID || got[1] != second.ID {
+		t.Fatalf("Expected [%s %s], got: %v", first.ID, second.ID, got)
+	}
+}
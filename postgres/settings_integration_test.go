@@ -0,0 +1,41 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/repository"
+)
+
+func TestRootSettings(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	locked, maxDepth := true, 3
+
+	// Execute
+	_, missingErr := repo.GetRootSettings(ctx, "article-1")
+	if err := repo.SetRootSettings(ctx, &models.RootSettings{RootID: "article-1", Locked: &locked, MaxDepth: &maxDepth}); err != nil {
+		t.Fatalf("Failed to set root settings: %v", err)
+	}
+	// A second write replaces the first, clearing what it leaves out
+	if err := repo.SetRootSettings(ctx, &models.RootSettings{RootID: "article-1", Locked: &locked}); err != nil {
+		t.Fatalf("Failed to replace root settings: %v", err)
+	}
+	settings, err := repo.GetRootSettings(ctx, "article-1")
+
+	// Assert
+	if !errors.Is(missingErr, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound before any settings are stored, got %v", missingErr)
+	}
+	if err != nil {
+		t.Fatalf("Failed to get root settings: %v", err)
+	}
+	if settings.Locked == nil || !*settings.Locked || settings.MaxDepth != nil || settings.PreModeration != nil || settings.UpdatedAt.IsZero() {
+		t.Errorf("Expected only the lock to be stored, got %+v", settings)
+	}
+}
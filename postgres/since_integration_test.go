@@ -0,0 +1,91 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+func TestGetCommentsSince(t *testing.T) {
+	// Setup: one comment before the cutoff, then a new one, a deleted one,
+	// a pending one, one deleted while pending and one on another root
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	seedComment(t, repo, db, "product-1", 0, time.Now())
+	var cutoff time.Time
+	if err := db.Get(&cutoff, `SELECT clock_timestamp()`); err != nil {
+		t.Fatalf("Failed to read the database clock: %v", err)
+	}
+	created := seedComment(t, repo, db, "product-1", 0, time.Now())
+	deleted := seedComment(t, repo, db, "product-1", 0, time.Now())
+	if err := repo.DeleteComment(ctx, deleted.ID, deleted.UserID, nil); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+	pending := seedComment(t, repo, db, "product-1", 0, time.Now())
+	if err := repo.SetCommentStatus(ctx, pending.ID, models.CommentStatusPending); err != nil {
+		t.Fatalf("Failed to hold comment: %v", err)
+	}
+	neverShown := seedComment(t, repo, db, "product-1", 0, time.Now())
+	if err := repo.SetCommentStatus(ctx, neverShown.ID, models.CommentStatusPending); err != nil {
+		t.Fatalf("Failed to hold comment: %v", err)
+	}
+	if err := repo.DeleteComment(ctx, neverShown.ID, neverShown.UserID, nil); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+	seedComment(t, repo, db, "product-2", 0, time.Now())
+
+	// Execute
+	comments, err := repo.GetCommentsSince(ctx, "product-1", cutoff, "", 0)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(comments) != 2 || comments[0].ID != created.ID || comments[1].ID != deleted.ID {
+		t.Fatalf("Expected the new comment then the deleted one, got %v", commentIDs(comments))
+	}
+	if !comments[1].IsDeleted {
+		t.Error("Expected the deleted comment to keep its flag")
+	}
+}
+
+func TestGetCommentsSince_PagesThroughTies(t *testing.T) {
+	// Setup: five comments updated by one statement share an updated_at
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		seedComment(t, repo, db, "product-1", 0, time.Now())
+	}
+	if _, err := db.Exec(`UPDATE comments SET content = 'Tied' WHERE root_id = 'product-1'`); err != nil {
+		t.Fatalf("Failed to tie the comments: %v", err)
+	}
+
+	// Execute
+	seen := make(map[string]bool)
+	since, afterID := time.Time{}, ""
+	for pages := 0; pages < 5; pages++ {
+		page, err := repo.GetCommentsSince(ctx, "product-1", since, afterID, 2)
+		if err != nil {
+			t.Fatalf("Failed to poll: %v", err)
+		}
+		for _, comment := range page {
+			if seen[comment.ID] {
+				t.Fatalf("Expected %s once, got it again", comment.ID)
+			}
+			seen[comment.ID] = true
+		}
+		if len(page) < 2 {
+			break
+		}
+		since, afterID = page[len(page)-1].UpdatedAt, page[len(page)-1].ID
+	}
+
+	// Assert
+	if len(seen) != 5 {
+		t.Errorf("Expected all 5 comments across the pages, got %d", len(seen))
+	}
+}
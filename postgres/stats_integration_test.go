@@ -0,0 +1,189 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+func TestGetCommentStatsBatch(t *testing.T) {
+	// Setup
+	repo, db := newTestRepository(t)
+	now := time.Now()
+	seedComment(t, repo, db, "product-1", 3, now)
+	seedComment(t, repo, db, "product-1", 2, now)
+	seedComment(t, repo, db, "product-2", -1, now)
+
+	// Execute
+	stats, err := repo.GetCommentStatsBatch(context.Background(), []string{"product-1", "product-2", "empty-product"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if got := stats["product-1"]; got == nil || got.TotalCount != 2 || got.TotalScore != 5 {
+		t.Fatalf("Expected 2 comments with score 5 for product-1, got: %+v", got)
+	}
+	if got := stats["product-2"]; got == nil || got.TotalCount != 1 || got.TotalScore != -1 {
+		t.Fatalf("Expected 1 comment with score -1 for product-2, got: %+v", got)
+	}
+	if got := stats["empty-product"]; got == nil || got.TotalCount != 0 {
+		t.Fatalf("Expected zero-valued stats for empty-product, got: %+v", got)
+	}
+}
+
+func TestGetCommentStats_Participants(t *testing.T) {
+	// Setup
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	older := time.Now().Add(-time.Hour)
+	newer := time.Now()
+	seedComment(t, repo, db, "product-1", 0, older)
+	seedComment(t, repo, db, "product-1", 0, newer)
+	other := &models.Comment{RootID: "product-1", UserID: "other", Content: "Deleted"}
+	if err := repo.CreateComment(ctx, other); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	if err := repo.DeleteComment(ctx, other.ID, "other", nil); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+
+	// Execute
+	stats, err := repo.GetCommentStats(ctx, "product-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if stats.ParticipantCount != 1 {
+		t.Errorf("Expected 1 participant, got: %d", stats.ParticipantCount)
+	}
+	if stats.AvgDepth != 0 {
+		t.Errorf("Expected an average depth of 0, got: %v", stats.AvgDepth)
+	}
+	if stats.LastCommentAt == nil || stats.LastCommentAt.Sub(newer).Abs() > time.Millisecond {
+		t.Errorf("Expected the last comment time %v, got: %v", newer, stats.LastCommentAt)
+	}
+}
+
+func TestGetTopCommenters(t *testing.T) {
+	// Setup
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	now := time.Now()
+	seedComment(t, repo, db, "product-1", 1, now)
+	seedComment(t, repo, db, "product-1", 1, now)
+	seedComment(t, repo, db, "product-2", 1, now)
+	for _, userID := range []string{"bob", "bob", "carol"} {
+		if err := repo.CreateComment(ctx, &models.Comment{RootID: "product-1", UserID: userID, Content: "Hello"}); err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+	}
+	deleted := &models.Comment{RootID: "product-1", UserID: "carol", Content: "Deleted"}
+	if err := repo.CreateComment(ctx, deleted); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	if err := repo.DeleteComment(ctx, deleted.ID, "carol", nil); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+
+	// Execute
+	commenters, err := repo.GetTopCommenters(ctx, "product-1", 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	want := []models.TopCommenter{
+		{UserID: "author", CommentCount: 2, TotalScore: 2},
+		{UserID: "bob", CommentCount: 2, TotalScore: 0},
+		{UserID: "carol", CommentCount: 1, TotalScore: 0},
+	}
+	if len(commenters) != len(want) {
+		t.Fatalf("Expected %d commenters, got: %+v", len(want), commenters)
+	}
+	for i := range want {
+		if *commenters[i] != want[i] {
+			t.Errorf("Expected %+v at position %d, got: %+v", want[i], i, *commenters[i])
+		}
+	}
+}
+
+func TestGetUserReputation(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	var comments []*models.Comment
+	for _, rootID := range []string{"product-1", "product-2"} {
+		comment := &models.Comment{RootID: rootID, UserID: "author", Content: "Hello"}
+		if err := repo.CreateComment(ctx, comment); err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		comments = append(comments, comment)
+	}
+	votes := []*models.Vote{
+		{CommentID: comments[0].ID, UserID: "voter-1", VoteType: models.VoteTypeUp},
+		{CommentID: comments[0].ID, UserID: "voter-2", VoteType: models.VoteTypeUp, Weight: 3},
+		{CommentID: comments[1].ID, UserID: "voter-1", VoteType: models.VoteTypeDown},
+		{CommentID: comments[1].ID, UserID: "author", VoteType: models.VoteTypeUp},
+	}
+	for _, vote := range votes {
+		if err := repo.CreateVote(ctx, vote); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+	}
+
+	// Execute
+	reputation, err := repo.GetUserReputation(ctx, "author")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert: the weight-3 upvote counts three times toward the score
+	want := models.UserReputation{UserID: "author", CommentCount: 2, TotalScore: 3, Upvotes: 2, Downvotes: 1}
+	if *reputation != want {
+		t.Errorf("Expected %+v, got: %+v", want, *reputation)
+	}
+}
+
+func TestGetRootVersion(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	empty, err := repo.GetRootVersion(ctx, "product-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	comment := &models.Comment{RootID: "product-1", UserID: "author", Content: "Versioned"}
+	if err := repo.CreateComment(ctx, comment); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	created, err := repo.GetRootVersion(ctx, "product-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Execute
+	if err := repo.CreateVote(ctx, &models.Vote{CommentID: comment.ID, UserID: "voter", VoteType: models.VoteTypeUp}); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+	voted, err := repo.GetRootVersion(ctx, "product-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if empty.CommentCount != 0 || empty.LastUpdatedAt != nil {
+		t.Errorf("Expected an empty root to have no comments or update time, got: %+v", empty)
+	}
+	if created.CommentCount != 1 || created.LastUpdatedAt == nil {
+		t.Fatalf("Expected one comment with an update time, got: %+v", created)
+	}
+	if voted.CommentCount != 1 || voted.LastUpdatedAt == nil || !voted.LastUpdatedAt.After(*created.LastUpdatedAt) {
+		t.Errorf("Expected the vote to move the update time past %v, got: %+v", created.LastUpdatedAt, voted)
+	}
+}
@@ -0,0 +1,41 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSubscriptions(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+
+	// Execute
+	for _, userID := range []string{"bob", "alice", "bob"} {
+		if err := repo.Subscribe(ctx, userID, "product-1"); err != nil {
+			t.Fatalf("Failed to subscribe %s: %v", userID, err)
+		}
+	}
+	subscribed, err := repo.GetSubscriberUserIDs(ctx, "product-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := repo.Unsubscribe(ctx, "bob", "product-1"); err != nil {
+		t.Fatalf("Failed to unsubscribe: %v", err)
+	}
+	unsubscribed, err := repo.GetSubscriberUserIDs(ctx, "product-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if strings.Join(subscribed, ",") != "alice,bob" {
+		t.Errorf("Expected alice and bob once each, got: %v", subscribed)
+	}
+	if strings.Join(unsubscribed, ",") != "alice" {
+		t.Errorf("Expected only alice after bob unsubscribed, got: %v", unsubscribed)
+	}
+}
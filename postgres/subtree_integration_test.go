@@ -0,0 +1,129 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/repository"
+	"github.com/google/uuid"
+)
+
+// seedWideThread creates width top-level comments on rootID, each with two
+// replies, plus a target thread that is a chain depth levels deep. It returns
+// the target's top-level comment.
+func seedWideThread(tb testing.TB, repo repository.CommentRepository, rootID string, width, depth int) *models.Comment {
+	tb.Helper()
+	ctx := context.Background()
+
+	create := func(parentID *string) *models.Comment {
+		comment := &models.Comment{RootID: rootID, ParentID: parentID, UserID: "author", Content: "Comment"}
+		if err := repo.CreateComment(ctx, comment); err != nil {
+			tb.Fatalf("Failed to create comment: %v", err)
+		}
+		return comment
+	}
+
+	for i := 0; i < width; i++ {
+		sibling := create(nil)
+		create(&sibling.ID)
+		create(&sibling.ID)
+	}
+
+	target := create(nil)
+	parent := target
+	for i := 0; i < depth; i++ {
+		parent = create(&parent.ID)
+	}
+	return target
+}
+
+func TestGetCommentSubtree(t *testing.T) {
+	// Setup: a -> b -> c -> d, with a deleted reply e under a and an unrelated top-level comment
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	a := seedWideThread(t, repo, "post-1", 1, 3)
+	e := &models.Comment{RootID: "post-1", ParentID: &a.ID, UserID: "author", Content: "Deleted reply"}
+	if err := repo.CreateComment(ctx, e); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	if err := repo.DeleteComment(ctx, e.ID, "author", nil); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+
+	// Execute
+	tree, err := repo.GetCommentSubtree(ctx, a.ID, 2, "created_at")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if tree.Comment.ID != a.ID {
+		t.Fatalf("Expected the subtree rooted at %s, got %s", a.ID, tree.Comment.ID)
+	}
+	if len(tree.Children) != 1 {
+		t.Fatalf("Expected only the live reply, got %d children", len(tree.Children))
+	}
+	b := tree.Children[0]
+	if len(b.Children) != 1 || len(b.Children[0].Children) != 0 {
+		t.Fatal("Expected the subtree to stop two levels below the comment")
+	}
+
+	// A reply can be the root of its own subtree
+	sub, err := repo.GetCommentSubtree(ctx, b.Comment.ID, 10, "created_at")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if sub.Comment.ID != b.Comment.ID || len(sub.Children) != 1 || len(sub.Children[0].Children) != 1 {
+		t.Fatalf("Expected the reply's two levels of descendants, got %+v", sub)
+	}
+
+	if _, err := repo.GetCommentSubtree(ctx, e.ID, 10, ""); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound for a deleted comment, got: %v", err)
+	}
+	if _, err := repo.GetCommentSubtree(ctx, uuid.New().String(), 10, ""); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound for a missing comment, got: %v", err)
+	}
+	if _, err := repo.GetCommentSubtree(ctx, "not-a-uuid", 10, ""); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound for a malformed ID, got: %v", err)
+	}
+}
+
+// BenchmarkCommentSubtree compares fetching one deep thread of a wide root
+// with the recursive CTE against loading the root's flat tree and picking the
+// thread out of it
+func BenchmarkCommentSubtree(b *testing.B) {
+	repo, _ := newTestRepository(b)
+	ctx := context.Background()
+	target := seedWideThread(b, repo, "post-1", 500, 8)
+
+	b.Run("RecursiveCTE", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := repo.GetCommentSubtree(ctx, target.ID, 10, "created_at"); err != nil {
+				b.Fatalf("Failed to get subtree: %v", err)
+			}
+		}
+	})
+
+	b.Run("FlatTree", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tree, err := repo.GetCommentTree(ctx, "post-1", 10, "created_at")
+			if err != nil {
+				b.Fatalf("Failed to get tree: %v", err)
+			}
+			found := false
+			for _, node := range tree {
+				if node.Comment.ID == target.ID {
+					found = true
+					break
+				}
+			}
+			if !found {
+				b.Fatal("Expected the target thread in the tree")
+			}
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrInvalidTablePrefix is returned by SetTablePrefix for a prefix that is not
+// a plain lowercase SQL identifier
+var ErrInvalidTablePrefix = errors.New("invalid table prefix")
+
+// maxTablePrefixLength leaves room for the longest table name, and the names
+// of the indexes on it, within Postgres's 63-byte identifier limit
+const maxTablePrefixLength = 32
+
+// tablePrefixPattern accepts lowercase identifiers that need no quoting, so a
+// prefix can be spliced into queries as it is
+var tablePrefixPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// tableNames lists the tables queries refer to with {name} placeholders
+var tableNames = []string{"comments", "votes", "root_settings", "user_blocks", "subscriptions"}
+
+// ValidateTablePrefix reports whether prefix can be put in front of the
+// table names: up to 32 lowercase letters, digits and underscores, not
+// starting with a digit. The empty prefix is valid.
+func ValidateTablePrefix(prefix string) error {
+	if prefix == "" {
+		return nil
+	}
+	if len(prefix) > maxTablePrefixLength || !tablePrefixPattern.MatchString(prefix) {
+		return fmt.Errorf("%w %q: use up to %d lowercase letters, digits and underscores, not starting with a digit", ErrInvalidTablePrefix, prefix, maxTablePrefixLength)
+	}
+	return nil
+}
+
+// SetTablePrefix makes repositories handed out by the provider use tables
+// named with prefix, e.g. cmt_comments and cmt_votes for "cmt_". The
+// migrations must have created the tables under those names.
+func (p *PostgresProvider) SetTablePrefix(prefix string) error {
+	if err := ValidateTablePrefix(prefix); err != nil {
+		return err
+	}
+	p.tables = newTables(prefix)
+	return nil
+}
+
+// tables expands the {comments}-style placeholders queries use for table
+// names, and {prefix}, which migrations put in front of the names of their
+// indexes, functions and constraints
+type tables struct {
+	prefix   string
+	replacer *strings.Replacer
+}
+
+func newTables(prefix string) *tables {
+	pairs := make([]string, 0, 2*len(tableNames)+2)
+	for _, name := range tableNames {
+		pairs = append(pairs, "{"+name+"}", prefix+name)
+	}
+	pairs = append(pairs, "{prefix}", prefix)
+	return &tables{prefix: prefix, replacer: strings.NewReplacer(pairs...)}
+}
+
+// defaultTables names the tables without a prefix
+var defaultTables = newTables("")
+
+// name returns the full name of table
+func (t *tables) name(table string) string {
+	return t.prefix + table
+}
+
+// expand replaces the table placeholders in query
+func (t *tables) expand(query string) string {
+	return t.replacer.Replace(query)
+}
+
+// queryer is the part of *sqlx.DB and *sqlx.Tx the repository runs
+// statements through
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error)
+	QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// tableQueryer expands table placeholders before running each statement
+type tableQueryer struct {
+	queryer
+	tables *tables
+}
+
+func (q tableQueryer) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return q.queryer.ExecContext(ctx, q.tables.expand(query), args...)
+}
+
+func (q tableQueryer) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return q.queryer.QueryContext(ctx, q.tables.expand(query), args...)
+}
+
+func (q tableQueryer) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	return q.queryer.QueryxContext(ctx, q.tables.expand(query), args...)
+}
+
+func (q tableQueryer) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	return q.queryer.QueryRowxContext(ctx, q.tables.expand(query), args...)
+}
+
+func (q tableQueryer) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return q.queryer.GetContext(ctx, dest, q.tables.expand(query), args...)
+}
+
+func (q tableQueryer) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return q.queryer.SelectContext(ctx, dest, q.tables.expand(query), args...)
+}
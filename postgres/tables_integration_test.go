@@ -0,0 +1,118 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/postgres"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestSetTablePrefix_RoundTrip(t *testing.T) {
+	// Setup
+	repo, db := newPrefixedTestRepository(t, "cmt_")
+	ctx := context.Background()
+	commentService := service.NewCommentService(repo)
+
+	// Execute
+	parent, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "prefixed-1", UserID: "alice", Content: "Parent"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	if _, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "prefixed-1", ParentID: &parent.ID, UserID: "bob", Content: "Reply"}); err != nil {
+		t.Fatalf("Failed to create reply: %v", err)
+	}
+	voted, _, _, err := commentService.VoteComment(ctx, parent.ID, "bob", models.VoteTypeUp)
+	if err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	// Assert
+	if voted.Upvotes != 1 || voted.ReplyCount != 1 {
+		t.Errorf("Expected 1 upvote and 1 reply from the triggers, got %d upvotes and %d replies", voted.Upvotes, voted.ReplyCount)
+	}
+	var tables []string
+	if err := db.Select(&tables, `SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() ORDER BY table_name`); err != nil {
+		t.Fatalf("Failed to list tables: %v", err)
+	}
+	for _, table := range tables {
+		if !strings.HasPrefix(table, "cmt_") {
+			t.Errorf("Expected only prefixed tables, found %s", table)
+		}
+	}
+	var stored int
+	if err := db.Get(&stored, `SELECT COUNT(*) FROM cmt_comments WHERE root_id = 'prefixed-1'`); err != nil {
+		t.Fatalf("Failed to count comments: %v", err)
+	}
+	if stored != 2 {
+		t.Errorf("Expected 2 comments in cmt_comments, got %d", stored)
+	}
+}
+
+func TestMigrate_TablePrefixesShareSchema(t *testing.T) {
+	// Setup
+	repo, db := newPrefixedTestRepository(t, "cmt_")
+	ctx := context.Background()
+	other := postgres.NewPostgresProvider(db)
+	if err := other.SetTablePrefix("other_"); err != nil {
+		t.Fatalf("Failed to set table prefix: %v", err)
+	}
+
+	// Execute
+	if err := other.Migrate(); err != nil {
+		t.Fatalf("Failed to migrate a second prefix into the schema: %v", err)
+	}
+	if err := other.Migrate(); err != nil {
+		t.Fatalf("Expected migrating again to do nothing, got: %v", err)
+	}
+	scores := make(map[string]int64)
+	for prefix, commentService := range map[string]*service.CommentService{
+		"cmt_":   service.NewCommentService(repo),
+		"other_": service.NewCommentService(other.GetCommentRepository()),
+	} {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "shared-1", UserID: "alice", Content: "Vote on me"})
+		if err != nil {
+			t.Fatalf("Failed to create comment under %s: %v", prefix, err)
+		}
+		if _, _, _, err := commentService.VoteComment(ctx, comment.ID, "bob", models.VoteTypeDown); err != nil {
+			t.Fatalf("Failed to vote under %s: %v", prefix, err)
+		}
+		stored, err := commentService.GetComment(ctx, comment.ID)
+		if err != nil {
+			t.Fatalf("Failed to get comment under %s: %v", prefix, err)
+		}
+		scores[prefix] = stored.Score
+	}
+
+	// Assert
+	for prefix, score := range scores {
+		if score != -1 {
+			t.Errorf("Expected the %s trigger to score the downvote -1, got %d", prefix, score)
+		}
+	}
+	var functions []string
+	if err := db.Select(&functions, `
+		SELECT p.proname FROM pg_proc p
+		JOIN pg_namespace n ON n.oid = p.pronamespace
+		WHERE n.nspname = current_schema()
+			AND NOT EXISTS (SELECT 1 FROM pg_depend d WHERE d.objid = p.oid AND d.deptype = 'e')
+		ORDER BY p.proname`); err != nil {
+		t.Fatalf("Failed to list functions: %v", err)
+	}
+	for _, function := range functions {
+		if !strings.HasPrefix(function, "cmt_") && !strings.HasPrefix(function, "other_") {
+			t.Errorf("Expected only prefixed trigger functions, found %s", function)
+		}
+	}
+	var fkeys int
+	if err := db.Get(&fkeys, `SELECT COUNT(*) FROM pg_constraint WHERE conrelid = 'cmt_votes'::regclass AND contype = 'f'`); err != nil {
+		t.Fatalf("Failed to count foreign keys: %v", err)
+	}
+	if fkeys != 1 {
+		t.Errorf("Expected one foreign key on cmt_votes, got %d", fkeys)
+	}
+}
@@ -0,0 +1,88 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// recordingConnector hands out connections that note each statement they
+// are asked to run and then fail it, so queries can be checked without a
+// database
+type recordingConnector struct {
+	queries []string
+}
+
+func (c *recordingConnector) Connect(context.Context) (driver.Conn, error) {
+	return &recordingConn{connector: c}, nil
+}
+
+func (c *recordingConnector) Driver() driver.Driver { return nil }
+
+type recordingConn struct {
+	connector *recordingConnector
+}
+
+var errRecorded = errors.New("recorded")
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	c.connector.queries = append(c.connector.queries, query)
+	return nil, errRecorded
+}
+
+func (c *recordingConn) Close() error { return nil }
+
+func (c *recordingConn) Begin() (driver.Tx, error) { return nil, errRecorded }
+
+func TestSetTablePrefix_QueriesUsePrefixedTables(t *testing.T) {
+	// Setup
+	connector := &recordingConnector{}
+	provider := postgres.NewPostgresProvider(sqlx.NewDb(sql.OpenDB(connector), "postgres"))
+	if err := provider.SetTablePrefix("cmt_"); err != nil {
+		t.Fatalf("Failed to set table prefix: %v", err)
+	}
+	repo := provider.GetCommentRepository()
+	ctx := context.Background()
+	commentID := uuid.New().String()
+
+	// Execute
+	repo.GetCommentByID(ctx, commentID)
+	repo.UpdateVote(ctx, commentID, "bob", models.VoteTypeUp)
+	repo.GetRootSettings(ctx, "post-1")
+	repo.GetBlockedUsers(ctx, "alice")
+	repo.GetSubscriberUserIDs(ctx, "post-1")
+
+	// Assert
+	all := strings.Join(connector.queries, "\n")
+	for _, table := range []string{"cmt_comments", "cmt_votes", "cmt_root_settings", "cmt_user_blocks", "cmt_subscriptions"} {
+		if !strings.Contains(all, table) {
+			t.Errorf("Expected a query against %s, got:\n%s", table, all)
+		}
+	}
+	if strings.Contains(all, "{") {
+		t.Errorf("Expected every table placeholder to be filled in, got:\n%s", all)
+	}
+}
+
+func TestSetTablePrefix_RejectsUnsafePrefixes(t *testing.T) {
+	provider := postgres.NewPostgresProvider(nil)
+
+	for _, prefix := range []string{"", "cmt_", "_x", "app2_"} {
+		if err := provider.SetTablePrefix(prefix); err != nil {
+			t.Errorf("Expected %q to be accepted, got: %v", prefix, err)
+		}
+	}
+	for _, prefix := range []string{"Cmt_", "2cmt_", "cmt-", "cmt.", `cmt"`, "cmt_; DROP TABLE comments; --", strings.Repeat("a", 33)} {
+		if err := provider.SetTablePrefix(prefix); !errors.Is(err, postgres.ErrInvalidTablePrefix) {
+			t.Errorf("Expected ErrInvalidTablePrefix for %q, got: %v", prefix, err)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Span attribute keys recorded on repository spans
+const (
+	attrRootID    = attribute.Key("commentific.root_id")
+	attrCommentID = attribute.Key("commentific.comment_id")
+)
+
+var dbSystem = attribute.String("db.system", "postgresql")
+
+// defaultTracer records nothing until SetTracer is called
+var defaultTracer trace.Tracer = noop.NewTracerProvider().Tracer("")
+
+// SetTracer sets the tracer for repositories handed out by the provider
+func (p *PostgresProvider) SetTracer(tracer trace.Tracer) {
+	p.tracer = tracer
+}
+
+// SetTracer replaces the tracer used for repository spans
+func (r *PostgresRepository) SetTracer(tracer trace.Tracer) {
+	r.tracer = tracer
+}
+
+// startSpan opens a span named after a repository method
+func (r *PostgresRepository) startSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	tracer := r.tracer
+	if tracer == nil {
+		tracer = defaultTracer
+	}
+	return tracer.Start(ctx, "PostgresRepository."+method, trace.WithAttributes(append(attrs, dbSystem)...))
+}
+
+// endSpan records err, if any, and ends the span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,50 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestGetCommentTreeWithUserVotes(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	commentService := service.NewCommentService(repo)
+
+	thread, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "tree-votes-1", UserID: "alice", Content: "Thread"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	reply, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "tree-votes-1", ParentID: &thread.ID, UserID: "bob", Content: "Reply"})
+	if err != nil {
+		t.Fatalf("Failed to create reply: %v", err)
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, reply.ID, "carol", models.VoteTypeDown); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, thread.ID, "dave", models.VoteTypeUp); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	// Execute
+	tree, err := repo.GetCommentTreeWithUserVotes(ctx, "tree-votes-1", "carol", 10, "created_at")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(tree) != 1 || tree[0].Comment.ID != thread.ID || len(tree[0].Children) != 1 {
+		t.Fatalf("Expected the thread with its reply, got: %+v", tree)
+	}
+	if tree[0].Comment.CurrentUserVote != nil {
+		t.Errorf("Expected no vote from carol on the thread, got: %v", *tree[0].Comment.CurrentUserVote)
+	}
+	if vote := tree[0].Children[0].Comment.CurrentUserVote; vote == nil || *vote != models.VoteTypeDown {
+		t.Errorf("Expected carol's downvote on the reply, got: %v", vote)
+	}
+}
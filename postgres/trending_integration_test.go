@@ -0,0 +1,50 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+	"github.com/google/uuid"
+)
+
+func TestGetTrendingRoots(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	commentService := service.NewCommentService(repo)
+
+	recent := time.Now().Add(-time.Hour)
+	old := time.Now().AddDate(0, 0, -3)
+	var comments []*models.Comment
+	for _, seed := range []struct {
+		rootID    string
+		createdAt time.Time
+	}{
+		{"trend-busy", recent}, {"trend-busy", recent}, {"trend-quiet", recent},
+		{"trend-stale", old}, {"trend-stale", old}, {"trend-stale", old},
+	} {
+		comments = append(comments, &models.Comment{
+			ID: uuid.New().String(), RootID: seed.rootID, UserID: "alice", Content: "Hello",
+			CreatedAt: seed.createdAt, UpdatedAt: seed.createdAt,
+		})
+	}
+	if err := commentService.ImportComments(ctx, comments); err != nil {
+		t.Fatalf("Failed to seed comments: %v", err)
+	}
+
+	// Execute
+	roots, err := repo.GetTrendingRoots(ctx, "day", 10)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(roots) != 2 || roots[0].RootID != "trend-busy" || roots[0].CommentCount != 2 || roots[1].RootID != "trend-quiet" {
+		t.Fatalf("Expected trend-busy then trend-quiet, got: %+v", roots)
+	}
+}
@@ -0,0 +1,54 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestGetUserVotes(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	commentService := service.NewCommentService(repo)
+
+	kept, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "votes-1", UserID: "alice", Content: "Kept"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	removed, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "votes-2", UserID: "alice", Content: "Removed"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	for _, comment := range []*models.Comment{kept, removed} {
+		if _, _, _, err := commentService.VoteComment(ctx, comment.ID, "carol", models.VoteTypeDown); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+	}
+	if err := commentService.DeleteComment(ctx, removed.ID, "alice"); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+
+	// Execute
+	voted, err := repo.GetUserVotes(ctx, "carol", &models.VoteFilter{})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(voted) != 1 || voted[0].ID != kept.ID || voted[0].VoteType != models.VoteTypeDown || voted[0].Content != "Kept" {
+		t.Fatalf("Expected carol's downvote on the live comment, got: %+v", voted)
+	}
+
+	voted, err = repo.GetUserVotes(ctx, "carol", &models.VoteFilter{IncludeDeleted: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(voted) != 2 {
+		t.Fatalf("Expected both votes with IncludeDeleted, got: %d", len(voted))
+	}
+}
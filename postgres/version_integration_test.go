@@ -0,0 +1,47 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/repository"
+	"github.com/google/uuid"
+)
+
+func TestUpdateComment_VersionConflict(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	comment := &models.Comment{ID: uuid.New().String(), RootID: "versioned-1", UserID: "alice", Content: "Original"}
+	if err := repo.CreateComment(ctx, comment); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	readVersion := comment.Version
+	first, second := "Author edit", "Moderator redaction"
+
+	// Execute
+	if err := repo.UpdateComment(ctx, comment.ID, &models.UpdateCommentRequest{Content: &first, Version: &readVersion}); err != nil {
+		t.Fatalf("Expected the first update to succeed, got: %v", err)
+	}
+	err := repo.UpdateComment(ctx, comment.ID, &models.UpdateCommentRequest{Content: &second, Version: &readVersion})
+
+	// Assert
+	if !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("Expected ErrVersionConflict, got: %v", err)
+	}
+	stored, err := repo.GetCommentByID(ctx, comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	if stored.Content != first || stored.Version != 2 {
+		t.Fatalf("Expected the first edit at version 2, got %q at version %d", stored.Content, stored.Version)
+	}
+	missing := uuid.New().String()
+	if err := repo.UpdateComment(ctx, missing, &models.UpdateCommentRequest{Content: &second, Version: &readVersion}); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound for a missing comment, got: %v", err)
+	}
+}
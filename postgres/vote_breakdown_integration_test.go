@@ -0,0 +1,45 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/repository"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestGetVoteBreakdown(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	commentService := service.NewCommentService(repo)
+
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "breakdown-1", UserID: "alice", Content: "Divisive"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	for userID, voteType := range map[string]models.VoteType{"bob": models.VoteTypeUp, "carol": models.VoteTypeUp, "dave": models.VoteTypeDown} {
+		if _, _, _, err := commentService.VoteComment(ctx, comment.ID, userID, voteType); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+	}
+
+	// Execute
+	breakdown, err := repo.GetVoteBreakdown(ctx, comment.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if breakdown.Upvotes != 2 || breakdown.Downvotes != 1 || breakdown.Voters != 3 {
+		t.Fatalf("Expected 2 up, 1 down from 3 voters, got: %+v", breakdown)
+	}
+
+	if _, err := repo.GetVoteBreakdown(ctx, "00000000-0000-0000-0000-000000000000"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound for a missing comment, got: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestVoteComment_ConcurrentVotesKeepCounts(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	commentService := service.NewCommentService(repo)
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "concurrent-1", UserID: "alice", Content: "Popular"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	const voters, flips = 10, 3
+	var wg sync.WaitGroup
+	errs := make(chan error, voters*flips)
+	for i := 0; i < voters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			userID := fmt.Sprintf("voter-%d", i)
+			for flip := 0; flip < flips; flip++ {
+				voteType := models.VoteTypeUp
+				if (i+flip)%2 == 1 {
+					voteType = models.VoteTypeDown
+				}
+				if _, _, _, err := commentService.VoteComment(ctx, comment.ID, userID, voteType); err != nil {
+					errs <- err
+				}
+			}
+		}(i)
+	}
+
+	// Execute
+	wg.Wait()
+	close(errs)
+
+	// Assert
+	for err := range errs {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+	got, err := repo.GetCommentByID(ctx, comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	// The last flip is up for even voters and down for odd ones
+	if got.Upvotes+got.Downvotes != voters {
+		t.Errorf("Expected %d votes, one per voter, got %d up and %d down", voters, got.Upvotes, got.Downvotes)
+	}
+	if got.Upvotes != voters/2 || got.Score != 0 {
+		t.Errorf("Expected %d up and score 0, got %d up and score %d", voters/2, got.Upvotes, got.Score)
+	}
+}
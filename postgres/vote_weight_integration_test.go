@@ -0,0 +1,91 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+func TestCreateVote_Weighted(t *testing.T) {
+	// Setup
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	comment := &models.Comment{RootID: "weights-1", UserID: "alice", Content: "A claim"}
+	if err := repo.CreateComment(ctx, comment); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Execute
+	if err := repo.CreateVote(ctx, &models.Vote{CommentID: comment.ID, UserID: "expert", VoteType: models.VoteTypeUp, Weight: 3}); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+	if err := repo.UpdateVote(ctx, comment.ID, "bob", models.VoteTypeDown); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	// Assert
+	got, err := repo.GetCommentByID(ctx, comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	if got.Score != 2 || got.Upvotes != 1 || got.Downvotes != 1 {
+		t.Errorf("Expected score 3 - 1 = 2 from 1 up and 1 down, got score %d from %d up and %d down", got.Score, got.Upvotes, got.Downvotes)
+	}
+	vote, err := repo.GetUserVote(ctx, comment.ID, "expert")
+	if err != nil {
+		t.Fatalf("Failed to get vote: %v", err)
+	}
+	if vote.Weight != 3 {
+		t.Errorf("Expected the vote stored with weight 3, got %d", vote.Weight)
+	}
+
+	// UpdateCommentScores recomputes the weighted score
+	db.MustExecContext(ctx, `UPDATE comments SET score = 0 WHERE id = $1`, comment.ID)
+	if err := repo.UpdateCommentScores(ctx, []string{comment.ID}); err != nil {
+		t.Fatalf("Failed to update scores: %v", err)
+	}
+	got, err = repo.GetCommentByID(ctx, comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	if got.Score != 2 {
+		t.Errorf("Expected UpdateCommentScores to restore score 2, got %d", got.Score)
+	}
+}
+
+func TestUpdateVote_KeepsWeight(t *testing.T) {
+	// Setup
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	comment := &models.Comment{RootID: "weights-1", UserID: "alice", Content: "A claim"}
+	if err := repo.CreateComment(ctx, comment); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	if err := repo.CreateVote(ctx, &models.Vote{CommentID: comment.ID, UserID: "expert", VoteType: models.VoteTypeUp, Weight: 3}); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	// Execute
+	if err := repo.UpdateVote(ctx, comment.ID, "expert", models.VoteTypeDown); err != nil {
+		t.Fatalf("Failed to update vote: %v", err)
+	}
+
+	// Assert
+	vote, err := repo.GetUserVote(ctx, comment.ID, "expert")
+	if err != nil {
+		t.Fatalf("Failed to get vote: %v", err)
+	}
+	if vote.VoteType != models.VoteTypeDown || vote.Weight != 3 {
+		t.Errorf("Expected a weight-3 downvote, got %+v", vote)
+	}
+	got, err := repo.GetCommentByID(ctx, comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	if got.Score != -3 {
+		t.Errorf("Expected score -3, got %d", got.Score)
+	}
+}
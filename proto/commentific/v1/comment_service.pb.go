@@ -0,0 +1,1247 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: commentific/v1/comment_service.proto
+
+package commentificv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type VoteType int32
+
+const (
+	VoteType_VOTE_TYPE_UNSPECIFIED VoteType = 0
+	VoteType_VOTE_TYPE_UP          VoteType = 1
+	VoteType_VOTE_TYPE_DOWN        VoteType = 2
+)
+
+// Enum value maps for VoteType.
+var (
+	VoteType_name = map[int32]string{
+		0: "VOTE_TYPE_UNSPECIFIED",
+		1: "VOTE_TYPE_UP",
+		2: "VOTE_TYPE_DOWN",
+	}
+	VoteType_value = map[string]int32{
+		"VOTE_TYPE_UNSPECIFIED": 0,
+		"VOTE_TYPE_UP":          1,
+		"VOTE_TYPE_DOWN":        2,
+	}
+)
+
+func (x VoteType) Enum() *VoteType {
+	p := new(VoteType)
+	*p = x
+	return p
+}
+
+func (x VoteType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (VoteType) Descriptor() protoreflect.EnumDescriptor {
+	return file_commentific_v1_comment_service_proto_enumTypes[0].Descriptor()
+}
+
+func (VoteType) Type() protoreflect.EnumType {
+	return &file_commentific_v1_comment_service_proto_enumTypes[0]
+}
+
+func (x VoteType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use VoteType.Descriptor instead.
+func (VoteType) EnumDescriptor() ([]byte, []int) {
+	return file_commentific_v1_comment_service_proto_rawDescGZIP(), []int{0}
+}
+
+type Comment struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RootId           string                 `protobuf:"bytes,2,opt,name=root_id,json=rootId,proto3" json:"root_id,omitempty"`
+	ParentId         *string                `protobuf:"bytes,3,opt,name=parent_id,json=parentId,proto3,oneof" json:"parent_id,omitempty"`
+	UserId           string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Content          string                 `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`
+	MediaUrl         *string                `protobuf:"bytes,6,opt,name=media_url,json=mediaUrl,proto3,oneof" json:"media_url,omitempty"`
+	LinkUrl          *string                `protobuf:"bytes,7,opt,name=link_url,json=linkUrl,proto3,oneof" json:"link_url,omitempty"`
+	Upvotes          int64                  `protobuf:"varint,8,opt,name=upvotes,proto3" json:"upvotes,omitempty"`
+	Downvotes        int64                  `protobuf:"varint,9,opt,name=downvotes,proto3" json:"downvotes,omitempty"`
+	Score            int64                  `protobuf:"varint,10,opt,name=score,proto3" json:"score,omitempty"`
+	Depth            int32                  `protobuf:"varint,11,opt,name=depth,proto3" json:"depth,omitempty"`
+	Path             string                 `protobuf:"bytes,12,opt,name=path,proto3" json:"path,omitempty"`
+	IsDeleted        bool                   `protobuf:"varint,13,opt,name=is_deleted,json=isDeleted,proto3" json:"is_deleted,omitempty"`
+	IsEdited         bool                   `protobuf:"varint,14,opt,name=is_edited,json=isEdited,proto3" json:"is_edited,omitempty"`
+	EditCount        int32                  `protobuf:"varint,15,opt,name=edit_count,json=editCount,proto3" json:"edit_count,omitempty"`
+	OriginalContent  *string                `protobuf:"bytes,16,opt,name=original_content,json=originalContent,proto3,oneof" json:"original_content,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt        *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ContentUpdatedAt *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=content_updated_at,json=contentUpdatedAt,proto3" json:"content_updated_at,omitempty"`
+	ReplyCount       int32                  `protobuf:"varint,20,opt,name=reply_count,json=replyCount,proto3" json:"reply_count,omitempty"`
+	DescendantCount  int32                  `protobuf:"varint,21,opt,name=descendant_count,json=descendantCount,proto3" json:"descendant_count,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Comment) Reset() {
+	*x = Comment{}
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Comment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Comment) ProtoMessage() {}
+
+func (x *Comment) ProtoReflect() protoreflect.Message {
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Comment.ProtoReflect.Descriptor instead.
+func (*Comment) Descriptor() ([]byte, []int) {
+	return file_commentific_v1_comment_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Comment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Comment) GetRootId() string {
+	if x != nil {
+		return x.RootId
+	}
+	return ""
+}
+
+func (x *Comment) GetParentId() string {
+	if x != nil && x.ParentId != nil {
+		return *x.ParentId
+	}
+	return ""
+}
+
+func (x *Comment) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Comment) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Comment) GetMediaUrl() string {
+	if x != nil && x.MediaUrl != nil {
+		return *x.MediaUrl
+	}
+	return ""
+}
+
+func (x *Comment) GetLinkUrl() string {
+	if x != nil && x.LinkUrl != nil {
+		return *x.LinkUrl
+	}
+	return ""
+}
+
+func (x *Comment) GetUpvotes() int64 {
+	if x != nil {
+		return x.Upvotes
+	}
+	return 0
+}
+
+func (x *Comment) GetDownvotes() int64 {
+	if x != nil {
+		return x.Downvotes
+	}
+	return 0
+}
+
+func (x *Comment) GetScore() int64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Comment) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *Comment) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Comment) GetIsDeleted() bool {
+	if x != nil {
+		return x.IsDeleted
+	}
+	return false
+}
+
+func (x *Comment) GetIsEdited() bool {
+	if x != nil {
+		return x.IsEdited
+	}
+	return false
+}
+
+func (x *Comment) GetEditCount() int32 {
+	if x != nil {
+		return x.EditCount
+	}
+	return 0
+}
+
+func (x *Comment) GetOriginalContent() string {
+	if x != nil && x.OriginalContent != nil {
+		return *x.OriginalContent
+	}
+	return ""
+}
+
+func (x *Comment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Comment) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Comment) GetContentUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ContentUpdatedAt
+	}
+	return nil
+}
+
+func (x *Comment) GetReplyCount() int32 {
+	if x != nil {
+		return x.ReplyCount
+	}
+	return 0
+}
+
+func (x *Comment) GetDescendantCount() int32 {
+	if x != nil {
+		return x.DescendantCount
+	}
+	return 0
+}
+
+type CommentTree struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comment       *Comment               `protobuf:"bytes,1,opt,name=comment,proto3" json:"comment,omitempty"`
+	Children      []*CommentTree         `protobuf:"bytes,2,rep,name=children,proto3" json:"children,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommentTree) Reset() {
+	*x = CommentTree{}
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommentTree) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommentTree) ProtoMessage() {}
+
+func (x *CommentTree) ProtoReflect() protoreflect.Message {
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommentTree.ProtoReflect.Descriptor instead.
+func (*CommentTree) Descriptor() ([]byte, []int) {
+	return file_commentific_v1_comment_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CommentTree) GetComment() *Comment {
+	if x != nil {
+		return x.Comment
+	}
+	return nil
+}
+
+func (x *CommentTree) GetChildren() []*CommentTree {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+type CommentStats struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	RootId             string                 `protobuf:"bytes,1,opt,name=root_id,json=rootId,proto3" json:"root_id,omitempty"`
+	TotalCount         int64                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	TotalScore         int64                  `protobuf:"varint,3,opt,name=total_score,json=totalScore,proto3" json:"total_score,omitempty"`
+	MaxDepth           int32                  `protobuf:"varint,4,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`
+	RecentCount        int64                  `protobuf:"varint,5,opt,name=recent_count,json=recentCount,proto3" json:"recent_count,omitempty"`
+	EditedCount        int64                  `protobuf:"varint,6,opt,name=edited_count,json=editedCount,proto3" json:"edited_count,omitempty"`
+	TotalEdits         int64                  `protobuf:"varint,7,opt,name=total_edits,json=totalEdits,proto3" json:"total_edits,omitempty"`
+	EditRate           float64                `protobuf:"fixed64,8,opt,name=edit_rate,json=editRate,proto3" json:"edit_rate,omitempty"`
+	AvgEditsPerComment float64                `protobuf:"fixed64,9,opt,name=avg_edits_per_comment,json=avgEditsPerComment,proto3" json:"avg_edits_per_comment,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CommentStats) Reset() {
+	*x = CommentStats{}
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommentStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommentStats) ProtoMessage() {}
+
+func (x *CommentStats) ProtoReflect() protoreflect.Message {
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommentStats.ProtoReflect.Descriptor instead.
+func (*CommentStats) Descriptor() ([]byte, []int) {
+	return file_commentific_v1_comment_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CommentStats) GetRootId() string {
+	if x != nil {
+		return x.RootId
+	}
+	return ""
+}
+
+func (x *CommentStats) GetTotalCount() int64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *CommentStats) GetTotalScore() int64 {
+	if x != nil {
+		return x.TotalScore
+	}
+	return 0
+}
+
+func (x *CommentStats) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+func (x *CommentStats) GetRecentCount() int64 {
+	if x != nil {
+		return x.RecentCount
+	}
+	return 0
+}
+
+func (x *CommentStats) GetEditedCount() int64 {
+	if x != nil {
+		return x.EditedCount
+	}
+	return 0
+}
+
+func (x *CommentStats) GetTotalEdits() int64 {
+	if x != nil {
+		return x.TotalEdits
+	}
+	return 0
+}
+
+func (x *CommentStats) GetEditRate() float64 {
+	if x != nil {
+		return x.EditRate
+	}
+	return 0
+}
+
+func (x *CommentStats) GetAvgEditsPerComment() float64 {
+	if x != nil {
+		return x.AvgEditsPerComment
+	}
+	return 0
+}
+
+type CreateCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RootId        string                 `protobuf:"bytes,1,opt,name=root_id,json=rootId,proto3" json:"root_id,omitempty"`
+	ParentId      *string                `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3,oneof" json:"parent_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Content       string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	MediaUrl      *string                `protobuf:"bytes,5,opt,name=media_url,json=mediaUrl,proto3,oneof" json:"media_url,omitempty"`
+	LinkUrl       *string                `protobuf:"bytes,6,opt,name=link_url,json=linkUrl,proto3,oneof" json:"link_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCommentRequest) Reset() {
+	*x = CreateCommentRequest{}
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCommentRequest) ProtoMessage() {}
+
+func (x *CreateCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCommentRequest.ProtoReflect.Descriptor instead.
+func (*CreateCommentRequest) Descriptor() ([]byte, []int) {
+	return file_commentific_v1_comment_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateCommentRequest) GetRootId() string {
+	if x != nil {
+		return x.RootId
+	}
+	return ""
+}
+
+func (x *CreateCommentRequest) GetParentId() string {
+	if x != nil && x.ParentId != nil {
+		return *x.ParentId
+	}
+	return ""
+}
+
+func (x *CreateCommentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateCommentRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *CreateCommentRequest) GetMediaUrl() string {
+	if x != nil && x.MediaUrl != nil {
+		return *x.MediaUrl
+	}
+	return ""
+}
+
+func (x *CreateCommentRequest) GetLinkUrl() string {
+	if x != nil && x.LinkUrl != nil {
+		return *x.LinkUrl
+	}
+	return ""
+}
+
+type GetCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentRequest) Reset() {
+	*x = GetCommentRequest{}
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentRequest) ProtoMessage() {}
+
+func (x *GetCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentRequest.ProtoReflect.Descriptor instead.
+func (*GetCommentRequest) Descriptor() ([]byte, []int) {
+	return file_commentific_v1_comment_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetCommentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UpdateCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Content       *string                `protobuf:"bytes,3,opt,name=content,proto3,oneof" json:"content,omitempty"`
+	MediaUrl      *string                `protobuf:"bytes,4,opt,name=media_url,json=mediaUrl,proto3,oneof" json:"media_url,omitempty"`
+	LinkUrl       *string                `protobuf:"bytes,5,opt,name=link_url,json=linkUrl,proto3,oneof" json:"link_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCommentRequest) Reset() {
+	*x = UpdateCommentRequest{}
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCommentRequest) ProtoMessage() {}
+
+func (x *UpdateCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCommentRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCommentRequest) Descriptor() ([]byte, []int) {
+	return file_commentific_v1_comment_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdateCommentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateCommentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateCommentRequest) GetContent() string {
+	if x != nil && x.Content != nil {
+		return *x.Content
+	}
+	return ""
+}
+
+func (x *UpdateCommentRequest) GetMediaUrl() string {
+	if x != nil && x.MediaUrl != nil {
+		return *x.MediaUrl
+	}
+	return ""
+}
+
+func (x *UpdateCommentRequest) GetLinkUrl() string {
+	if x != nil && x.LinkUrl != nil {
+		return *x.LinkUrl
+	}
+	return ""
+}
+
+type DeleteCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCommentRequest) Reset() {
+	*x = DeleteCommentRequest{}
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCommentRequest) ProtoMessage() {}
+
+func (x *DeleteCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCommentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCommentRequest) Descriptor() ([]byte, []int) {
+	return file_commentific_v1_comment_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeleteCommentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteCommentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListCommentsByRootRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RootId        string                 `protobuf:"bytes,1,opt,name=root_id,json=rootId,proto3" json:"root_id,omitempty"`
+	Limit         *int32                 `protobuf:"varint,2,opt,name=limit,proto3,oneof" json:"limit,omitempty"`
+	Offset        *int32                 `protobuf:"varint,3,opt,name=offset,proto3,oneof" json:"offset,omitempty"`
+	SortBy        string                 `protobuf:"bytes,4,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	SortOrder     string                 `protobuf:"bytes,5,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	MaxDepth      *int32                 `protobuf:"varint,6,opt,name=max_depth,json=maxDepth,proto3,oneof" json:"max_depth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCommentsByRootRequest) Reset() {
+	*x = ListCommentsByRootRequest{}
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCommentsByRootRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCommentsByRootRequest) ProtoMessage() {}
+
+func (x *ListCommentsByRootRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCommentsByRootRequest.ProtoReflect.Descriptor instead.
+func (*ListCommentsByRootRequest) Descriptor() ([]byte, []int) {
+	return file_commentific_v1_comment_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListCommentsByRootRequest) GetRootId() string {
+	if x != nil {
+		return x.RootId
+	}
+	return ""
+}
+
+func (x *ListCommentsByRootRequest) GetLimit() int32 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+func (x *ListCommentsByRootRequest) GetOffset() int32 {
+	if x != nil && x.Offset != nil {
+		return *x.Offset
+	}
+	return 0
+}
+
+func (x *ListCommentsByRootRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListCommentsByRootRequest) GetSortOrder() string {
+	if x != nil {
+		return x.SortOrder
+	}
+	return ""
+}
+
+func (x *ListCommentsByRootRequest) GetMaxDepth() int32 {
+	if x != nil && x.MaxDepth != nil {
+		return *x.MaxDepth
+	}
+	return 0
+}
+
+type ListCommentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comments      []*Comment             `protobuf:"bytes,1,rep,name=comments,proto3" json:"comments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCommentsResponse) Reset() {
+	*x = ListCommentsResponse{}
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCommentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCommentsResponse) ProtoMessage() {}
+
+func (x *ListCommentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCommentsResponse.ProtoReflect.Descriptor instead.
+func (*ListCommentsResponse) Descriptor() ([]byte, []int) {
+	return file_commentific_v1_comment_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListCommentsResponse) GetComments() []*Comment {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+type GetCommentTreeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RootId        string                 `protobuf:"bytes,1,opt,name=root_id,json=rootId,proto3" json:"root_id,omitempty"`
+	MaxDepth      int32                  `protobuf:"varint,2,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`
+	SortBy        string                 `protobuf:"bytes,3,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentTreeRequest) Reset() {
+	*x = GetCommentTreeRequest{}
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentTreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentTreeRequest) ProtoMessage() {}
+
+func (x *GetCommentTreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentTreeRequest.ProtoReflect.Descriptor instead.
+func (*GetCommentTreeRequest) Descriptor() ([]byte, []int) {
+	return file_commentific_v1_comment_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetCommentTreeRequest) GetRootId() string {
+	if x != nil {
+		return x.RootId
+	}
+	return ""
+}
+
+func (x *GetCommentTreeRequest) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+func (x *GetCommentTreeRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+type GetCommentTreeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Roots         []*CommentTree         `protobuf:"bytes,1,rep,name=roots,proto3" json:"roots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentTreeResponse) Reset() {
+	*x = GetCommentTreeResponse{}
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentTreeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentTreeResponse) ProtoMessage() {}
+
+func (x *GetCommentTreeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentTreeResponse.ProtoReflect.Descriptor instead.
+func (*GetCommentTreeResponse) Descriptor() ([]byte, []int) {
+	return file_commentific_v1_comment_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetCommentTreeResponse) GetRoots() []*CommentTree {
+	if x != nil {
+		return x.Roots
+	}
+	return nil
+}
+
+type VoteCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CommentId     string                 `protobuf:"bytes,1,opt,name=comment_id,json=commentId,proto3" json:"comment_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	VoteType      VoteType               `protobuf:"varint,3,opt,name=vote_type,json=voteType,proto3,enum=commentific.v1.VoteType" json:"vote_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VoteCommentRequest) Reset() {
+	*x = VoteCommentRequest{}
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VoteCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoteCommentRequest) ProtoMessage() {}
+
+func (x *VoteCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoteCommentRequest.ProtoReflect.Descriptor instead.
+func (*VoteCommentRequest) Descriptor() ([]byte, []int) {
+	return file_commentific_v1_comment_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *VoteCommentRequest) GetCommentId() string {
+	if x != nil {
+		return x.CommentId
+	}
+	return ""
+}
+
+func (x *VoteCommentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *VoteCommentRequest) GetVoteType() VoteType {
+	if x != nil {
+		return x.VoteType
+	}
+	return VoteType_VOTE_TYPE_UNSPECIFIED
+}
+
+type GetCommentStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RootId        string                 `protobuf:"bytes,1,opt,name=root_id,json=rootId,proto3" json:"root_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentStatsRequest) Reset() {
+	*x = GetCommentStatsRequest{}
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentStatsRequest) ProtoMessage() {}
+
+func (x *GetCommentStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_commentific_v1_comment_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetCommentStatsRequest) Descriptor() ([]byte, []int) {
+	return file_commentific_v1_comment_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetCommentStatsRequest) GetRootId() string {
+	if x != nil {
+		return x.RootId
+	}
+	return ""
+}
+
+var File_commentific_v1_comment_service_proto protoreflect.FileDescriptor
+
+const file_commentific_v1_comment_service_proto_rawDesc = "" +
+	"\n" +
+	"$commentific/v1/comment_service.proto\x12\x0ecommentific.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x96\x06\n" +
+	"\aComment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\aroot_id\x18\x02 \x01(\tR\x06rootId\x12 \n" +
+	"\tparent_id\x18\x03 \x01(\tH\x00R\bparentId\x88\x01\x01\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12\x18\n" +
+	"\acontent\x18\x05 \x01(\tR\acontent\x12 \n" +
+	"\tmedia_url\x18\x06 \x01(\tH\x01R\bmediaUrl\x88\x01\x01\x12\x1e\n" +
+	"\blink_url\x18\a \x01(\tH\x02R\alinkUrl\x88\x01\x01\x12\x18\n" +
+	"\aupvotes\x18\b \x01(\x03R\aupvotes\x12\x1c\n" +
+	"\tdownvotes\x18\t \x01(\x03R\tdownvotes\x12\x14\n" +
+	"\x05score\x18\n" +
+	" \x01(\x03R\x05score\x12\x14\n" +
+	"\x05depth\x18\v \x01(\x05R\x05depth\x12\x12\n" +
+	"\x04path\x18\f \x01(\tR\x04path\x12\x1d\n" +
+	"\n" +
+	"is_deleted\x18\r \x01(\bR\tisDeleted\x12\x1b\n" +
+	"\tis_edited\x18\x0e \x01(\bR\bisEdited\x12\x1d\n" +
+	"\n" +
+	"edit_count\x18\x0f \x01(\x05R\teditCount\x12.\n" +
+	"\x10original_content\x18\x10 \x01(\tH\x03R\x0foriginalContent\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12H\n" +
+	"\x12content_updated_at\x18\x13 \x01(\v2\x1a.google.protobuf.TimestampR\x10contentUpdatedAt\x12\x1f\n" +
+	"\vreply_count\x18\x14 \x01(\x05R\n" +
+	"replyCount\x12)\n" +
+	"\x10descendant_count\x18\x15 \x01(\x05R\x0fdescendantCountB\f\n" +
+	"\n" +
+	"_parent_idB\f\n" +
+	"\n" +
+	"_media_urlB\v\n" +
+	"\t_link_urlB\x13\n" +
+	"\x11_original_content\"y\n" +
+	"\vCommentTree\x121\n" +
+	"\acomment\x18\x01 \x01(\v2\x17.commentific.v1.CommentR\acomment\x127\n" +
+	"\bchildren\x18\x02 \x03(\v2\x1b.commentific.v1.CommentTreeR\bchildren\"\xbd\x02\n" +
+	"\fCommentStats\x12\x17\n" +
+	"\aroot_id\x18\x01 \x01(\tR\x06rootId\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x03R\n" +
+	"totalCount\x12\x1f\n" +
+	"\vtotal_score\x18\x03 \x01(\x03R\n" +
+	"totalScore\x12\x1b\n" +
+	"\tmax_depth\x18\x04 \x01(\x05R\bmaxDepth\x12!\n" +
+	"\frecent_count\x18\x05 \x01(\x03R\vrecentCount\x12!\n" +
+	"\fedited_count\x18\x06 \x01(\x03R\veditedCount\x12\x1f\n" +
+	"\vtotal_edits\x18\a \x01(\x03R\n" +
+	"totalEdits\x12\x1b\n" +
+	"\tedit_rate\x18\b \x01(\x01R\beditRate\x121\n" +
+	"\x15avg_edits_per_comment\x18\t \x01(\x01R\x12avgEditsPerComment\"\xef\x01\n" +
+	"\x14CreateCommentRequest\x12\x17\n" +
+	"\aroot_id\x18\x01 \x01(\tR\x06rootId\x12 \n" +
+	"\tparent_id\x18\x02 \x01(\tH\x00R\bparentId\x88\x01\x01\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\tR\acontent\x12 \n" +
+	"\tmedia_url\x18\x05 \x01(\tH\x01R\bmediaUrl\x88\x01\x01\x12\x1e\n" +
+	"\blink_url\x18\x06 \x01(\tH\x02R\alinkUrl\x88\x01\x01B\f\n" +
+	"\n" +
+	"_parent_idB\f\n" +
+	"\n" +
+	"_media_urlB\v\n" +
+	"\t_link_url\"#\n" +
+	"\x11GetCommentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xc7\x01\n" +
+	"\x14UpdateCommentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1d\n" +
+	"\acontent\x18\x03 \x01(\tH\x00R\acontent\x88\x01\x01\x12 \n" +
+	"\tmedia_url\x18\x04 \x01(\tH\x01R\bmediaUrl\x88\x01\x01\x12\x1e\n" +
+	"\blink_url\x18\x05 \x01(\tH\x02R\alinkUrl\x88\x01\x01B\n" +
+	"\n" +
+	"\b_contentB\f\n" +
+	"\n" +
+	"_media_urlB\v\n" +
+	"\t_link_url\"?\n" +
+	"\x14DeleteCommentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xe9\x01\n" +
+	"\x19ListCommentsByRootRequest\x12\x17\n" +
+	"\aroot_id\x18\x01 \x01(\tR\x06rootId\x12\x19\n" +
+	"\x05limit\x18\x02 \x01(\x05H\x00R\x05limit\x88\x01\x01\x12\x1b\n" +
+	"\x06offset\x18\x03 \x01(\x05H\x01R\x06offset\x88\x01\x01\x12\x17\n" +
+	"\asort_by\x18\x04 \x01(\tR\x06sortBy\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\x05 \x01(\tR\tsortOrder\x12 \n" +
+	"\tmax_depth\x18\x06 \x01(\x05H\x02R\bmaxDepth\x88\x01\x01B\b\n" +
+	"\x06_limitB\t\n" +
+	"\a_offsetB\f\n" +
+	"\n" +
+	"_max_depth\"K\n" +
+	"\x14ListCommentsResponse\x123\n" +
+	"\bcomments\x18\x01 \x03(\v2\x17.commentific.v1.CommentR\bcomments\"f\n" +
+	"\x15GetCommentTreeRequest\x12\x17\n" +
+	"\aroot_id\x18\x01 \x01(\tR\x06rootId\x12\x1b\n" +
+	"\tmax_depth\x18\x02 \x01(\x05R\bmaxDepth\x12\x17\n" +
+	"\asort_by\x18\x03 \x01(\tR\x06sortBy\"K\n" +
+	"\x16GetCommentTreeResponse\x121\n" +
+	"\x05roots\x18\x01 \x03(\v2\x1b.commentific.v1.CommentTreeR\x05roots\"\x83\x01\n" +
+	"\x12VoteCommentRequest\x12\x1d\n" +
+	"\n" +
+	"comment_id\x18\x01 \x01(\tR\tcommentId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x125\n" +
+	"\tvote_type\x18\x03 \x01(\x0e2\x18.commentific.v1.VoteTypeR\bvoteType\"1\n" +
+	"\x16GetCommentStatsRequest\x12\x17\n" +
+	"\aroot_id\x18\x01 \x01(\tR\x06rootId*K\n" +
+	"\bVoteType\x12\x19\n" +
+	"\x15VOTE_TYPE_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fVOTE_TYPE_UP\x10\x01\x12\x12\n" +
+	"\x0eVOTE_TYPE_DOWN\x10\x022\xb6\x05\n" +
+	"\x0eCommentService\x12N\n" +
+	"\rCreateComment\x12$.commentific.v1.CreateCommentRequest\x1a\x17.commentific.v1.Comment\x12H\n" +
+	"\n" +
+	"GetComment\x12!.commentific.v1.GetCommentRequest\x1a\x17.commentific.v1.Comment\x12N\n" +
+	"\rUpdateComment\x12$.commentific.v1.UpdateCommentRequest\x1a\x17.commentific.v1.Comment\x12M\n" +
+	"\rDeleteComment\x12$.commentific.v1.DeleteCommentRequest\x1a\x16.google.protobuf.Empty\x12e\n" +
+	"\x12ListCommentsByRoot\x12).commentific.v1.ListCommentsByRootRequest\x1a$.commentific.v1.ListCommentsResponse\x12_\n" +
+	"\x0eGetCommentTree\x12%.commentific.v1.GetCommentTreeRequest\x1a&.commentific.v1.GetCommentTreeResponse\x12J\n" +
+	"\vVoteComment\x12\".commentific.v1.VoteCommentRequest\x1a\x17.commentific.v1.Comment\x12W\n" +
+	"\x0fGetCommentStats\x12&.commentific.v1.GetCommentStatsRequest\x1a\x1c.commentific.v1.CommentStatsBLZJgithub.com/christopher18/commentific/v2/proto/commentific/v1;commentificv1b\x06proto3"
+
+var (
+	file_commentific_v1_comment_service_proto_rawDescOnce sync.Once
+	file_commentific_v1_comment_service_proto_rawDescData []byte
+)
+
+func file_commentific_v1_comment_service_proto_rawDescGZIP() []byte {
+	file_commentific_v1_comment_service_proto_rawDescOnce.Do(func() {
+		file_commentific_v1_comment_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_commentific_v1_comment_service_proto_rawDesc), len(file_commentific_v1_comment_service_proto_rawDesc)))
+	})
+	return file_commentific_v1_comment_service_proto_rawDescData
+}
+
+var file_commentific_v1_comment_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_commentific_v1_comment_service_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_commentific_v1_comment_service_proto_goTypes = []any{
+	(VoteType)(0),                     // 0: commentific.v1.VoteType
+	(*Comment)(nil),                   // 1: commentific.v1.Comment
+	(*CommentTree)(nil),               // 2: commentific.v1.CommentTree
+	(*CommentStats)(nil),              // 3: commentific.v1.CommentStats
+	(*CreateCommentRequest)(nil),      // 4: commentific.v1.CreateCommentRequest
+	(*GetCommentRequest)(nil),         // 5: commentific.v1.GetCommentRequest
+	(*UpdateCommentRequest)(nil),      // 6: commentific.v1.UpdateCommentRequest
+	(*DeleteCommentRequest)(nil),      // 7: commentific.v1.DeleteCommentRequest
+	(*ListCommentsByRootRequest)(nil), // 8: commentific.v1.ListCommentsByRootRequest
+	(*ListCommentsResponse)(nil),      // 9: commentific.v1.ListCommentsResponse
+	(*GetCommentTreeRequest)(nil),     // 10: commentific.v1.GetCommentTreeRequest
+	(*GetCommentTreeResponse)(nil),    // 11: commentific.v1.GetCommentTreeResponse
+	(*VoteCommentRequest)(nil),        // 12: commentific.v1.VoteCommentRequest
+	(*GetCommentStatsRequest)(nil),    // 13: commentific.v1.GetCommentStatsRequest
+	(*timestamppb.Timestamp)(nil),     // 14: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),             // 15: google.protobuf.Empty
+}
+var file_commentific_v1_comment_service_proto_depIdxs = []int32{
+	14, // 0: commentific.v1.Comment.created_at:type_name -> google.protobuf.Timestamp
+	14, // 1: commentific.v1.Comment.updated_at:type_name -> google.protobuf.Timestamp
+	14, // 2: commentific.v1.Comment.content_updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 3: commentific.v1.CommentTree.comment:type_name -> commentific.v1.Comment
+	2,  // 4: commentific.v1.CommentTree.children:type_name -> commentific.v1.CommentTree
+	1,  // 5: commentific.v1.ListCommentsResponse.comments:type_name -> commentific.v1.Comment
+	2,  // 6: commentific.v1.GetCommentTreeResponse.roots:type_name -> commentific.v1.CommentTree
+	0,  // 7: commentific.v1.VoteCommentRequest.vote_type:type_name -> commentific.v1.VoteType
+	4,  // 8: commentific.v1.CommentService.CreateComment:input_type -> commentific.v1.CreateCommentRequest
+	5,  // 9: commentific.v1.CommentService.GetComment:input_type -> commentific.v1.GetCommentRequest
+	6,  // 10: commentific.v1.CommentService.UpdateComment:input_type -> commentific.v1.UpdateCommentRequest
+	7,  // 11: commentific.v1.CommentService.DeleteComment:input_type -> commentific.v1.DeleteCommentRequest
+	8,  // 12: commentific.v1.CommentService.ListCommentsByRoot:input_type -> commentific.v1.ListCommentsByRootRequest
+	10, // 13: commentific.v1.CommentService.GetCommentTree:input_type -> commentific.v1.GetCommentTreeRequest
+	12, // 14: commentific.v1.CommentService.VoteComment:input_type -> commentific.v1.VoteCommentRequest
+	13, // 15: commentific.v1.CommentService.GetCommentStats:input_type -> commentific.v1.GetCommentStatsRequest
+	1,  // 16: commentific.v1.CommentService.CreateComment:output_type -> commentific.v1.Comment
+	1,  // 17: commentific.v1.CommentService.GetComment:output_type -> commentific.v1.Comment
+	1,  // 18: commentific.v1.CommentService.UpdateComment:output_type -> commentific.v1.Comment
+	15, // 19: commentific.v1.CommentService.DeleteComment:output_type -> google.protobuf.Empty
+	9,  // 20: commentific.v1.CommentService.ListCommentsByRoot:output_type -> commentific.v1.ListCommentsResponse
+	11, // 21: commentific.v1.CommentService.GetCommentTree:output_type -> commentific.v1.GetCommentTreeResponse
+	1,  // 22: commentific.v1.CommentService.VoteComment:output_type -> commentific.v1.Comment
+	3,  // 23: commentific.v1.CommentService.GetCommentStats:output_type -> commentific.v1.CommentStats
+	16, // [16:24] is the sub-list for method output_type
+	8,  // [8:16] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_commentific_v1_comment_service_proto_init() }
+func file_commentific_v1_comment_service_proto_init() {
+	if File_commentific_v1_comment_service_proto != nil {
+		return
+	}
+	file_commentific_v1_comment_service_proto_msgTypes[0].OneofWrappers = []any{}
+	file_commentific_v1_comment_service_proto_msgTypes[3].OneofWrappers = []any{}
+	file_commentific_v1_comment_service_proto_msgTypes[5].OneofWrappers = []any{}
+	file_commentific_v1_comment_service_proto_msgTypes[7].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_commentific_v1_comment_service_proto_rawDesc), len(file_commentific_v1_comment_service_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_commentific_v1_comment_service_proto_goTypes,
+		DependencyIndexes: file_commentific_v1_comment_service_proto_depIdxs,
+		EnumInfos:         file_commentific_v1_comment_service_proto_enumTypes,
+		MessageInfos:      file_commentific_v1_comment_service_proto_msgTypes,
+	}.Build()
+	File_commentific_v1_comment_service_proto = out.File
+	file_commentific_v1_comment_service_proto_goTypes = nil
+	file_commentific_v1_comment_service_proto_depIdxs = nil
+}
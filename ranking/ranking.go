@@ -0,0 +1,77 @@
+// Package ranking implements the comment ranking formulas used by the
+// computed sort options. The Postgres repository evaluates the same formulas
+// in SQL; these Go versions back the in-memory repository and tests.
+package ranking
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// hotEpoch is the reference time for hot scores. Only relative order
+	// matters, so any fixed instant works; this is the one Reddit uses.
+	hotEpoch = 1134028003
+
+	// hotDecaySeconds is how much newer a comment must be to be worth a
+	// 10x higher score (12.5 hours)
+	hotDecaySeconds = 45000
+
+	// wilsonZ is the z-score for the Wilson interval (95% confidence)
+	wilsonZ = 1.96
+)
+
+// Hot combines score and age so fresh comments can outrank older ones with
+// more votes. Each order of magnitude of score is worth hotDecaySeconds of
+// recency. The value depends only on stored fields, so ordering is stable.
+func Hot(score int64, createdAt time.Time) float64 {
+	order := math.Log10(math.Max(math.Abs(float64(score)), 1))
+
+	var sign float64
+	switch {
+	case score > 0:
+		sign = 1
+	case score < 0:
+		sign = -1
+	}
+
+	seconds := float64(createdAt.UnixNano())/float64(time.Second) - hotEpoch
+	return sign*order + seconds/hotDecaySeconds
+}
+
+// Best returns the lower bound of the Wilson score confidence interval for
+// the proportion of upvotes. It favors comments whose ratio is both high and
+// backed by enough votes, so 50 up / 0 down beats 200 up / 150 down while a
+// single upvote does not beat a long, consistently positive record.
+func Best(upvotes, downvotes int64) float64 {
+	n := float64(upvotes + downvotes)
+	if n == 0 {
+		return 0
+	}
+
+	p := float64(upvotes) / n
+	z2 := wilsonZ * wilsonZ
+	return (p + z2/(2*n) - wilsonZ*math.Sqrt(p*(1-p)/n+z2/(4*n*n))) / (1 + z2/n)
+}
+
+// Controversial ranks comments with many votes split nearly evenly highest.
+// Vote volume is raised to the power of the balance between up and down
+// votes, so a one-sided comment scores 0 however popular it is.
+func Controversial(upvotes, downvotes int64) float64 {
+	if upvotes <= 0 || downvotes <= 0 {
+		return 0
+	}
+
+	magnitude := float64(upvotes + downvotes)
+	balance := float64(min(upvotes, downvotes)) / float64(max(upvotes, downvotes))
+	return math.Pow(magnitude, balance)
+}
+
+// DecayWeight returns how much a vote of the given age still counts when its
+// weight halves every halfLife. Votes from the future count fully.
+func DecayWeight(age, halfLife time.Duration) float64 {
+	if age <= 0 || halfLife <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, float64(age)/float64(halfLife))
+}
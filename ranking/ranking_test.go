@@ -0,0 +1,93 @@
+package ranking_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/ranking"
+)
+
+func TestHot_NewerLowerScoreOutranksOlderHigherScore(t *testing.T) {
+	// Setup
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	oldScore, oldCreated := int64(50), now.Add(-3*24*time.Hour)
+	newScore, newCreated := int64(5), now.Add(-1*time.Hour)
+
+	// Execute
+	oldHot := ranking.Hot(oldScore, oldCreated)
+	newHot := ranking.Hot(newScore, newCreated)
+
+	// Assert
+	if newHot <= oldHot {
+		t.Fatalf("Expected newer comment to rank higher under hot, got new=%f old=%f", newHot, oldHot)
+	}
+	if newScore >= oldScore {
+		t.Fatalf("Expected older comment to rank higher under score, got new=%d old=%d", newScore, oldScore)
+	}
+}
+
+func TestHot_SameAgeOrdersByScore(t *testing.T) {
+	created := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	if ranking.Hot(100, created) <= ranking.Hot(10, created) {
+		t.Fatal("Expected higher score to rank higher at the same age")
+	}
+	if ranking.Hot(-10, created) >= ranking.Hot(0, created) {
+		t.Fatal("Expected negative score to rank below zero at the same age")
+	}
+}
+
+func TestBest_SmallUnanimousSampleOutranksLargeNoisySample(t *testing.T) {
+	// Execute
+	unanimous := ranking.Best(50, 0)
+	noisy := ranking.Best(200, 150)
+
+	// Assert
+	if unanimous <= noisy {
+		t.Fatalf("Expected 50/0 to rank above 200/150, got %f vs %f", unanimous, noisy)
+	}
+}
+
+func TestBest_PenalizesTinySamples(t *testing.T) {
+	single := ranking.Best(1, 0)
+	established := ranking.Best(50, 5)
+
+	if single >= established {
+		t.Fatalf("Expected 1/0 to rank below 50/5, got %f vs %f", single, established)
+	}
+	if got := ranking.Best(0, 0); got != 0 {
+		t.Fatalf("Expected 0 for no votes, got: %f", got)
+	}
+}
+
+func TestControversial_EvenHighVolumeSplitRanksHighest(t *testing.T) {
+	// Execute
+	contested := ranking.Controversial(100, 100)
+	oneSided := ranking.Controversial(200, 0)
+	small := ranking.Controversial(2, 2)
+
+	// Assert
+	if contested <= oneSided {
+		t.Fatalf("Expected 100/100 to rank above 200/0, got %f vs %f", contested, oneSided)
+	}
+	if contested <= small {
+		t.Fatalf("Expected 100/100 to rank above 2/2, got %f vs %f", contested, small)
+	}
+	if ranking.Controversial(60, 40) <= ranking.Controversial(90, 10) {
+		t.Fatal("Expected a closer split to rank higher at equal volume")
+	}
+}
+
+func TestDecayWeight_HalvesEveryHalfLife(t *testing.T) {
+	halfLife := 7 * 24 * time.Hour
+
+	if got := ranking.DecayWeight(0, halfLife); got != 1 {
+		t.Fatalf("Expected a new vote to count fully, got: %f", got)
+	}
+	if got := ranking.DecayWeight(halfLife, halfLife); got != 0.5 {
+		t.Fatalf("Expected half weight after one half-life, got: %f", got)
+	}
+	if got := ranking.DecayWeight(2*halfLife, halfLife); got != 0.25 {
+		t.Fatalf("Expected quarter weight after two half-lives, got: %f", got)
+	}
+}
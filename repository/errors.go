@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrNotFound is returned when a requested comment does not exist or has been deleted
+var ErrNotFound = errors.New("comment not found")
+
+// ErrHasReplies is returned when hard deleting a comment that still has replies
+var ErrHasReplies = errors.New("comment has replies")
+
+// ErrVersionConflict is returned when an update names a version other than
+// the stored one, meaning the comment changed since the caller read it
+var ErrVersionConflict = errors.New("comment was modified concurrently")
+
+// PathSeparator joins comment IDs in a comment's materialized path
+const PathSeparator = "."
+
+// ErrInvalidID is returned when a comment ID contains PathSeparator, which
+// would make the comment's path ambiguous
+var ErrInvalidID = errors.New(`comment ID must not contain "` + PathSeparator + `"`)
+
+// ValidateCommentID returns ErrInvalidID if id can't be used in a path
+func ValidateCommentID(id string) error {
+	if strings.Contains(id, PathSeparator) {
+		return ErrInvalidID
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/christopher18/commentific/v2/models"
 )
@@ -11,38 +12,85 @@ type CommentRepository interface {
 	// Comment CRUD operations
 	CreateComment(ctx context.Context, comment *models.Comment) error
 	GetCommentByID(ctx context.Context, id string) (*models.Comment, error)
+	GetCommentForUpdate(ctx context.Context, id string) (*models.Comment, error)                        // GetCommentByID that, inside a transaction, locks the row until it ends
+	GetCommentsByIDs(ctx context.Context, ids []string, includeDeleted bool) ([]*models.Comment, error) // In input order; missing IDs are skipped
 	UpdateComment(ctx context.Context, id string, updates *models.UpdateCommentRequest) error
-	DeleteComment(ctx context.Context, id string, userID string) error // Soft delete with user verification
+	DeleteComment(ctx context.Context, id string, userID string, reason *string) error      // Soft delete with user verification, recording the author as DeletedBy
+	RemoveComment(ctx context.Context, id string, moderatorID string, reason *string) error // Soft delete anyone's comment, recording the moderator as DeletedBy
+	HardDeleteComment(ctx context.Context, id string) error                                 // Remove the row and its votes; ErrHasReplies if anything replies to it
 
 	// Comment querying and filtering
 	GetComments(ctx context.Context, filter *models.CommentFilter) ([]*models.Comment, error)
 	GetCommentsByRootID(ctx context.Context, rootID string, filter *models.CommentFilter) ([]*models.Comment, error)
+	GetCommentsSince(ctx context.Context, rootID string, since time.Time, afterID string, limit int) ([]*models.Comment, error) // Approved comments, deleted or not, updated after (since, afterID) in (updated_at, id) order
 	GetCommentsByUserID(ctx context.Context, userID string, filter *models.CommentFilter) ([]*models.Comment, error)
-	GetCommentChildren(ctx context.Context, parentID string, maxDepth int) ([]*models.Comment, error)
+	GetCommentChildren(ctx context.Context, parentID string, maxDepth int, filter *models.CommentFilter) ([]*models.Comment, error) // Path order; filter supplies Limit/Offset
+	GetDirectChildren(ctx context.Context, parentID string, filter *models.CommentFilter) ([]*models.Comment, error)                // Immediate replies only
+	ExportComments(ctx context.Context, rootID, afterPath string, limit int, includeDeleted bool) ([]*models.Comment, error)        // Byte-wise path order after afterPath, so parents precede children
 
 	// Hierarchical operations
 	GetCommentTree(ctx context.Context, rootID string, maxDepth int, sortBy string) ([]*models.CommentTree, error)
-	GetCommentPath(ctx context.Context, commentID string) ([]*models.Comment, error) // Get path from root to comment
+	GetCommentSubtree(ctx context.Context, commentID string, maxDepth int, sortBy string) (*models.CommentTree, error) // The comment and its replies up to maxDepth levels down; ErrNotFound if missing, deleted or unapproved
+	GetCommentPath(ctx context.Context, commentID string) ([]*models.Comment, error)                                   // Get path from root to comment
+	GetCommentAncestors(ctx context.Context, commentID string) ([]*models.Comment, error)                              // Root first, following parent links rather than the path; excludes the comment itself
+	MoveComment(ctx context.Context, commentID, newParentID string) error                                              // Re-parent, rewriting path and depth for the whole subtree
 
 	// Vote operations
 	CreateVote(ctx context.Context, vote *models.Vote) error
 	UpdateVote(ctx context.Context, commentID, userID string, voteType models.VoteType) error
 	DeleteVote(ctx context.Context, commentID, userID string) error
+	DeleteVotes(ctx context.Context, commentIDs []string, userID string) ([]string, error) // One statement; returns the IDs of the comments that had a vote
 	GetUserVote(ctx context.Context, commentID, userID string) (*models.Vote, error)
 	GetCommentVotes(ctx context.Context, commentID string) ([]*models.Vote, error)
+	GetVotesForComments(ctx context.Context, commentIDs []string) (map[string][]*models.Vote, error)            // Every vote, keyed by comment ID
+	GetUserVotes(ctx context.Context, userID string, filter *models.VoteFilter) ([]*models.VotedComment, error) // Most recent vote first
+	GetRepliesToUser(ctx context.Context, userID string, filter *models.CommentFilter) ([]*models.Reply, error) // Newest first; others' live replies to the user's live comments, leaving out users they blocked; filter supplies RootID, Limit/Offset
+	GetVoteBreakdown(ctx context.Context, commentID string) (*models.VoteBreakdown, error)                      // Counted from the vote rows; ErrNotFound for missing or deleted comments
 
 	// Batch operations for performance
-	GetCommentsWithUserVotes(ctx context.Context, rootID, userID string, filter *models.CommentFilter) ([]*models.Comment, map[string]*models.Vote, error)
+	GetCommentsWithUserVotes(ctx context.Context, rootID, userID string, filter *models.CommentFilter) ([]*models.Comment, map[string]*models.Vote, error) // Votes keyed by comment ID, also set as each comment's CurrentUserVote
+	GetUserVotesForComments(ctx context.Context, commentIDs []string, userID string) (map[string]*models.Vote, error)                                      // Keyed by comment ID
+	GetCommentTreeWithUserVotes(ctx context.Context, rootID, userID string, maxDepth int, sortBy string) ([]*models.CommentTree, error)                    // GetCommentTree as userID sees it, each comment carrying their vote
 	UpdateCommentScores(ctx context.Context, commentIDs []string) error
+	ImportComments(ctx context.Context, comments []*models.Comment) error // Insert as given (IDs, paths, timestamps); parents must precede children
 
 	// Statistics and analytics
 	GetCommentStats(ctx context.Context, rootID string) (*models.CommentStats, error)
+	GetCommentStatsBatch(ctx context.Context, rootIDs []string) (map[string]*models.CommentStats, error) // Keyed by root ID
+	GetRootVersion(ctx context.Context, rootID string) (*models.RootVersion, error)                      // Counts every row on the root, deleted and unapproved included
 	GetUserCommentCount(ctx context.Context, userID string) (int64, error)
+	GetUserReputation(ctx context.Context, userID string) (*models.UserReputation, error) // Counted from the vote rows, skipping self-votes; zero values for unknown users
 	GetTopComments(ctx context.Context, rootID string, limit int, timeRange string) ([]*models.Comment, error)
+	GetTrendingRoots(ctx context.Context, timeRange string, limit int) ([]*models.TrendingRoot, error) // Most commented roots within the window
+	GetTopCommenters(ctx context.Context, rootID string, limit int) ([]*models.TopCommenter, error)    // Most comments first, then highest combined score
+
+	// Moderation
+	SetCommentStatus(ctx context.Context, id string, status models.CommentStatus) error                             // Reply counts follow the change; ErrNotFound for missing or deleted comments
+	GetModerationQueue(ctx context.Context, rootID string, filter *models.CommentFilter) ([]*models.Comment, error) // Pending and quarantined comments, oldest first; filter supplies Limit/Offset
+
+	// Per-root settings
+	GetRootSettings(ctx context.Context, rootID string) (*models.RootSettings, error) // ErrNotFound when the root has no overrides
+	SetRootSettings(ctx context.Context, settings *models.RootSettings) error         // Replace the root's overrides, setting UpdatedAt
+
+	// User blocks; reads given a CommentFilter.ViewerID leave out comments by users the viewer blocked
+	AddBlock(ctx context.Context, blockerID, blockedID string) error    // No-op if already blocked
+	RemoveBlock(ctx context.Context, blockerID, blockedID string) error // No-op if not blocked
+	GetBlockedUsers(ctx context.Context, blockerID string) ([]string, error)
+
+	// Subscriptions
+	Subscribe(ctx context.Context, userID, rootID string) error   // No-op if already subscribed
+	Unsubscribe(ctx context.Context, userID, rootID string) error // No-op if not subscribed
+	GetSubscriberUserIDs(ctx context.Context, rootID string) ([]string, error)
+
+	// Link previews
+	SetLinkPreview(ctx context.Context, id string, preview *models.LinkPreview) error // ErrNotFound unless the comment exists and still links to preview.URL
 
 	// Maintenance operations
 	PurgeDeletedComments(ctx context.Context, olderThan int) (int64, error) // Delete soft-deleted comments older than X days
 	RecalculateCommentScores(ctx context.Context) error
+	RepairCommentPaths(ctx context.Context, rootID string) (int64, error)                      // Rebuild path and depth from parent_id; returns rows corrected
+	RecalculateReplyCounts(ctx context.Context) error                                          // Backfill denormalized reply and descendant counts
+	RecalculateDecayedScores(ctx context.Context, halfLife time.Duration, now time.Time) error // Weight each vote by its age as of now
 
 	// Transaction support
 	BeginTx(ctx context.Context) (Repository, error)
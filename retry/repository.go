@@ -0,0 +1,466 @@
+package retry
+
+import (
+	"context"
+	"time"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/repository"
+)
+
+// retryingRepository retries each CommentRepository call on transient errors
+type retryingRepository struct {
+	repo   repository.CommentRepository
+	config Config
+}
+
+var _ repository.CommentRepository = (*retryingRepository)(nil)
+
+func (r *retryingRepository) CreateComment(ctx context.Context, comment *models.Comment) error {
+	return r.do(ctx, func() error {
+		return r.repo.CreateComment(ctx, comment)
+	})
+}
+
+func (r *retryingRepository) GetCommentByID(ctx context.Context, id string) (comment *models.Comment, err error) {
+	err = r.do(ctx, func() error {
+		comment, err = r.repo.GetCommentByID(ctx, id)
+		return err
+	})
+	return comment, err
+}
+
+func (r *retryingRepository) GetCommentForUpdate(ctx context.Context, id string) (comment *models.Comment, err error) {
+	err = r.do(ctx, func() error {
+		comment, err = r.repo.GetCommentForUpdate(ctx, id)
+		return err
+	})
+	return comment, err
+}
+
+func (r *retryingRepository) GetCommentsByIDs(ctx context.Context, ids []string, includeDeleted bool) (comments []*models.Comment, err error) {
+	err = r.do(ctx, func() error {
+		comments, err = r.repo.GetCommentsByIDs(ctx, ids, includeDeleted)
+		return err
+	})
+	return comments, err
+}
+
+func (r *retryingRepository) UpdateComment(ctx context.Context, id string, updates *models.UpdateCommentRequest) error {
+	return r.do(ctx, func() error {
+		return r.repo.UpdateComment(ctx, id, updates)
+	})
+}
+
+func (r *retryingRepository) DeleteComment(ctx context.Context, id string, userID string, reason *string) error {
+	return r.do(ctx, func() error {
+		return r.repo.DeleteComment(ctx, id, userID, reason)
+	})
+}
+
+func (r *retryingRepository) RemoveComment(ctx context.Context, id string, moderatorID string, reason *string) error {
+	return r.do(ctx, func() error {
+		return r.repo.RemoveComment(ctx, id, moderatorID, reason)
+	})
+}
+
+func (r *retryingRepository) HardDeleteComment(ctx context.Context, id string) error {
+	return r.do(ctx, func() error {
+		return r.repo.HardDeleteComment(ctx, id)
+	})
+}
+
+func (r *retryingRepository) GetComments(ctx context.Context, filter *models.CommentFilter) (comments []*models.Comment, err error) {
+	err = r.do(ctx, func() error {
+		comments, err = r.repo.GetComments(ctx, filter)
+		return err
+	})
+	return comments, err
+}
+
+func (r *retryingRepository) GetCommentsByRootID(ctx context.Context, rootID string, filter *models.CommentFilter) (comments []*models.Comment, err error) {
+	err = r.do(ctx, func() error {
+		comments, err = r.repo.GetCommentsByRootID(ctx, rootID, filter)
+		return err
+	})
+	return comments, err
+}
+
+func (r *retryingRepository) GetCommentsSince(ctx context.Context, rootID string, since time.Time, afterID string, limit int) (comments []*models.Comment, err error) {
+	err = r.do(ctx, func() error {
+		comments, err = r.repo.GetCommentsSince(ctx, rootID, since, afterID, limit)
+		return err
+	})
+	return comments, err
+}
+
+func (r *retryingRepository) GetCommentsByUserID(ctx context.Context, userID string, filter *models.CommentFilter) (comments []*models.Comment, err error) {
+	err = r.do(ctx, func() error {
+		comments, err = r.repo.GetCommentsByUserID(ctx, userID, filter)
+		return err
+	})
+	return comments, err
+}
+
+func (r *retryingRepository) GetCommentChildren(ctx context.Context, parentID string, maxDepth int, filter *models.CommentFilter) (comments []*models.Comment, err error) {
+	err = r.do(ctx, func() error {
+		comments, err = r.repo.GetCommentChildren(ctx, parentID, maxDepth, filter)
+		return err
+	})
+	return comments, err
+}
+
+func (r *retryingRepository) GetDirectChildren(ctx context.Context, parentID string, filter *models.CommentFilter) (comments []*models.Comment, err error) {
+	err = r.do(ctx, func() error {
+		comments, err = r.repo.GetDirectChildren(ctx, parentID, filter)
+		return err
+	})
+	return comments, err
+}
+
+func (r *retryingRepository) ExportComments(ctx context.Context, rootID, afterPath string, limit int, includeDeleted bool) (comments []*models.Comment, err error) {
+	err = r.do(ctx, func() error {
+		comments, err = r.repo.ExportComments(ctx, rootID, afterPath, limit, includeDeleted)
+		return err
+	})
+	return comments, err
+}
+
+func (r *retryingRepository) GetCommentTree(ctx context.Context, rootID string, maxDepth int, sortBy string) (tree []*models.CommentTree, err error) {
+	err = r.do(ctx, func() error {
+		tree, err = r.repo.GetCommentTree(ctx, rootID, maxDepth, sortBy)
+		return err
+	})
+	return tree, err
+}
+
+func (r *retryingRepository) GetCommentSubtree(ctx context.Context, commentID string, maxDepth int, sortBy string) (tree *models.CommentTree, err error) {
+	err = r.do(ctx, func() error {
+		tree, err = r.repo.GetCommentSubtree(ctx, commentID, maxDepth, sortBy)
+		return err
+	})
+	return tree, err
+}
+
+func (r *retryingRepository) GetCommentPath(ctx context.Context, commentID string) (comments []*models.Comment, err error) {
+	err = r.do(ctx, func() error {
+		comments, err = r.repo.GetCommentPath(ctx, commentID)
+		return err
+	})
+	return comments, err
+}
+
+func (r *retryingRepository) GetCommentAncestors(ctx context.Context, commentID string) (comments []*models.Comment, err error) {
+	err = r.do(ctx, func() error {
+		comments, err = r.repo.GetCommentAncestors(ctx, commentID)
+		return err
+	})
+	return comments, err
+}
+
+func (r *retryingRepository) MoveComment(ctx context.Context, commentID, newParentID string) error {
+	return r.do(ctx, func() error {
+		return r.repo.MoveComment(ctx, commentID, newParentID)
+	})
+}
+
+func (r *retryingRepository) CreateVote(ctx context.Context, vote *models.Vote) error {
+	return r.do(ctx, func() error {
+		return r.repo.CreateVote(ctx, vote)
+	})
+}
+
+func (r *retryingRepository) UpdateVote(ctx context.Context, commentID, userID string, voteType models.VoteType) error {
+	return r.do(ctx, func() error {
+		return r.repo.UpdateVote(ctx, commentID, userID, voteType)
+	})
+}
+
+func (r *retryingRepository) DeleteVote(ctx context.Context, commentID, userID string) error {
+	return r.do(ctx, func() error {
+		return r.repo.DeleteVote(ctx, commentID, userID)
+	})
+}
+
+func (r *retryingRepository) DeleteVotes(ctx context.Context, commentIDs []string, userID string) (removed []string, err error) {
+	err = r.do(ctx, func() error {
+		removed, err = r.repo.DeleteVotes(ctx, commentIDs, userID)
+		return err
+	})
+	return removed, err
+}
+
+func (r *retryingRepository) GetUserVote(ctx context.Context, commentID, userID string) (vote *models.Vote, err error) {
+	err = r.do(ctx, func() error {
+		vote, err = r.repo.GetUserVote(ctx, commentID, userID)
+		return err
+	})
+	return vote, err
+}
+
+func (r *retryingRepository) GetCommentVotes(ctx context.Context, commentID string) (votes []*models.Vote, err error) {
+	err = r.do(ctx, func() error {
+		votes, err = r.repo.GetCommentVotes(ctx, commentID)
+		return err
+	})
+	return votes, err
+}
+
+func (r *retryingRepository) GetVotesForComments(ctx context.Context, commentIDs []string) (votes map[string][]*models.Vote, err error) {
+	err = r.do(ctx, func() error {
+		votes, err = r.repo.GetVotesForComments(ctx, commentIDs)
+		return err
+	})
+	return votes, err
+}
+
+func (r *retryingRepository) GetUserVotes(ctx context.Context, userID string, filter *models.VoteFilter) (voted []*models.VotedComment, err error) {
+	err = r.do(ctx, func() error {
+		voted, err = r.repo.GetUserVotes(ctx, userID, filter)
+		return err
+	})
+	return voted, err
+}
+
+func (r *retryingRepository) GetVoteBreakdown(ctx context.Context, commentID string) (breakdown *models.VoteBreakdown, err error) {
+	err = r.do(ctx, func() error {
+		breakdown, err = r.repo.GetVoteBreakdown(ctx, commentID)
+		return err
+	})
+	return breakdown, err
+}
+
+func (r *retryingRepository) GetCommentsWithUserVotes(ctx context.Context, rootID, userID string, filter *models.CommentFilter) (comments []*models.Comment, votes map[string]*models.Vote, err error) {
+	err = r.do(ctx, func() error {
+		comments, votes, err = r.repo.GetCommentsWithUserVotes(ctx, rootID, userID, filter)
+		return err
+	})
+	return comments, votes, err
+}
+
+func (r *retryingRepository) GetUserVotesForComments(ctx context.Context, commentIDs []string, userID string) (votes map[string]*models.Vote, err error) {
+	err = r.do(ctx, func() error {
+		votes, err = r.repo.GetUserVotesForComments(ctx, commentIDs, userID)
+		return err
+	})
+	return votes, err
+}
+
+func (r *retryingRepository) GetCommentTreeWithUserVotes(ctx context.Context, rootID, userID string, maxDepth int, sortBy string) (tree []*models.CommentTree, err error) {
+	err = r.do(ctx, func() error {
+		tree, err = r.repo.GetCommentTreeWithUserVotes(ctx, rootID, userID, maxDepth, sortBy)
+		return err
+	})
+	return tree, err
+}
+
+func (r *retryingRepository) UpdateCommentScores(ctx context.Context, commentIDs []string) error {
+	return r.do(ctx, func() error {
+		return r.repo.UpdateCommentScores(ctx, commentIDs)
+	})
+}
+
+func (r *retryingRepository) ImportComments(ctx context.Context, comments []*models.Comment) error {
+	return r.do(ctx, func() error {
+		return r.repo.ImportComments(ctx, comments)
+	})
+}
+
+func (r *retryingRepository) GetCommentStats(ctx context.Context, rootID string) (stats *models.CommentStats, err error) {
+	err = r.do(ctx, func() error {
+		stats, err = r.repo.GetCommentStats(ctx, rootID)
+		return err
+	})
+	return stats, err
+}
+
+func (r *retryingRepository) GetCommentStatsBatch(ctx context.Context, rootIDs []string) (stats map[string]*models.CommentStats, err error) {
+	err = r.do(ctx, func() error {
+		stats, err = r.repo.GetCommentStatsBatch(ctx, rootIDs)
+		return err
+	})
+	return stats, err
+}
+
+func (r *retryingRepository) GetRootVersion(ctx context.Context, rootID string) (version *models.RootVersion, err error) {
+	err = r.do(ctx, func() error {
+		version, err = r.repo.GetRootVersion(ctx, rootID)
+		return err
+	})
+	return version, err
+}
+
+func (r *retryingRepository) GetUserCommentCount(ctx context.Context, userID string) (count int64, err error) {
+	err = r.do(ctx, func() error {
+		count, err = r.repo.GetUserCommentCount(ctx, userID)
+		return err
+	})
+	return count, err
+}
+
+func (r *retryingRepository) GetTopComments(ctx context.Context, rootID string, limit int, timeRange string) (comments []*models.Comment, err error) {
+	err = r.do(ctx, func() error {
+		comments, err = r.repo.GetTopComments(ctx, rootID, limit, timeRange)
+		return err
+	})
+	return comments, err
+}
+
+func (r *retryingRepository) GetTrendingRoots(ctx context.Context, timeRange string, limit int) (roots []*models.TrendingRoot, err error) {
+	err = r.do(ctx, func() error {
+		roots, err = r.repo.GetTrendingRoots(ctx, timeRange, limit)
+		return err
+	})
+	return roots, err
+}
+
+func (r *retryingRepository) GetUserReputation(ctx context.Context, userID string) (reputation *models.UserReputation, err error) {
+	err = r.do(ctx, func() error {
+		reputation, err = r.repo.GetUserReputation(ctx, userID)
+		return err
+	})
+	return reputation, err
+}
+
+func (r *retryingRepository) AddBlock(ctx context.Context, blockerID, blockedID string) error {
+	return r.do(ctx, func() error {
+		return r.repo.AddBlock(ctx, blockerID, blockedID)
+	})
+}
+
+func (r *retryingRepository) RemoveBlock(ctx context.Context, blockerID, blockedID string) error {
+	return r.do(ctx, func() error {
+		return r.repo.RemoveBlock(ctx, blockerID, blockedID)
+	})
+}
+
+func (r *retryingRepository) GetBlockedUsers(ctx context.Context, blockerID string) (blocked []string, err error) {
+	err = r.do(ctx, func() error {
+		blocked, err = r.repo.GetBlockedUsers(ctx, blockerID)
+		return err
+	})
+	return blocked, err
+}
+
+func (r *retryingRepository) Subscribe(ctx context.Context, userID, rootID string) error {
+	return r.do(ctx, func() error {
+		return r.repo.Subscribe(ctx, userID, rootID)
+	})
+}
+
+func (r *retryingRepository) Unsubscribe(ctx context.Context, userID, rootID string) error {
+	return r.do(ctx, func() error {
+		return r.repo.Unsubscribe(ctx, userID, rootID)
+	})
+}
+
+func (r *retryingRepository) GetSubscriberUserIDs(ctx context.Context, rootID string) (subscribers []string, err error) {
+	err = r.do(ctx, func() error {
+		subscribers, err = r.repo.GetSubscriberUserIDs(ctx, rootID)
+		return err
+	})
+	return subscribers, err
+}
+
+func (r *retryingRepository) GetRepliesToUser(ctx context.Context, userID string, filter *models.CommentFilter) (replies []*models.Reply, err error) {
+	err = r.do(ctx, func() error {
+		replies, err = r.repo.GetRepliesToUser(ctx, userID, filter)
+		return err
+	})
+	return replies, err
+}
+
+func (r *retryingRepository) GetTopCommenters(ctx context.Context, rootID string, limit int) (commenters []*models.TopCommenter, err error) {
+	err = r.do(ctx, func() error {
+		commenters, err = r.repo.GetTopCommenters(ctx, rootID, limit)
+		return err
+	})
+	return commenters, err
+}
+
+func (r *retryingRepository) SetCommentStatus(ctx context.Context, id string, status models.CommentStatus) error {
+	return r.do(ctx, func() error {
+		return r.repo.SetCommentStatus(ctx, id, status)
+	})
+}
+
+func (r *retryingRepository) GetModerationQueue(ctx context.Context, rootID string, filter *models.CommentFilter) (comments []*models.Comment, err error) {
+	err = r.do(ctx, func() error {
+		comments, err = r.repo.GetModerationQueue(ctx, rootID, filter)
+		return err
+	})
+	return comments, err
+}
+
+func (r *retryingRepository) GetRootSettings(ctx context.Context, rootID string) (settings *models.RootSettings, err error) {
+	err = r.do(ctx, func() error {
+		settings, err = r.repo.GetRootSettings(ctx, rootID)
+		return err
+	})
+	return settings, err
+}
+
+func (r *retryingRepository) SetRootSettings(ctx context.Context, settings *models.RootSettings) error {
+	return r.do(ctx, func() error {
+		return r.repo.SetRootSettings(ctx, settings)
+	})
+}
+
+func (r *retryingRepository) SetLinkPreview(ctx context.Context, id string, preview *models.LinkPreview) error {
+	return r.do(ctx, func() error {
+		return r.repo.SetLinkPreview(ctx, id, preview)
+	})
+}
+
+func (r *retryingRepository) PurgeDeletedComments(ctx context.Context, olderThan int) (count int64, err error) {
+	err = r.do(ctx, func() error {
+		count, err = r.repo.PurgeDeletedComments(ctx, olderThan)
+		return err
+	})
+	return count, err
+}
+
+func (r *retryingRepository) RecalculateCommentScores(ctx context.Context) error {
+	return r.do(ctx, func() error {
+		return r.repo.RecalculateCommentScores(ctx)
+	})
+}
+
+func (r *retryingRepository) RepairCommentPaths(ctx context.Context, rootID string) (count int64, err error) {
+	err = r.do(ctx, func() error {
+		count, err = r.repo.RepairCommentPaths(ctx, rootID)
+		return err
+	})
+	return count, err
+}
+
+func (r *retryingRepository) RecalculateReplyCounts(ctx context.Context) error {
+	return r.do(ctx, func() error {
+		return r.repo.RecalculateReplyCounts(ctx)
+	})
+}
+
+func (r *retryingRepository) RecalculateDecayedScores(ctx context.Context, halfLife time.Duration, now time.Time) error {
+	return r.do(ctx, func() error {
+		return r.repo.RecalculateDecayedScores(ctx, halfLife, now)
+	})
+}
+
+// BeginTx retries opening the transaction, but the handle it returns is not
+// wrapped: once a statement fails Postgres aborts the transaction, so only
+// the caller can retry it, from BeginTx onwards
+func (r *retryingRepository) BeginTx(ctx context.Context) (tx repository.Repository, err error) {
+	err = r.do(ctx, func() error {
+		tx, err = r.repo.BeginTx(ctx)
+		return err
+	})
+	return tx, err
+}
+
+func (r *retryingRepository) CommitTx(ctx context.Context) error {
+	return r.repo.CommitTx(ctx)
+}
+
+func (r *retryingRepository) RollbackTx(ctx context.Context) error {
+	return r.repo.RollbackTx(ctx)
+}
@@ -0,0 +1,77 @@
+// Package retry retries repository calls that fail with transient database
+// errors, such as a dropped connection or a serialization failure, so brief
+// blips don't surface to users as errors. It is opt-in:
+//
+//	repo := retry.WrapRepository(provider.GetCommentRepository(), nil)
+//	commentService := service.NewCommentService(repo)
+package retry
+
+import (
+	"context"
+	"time"
+
+	"github.com/christopher18/commentific/v2/postgres"
+	"github.com/christopher18/commentific/v2/repository"
+)
+
+// Default retry settings
+const (
+	DefaultMaxRetries = 3
+	DefaultBaseDelay  = 50 * time.Millisecond
+	DefaultMaxDelay   = time.Second
+)
+
+// Config controls how failed calls are retried
+type Config struct {
+	MaxRetries int           // Retries after the first attempt
+	BaseDelay  time.Duration // Wait before the first retry, doubled for each one after
+	MaxDelay   time.Duration // Upper bound on the wait between retries
+
+	// IsTransient reports whether an error is worth retrying. Defaults to
+	// postgres.IsTransientError.
+	IsTransient func(error) bool
+}
+
+// WrapRepository returns a repository that retries calls to repo failing
+// with transient errors, waiting with exponential backoff in between.
+// Unset config fields take their defaults; a nil config uses all defaults.
+func WrapRepository(repo repository.CommentRepository, config *Config) repository.CommentRepository {
+	r := &retryingRepository{repo: repo}
+	if config != nil {
+		r.config = *config
+	}
+	if r.config.MaxRetries <= 0 {
+		r.config.MaxRetries = DefaultMaxRetries
+	}
+	if r.config.BaseDelay <= 0 {
+		r.config.BaseDelay = DefaultBaseDelay
+	}
+	if r.config.MaxDelay <= 0 {
+		r.config.MaxDelay = DefaultMaxDelay
+	}
+	if r.config.IsTransient == nil {
+		r.config.IsTransient = postgres.IsTransientError
+	}
+	return r
+}
+
+// do runs call until it succeeds, fails with a non-transient error or runs
+// out of retries. Cancelling ctx stops the wait and returns the last error.
+func (r *retryingRepository) do(ctx context.Context, call func() error) error {
+	delay := r.config.BaseDelay
+	for attempt := 0; ; attempt++ {
+		err := call()
+		if err == nil || attempt >= r.config.MaxRetries || !r.config.IsTransient(err) {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay = min(delay*2, r.config.MaxDelay)
+	}
+}
@@ -0,0 +1,111 @@
+package retry_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/repository"
+	"github.com/christopher18/commentific/v2/retry"
+	"github.com/christopher18/commentific/v2/service"
+	"github.com/lib/pq"
+)
+
+// flakyRepository fails the first failures calls to CreateComment with err
+type flakyRepository struct {
+	repository.CommentRepository
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakyRepository) CreateComment(ctx context.Context, comment *models.Comment) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return f.err
+	}
+	return f.CommentRepository.CreateComment(ctx, comment)
+}
+
+func fastConfig() *retry.Config {
+	return &retry.Config{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+}
+
+func createComment(repo repository.CommentRepository) (*models.Comment, error) {
+	return service.NewCommentService(repo).CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Retried"})
+}
+
+func TestRetry_SucceedsOnSecondAttempt(t *testing.T) {
+	// Setup
+	flaky := &flakyRepository{CommentRepository: memory.NewMemoryRepository(), failures: 1, err: driver.ErrBadConn}
+
+	// Execute
+	comment, err := createComment(retry.WrapRepository(flaky, fastConfig()))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected retry to succeed, got: %v", err)
+	}
+	if flaky.calls != 2 {
+		t.Fatalf("Expected 2 attempts, got: %d", flaky.calls)
+	}
+	if _, err := flaky.GetCommentByID(context.Background(), comment.ID); err != nil {
+		t.Fatalf("Expected comment to be stored, got: %v", err)
+	}
+}
+
+func TestRetry_DoesNotRetryConstraintViolations(t *testing.T) {
+	// Setup
+	flaky := &flakyRepository{CommentRepository: memory.NewMemoryRepository(), failures: 1, err: &pq.Error{Code: "23505"}}
+
+	// Execute
+	_, err := createComment(retry.WrapRepository(flaky, fastConfig()))
+
+	// Assert
+	if err == nil {
+		t.Fatalf("Expected unique violation to be returned, got: nil")
+	}
+	if flaky.calls != 1 {
+		t.Fatalf("Expected 1 attempt, got: %d", flaky.calls)
+	}
+}
+
+func TestRetry_GivesUpAfterMaxRetries(t *testing.T) {
+	// Setup
+	flaky := &flakyRepository{CommentRepository: memory.NewMemoryRepository(), failures: 10, err: &pq.Error{Code: "40001"}}
+
+	// Execute
+	_, err := createComment(retry.WrapRepository(flaky, fastConfig()))
+
+	// Assert
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != "40001" {
+		t.Fatalf("Expected serialization failure after retries, got: %v", err)
+	}
+	if flaky.calls != 4 {
+		t.Fatalf("Expected 1 attempt and 3 retries, got: %d calls", flaky.calls)
+	}
+}
+
+func TestRetry_StopsWhenContextCancelled(t *testing.T) {
+	// Setup
+	flaky := &flakyRepository{CommentRepository: memory.NewMemoryRepository(), failures: 10, err: driver.ErrBadConn}
+	repo := retry.WrapRepository(flaky, &retry.Config{MaxRetries: 5, BaseDelay: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Execute
+	err := repo.CreateComment(ctx, &models.Comment{ID: "c1", RootID: "product-1", UserID: "author", Content: "Cancelled"})
+
+	// Assert
+	if !errors.Is(err, driver.ErrBadConn) {
+		t.Fatalf("Expected last error to be returned, got: %v", err)
+	}
+	if flaky.calls != 1 {
+		t.Fatalf("Expected no retries after cancellation, got: %d calls", flaky.calls)
+	}
+}
@@ -0,0 +1,56 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestGetCommentAncestors(t *testing.T) {
+	// Setup: a reply three levels below a top-level comment
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	var chain []*models.Comment
+	var parentID *string
+	for _, content := range []string{"Top", "Reply", "Reply to reply", "Deepest"} {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: parentID, UserID: "alice", Content: content})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		chain = append(chain, comment)
+		parentID = &comment.ID
+	}
+
+	// Execute
+	ancestors, err := commentService.GetCommentAncestors(ctx, chain[3].ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to get ancestors: %v", err)
+	}
+	if len(ancestors) != 3 {
+		t.Fatalf("Expected 3 ancestors, got %d", len(ancestors))
+	}
+	for i, ancestor := range ancestors {
+		if ancestor.ID != chain[i].ID {
+			t.Errorf("Expected ancestor %d to be %q, got %q", i, chain[i].Content, ancestor.Content)
+		}
+	}
+
+	top, err := commentService.GetCommentAncestors(ctx, chain[0].ID)
+	if err != nil {
+		t.Fatalf("Failed to get ancestors of a top-level comment: %v", err)
+	}
+	if len(top) != 0 {
+		t.Errorf("Expected no ancestors for a top-level comment, got %d", len(top))
+	}
+
+	if _, err := commentService.GetCommentAncestors(ctx, "missing"); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing comment, got %v", err)
+	}
+}
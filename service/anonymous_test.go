@@ -0,0 +1,86 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestCreateComment_AnonymousIssuesGuestToken(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{AllowAnonymous: true})
+	displayName := "  Passerby  "
+
+	// Execute
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{
+		RootID:      "post-1",
+		Content:     "Just visiting",
+		Anonymous:   true,
+		DisplayName: &displayName,
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !comment.IsAnonymous || !strings.HasPrefix(comment.UserID, models.GuestUserIDPrefix) {
+		t.Fatalf("Expected an anonymous comment with a guest token, got: %+v", comment)
+	}
+	if comment.DisplayName == nil || *comment.DisplayName != "Passerby" {
+		t.Fatalf("Expected trimmed display name, got: %v", comment.DisplayName)
+	}
+
+	// The guest token owns the comment
+	newContent := "Edited by the guest"
+	if err := commentService.UpdateComment(ctx, comment.ID, comment.UserID, &models.UpdateCommentRequest{Content: &newContent}); err != nil {
+		t.Fatalf("Expected the guest to edit their comment, got: %v", err)
+	}
+	stored, err := commentService.GetComment(ctx, comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	if !stored.IsAnonymous || stored.DisplayName == nil {
+		t.Fatalf("Expected stored comment to stay anonymous, got: %+v", stored)
+	}
+}
+
+func TestCreateComment_AnonymousDisabledByDefault(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	// Execute
+	_, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{
+		RootID:    "post-1",
+		Content:   "Just visiting",
+		Anonymous: true,
+	})
+
+	// Assert
+	if !errors.Is(err, service.ErrInvalidInput) {
+		t.Fatalf("Expected ErrInvalidInput, got: %v", err)
+	}
+}
+
+func TestCreateComment_AnonymousRejectsRegisteredUserID(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{AllowAnonymous: true})
+
+	// Execute
+	_, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{
+		RootID:    "post-1",
+		UserID:    "alice",
+		Content:   "Not really a guest",
+		Anonymous: true,
+	})
+
+	// Assert
+	if !errors.Is(err, service.ErrInvalidInput) {
+		t.Fatalf("Expected ErrInvalidInput, got: %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestBatchVoteComments_FlipsExistingVote(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	repo := memory.NewMemoryRepository()
+	commentService := service.NewCommentService(repo)
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Comment"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, comment.ID, "voter", models.VoteTypeUp); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	// Execute
+	err = commentService.BatchVoteComments(ctx, []models.VoteRequest{{CommentID: comment.ID, UserID: "voter", VoteType: models.VoteTypeDown}}, "voter")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	vote, err := repo.GetUserVote(ctx, comment.ID, "voter")
+	if err != nil || vote == nil || vote.VoteType != models.VoteTypeDown {
+		t.Fatalf("Expected the upvote flipped to a downvote, got %+v (err %v)", vote, err)
+	}
+	updated, err := commentService.GetComment(ctx, comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	if updated.Upvotes != 0 || updated.Downvotes != 1 || updated.Score != -1 {
+		t.Errorf("Expected 0 up, 1 down and a score of -1, got %d up, %d down and %d", updated.Upvotes, updated.Downvotes, updated.Score)
+	}
+}
+
+func TestBatchVoteComments_RequiresCommentID(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	// Execute
+	err := commentService.BatchVoteComments(context.Background(), []models.VoteRequest{{UserID: "voter", VoteType: models.VoteTypeUp}}, "voter")
+
+	// Assert
+	if !errors.Is(err, service.ErrInvalidInput) {
+		t.Fatalf("Expected ErrInvalidInput, got: %v", err)
+	}
+}
+
+func TestBatchVoteComments_AppliesVoteRules(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{PreModeration: true})
+	create := func(userID string) *models.Comment {
+		t.Helper()
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: userID, Content: "Comment"})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		return comment
+	}
+	approved := create("alice")
+	if _, err := commentService.ApproveComment(ctx, approved.ID, "moderator"); err != nil {
+		t.Fatalf("Failed to approve comment: %v", err)
+	}
+	own := create("voter")
+	if _, err := commentService.ApproveComment(ctx, own.ID, "moderator"); err != nil {
+		t.Fatalf("Failed to approve comment: %v", err)
+	}
+	pending := create("alice")
+
+	cases := map[string]struct {
+		commentID string
+		wantErr   error
+	}{
+		"self-vote":         {commentID: own.ID, wantErr: service.ErrSelfVote},
+		"pending comment":   {commentID: pending.ID, wantErr: service.ErrInvalidInput},
+		"unknown vote type": {commentID: approved.ID, wantErr: service.ErrInvalidInput},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			voteType := models.VoteTypeUp
+			if name == "unknown vote type" {
+				voteType = models.VoteTypeNone
+			}
+			batch := []models.VoteRequest{
+				{CommentID: approved.ID, UserID: "voter", VoteType: models.VoteTypeUp},
+				{CommentID: tc.commentID, UserID: "voter", VoteType: voteType},
+			}
+
+			// Execute
+			err := commentService.BatchVoteComments(ctx, batch, "voter")
+
+			// Assert
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Expected %v, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestBatchVoteComments_Weighted(t *testing.T) {
+	// Setup: the expert already holds a weight-3 upvote on one comment
+	ctx := context.Background()
+	repo := memory.NewMemoryRepository()
+	commentService := service.NewCommentService(repo)
+	commentService.SetVoteWeightResolver(roleWeights{experts: map[string]bool{"expert": true}})
+	var ids []string
+	for i := 0; i < 2; i++ {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Comment"})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		ids = append(ids, comment.ID)
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, ids[0], "expert", models.VoteTypeUp); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	// Execute
+	err := commentService.BatchVoteComments(ctx, []models.VoteRequest{
+		{CommentID: ids[0], UserID: "expert", VoteType: models.VoteTypeDown},
+		{CommentID: ids[1], UserID: "expert", VoteType: models.VoteTypeUp},
+	}, "expert")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for i, wantScore := range []int64{-3, 3} {
+		vote, err := repo.GetUserVote(ctx, ids[i], "expert")
+		if err != nil || vote == nil || vote.Weight != 3 {
+			t.Errorf("Expected a weight-3 vote on comment %d, got %+v (err %v)", i, vote, err)
+		}
+		comment, err := commentService.GetComment(ctx, ids[i])
+		if err != nil {
+			t.Fatalf("Failed to get comment: %v", err)
+		}
+		if comment.Score != wantScore {
+			t.Errorf("Expected comment %d to score %d, got %d", i, wantScore, comment.Score)
+		}
+	}
+}
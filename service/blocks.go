@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+// AddBlock stops blockerID seeing comments by blockedID in lists and trees
+// read with blockerID as the viewer (see WithViewer). The block only goes one
+// way and doesn't change what anyone else sees. Blocking someone twice is
+// not an error.
+func (s *CommentService) AddBlock(ctx context.Context, blockerID, blockedID string) (err error) {
+	ctx, span := s.startSpan(ctx, "AddBlock")
+	defer func() { endSpan(span, err) }()
+
+	if err := checkBlock(blockerID, blockedID); err != nil {
+		return err
+	}
+	return s.repo.AddBlock(ctx, blockerID, blockedID)
+}
+
+// RemoveBlock lifts a block added with AddBlock. Removing a block that
+// doesn't exist is not an error.
+func (s *CommentService) RemoveBlock(ctx context.Context, blockerID, blockedID string) (err error) {
+	ctx, span := s.startSpan(ctx, "RemoveBlock")
+	defer func() { endSpan(span, err) }()
+
+	if err := checkBlock(blockerID, blockedID); err != nil {
+		return err
+	}
+	return s.repo.RemoveBlock(ctx, blockerID, blockedID)
+}
+
+// GetBlockedUsers lists the users blockerID has blocked, sorted
+func (s *CommentService) GetBlockedUsers(ctx context.Context, blockerID string) (_ []string, err error) {
+	ctx, span := s.startSpan(ctx, "GetBlockedUsers")
+	defer func() { endSpan(span, err) }()
+
+	if blockerID == "" {
+		return nil, invalidInput("user ID is required")
+	}
+	return s.repo.GetBlockedUsers(ctx, blockerID)
+}
+
+// checkBlock validates the users named in a block
+func checkBlock(blockerID, blockedID string) error {
+	if blockerID == "" {
+		return invalidField("user_id", "required", "user ID is required")
+	}
+	if blockedID == "" {
+		return invalidField("blocked_user_id", "required", "blocked user ID is required")
+	}
+	if blockerID == blockedID {
+		return invalidField("blocked_user_id", "not_self", "users cannot block themselves")
+	}
+	return nil
+}
+
+// hideBlocked drops the comments by users the viewer in ctx has blocked from
+// a tree, along with the replies under them
+func (s *CommentService) hideBlocked(ctx context.Context, nodes []*models.CommentTree) ([]*models.CommentTree, error) {
+	viewer := ViewerFromContext(ctx)
+	if viewer == "" {
+		return nodes, nil
+	}
+	blockedIDs, err := s.repo.GetBlockedUsers(ctx, viewer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked users: %w", err)
+	}
+	if len(blockedIDs) == 0 {
+		return nodes, nil
+	}
+
+	blocked := make(map[string]bool, len(blockedIDs))
+	for _, userID := range blockedIDs {
+		blocked[userID] = true
+	}
+	return dropBlocked(nodes, blocked), nil
+}
+
+// dropBlocked filters nodes in place, recursing into the ones it keeps
+func dropBlocked(nodes []*models.CommentTree, blocked map[string]bool) []*models.CommentTree {
+	kept := nodes[:0]
+	for _, node := range nodes {
+		if blocked[node.Comment.UserID] {
+			continue
+		}
+		node.Children = dropBlocked(node.Children, blocked)
+		kept = append(kept, node)
+	}
+	return kept
+}
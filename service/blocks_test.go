@@ -0,0 +1,102 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestAddBlock_HidesCommentsFromBlockerOnly(t *testing.T) {
+	// Setup: bob replies to alice, carol replies to bob, and alice blocks bob
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	create := func(userID string, parentID *string) *models.Comment {
+		t.Helper()
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: parentID, UserID: userID, Content: "Comment"})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		return comment
+	}
+	top := create("alice", nil)
+	reply := create("bob", &top.ID)
+	create("carol", &reply.ID)
+	create("bob", nil)
+	if err := commentService.AddBlock(ctx, "alice", "bob"); err != nil {
+		t.Fatalf("Failed to block: %v", err)
+	}
+	asAlice := service.WithViewer(ctx, "alice")
+	asCarol := service.WithViewer(ctx, "carol")
+
+	// Execute
+	aliceList, err := commentService.GetCommentsByRoot(asAlice, "post-1", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	carolList, err := commentService.GetCommentsByRoot(asCarol, "post-1", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	aliceTree, err := commentService.GetCommentTree(asAlice, "post-1", 10, "created_at")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	carolTree, err := commentService.GetCommentTree(asCarol, "post-1", 10, "created_at")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	for _, comment := range aliceList {
+		if comment.UserID == "bob" {
+			t.Errorf("Expected alice not to see bob's comments, got: %+v", comment)
+		}
+	}
+	if len(aliceList) != 2 {
+		t.Errorf("Expected alice to see 2 comments, got: %d", len(aliceList))
+	}
+	if len(carolList) != 4 {
+		t.Errorf("Expected carol to see all 4 comments, got: %d", len(carolList))
+	}
+	// The reply under bob's comment goes with it in the tree
+	if len(aliceTree) != 1 || aliceTree[0].Comment.ID != top.ID || len(aliceTree[0].Children) != 0 {
+		t.Errorf("Expected alice's tree to hold only her comment, got: %+v", aliceTree)
+	}
+	if len(carolTree) != 2 {
+		t.Errorf("Expected carol to see both threads, got: %d", len(carolTree))
+	}
+	for _, node := range carolTree {
+		if node.Comment.ID == top.ID && (len(node.Children) != 1 || len(node.Children[0].Children) != 1) {
+			t.Errorf("Expected carol to see the whole of alice's thread, got: %+v", node)
+		}
+	}
+
+	// Lifting the block shows bob again
+	if err := commentService.RemoveBlock(ctx, "alice", "bob"); err != nil {
+		t.Fatalf("Failed to unblock: %v", err)
+	}
+	aliceList, err = commentService.GetCommentsByRoot(asAlice, "post-1", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(aliceList) != 4 {
+		t.Errorf("Expected alice to see all 4 comments after unblocking, got: %d", len(aliceList))
+	}
+}
+
+func TestAddBlock_RejectsSelf(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	// Execute
+	err := commentService.AddBlock(context.Background(), "alice", "alice")
+
+	// Assert
+	if !errors.Is(err, service.ErrInvalidInput) {
+		t.Fatalf("Expected ErrInvalidInput, got: %v", err)
+	}
+}
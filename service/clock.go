@@ -0,0 +1,23 @@
+package service
+
+import (
+	"time"
+)
+
+// Clock supplies the current time. Replace it with SetClock to control
+// time-dependent behavior such as score decay in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock reads the wall clock
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SetClock replaces the clock used by the service
+func (s *CommentService) SetClock(clock Clock) {
+	s.clock = clock
+}
@@ -2,110 +2,249 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/christopher18/commentific/v2/models"
 	"github.com/christopher18/commentific/v2/repository"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // CommentService handles business logic for comments
 type CommentService struct {
 	repo      repository.CommentRepository
 	validator *validator.Validate
+	clock     Clock
+	config    CommentServiceConfig
+	tracer    trace.Tracer
+	logger    *slog.Logger
+
+	spamScorer  SpamScorer
+	voteWeights VoteWeightResolver
+	markdown    *markdownRenderer // nil unless RenderMarkdown is set
+
+	linkPreviewer LinkPreviewer  // nil turns link previews off
+	background    sync.WaitGroup // Link preview fetches that outlive their request
+
+	idempotency      IdempotencyStore
+	idempotencyLocks keyLocks
+
+	emittersMu sync.RWMutex
+	emitters   []EventEmitter
 }
 
 // NewCommentService creates a new comment service
 func NewCommentService(repo repository.CommentRepository) *CommentService {
-	return &CommentService{
-		repo:      repo,
-		validator: validator.New(),
-	}
+	return NewCommentServiceWithConfig(repo, nil)
 }
 
 // CreateComment creates a new comment with validation and business logic
-func (s *CommentService) CreateComment(ctx context.Context, req *models.CreateCommentRequest) (*models.Comment, error) {
-	// Validate the request
-	if err := s.validator.Struct(req); err != nil {
-		return nil, fmt.Errorf("validation failed: %w", err)
+func (s *CommentService) CreateComment(ctx context.Context, req *models.CreateCommentRequest) (_ *models.Comment, err error) {
+	ctx, span := s.startSpan(ctx, "CreateComment")
+	defer func() {
+		s.warnInvalidInput(ctx, "CreateComment", err)
+		endSpan(span, err)
+	}()
+
+	comment, rules, problems, err := s.draftComment(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, problems[0]
 	}
+	span.SetAttributes(attrRootID.String(req.RootID))
 
-	// Sanitize content
-	req.Content = strings.TrimSpace(req.Content)
-	if req.Content == "" {
-		return nil, fmt.Errorf("comment content cannot be empty")
+	s.quarantineIfSpam(ctx, comment)
+
+	if err := s.insertComment(ctx, comment, rules.maxDepth); err != nil {
+		return nil, err
 	}
+	s.autoSubscribe(ctx, comment)
 
-	// Validate URLs if provided
-	if req.MediaURL != nil && *req.MediaURL != "" {
-		if !s.isValidURL(*req.MediaURL) {
-			return nil, fmt.Errorf("invalid media URL")
-		}
+	// Held comments are announced when a moderator approves them
+	if s.hasEmitters() && comment.Status == models.CommentStatusApproved {
+		s.emit(ctx, &models.CommentEvent{
+			Type:      models.EventCommentCreated,
+			RootID:    comment.RootID,
+			CommentID: comment.ID,
+			UserID:    comment.UserID,
+			Comment:   comment,
+		})
 	}
 
-	if req.LinkURL != nil && *req.LinkURL != "" {
-		if !s.isValidURL(*req.LinkURL) {
-			return nil, fmt.Errorf("invalid link URL")
+	s.fetchLinkPreviewAsync(ctx, comment)
+	s.renderContent(comment)
+	return comment, nil
+}
+
+// insertComment stores comment. A reply is stored in a transaction that
+// first locks its parent and checks it again, so a parent deleted, rejected
+// or moved since the request was validated can't end up with an orphaned
+// reply. Top-level comments have nothing to race with and skip it.
+func (s *CommentService) insertComment(ctx context.Context, comment *models.Comment, maxDepth int) (err error) {
+	if comment.ParentID == nil {
+		if err := s.repo.CreateComment(ctx, comment); err != nil {
+			return fmt.Errorf("failed to create comment: %w", err)
 		}
+		return nil
 	}
 
-	// Create the comment model
-	comment := &models.Comment{
-		ID:       uuid.New().String(),
-		RootID:   req.RootID,
-		ParentID: req.ParentID,
-		UserID:   req.UserID,
-		Content:  req.Content,
-		MediaURL: req.MediaURL,
-		LinkURL:  req.LinkURL,
+	repo, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-
-	// Validate parent comment exists and belongs to same root if parentID is provided
-	if req.ParentID != nil {
-		parent, err := s.repo.GetCommentByID(ctx, *req.ParentID)
+	defer func() {
 		if err != nil {
-			return nil, fmt.Errorf("parent comment not found: %w", err)
-		}
-		if parent.RootID != req.RootID {
-			return nil, fmt.Errorf("parent comment belongs to different root")
+			repo.RollbackTx(ctx)
 		}
-		if parent.Depth >= 100 { // Prevent extremely deep nesting
-			return nil, fmt.Errorf("maximum comment depth exceeded")
+	}()
+
+	parent, err := repo.GetCommentForUpdate(ctx, *comment.ParentID)
+	if errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("parent comment not found: %w", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get parent comment: %w", err)
+	}
+	if problem := parentProblem(parent, comment.RootID, maxDepth); problem != nil {
+		return problem
+	}
+
+	if err := repo.CreateComment(ctx, comment); err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+	return repo.CommitTx(ctx)
+}
+
+// prepareAnonymous marks comment as a guest comment, issuing a guest token
+// when the request has none so the guest can edit or delete it later
+func (s *CommentService) prepareAnonymous(comment *models.Comment, req *models.CreateCommentRequest, allowed bool) error {
+	if !allowed {
+		return invalidField("anonymous", "allowed", "anonymous comments are not enabled")
+	}
+
+	if comment.UserID == "" {
+		comment.UserID = models.GuestUserIDPrefix + uuid.New().String()
+	} else if !strings.HasPrefix(comment.UserID, models.GuestUserIDPrefix) {
+		return invalidField("user_id", "guest_token", "anonymous comments must use a guest token as user ID")
+	}
+	comment.IsAnonymous = true
+
+	if req.DisplayName != nil {
+		if name := strings.TrimSpace(*req.DisplayName); name != "" {
+			comment.DisplayName = &name
 		}
 	}
+	return nil
+}
 
-	// Create the comment
-	err := s.repo.CreateComment(ctx, comment)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create comment: %w", err)
+// checkContentLength enforces MinCommentLength and MaxCommentLength on trimmed
+// content, counting characters rather than bytes so multibyte text isn't
+// penalized
+func (s *CommentService) checkContentLength(content string) error {
+	length := utf8.RuneCountInString(content)
+	if s.config.MinCommentLength > 0 && length < s.config.MinCommentLength {
+		return &ContentTooShortError{Limit: s.config.MinCommentLength}
+	}
+	if length > s.config.MaxCommentLength {
+		return invalidField("content", "max", "comment content too long (maximum %d characters)", s.config.MaxCommentLength)
 	}
+	return nil
+}
 
-	return comment, nil
+// allowsEmptyContent reports whether a comment with mediaURL may have no text
+// under AllowMediaOnlyComments
+func (s *CommentService) allowsEmptyContent(mediaURL *string) bool {
+	return s.config.AllowMediaOnlyComments && mediaURL != nil && strings.TrimSpace(*mediaURL) != ""
 }
 
 // GetComment retrieves a comment by ID
-func (s *CommentService) GetComment(ctx context.Context, id string) (*models.Comment, error) {
+func (s *CommentService) GetComment(ctx context.Context, id string) (_ *models.Comment, err error) {
+	ctx, span := s.startSpan(ctx, "GetComment", attrCommentID.String(id))
+	defer func() { endSpan(span, err) }()
+
 	if id == "" {
-		return nil, fmt.Errorf("comment ID is required")
+		return nil, invalidInput("comment ID is required")
 	}
 
 	comment, err := s.repo.GetCommentByID(ctx, id)
+	if errors.Is(err, ErrNotFound) && s.config.AuthorsSeeDeleted {
+		comment, err = s.deletedCommentFor(ctx, id)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get comment: %w", err)
 	}
+	if !visibleToViewer(ctx, comment) {
+		return nil, fmt.Errorf("failed to get comment: %w", ErrNotFound)
+	}
 
+	hideDeletionAudit(ctx, comment)
+	s.renderContent(comment)
 	return comment, nil
 }
 
+// deletedCommentFor returns the soft-deleted comment id when the reader in
+// ctx is its author or a moderator, and ErrNotFound for anyone else
+func (s *CommentService) deletedCommentFor(ctx context.Context, id string) (*models.Comment, error) {
+	viewer := ViewerFromContext(ctx)
+	if viewer == "" && !IsModerator(ctx) {
+		return nil, ErrNotFound
+	}
+	comments, err := s.repo.GetCommentsByIDs(ctx, []string{id}, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(comments) == 0 || !comments[0].IsDeleted {
+		return nil, ErrNotFound
+	}
+	if comment := comments[0]; IsModerator(ctx) || comment.UserID == viewer {
+		return comment, nil
+	}
+	return nil, ErrNotFound
+}
+
+// GetCommentsByIDs retrieves many comments at once, in the order requested.
+// Missing IDs are skipped, as are deleted comments unless includeDeleted is set
+// (check IsDeleted on the results to tell them apart). More than MaxBatchSize
+// IDs fail with a *BatchTooLargeError.
+func (s *CommentService) GetCommentsByIDs(ctx context.Context, ids []string, includeDeleted bool) ([]*models.Comment, error) {
+	if len(ids) == 0 {
+		return []*models.Comment{}, nil
+	}
+	if len(ids) > s.config.MaxBatchSize {
+		return nil, &BatchTooLargeError{Size: len(ids), Limit: s.config.MaxBatchSize}
+	}
+
+	comments, err := s.repo.GetCommentsByIDs(ctx, ids, includeDeleted)
+	if err != nil {
+		return nil, err
+	}
+	hideDeletionAudit(ctx, comments...)
+	s.renderContent(comments...)
+	return comments, nil
+}
+
 // UpdateComment updates a comment's content
-func (s *CommentService) UpdateComment(ctx context.Context, id, userID string, req *models.UpdateCommentRequest) error {
+func (s *CommentService) UpdateComment(ctx context.Context, id, userID string, req *models.UpdateCommentRequest) (err error) {
+	ctx, span := s.startSpan(ctx, "UpdateComment", attrCommentID.String(id))
+	defer func() {
+		s.warnInvalidInput(ctx, "UpdateComment", err)
+		endSpan(span, err)
+	}()
+
 	if id == "" {
-		return fmt.Errorf("comment ID is required")
+		return invalidInput("comment ID is required")
 	}
 	if userID == "" {
-		return fmt.Errorf("user ID is required")
+		return invalidInput("user ID is required")
 	}
 
 	// Get the existing comment to verify ownership
@@ -115,68 +254,225 @@ func (s *CommentService) UpdateComment(ctx context.Context, id, userID string, r
 	}
 
 	if comment.UserID != userID {
-		return fmt.Errorf("user not authorized to update this comment")
+		return fmt.Errorf("%w to update this comment", ErrNotAuthorized)
+	}
+	// Fail fast on a stale version; the repository checks again as it writes
+	if req.Version != nil && *req.Version != comment.Version {
+		return ErrVersionConflict
 	}
 
 	// Validate and sanitize content if provided
 	if req.Content != nil {
-		*req.Content = strings.TrimSpace(*req.Content)
-		if *req.Content == "" {
-			return fmt.Errorf("comment content cannot be empty")
-		}
-		if len(*req.Content) > 10000 {
-			return fmt.Errorf("comment content too long")
+		*req.Content = s.normalizeContent(*req.Content)
+		if *req.Content != "" {
+			if *req.Content, err = s.limitLines(*req.Content); err != nil {
+				return err
+			}
+			if err := s.checkContentLength(*req.Content); err != nil {
+				return err
+			}
 		}
 	}
+	// Text may only be left empty, or the media removed from a comment
+	// without text, while the media carries the comment
+	content, mediaURL := comment.Content, comment.MediaURL
+	if req.Content != nil {
+		content = *req.Content
+	}
+	if req.MediaURL != nil {
+		mediaURL = req.MediaURL
+	}
+	if content == "" && (req.Content != nil || req.MediaURL != nil) && !s.allowsEmptyContent(mediaURL) {
+		return invalidInput("comment content cannot be empty")
+	}
 
 	// Validate URLs if provided
 	if req.MediaURL != nil && *req.MediaURL != "" {
 		if !s.isValidURL(*req.MediaURL) {
-			return fmt.Errorf("invalid media URL")
+			return invalidInput("invalid media URL")
 		}
 	}
 
 	if req.LinkURL != nil && *req.LinkURL != "" {
 		if !s.isValidURL(*req.LinkURL) {
-			return fmt.Errorf("invalid link URL")
+			return invalidInput("invalid link URL")
 		}
 	}
 
-	return s.repo.UpdateComment(ctx, id, req)
+	if err := s.repo.UpdateComment(ctx, id, req); err != nil {
+		return err
+	}
+
+	// A new link needs a new preview
+	if req.LinkURL != nil && (comment.LinkURL == nil || *comment.LinkURL != *req.LinkURL) {
+		comment.LinkURL = req.LinkURL
+		s.fetchLinkPreviewAsync(ctx, comment)
+	}
+
+	s.emitCommentEvent(ctx, models.EventCommentUpdated, id, userID, nil)
+	return nil
 }
 
 // DeleteComment soft deletes a comment
 func (s *CommentService) DeleteComment(ctx context.Context, id, userID string) error {
+	return s.DeleteCommentWithReason(ctx, id, userID, "")
+}
+
+// maxDeleteReasonLength caps the reason recorded with a deletion, in characters
+const maxDeleteReasonLength = 500
+
+// deleteReason validates an optional deletion reason, returning nil for none
+func deleteReason(reason string) (*string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, nil
+	}
+	if utf8.RuneCountInString(reason) > maxDeleteReasonLength {
+		return nil, invalidInput("delete reason too long (maximum %d characters)", maxDeleteReasonLength)
+	}
+	return &reason, nil
+}
+
+// DeleteCommentWithReason soft deletes the author's own comment, recording
+// the author as DeletedBy along with reason, which may be empty. Only
+// moderators see the two fields on reads; see WithModerator.
+func (s *CommentService) DeleteCommentWithReason(ctx context.Context, id, userID, reason string) (err error) {
+	ctx, span := s.startSpan(ctx, "DeleteComment", attrCommentID.String(id))
+	defer func() { endSpan(span, err) }()
+
+	if id == "" {
+		return invalidInput("comment ID is required")
+	}
+	if userID == "" {
+		return invalidInput("user ID is required")
+	}
+	why, err := deleteReason(reason)
+	if err != nil {
+		return err
+	}
+
+	if !s.hasEmitters() {
+		return s.repo.DeleteComment(ctx, id, userID, why)
+	}
+
+	// Look up the root before the comment disappears from reads
+	comment, err := s.repo.GetCommentByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("comment not found: %w", err)
+	}
+
+	if err := s.repo.DeleteComment(ctx, id, userID, why); err != nil {
+		return err
+	}
+
+	s.emit(ctx, &models.CommentEvent{
+		Type:      models.EventCommentDeleted,
+		RootID:    comment.RootID,
+		CommentID: id,
+		UserID:    userID,
+	})
+	return nil
+}
+
+// HardDeleteComment permanently erases a comment and its votes, for erasure
+// requests where a soft delete isn't enough. The author may hard delete, as
+// may a moderator, marked on ctx with WithModerator. A comment that has
+// replies, soft deleted or not, is refused with ErrHasReplies rather than
+// cascading: erase the replies first or soft delete the parent instead.
+func (s *CommentService) HardDeleteComment(ctx context.Context, id, userID string) (err error) {
+	ctx, span := s.startSpan(ctx, "HardDeleteComment", attrCommentID.String(id))
+	defer func() { endSpan(span, err) }()
+
 	if id == "" {
-		return fmt.Errorf("comment ID is required")
+		return invalidInput("comment ID is required")
 	}
 	if userID == "" {
-		return fmt.Errorf("user ID is required")
+		return invalidInput("user ID is required")
+	}
+
+	// Include soft-deleted comments so already-deleted content can still be erased
+	comments, err := s.repo.GetCommentsByIDs(ctx, []string{id}, true)
+	if err != nil {
+		return fmt.Errorf("failed to get comment: %w", err)
+	}
+	if len(comments) == 0 {
+		return fmt.Errorf("comment not found: %w", ErrNotFound)
+	}
+	comment := comments[0]
+	if comment.UserID != userID && !IsModerator(ctx) {
+		return ErrNotAuthorized
+	}
+
+	repo, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	if err := repo.HardDeleteComment(ctx, id); err != nil {
+		repo.RollbackTx(ctx)
+		return err
+	}
+	if err := repo.CommitTx(ctx); err != nil {
+		return err
+	}
+
+	if !comment.IsDeleted {
+		s.emit(ctx, &models.CommentEvent{
+			Type:      models.EventCommentDeleted,
+			RootID:    comment.RootID,
+			CommentID: id,
+			UserID:    userID,
+		})
+	}
+	return nil
+}
+
+// validateFilter rejects filters that contradict themselves
+func validateFilter(filter *models.CommentFilter) error {
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && filter.CreatedAfter.After(*filter.CreatedBefore) {
+		return invalidInput("created_after must not be later than created_before")
+	}
+	return nil
+}
 
-	return s.repo.DeleteComment(ctx, id, userID)
+// clampPage fills in a missing limit with the default page size and keeps
+// the limit between 1 and MaxPageSize and the offset at 0 or more
+func (s *CommentService) clampPage(limit, offset **int) {
+	switch {
+	case *limit == nil:
+		size := s.config.DefaultPageSize
+		*limit = &size
+	case **limit < 1:
+		size := 1
+		*limit = &size
+	case **limit > s.config.MaxPageSize:
+		size := s.config.MaxPageSize
+		*limit = &size
+	}
+	if *offset == nil || **offset < 0 {
+		start := 0
+		*offset = &start
+	}
 }
 
 // GetCommentsByRoot retrieves comments for a specific root with enhanced filtering
-func (s *CommentService) GetCommentsByRoot(ctx context.Context, rootID string, filter *models.CommentFilter) ([]*models.Comment, error) {
+func (s *CommentService) GetCommentsByRoot(ctx context.Context, rootID string, filter *models.CommentFilter) (_ []*models.Comment, err error) {
+	ctx, span := s.startSpan(ctx, "GetCommentsByRoot", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
 	if rootID == "" {
-		return nil, fmt.Errorf("root ID is required")
+		return nil, invalidInput("root ID is required")
 	}
 
 	// Set default values for pagination
 	if filter == nil {
 		filter = &models.CommentFilter{}
 	}
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
 
 	// Set reasonable defaults
-	if filter.Limit == nil {
-		defaultLimit := 50
-		filter.Limit = &defaultLimit
-	}
-	if filter.Offset == nil {
-		defaultOffset := 0
-		filter.Offset = &defaultOffset
-	}
+	s.clampPage(&filter.Limit, &filter.Offset)
 	if filter.SortBy == "" {
 		filter.SortBy = "created_at"
 	}
@@ -184,181 +480,680 @@ func (s *CommentService) GetCommentsByRoot(ctx context.Context, rootID string, f
 		filter.SortOrder = "desc"
 	}
 
-	// Enforce maximum limits to prevent abuse
-	if *filter.Limit > 1000 {
-		maxLimit := 1000
-		filter.Limit = &maxLimit
+	applyViewer(ctx, filter)
+
+	comments, err := s.repo.GetCommentsByRootID(ctx, rootID, filter)
+	if err != nil {
+		return nil, err
+	}
+	s.renderContent(comments...)
+	return comments, nil
+}
+
+// GetCommentsSince retrieves a page of the root's comments created or updated
+// after since, oldest update first, so a polling client can merge them into
+// what it already has. Deleted comments that were once shown come back as
+// tombstones: IsDeleted set and their content cleared. Pass the UpdatedAt and
+// ID of the last comment returned as the next since and afterID; the
+// server's clock, not the client's, decides what is new. A full page of limit
+// comments (DefaultPageSize when 0, at most MaxPageSize) means more may be
+// waiting.
+func (s *CommentService) GetCommentsSince(ctx context.Context, rootID string, since time.Time, afterID string, limit int) (_ []*models.Comment, err error) {
+	ctx, span := s.startSpan(ctx, "GetCommentsSince", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	if rootID == "" {
+		return nil, invalidInput("root ID is required")
+	}
+	if limit <= 0 {
+		limit = s.config.DefaultPageSize
+	}
+	limit = min(limit, s.config.MaxPageSize)
+
+	ctx, cancel := s.withQueryTimeout(ctx)
+	defer cancel()
+
+	comments, err := s.repo.GetCommentsSince(ctx, rootID, since, afterID, limit)
+	if err != nil {
+		return nil, timeoutError(ctx, err)
+	}
+	for _, comment := range comments {
+		if comment.IsDeleted {
+			tombstone(comment)
+		}
 	}
+	hideDeletionAudit(ctx, comments...)
+	s.renderContent(comments...)
+	return comments, nil
+}
 
-	return s.repo.GetCommentsByRootID(ctx, rootID, filter)
+// tombstone clears what a deleted comment said, keeping where it was
+func tombstone(comment *models.Comment) {
+	comment.Content = ""
+	comment.OriginalContent = nil
+	comment.MediaURL = nil
+	comment.LinkURL = nil
+	comment.LinkPreview = nil
 }
 
 // GetCommentTree retrieves a hierarchical comment tree
 func (s *CommentService) GetCommentTree(ctx context.Context, rootID string, maxDepth int, sortBy string) ([]*models.CommentTree, error) {
+	return s.GetCommentTreeWithOptions(ctx, rootID, maxDepth, sortBy, TreeOptions{})
+}
+
+// GetCommentTreeWithOptions is GetCommentTree with the tree shaped by opts
+func (s *CommentService) GetCommentTreeWithOptions(ctx context.Context, rootID string, maxDepth int, sortBy string, opts TreeOptions) (_ []*models.CommentTree, err error) {
+	ctx, span := s.startSpan(ctx, "GetCommentTree", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	if rootID == "" {
+		return nil, invalidInput("root ID is required")
+	}
+
+	ctx, cancel := s.withQueryTimeout(ctx)
+	defer cancel()
+
+	maxDepth, sortBy, err = s.treeBounds(ctx, rootID, maxDepth, sortBy)
+	if err != nil {
+		return nil, timeoutError(ctx, err)
+	}
+
+	tree, err := s.repo.GetCommentTree(ctx, rootID, maxDepth, sortBy)
+	if err != nil {
+		return nil, timeoutError(ctx, err)
+	}
+	tree, err = s.hideBlocked(ctx, tree)
+	if err != nil {
+		return nil, timeoutError(ctx, err)
+	}
+	s.renderTree(tree)
+	opts.apply(tree)
+	return tree, nil
+}
+
+// GetCommentTreeWithUserVotes is GetCommentTree as userID sees it, with their
+// vote set as CurrentUserVote on each comment they voted on, so a threaded
+// view needs no separate vote lookup
+func (s *CommentService) GetCommentTreeWithUserVotes(ctx context.Context, rootID, userID string, maxDepth int, sortBy string) (_ []*models.CommentTree, err error) {
+	ctx, span := s.startSpan(ctx, "GetCommentTreeWithUserVotes", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
 	if rootID == "" {
-		return nil, fmt.Errorf("root ID is required")
+		return nil, invalidInput("root ID is required")
+	}
+	if userID == "" {
+		return nil, invalidInput("user ID is required")
+	}
+
+	ctx, cancel := s.withQueryTimeout(ctx)
+	defer cancel()
+
+	maxDepth, sortBy, err = s.treeBounds(ctx, rootID, maxDepth, sortBy)
+	if err != nil {
+		return nil, timeoutError(ctx, err)
 	}
 
+	// The repository leaves out the comments of users userID blocked
+	tree, err := s.repo.GetCommentTreeWithUserVotes(ctx, rootID, userID, maxDepth, sortBy)
+	if err != nil {
+		return nil, timeoutError(ctx, err)
+	}
+	s.renderTree(tree)
+	return tree, nil
+}
+
+// treeBounds fills in the default depth and sort of a root's tree, keeping
+// the depth within the configured and per-root limits
+func (s *CommentService) treeBounds(ctx context.Context, rootID string, maxDepth int, sortBy string) (int, string, error) {
 	// Set reasonable defaults
 	if maxDepth <= 0 {
 		maxDepth = 10 // Default max depth
 	}
-	if maxDepth > 50 {
-		maxDepth = 50 // Prevent extremely deep trees
+	if maxDepth > s.config.MaxTreeDepth {
+		maxDepth = s.config.MaxTreeDepth // Prevent extremely deep trees
 	}
 
 	if sortBy == "" {
 		sortBy = "score" // Default to sorting by score for tree view
 	}
 
-	return s.repo.GetCommentTree(ctx, rootID, maxDepth, sortBy)
+	// Replies left deeper than a root's lowered max depth stay hidden
+	rules, err := s.rulesFor(ctx, rootID)
+	if err != nil {
+		return 0, "", err
+	}
+	if maxDepth > rules.maxDepth {
+		maxDepth = rules.maxDepth
+	}
+	return maxDepth, sortBy, nil
+}
+
+// GetCommentSubtree retrieves one comment with its replies nested up to
+// maxDepth levels below it. Prefer it to GetCommentTree when a client expands
+// a single thread of a busy root: only that thread is read, not the root.
+func (s *CommentService) GetCommentSubtree(ctx context.Context, commentID string, maxDepth int, sortBy string) (_ *models.CommentTree, err error) {
+	ctx, span := s.startSpan(ctx, "GetCommentSubtree", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
+
+	if commentID == "" {
+		return nil, invalidInput("comment ID is required")
+	}
+
+	if maxDepth <= 0 {
+		maxDepth = 10
+	}
+	if maxDepth > s.config.MaxTreeDepth {
+		maxDepth = s.config.MaxTreeDepth
+	}
+
+	if sortBy == "" {
+		sortBy = "score"
+	}
+
+	ctx, cancel := s.withQueryTimeout(ctx)
+	defer cancel()
+
+	tree, err := s.repo.GetCommentSubtree(ctx, commentID, maxDepth, sortBy)
+	if err != nil {
+		return nil, timeoutError(ctx, fmt.Errorf("failed to get comment subtree: %w", err))
+	}
+	// The requested comment itself stays; only the replies are filtered
+	tree.Children, err = s.hideBlocked(ctx, tree.Children)
+	if err != nil {
+		return nil, timeoutError(ctx, err)
+	}
+	s.renderTree([]*models.CommentTree{tree})
+	return tree, nil
 }
 
-// GetCommentsByUser retrieves comments by a specific user
-func (s *CommentService) GetCommentsByUser(ctx context.Context, userID string, filter *models.CommentFilter) ([]*models.Comment, error) {
+// GetCommentsByUser retrieves comments by a specific user. Other filter fields
+// still apply, so setting filter.RootID returns the user's comments on one root.
+func (s *CommentService) GetCommentsByUser(ctx context.Context, userID string, filter *models.CommentFilter) (_ []*models.Comment, err error) {
+	ctx, span := s.startSpan(ctx, "GetCommentsByUser")
+	defer func() { endSpan(span, err) }()
+
 	if userID == "" {
-		return nil, fmt.Errorf("user ID is required")
+		return nil, invalidInput("user ID is required")
 	}
 
 	// Set default pagination
 	if filter == nil {
 		filter = &models.CommentFilter{}
 	}
-	if filter.Limit == nil {
-		defaultLimit := 50
-		filter.Limit = &defaultLimit
-	}
-	if filter.Offset == nil {
-		defaultOffset := 0
-		filter.Offset = &defaultOffset
+	if err := validateFilter(filter); err != nil {
+		return nil, err
 	}
+	s.clampPage(&filter.Limit, &filter.Offset)
+	applyViewer(ctx, filter)
 
-	return s.repo.GetCommentsByUserID(ctx, userID, filter)
+	comments, err := s.repo.GetCommentsByUserID(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	s.renderContent(comments...)
+	return comments, nil
 }
 
-// VoteComment handles voting on a comment
-func (s *CommentService) VoteComment(ctx context.Context, commentID, userID string, voteType models.VoteType) error {
+// VoteComment handles voting on a comment and returns the comment with its
+// updated counts along with the stored vote and how much this vote moved the
+// comment's score: +1 for a first upvote, -2 for switching an upvote to a
+// downvote, 0 for repeating a vote, scaled by vote weights. With ToggleVotes
+// set, repeating a vote removes it instead: the vote comes back nil and the
+// delta undoes the removed vote.
+func (s *CommentService) VoteComment(ctx context.Context, commentID, userID string, voteType models.VoteType) (_ *models.Comment, _ *models.Vote, scoreDelta int64, err error) {
+	ctx, span := s.startSpan(ctx, "VoteComment", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
+
 	if commentID == "" {
-		return fmt.Errorf("comment ID is required")
+		return nil, nil, 0, invalidInput("comment ID is required")
 	}
 	if userID == "" {
-		return fmt.Errorf("user ID is required")
+		return nil, nil, 0, invalidInput("user ID is required")
 	}
 	if voteType != models.VoteTypeUp && voteType != models.VoteTypeDown {
-		return fmt.Errorf("invalid vote type")
+		return nil, nil, 0, invalidInput("invalid vote type")
 	}
 
-	// Verify comment exists
-	_, err := s.repo.GetCommentByID(ctx, commentID)
+	cast, scoreDelta, err := s.applyVote(ctx, commentID, userID, voteType)
 	if err != nil {
-		return fmt.Errorf("comment not found: %w", err)
+		return nil, nil, 0, err
 	}
 
-	// Prevent users from voting on their own comments
-	comment, err := s.repo.GetCommentByID(ctx, commentID)
+	s.emitCommentEvent(ctx, models.EventCommentVoted, commentID, userID, &cast)
+
+	// Re-read so the caller sees the counts the vote triggers produced
+	updated, err := s.repo.GetCommentByID(ctx, commentID)
 	if err != nil {
-		return fmt.Errorf("failed to get comment: %w", err)
+		return nil, nil, 0, fmt.Errorf("failed to get updated comment: %w", err)
 	}
-	if comment.UserID == userID {
-		return fmt.Errorf("users cannot vote on their own comments")
+	vote, err := s.repo.GetUserVote(ctx, commentID, userID)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to get vote: %w", err)
 	}
 
-	return s.repo.UpdateVote(ctx, commentID, userID, voteType)
+	s.renderContent(updated)
+	return updated, vote, scoreDelta, nil
 }
 
-// RemoveVote removes a user's vote from a comment
-func (s *CommentService) RemoveVote(ctx context.Context, commentID, userID string) error {
-	if commentID == "" {
-		return fmt.Errorf("comment ID is required")
-	}
-	if userID == "" {
-		return fmt.Errorf("user ID is required")
+// applyVote records userID's vote, weighted by the VoteWeightResolver, in a
+// transaction that locks the comment first. It returns the vote that now
+// stands, VoteTypeNone when ToggleVotes removed it, and how much the score
+// moved. The vote triggers recount the comment's votes, and without the lock
+// two concurrent votes can each miss the other's and leave the counts short;
+// it also keeps the vote being replaced from changing under us.
+func (s *CommentService) applyVote(ctx context.Context, commentID, userID string, voteType models.VoteType) (_ models.VoteType, scoreDelta int64, err error) {
+	weight := s.voteWeight(ctx, userID)
+
+	repo, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer func() {
+		if err != nil {
+			repo.RollbackTx(ctx)
+		}
+	}()
 
-	return s.repo.DeleteVote(ctx, commentID, userID)
+	cast, scoreDelta, err := s.castVote(ctx, repo, commentID, userID, voteType, weight)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err = repo.CommitTx(ctx); err != nil {
+		return 0, 0, err
+	}
+	return cast, scoreDelta, nil
 }
 
-// GetCommentsWithUserVotes retrieves comments with user's voting status for efficient frontend rendering
-func (s *CommentService) GetCommentsWithUserVotes(ctx context.Context, rootID, userID string, filter *models.CommentFilter) ([]*models.Comment, map[string]*models.Vote, error) {
-	if rootID == "" {
-		return nil, nil, fmt.Errorf("root ID is required")
+// castVote is applyVote inside repo's transaction, which the caller commits
+func (s *CommentService) castVote(ctx context.Context, repo repository.CommentRepository, commentID, userID string, voteType models.VoteType, weight int) (_ models.VoteType, scoreDelta int64, err error) {
+	comment, err := repo.GetCommentForUpdate(ctx, commentID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get comment: %w", err)
 	}
-	if userID == "" {
-		return nil, nil, fmt.Errorf("user ID is required")
+	if comment.Status != models.CommentStatusApproved {
+		return 0, 0, invalidInput("cannot vote on a comment that has not been approved")
+	}
+	// Prevent users from voting on their own comments
+	if comment.UserID == userID {
+		return 0, 0, ErrSelfVote
 	}
 
-	// Set defaults
-	if filter == nil {
-		filter = &models.CommentFilter{}
+	prior, err := repo.GetUserVote(ctx, commentID, userID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get existing vote: %w", err)
 	}
-	if filter.Limit == nil {
-		defaultLimit := 50
-		filter.Limit = &defaultLimit
+
+	if s.config.ToggleVotes && prior != nil && prior.VoteType == voteType {
+		if err = repo.DeleteVote(ctx, commentID, userID); err != nil {
+			return 0, 0, err
+		}
+		return models.VoteTypeNone, -votePoints(prior), nil
 	}
 
-	return s.repo.GetCommentsWithUserVotes(ctx, rootID, userID, filter)
+	vote := &models.Vote{CommentID: commentID, UserID: userID, VoteType: voteType, Weight: weight}
+	if err = repo.CreateVote(ctx, vote); err != nil {
+		return 0, 0, err
+	}
+	return voteType, votePoints(vote) - votePoints(prior), nil
 }
 
-// GetCommentStats retrieves statistics for a comment thread
-func (s *CommentService) GetCommentStats(ctx context.Context, rootID string) (*models.CommentStats, error) {
-	if rootID == "" {
-		return nil, fmt.Errorf("root ID is required")
+// votePoints is what vote adds to its comment's score; nil adds nothing
+func votePoints(vote *models.Vote) int64 {
+	if vote == nil {
+		return 0
 	}
-
-	return s.repo.GetCommentStats(ctx, rootID)
+	return int64(vote.VoteType) * int64(max(vote.Weight, 1))
 }
 
-// GetTopComments retrieves the highest-scored comments within a time range
-func (s *CommentService) GetTopComments(ctx context.Context, rootID string, limit int, timeRange string) ([]*models.Comment, error) {
-	if rootID == "" {
-		return nil, fmt.Errorf("root ID is required")
-	}
+// RemoveVote removes a user's vote from a comment
+func (s *CommentService) RemoveVote(ctx context.Context, commentID, userID string) (err error) {
+	ctx, span := s.startSpan(ctx, "RemoveVote", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
 
-	if limit <= 0 {
-		limit = 10
+	if commentID == "" {
+		return invalidInput("comment ID is required")
 	}
-	if limit > 100 {
-		limit = 100 // Prevent abuse
+	if userID == "" {
+		return invalidInput("user ID is required")
+	}
+
+	if err := s.repo.DeleteVote(ctx, commentID, userID); err != nil {
+		return err
+	}
+
+	voteType := models.VoteTypeNone
+	s.emitCommentEvent(ctx, models.EventCommentVoted, commentID, userID, &voteType)
+	return nil
+}
+
+// GetUserVotes retrieves a page of the comments a user has voted on along
+// with their vote, most recently voted first. Votes on soft-deleted comments
+// are left out unless filter.IncludeDeleted is set.
+func (s *CommentService) GetUserVotes(ctx context.Context, userID string, filter *models.VoteFilter) (_ []*models.VotedComment, err error) {
+	ctx, span := s.startSpan(ctx, "GetUserVotes")
+	defer func() { endSpan(span, err) }()
+
+	if userID == "" {
+		return nil, invalidInput("user ID is required")
+	}
+
+	if filter == nil {
+		filter = &models.VoteFilter{}
+	}
+	if filter.VoteType != nil && *filter.VoteType != models.VoteTypeUp && *filter.VoteType != models.VoteTypeDown {
+		return nil, invalidInput("vote type filter must be up or down")
+	}
+	s.clampPage(&filter.Limit, &filter.Offset)
+
+	voted, err := s.repo.GetUserVotes(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range voted {
+		hideDeletionAudit(ctx, v.Comment)
+		s.renderContent(v.Comment)
+	}
+	return voted, nil
+}
+
+// GetRepliesToUser retrieves the replies other users left on userID's
+// comments, newest first, each with the comment it answers. Replies by users
+// userID has blocked are left out. filter.RootID narrows the feed to one root;
+// its other fields besides Limit and Offset are ignored.
+func (s *CommentService) GetRepliesToUser(ctx context.Context, userID string, filter *models.CommentFilter) (_ []*models.Reply, err error) {
+	ctx, span := s.startSpan(ctx, "GetRepliesToUser")
+	defer func() { endSpan(span, err) }()
+
+	if userID == "" {
+		return nil, invalidInput("user ID is required")
+	}
+
+	if filter == nil {
+		filter = &models.CommentFilter{}
+	}
+	s.clampPage(&filter.Limit, &filter.Offset)
+
+	replies, err := s.repo.GetRepliesToUser(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, reply := range replies {
+		s.renderContent(reply.Comment, reply.Parent)
+	}
+	return replies, nil
+}
+
+// GetVoteBreakdown retrieves the up/down split and voter count for a comment
+func (s *CommentService) GetVoteBreakdown(ctx context.Context, commentID string) (_ *models.VoteBreakdown, err error) {
+	ctx, span := s.startSpan(ctx, "GetVoteBreakdown", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
+
+	if commentID == "" {
+		return nil, invalidInput("comment ID is required")
+	}
+
+	breakdown, err := s.repo.GetVoteBreakdown(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vote breakdown: %w", err)
+	}
+	return breakdown, nil
+}
+
+// GetUserVote retrieves a user's vote on a comment, or nil if they haven't voted
+func (s *CommentService) GetUserVote(ctx context.Context, commentID, userID string) (_ *models.Vote, err error) {
+	ctx, span := s.startSpan(ctx, "GetUserVote", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
+
+	if commentID == "" {
+		return nil, invalidInput("comment ID is required")
+	}
+	if userID == "" {
+		return nil, invalidInput("user ID is required")
+	}
+
+	return s.repo.GetUserVote(ctx, commentID, userID)
+}
+
+// GetCommentsWithUserVotes retrieves comments with user's voting status for efficient frontend rendering.
+// Each vote is returned both in the map, keyed by comment ID, and as the comment's CurrentUserVote.
+func (s *CommentService) GetCommentsWithUserVotes(ctx context.Context, rootID, userID string, filter *models.CommentFilter) (_ []*models.Comment, _ map[string]*models.Vote, err error) {
+	ctx, span := s.startSpan(ctx, "GetCommentsWithUserVotes", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	if rootID == "" {
+		return nil, nil, invalidInput("root ID is required")
+	}
+	if userID == "" {
+		return nil, nil, invalidInput("user ID is required")
+	}
+
+	// Set defaults
+	if filter == nil {
+		filter = &models.CommentFilter{}
+	}
+	if err := validateFilter(filter); err != nil {
+		return nil, nil, err
+	}
+	s.clampPage(&filter.Limit, &filter.Offset)
+
+	comments, votes, err := s.repo.GetCommentsWithUserVotes(ctx, rootID, userID, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.renderContent(comments...)
+	return comments, votes, nil
+}
+
+// GetUserVotesForComments retrieves a user's votes for many comments at once, keyed by comment ID.
+// More than MaxBatchSize IDs are read in several queries of at most that many.
+func (s *CommentService) GetUserVotesForComments(ctx context.Context, commentIDs []string, userID string) (map[string]*models.Vote, error) {
+	if userID == "" {
+		return nil, invalidInput("user ID is required")
+	}
+	if len(commentIDs) <= s.config.MaxBatchSize {
+		if len(commentIDs) == 0 {
+			return map[string]*models.Vote{}, nil
+		}
+		return s.repo.GetUserVotesForComments(ctx, commentIDs, userID)
+	}
+
+	votes := make(map[string]*models.Vote, len(commentIDs))
+	for start := 0; start < len(commentIDs); start += s.config.MaxBatchSize {
+		end := min(start+s.config.MaxBatchSize, len(commentIDs))
+		chunk, err := s.repo.GetUserVotesForComments(ctx, commentIDs[start:end], userID)
+		if err != nil {
+			return nil, err
+		}
+		for commentID, vote := range chunk {
+			votes[commentID] = vote
+		}
+	}
+	return votes, nil
+}
+
+// GetCommentStats retrieves statistics for a comment thread
+func (s *CommentService) GetCommentStats(ctx context.Context, rootID string) (_ *models.CommentStats, err error) {
+	ctx, span := s.startSpan(ctx, "GetCommentStats", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	if rootID == "" {
+		return nil, invalidInput("root ID is required")
+	}
+
+	return s.repo.GetCommentStats(ctx, rootID)
+}
+
+// GetCommentStatsBatch retrieves statistics for many roots in one call. Roots
+// without comments are included with zero values. More than MaxBatchSize
+// roots fail with a *BatchTooLargeError.
+func (s *CommentService) GetCommentStatsBatch(ctx context.Context, rootIDs []string) (map[string]*models.CommentStats, error) {
+	if len(rootIDs) > s.config.MaxBatchSize {
+		return nil, &BatchTooLargeError{Size: len(rootIDs), Limit: s.config.MaxBatchSize}
+	}
+	for _, rootID := range rootIDs {
+		if rootID == "" {
+			return nil, invalidInput("root ID is required")
+		}
+	}
+
+	return s.repo.GetCommentStatsBatch(ctx, rootIDs)
+}
+
+// GetRootVersion reports the root's comment count and latest update time,
+// which together change whenever anything on the root does. It is much
+// cheaper than reading the comments, so callers use it to validate caches.
+func (s *CommentService) GetRootVersion(ctx context.Context, rootID string) (_ *models.RootVersion, err error) {
+	ctx, span := s.startSpan(ctx, "GetRootVersion", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	if rootID == "" {
+		return nil, invalidInput("root ID is required")
 	}
 
-	validTimeRanges := map[string]bool{
-		"hour": true, "day": true, "week": true, "month": true, "all": true,
+	return s.repo.GetRootVersion(ctx, rootID)
+}
+
+// GetTopComments retrieves the highest-scored comments within a time range
+func (s *CommentService) GetTopComments(ctx context.Context, rootID string, limit int, timeRange string) ([]*models.Comment, error) {
+	if rootID == "" {
+		return nil, invalidInput("root ID is required")
+	}
+
+	if limit <= 0 {
+		limit = 10
 	}
+	if limit > s.config.MaxPageSize {
+		limit = s.config.MaxPageSize // Prevent abuse
+	}
+
 	if !validTimeRanges[timeRange] {
 		timeRange = "day" // Default to day
 	}
 
-	return s.repo.GetTopComments(ctx, rootID, limit, timeRange)
+	ctx, cancel := s.withQueryTimeout(ctx)
+	defer cancel()
+
+	comments, err := s.repo.GetTopComments(ctx, rootID, limit, timeRange)
+	if err != nil {
+		return nil, timeoutError(ctx, err)
+	}
+	s.renderContent(comments...)
+	return comments, nil
+}
+
+// validTimeRanges are the windows accepted by the time-ranged queries
+var validTimeRanges = map[string]bool{
+	"hour": true, "day": true, "week": true, "month": true, "all": true,
+}
+
+// GetTrendingRoots retrieves the roots with the most comment activity within
+// a time range, most active first
+func (s *CommentService) GetTrendingRoots(ctx context.Context, timeRange string, limit int) (_ []*models.TrendingRoot, err error) {
+	ctx, span := s.startSpan(ctx, "GetTrendingRoots")
+	defer func() { endSpan(span, err) }()
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > s.config.MaxPageSize {
+		limit = s.config.MaxPageSize // Prevent abuse
+	}
+	if !validTimeRanges[timeRange] {
+		timeRange = "day"
+	}
+
+	return s.repo.GetTrendingRoots(ctx, timeRange, limit)
+}
+
+// GetTopCommenters retrieves the users with the most live comments on a root,
+// most active first
+func (s *CommentService) GetTopCommenters(ctx context.Context, rootID string, limit int) (_ []*models.TopCommenter, err error) {
+	ctx, span := s.startSpan(ctx, "GetTopCommenters", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	if rootID == "" {
+		return nil, invalidInput("root ID is required")
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > s.config.MaxPageSize {
+		limit = s.config.MaxPageSize // Prevent abuse
+	}
+
+	ctx, cancel := s.withQueryTimeout(ctx)
+	defer cancel()
+
+	commenters, err := s.repo.GetTopCommenters(ctx, rootID, limit)
+	if err != nil {
+		return nil, timeoutError(ctx, err)
+	}
+	return commenters, nil
 }
 
 // GetUserCommentCount retrieves the total number of comments by a user
 func (s *CommentService) GetUserCommentCount(ctx context.Context, userID string) (int64, error) {
 	if userID == "" {
-		return 0, fmt.Errorf("user ID is required")
+		return 0, invalidInput("user ID is required")
 	}
 
 	return s.repo.GetUserCommentCount(ctx, userID)
 }
 
+// GetUserReputation retrieves the votes a user's comments have received across
+// every root. Self-votes, which VoteComment refuses but imports may carry,
+// are left out.
+func (s *CommentService) GetUserReputation(ctx context.Context, userID string) (_ *models.UserReputation, err error) {
+	ctx, span := s.startSpan(ctx, "GetUserReputation")
+	defer func() { endSpan(span, err) }()
+
+	if userID == "" {
+		return nil, invalidInput("user ID is required")
+	}
+
+	ctx, cancel := s.withQueryTimeout(ctx)
+	defer cancel()
+
+	reputation, err := s.repo.GetUserReputation(ctx, userID)
+	if err != nil {
+		return nil, timeoutError(ctx, err)
+	}
+	return reputation, nil
+}
+
 // SearchComments searches for comments containing specific text
-func (s *CommentService) SearchComments(ctx context.Context, rootID, query string, filter *models.CommentFilter) ([]*models.Comment, error) {
+func (s *CommentService) SearchComments(ctx context.Context, rootID, query string, filter *models.CommentFilter) (_ []*models.Comment, err error) {
+	ctx, span := s.startSpan(ctx, "SearchComments", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
 	if rootID == "" {
-		return nil, fmt.Errorf("root ID is required")
+		return nil, invalidInput("root ID is required")
 	}
 	if query == "" {
-		return nil, fmt.Errorf("search query is required")
+		return nil, invalidInput("search query is required")
 	}
 
 	query = strings.TrimSpace(query)
 	if len(query) < 3 {
-		return nil, fmt.Errorf("search query must be at least 3 characters")
+		return nil, invalidInput("search query must be at least 3 characters")
+	}
+
+	if filter == nil {
+		filter = &models.CommentFilter{}
 	}
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+	applyViewer(ctx, filter)
+
+	ctx, cancel := s.withQueryTimeout(ctx)
+	defer cancel()
 
 	// This is a simplified search - in production you might want to use
 	// full-text search capabilities or external search services
 	comments, err := s.repo.GetCommentsByRootID(ctx, rootID, filter)
 	if err != nil {
-		return nil, err
+		return nil, timeoutError(ctx, err)
 	}
 
 	// Filter comments containing the query
@@ -370,15 +1165,52 @@ func (s *CommentService) SearchComments(ctx context.Context, rootID, query strin
 		}
 	}
 
+	s.renderContent(results...)
 	return results, nil
 }
 
+// SearchAllComments performs a full-text search across every root. Set
+// filter.UserID to search a single user's comments, or filter.RootID to narrow
+// to one root. Results are paginated like GetCommentsByRoot.
+func (s *CommentService) SearchAllComments(ctx context.Context, query string, filter *models.CommentFilter) (_ []*models.Comment, err error) {
+	ctx, span := s.startSpan(ctx, "SearchAllComments")
+	defer func() { endSpan(span, err) }()
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, invalidInput("search query is required")
+	}
+	if len(query) < 3 {
+		return nil, invalidInput("search query must be at least 3 characters")
+	}
+
+	if filter == nil {
+		filter = &models.CommentFilter{}
+	}
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+	s.clampPage(&filter.Limit, &filter.Offset)
+	filter.Search = &query
+	applyViewer(ctx, filter)
+
+	ctx, cancel := s.withQueryTimeout(ctx)
+	defer cancel()
+
+	comments, err := s.repo.GetComments(ctx, filter)
+	if err != nil {
+		return nil, timeoutError(ctx, err)
+	}
+	s.renderContent(comments...)
+	return comments, nil
+}
+
 // Maintenance Operations
 
 // PurgeOldDeletedComments removes soft-deleted comments older than specified days
 func (s *CommentService) PurgeOldDeletedComments(ctx context.Context, olderThanDays int) (int64, error) {
 	if olderThanDays < 1 {
-		return 0, fmt.Errorf("olderThanDays must be at least 1")
+		return 0, invalidInput("olderThanDays must be at least 1")
 	}
 
 	return s.repo.PurgeDeletedComments(ctx, olderThanDays)
@@ -389,6 +1221,37 @@ func (s *CommentService) RecalculateAllScores(ctx context.Context) error {
 	return s.repo.RecalculateCommentScores(ctx)
 }
 
+// RepairCommentPaths recomputes depth and materialized path for every comment
+// in a root from its parent chain, fixing drift left by manual edits or bugs.
+// It returns the number of comments that were corrected.
+func (s *CommentService) RepairCommentPaths(ctx context.Context, rootID string) (int64, error) {
+	if rootID == "" {
+		return 0, invalidInput("root ID is required")
+	}
+
+	return s.repo.RepairCommentPaths(ctx, rootID)
+}
+
+// BackfillReplyCounts recomputes every comment's reply and descendant counts.
+// Counts are maintained automatically; this is for repairing existing data.
+func (s *CommentService) BackfillReplyCounts(ctx context.Context) error {
+	return s.repo.RecalculateReplyCounts(ctx)
+}
+
+// DefaultScoreDecayHalfLife is the vote half-life used when none is given
+const DefaultScoreDecayHalfLife = 7 * 24 * time.Hour
+
+// RecalculateDecayedScores recomputes every comment's decayed score, halving
+// each vote's weight for every halfLife since it was cast. Run it periodically
+// to keep sort_by=decayed fresh; a non-positive halfLife uses the default.
+func (s *CommentService) RecalculateDecayedScores(ctx context.Context, halfLife time.Duration) error {
+	if halfLife <= 0 {
+		halfLife = DefaultScoreDecayHalfLife
+	}
+
+	return s.repo.RecalculateDecayedScores(ctx, halfLife, s.clock.Now())
+}
+
 // Utility methods
 
 // isValidURL performs basic URL validation
@@ -399,39 +1262,207 @@ func (s *CommentService) isValidURL(url string) bool {
 }
 
 // GetCommentPath retrieves the full path from root to a specific comment
-func (s *CommentService) GetCommentPath(ctx context.Context, commentID string) ([]*models.Comment, error) {
+func (s *CommentService) GetCommentPath(ctx context.Context, commentID string) (_ []*models.Comment, err error) {
+	ctx, span := s.startSpan(ctx, "GetCommentPath", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
+
 	if commentID == "" {
-		return nil, fmt.Errorf("comment ID is required")
+		return nil, invalidInput("comment ID is required")
 	}
 
-	return s.repo.GetCommentPath(ctx, commentID)
+	path, err := s.repo.GetCommentPath(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	// Only the comment itself can be awaiting moderation; nothing replies to one
+	if len(path) > 0 && !visibleToViewer(ctx, path[len(path)-1]) {
+		return nil, fmt.Errorf("failed to get comment path: %w", ErrNotFound)
+	}
+
+	s.renderContent(path...)
+	return path, nil
+}
+
+// GetCommentAncestors retrieves the comments above commentID, top-level
+// first, excluding the comment itself. It follows parent links, so it works
+// even where a comment's stored path is wrong.
+func (s *CommentService) GetCommentAncestors(ctx context.Context, commentID string) (_ []*models.Comment, err error) {
+	ctx, span := s.startSpan(ctx, "GetCommentAncestors", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
+
+	if commentID == "" {
+		return nil, invalidInput("comment ID is required")
+	}
+
+	comment, err := s.repo.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if !visibleToViewer(ctx, comment) {
+		return nil, fmt.Errorf("failed to get comment ancestors: %w", ErrNotFound)
+	}
+
+	ancestors, err := s.repo.GetCommentAncestors(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.renderContent(ancestors...)
+	return ancestors, nil
 }
 
 // GetCommentChildren retrieves all child comments for a given comment
-func (s *CommentService) GetCommentChildren(ctx context.Context, parentID string, maxDepth int) ([]*models.Comment, error) {
+func (s *CommentService) GetCommentChildren(ctx context.Context, parentID string, maxDepth int, filter *models.CommentFilter) (_ []*models.Comment, err error) {
+	ctx, span := s.startSpan(ctx, "GetCommentChildren", attrCommentID.String(parentID))
+	defer func() { endSpan(span, err) }()
+
 	if parentID == "" {
-		return nil, fmt.Errorf("parent ID is required")
+		return nil, invalidInput("parent ID is required")
 	}
 
 	if maxDepth <= 0 {
 		maxDepth = 10
 	}
-	if maxDepth > 50 {
-		maxDepth = 50
+	if maxDepth > s.config.MaxTreeDepth {
+		maxDepth = s.config.MaxTreeDepth
+	}
+
+	// Subtrees are always paged so a large thread can't come back in one response
+	if filter == nil {
+		filter = &models.CommentFilter{}
+	}
+	s.clampPage(&filter.Limit, &filter.Offset)
+	applyViewer(ctx, filter)
+
+	comments, err := s.repo.GetCommentChildren(ctx, parentID, maxDepth, filter)
+	if err != nil {
+		return nil, err
+	}
+	s.renderContent(comments...)
+	return comments, nil
+}
+
+// GetDirectChildren retrieves one page of a comment's immediate replies, for
+// clients that expand a thread one level at a time
+func (s *CommentService) GetDirectChildren(ctx context.Context, parentID string, filter *models.CommentFilter) (_ []*models.Comment, err error) {
+	ctx, span := s.startSpan(ctx, "GetDirectChildren", attrCommentID.String(parentID))
+	defer func() { endSpan(span, err) }()
+
+	if parentID == "" {
+		return nil, invalidInput("parent ID is required")
+	}
+
+	if _, err := s.repo.GetCommentByID(ctx, parentID); err != nil {
+		return nil, fmt.Errorf("comment not found: %w", err)
+	}
+
+	// Set default pagination
+	if filter == nil {
+		filter = &models.CommentFilter{}
+	}
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+	s.clampPage(&filter.Limit, &filter.Offset)
+	applyViewer(ctx, filter)
+
+	comments, err := s.repo.GetDirectChildren(ctx, parentID, filter)
+	if err != nil {
+		return nil, err
+	}
+	s.renderContent(comments...)
+	return comments, nil
+}
+
+// MoveComment re-parents a comment and its replies under newParentID within
+// the same root. The move runs in a transaction. Callers are responsible for
+// checking that userID is allowed to moderate the root.
+func (s *CommentService) MoveComment(ctx context.Context, commentID, newParentID, userID string) (_ *models.Comment, err error) {
+	ctx, span := s.startSpan(ctx, "MoveComment", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
+
+	if commentID == "" {
+		return nil, invalidInput("comment ID is required")
+	}
+	if newParentID == "" {
+		return nil, invalidInput("new parent ID is required")
+	}
+	if userID == "" {
+		return nil, invalidInput("user ID is required")
+	}
+	if commentID == newParentID {
+		return nil, invalidInput("a comment cannot be its own parent")
+	}
+
+	comment, err := s.repo.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("comment not found: %w", err)
+	}
+	parent, err := s.repo.GetCommentByID(ctx, newParentID)
+	if err != nil {
+		return nil, fmt.Errorf("new parent not found: %w", err)
+	}
+	if parent.RootID != comment.RootID {
+		return nil, invalidInput("cannot move a comment to a different root")
+	}
+	if comment.Status != models.CommentStatusApproved || parent.Status != models.CommentStatusApproved {
+		return nil, invalidInput("only approved comments can be moved, or moved under")
+	}
+	if strings.HasPrefix(parent.Path, comment.Path+".") {
+		return nil, invalidInput("cannot move a comment under one of its own replies")
+	}
+	if comment.ParentID != nil && *comment.ParentID == newParentID {
+		s.renderContent(comment)
+		return comment, nil
+	}
+
+	repo, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := repo.MoveComment(ctx, commentID, newParentID); err != nil {
+		repo.RollbackTx(ctx)
+		return nil, err
+	}
+	if err := repo.CommitTx(ctx); err != nil {
+		return nil, err
 	}
 
-	return s.repo.GetCommentChildren(ctx, parentID, maxDepth)
+	s.emitCommentEvent(ctx, models.EventCommentUpdated, commentID, userID, nil)
+	moved, err := s.repo.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	s.renderContent(moved)
+	return moved, nil
 }
 
-// BatchVoteComments allows voting on multiple comments at once (useful for bulk operations)
-func (s *CommentService) BatchVoteComments(ctx context.Context, votes []models.VoteRequest, userID string) error {
+// BatchVoteComments allows voting on multiple comments at once (useful for bulk operations).
+// Each vote follows the rules VoteComment applies, in one transaction that a
+// refused vote rolls back. More than MaxBatchSize votes fail with a
+// *BatchTooLargeError.
+func (s *CommentService) BatchVoteComments(ctx context.Context, votes []models.VoteRequest, userID string) (err error) {
 	if userID == "" {
-		return fmt.Errorf("user ID is required")
+		return invalidInput("user ID is required")
+	}
+
+	if len(votes) > s.config.MaxBatchSize {
+		return &BatchTooLargeError{Size: len(votes), Limit: s.config.MaxBatchSize}
 	}
 
-	if len(votes) > 100 {
-		return fmt.Errorf("too many votes in batch, maximum is 100")
+	for _, vote := range votes {
+		// Basic validation
+		if vote.UserID != userID {
+			return invalidInput("user ID mismatch in vote request")
+		}
+		if vote.CommentID == "" {
+			return invalidInput("comment ID is required in vote request")
+		}
+		if vote.VoteType != models.VoteTypeUp && vote.VoteType != models.VoteTypeDown {
+			return invalidInput("invalid vote type")
+		}
 	}
+	weight := s.voteWeight(ctx, userID)
 
 	// Use transaction for batch operations
 	repo, err := s.repo.BeginTx(ctx)
@@ -445,44 +1476,375 @@ func (s *CommentService) BatchVoteComments(ctx context.Context, votes []models.V
 		}
 	}()
 
-	for _, vote := range votes {
-		// Basic validation
-		if vote.UserID != userID {
-			err = fmt.Errorf("user ID mismatch in vote request")
-			return err
+	cast := make([]models.VoteType, len(votes))
+	for i, vote := range votes {
+		cast[i], _, err = s.castVote(ctx, repo, vote.CommentID, userID, vote.VoteType, weight)
+		if err != nil {
+			return fmt.Errorf("failed to apply vote on %s: %w", vote.CommentID, err)
 		}
+	}
+	if err = repo.CommitTx(ctx); err != nil {
+		return err
+	}
 
-		// Apply the vote
-		err = repo.UpdateVote(ctx, "", vote.UserID, vote.VoteType)
+	for i, vote := range votes {
+		s.emitCommentEvent(ctx, models.EventCommentVoted, vote.CommentID, userID, &cast[i])
+	}
+	return nil
+}
+
+// BatchRemoveVotes removes userID's votes on many comments at once, updating
+// their scores. Comments the user hasn't voted on are skipped. More than
+// MaxBatchSize comment IDs fail with a *BatchTooLargeError.
+func (s *CommentService) BatchRemoveVotes(ctx context.Context, commentIDs []string, userID string) (err error) {
+	ctx, span := s.startSpan(ctx, "BatchRemoveVotes")
+	defer func() { endSpan(span, err) }()
+
+	if userID == "" {
+		return invalidInput("user ID is required")
+	}
+	if len(commentIDs) > s.config.MaxBatchSize {
+		return &BatchTooLargeError{Size: len(commentIDs), Limit: s.config.MaxBatchSize}
+	}
+	for _, commentID := range commentIDs {
+		if commentID == "" {
+			return invalidInput("comment ID is required")
+		}
+	}
+	if len(commentIDs) == 0 {
+		return nil
+	}
+
+	removed, err := s.repo.DeleteVotes(ctx, commentIDs, userID)
+	if err != nil {
+		return err
+	}
+
+	voteType := models.VoteTypeNone
+	for _, commentID := range removed {
+		s.emitCommentEvent(ctx, models.EventCommentVoted, commentID, userID, &voteType)
+	}
+	return nil
+}
+
+// ImportComments bulk-inserts comments migrated from another system in a
+// single transaction. IDs, timestamps, edit tracking and vote counts are kept
+// as given (a missing ID is generated, a zero CreatedAt becomes now); depth
+// and path are recomputed from ParentID, and parents are inserted before
+// their children whatever their order in comments. Every parent must be in
+// the batch or already stored, in the same root. The comments are updated in
+// place with their computed depth and path. No events are emitted.
+func (s *CommentService) ImportComments(ctx context.Context, comments []*models.Comment) (err error) {
+	ctx, span := s.startSpan(ctx, "ImportComments")
+	defer func() { endSpan(span, err) }()
+
+	if len(comments) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*models.Comment, len(comments))
+	for _, comment := range comments {
+		if comment.ID == "" {
+			comment.ID = uuid.New().String()
+		}
+		if err := repository.ValidateCommentID(comment.ID); err != nil {
+			return &InputError{Message: fmt.Sprintf("comment %s: %v", comment.ID, err), Err: err}
+		}
+		if comment.RootID == "" || comment.UserID == "" {
+			return invalidInput("comment %s: root ID and user ID are required", comment.ID)
+		}
+		if strings.TrimSpace(comment.Content) == "" && !s.allowsEmptyContent(comment.MediaURL) {
+			return invalidInput("comment %s: content cannot be empty", comment.ID)
+		}
+		if _, duplicate := byID[comment.ID]; duplicate {
+			return invalidInput("duplicate comment ID %s", comment.ID)
+		}
+		byID[comment.ID] = comment
+	}
+
+	// Parents outside the batch must already be stored; deleted ones count
+	var externalIDs []string
+	for _, comment := range comments {
+		if comment.ParentID != nil {
+			if _, inBatch := byID[*comment.ParentID]; !inBatch {
+				externalIDs = append(externalIDs, *comment.ParentID)
+			}
+		}
+	}
+	external := make(map[string]*models.Comment, len(externalIDs))
+	if len(externalIDs) > 0 {
+		parents, err := s.repo.GetCommentsByIDs(ctx, externalIDs, true)
 		if err != nil {
-			return fmt.Errorf("failed to apply vote: %w", err)
+			return fmt.Errorf("failed to look up parent comments: %w", err)
+		}
+		for _, parent := range parents {
+			external[parent.ID] = parent
+		}
+	}
+
+	// Order parents before children, computing depth and path on the way
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	ordered := make([]*models.Comment, 0, len(comments))
+	state := make(map[string]int, len(comments))
+	var visit func(comment *models.Comment) error
+	visit = func(comment *models.Comment) error {
+		switch state[comment.ID] {
+		case visited:
+			return nil
+		case visiting:
+			return invalidInput("comment %s is its own ancestor", comment.ID)
+		}
+		state[comment.ID] = visiting
+
+		if comment.ParentID == nil {
+			comment.Depth = 0
+			comment.Path = comment.ID
+		} else {
+			parent, inBatch := byID[*comment.ParentID]
+			if inBatch {
+				if err := visit(parent); err != nil {
+					return err
+				}
+			} else if parent = external[*comment.ParentID]; parent == nil {
+				return invalidInput("parent %s of comment %s not found", *comment.ParentID, comment.ID)
+			}
+			if parent.RootID != comment.RootID {
+				return invalidInput("comment %s belongs to a different root than its parent", comment.ID)
+			}
+			if parent.Depth >= s.config.MaxCommentDepth {
+				return &MaxDepthError{Limit: s.config.MaxCommentDepth}
+			}
+			comment.Depth = parent.Depth + 1
+			comment.Path = parent.Path + "." + comment.ID
+		}
+
+		state[comment.ID] = visited
+		ordered = append(ordered, comment)
+		return nil
+	}
+	for _, comment := range comments {
+		if err := visit(comment); err != nil {
+			return err
 		}
 	}
 
+	now := s.clock.Now()
+	for _, comment := range ordered {
+		if comment.CreatedAt.IsZero() {
+			comment.CreatedAt = now
+		}
+		if comment.UpdatedAt.IsZero() {
+			comment.UpdatedAt = comment.CreatedAt
+		}
+		comment.Score = comment.Upvotes - comment.Downvotes
+	}
+
+	repo, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err = repo.ImportComments(ctx, ordered); err != nil {
+		repo.RollbackTx(ctx)
+		return err
+	}
 	return repo.CommitTx(ctx)
 }
 
+// exportPageSize is how many comments StreamExport reads per query
+const exportPageSize = 500
+
+// ExportOptions controls what a thread export includes
+type ExportOptions struct {
+	IncludeVotes   bool // Attach every vote cast on each comment
+	IncludeDeleted bool // Include soft-deleted comments
+}
+
+// StreamExport calls fn for every comment in a root, parents before their
+// replies, reading the thread in pages so large roots are never held in
+// memory at once. An error from fn stops the export and is returned as is.
+func (s *CommentService) StreamExport(ctx context.Context, rootID string, opts ExportOptions, fn func(*models.ExportedComment) error) (err error) {
+	ctx, span := s.startSpan(ctx, "StreamExport", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	if rootID == "" {
+		return invalidInput("root ID cannot be empty")
+	}
+
+	afterPath := ""
+	for {
+		comments, err := s.repo.ExportComments(ctx, rootID, afterPath, exportPageSize, opts.IncludeDeleted)
+		if err != nil {
+			return err
+		}
+
+		var votes map[string][]*models.Vote
+		if opts.IncludeVotes && len(comments) > 0 {
+			ids := make([]string, len(comments))
+			for i, comment := range comments {
+				ids[i] = comment.ID
+			}
+			if votes, err = s.repo.GetVotesForComments(ctx, ids); err != nil {
+				return err
+			}
+		}
+
+		hideDeletionAudit(ctx, comments...)
+		for _, comment := range comments {
+			exported := &models.ExportedComment{Comment: comment}
+			if opts.IncludeVotes {
+				exported.Votes = votes[comment.ID]
+			}
+			if err := fn(exported); err != nil {
+				return err
+			}
+		}
+
+		if len(comments) < exportPageSize {
+			return nil
+		}
+		afterPath = comments[len(comments)-1].Path
+	}
+}
+
+// ExportRoot collects a root's entire thread into a single snapshot.
+// Prefer StreamExport for roots too large to hold in memory.
+func (s *CommentService) ExportRoot(ctx context.Context, rootID string, opts ExportOptions) (*models.RootExport, error) {
+	export := &models.RootExport{
+		RootID:     rootID,
+		ExportedAt: s.clock.Now(),
+		Comments:   []*models.ExportedComment{},
+	}
+	err := s.StreamExport(ctx, rootID, opts, func(comment *models.ExportedComment) error {
+		export.Comments = append(export.Comments, comment)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// StreamUserExport calls fn for every live comment a user has written,
+// oldest first, reading them in pages. An error from fn stops the export
+// and is returned as is.
+func (s *CommentService) StreamUserExport(ctx context.Context, userID string, fn func(*models.Comment) error) (err error) {
+	ctx, span := s.startSpan(ctx, "StreamUserExport")
+	defer func() { endSpan(span, err) }()
+
+	if userID == "" {
+		return invalidInput("user ID is required")
+	}
+
+	limit := exportPageSize
+	for offset := 0; ; offset += limit {
+		// Users export everything they wrote, including comments still awaiting moderation
+		filter := &models.CommentFilter{SortBy: "created_at", SortOrder: "asc", Limit: &limit, Offset: &offset, ViewerID: &userID}
+		comments, err := s.repo.GetCommentsByUserID(ctx, userID, filter)
+		if err != nil {
+			return err
+		}
+		for _, comment := range comments {
+			if err := fn(comment); err != nil {
+				return err
+			}
+		}
+		if len(comments) < limit {
+			return nil
+		}
+	}
+}
+
 // CommentServiceConfig holds configuration for the comment service
 type CommentServiceConfig struct {
-	MaxCommentLength int
-	MaxTreeDepth     int
-	MaxBatchSize     int
-	DefaultPageSize  int
-	MaxPageSize      int
+	MinCommentLength       int                  // Fewest characters (runes) content may have after trimming; 0 disables the check
+	MaxCommentLength       int                  // Most characters (runes) content may have after trimming (default 10000)
+	AllowMediaOnlyComments bool                 // Accept comments with no text when they have a media URL, e.g. image-only comments; off by default
+	MaxCommentDepth        int                  // Deepest depth a reply may have; top-level comments are depth 0
+	MaxTreeDepth           int                  // Upper bound on the depth requested from tree and subtree reads
+	MaxBatchSize           int                  // Most votes, comment IDs or root IDs one batch call takes; larger batches fail with *BatchTooLargeError (default 100)
+	DefaultPageSize        int                  // Page size of list reads that don't set a limit (default 50)
+	MaxPageSize            int                  // Largest limit a list or top-N read may ask for; larger ones are cut to it (default 1000)
+	AllowAnonymous         bool                 // Accept guest comments from CreateCommentRequest.Anonymous; off by default
+	PreModeration          bool                 // Hold new comments as pending until a moderator approves them
+	SpamThreshold          float64              // Quarantine new comments the SpamScorer rates above this (default 0.8)
+	RenderMarkdown         bool                 // Fill in Comment.ContentHTML from Markdown content on read; off by default
+	LinkPreviewTimeout     time.Duration        // Longest a LinkPreviewer fetch may take (default 5s)
+	QueryTimeout           time.Duration        // Bound on tree, search and top-comment reads whose context has no deadline; 0 leaves them unbounded
+	IdempotencyTTL         time.Duration        // How long CreateCommentIdempotent remembers a key (default 24h)
+	AutoSubscribe          bool                 // Subscribe authors to the roots they comment on, so they hear about the replies; off by default
+	AuthorsSeeDeleted      bool                 // Let a comment's author, and moderators, still read it by ID after it is soft-deleted, so their client can show a tombstone; off by default
+	ContentNormalization   ContentNormalization // What to strip from content besides surrounding whitespace (default NormalizeStandard: control and zero-width characters)
+	MaxLines               int                  // Most lines content may have; 0 disables the check
+	MaxBlankLines          int                  // Most consecutive blank lines content may have; 0 disables the check
+	CollapseLines          bool                 // Fit content within MaxLines and MaxBlankLines instead of rejecting it with *TooManyLinesError; off by default
+	ToggleVotes            bool                 // Make voting the way a user already voted remove the vote, like clicking a highlighted upvote; off by default, when repeating a vote changes nothing
 }
 
-// NewCommentServiceWithConfig creates a comment service with custom configuration
+// Defaults applied to zero-valued CommentServiceConfig fields
+const (
+	DefaultMaxCommentLength = 10000
+	DefaultMaxCommentDepth  = 100
+	DefaultMaxTreeDepth     = 50
+	DefaultPageSize         = 50
+	DefaultMaxPageSize      = 1000
+	DefaultMaxBatchSize     = 100
+)
+
+// NewCommentServiceWithConfig creates a comment service with custom configuration.
+// Zero-valued fields fall back to the defaults.
 func NewCommentServiceWithConfig(repo repository.CommentRepository, config *CommentServiceConfig) *CommentService {
 	service := &CommentService{
 		repo:      repo,
-		validator: validator.New(),
+		validator: newValidator(),
+		clock:     systemClock{},
+		tracer:    defaultTracer,
+		logger:    discardLogger,
+
+		spamScorer:  noopSpamScorer{},
+		voteWeights: unitVoteWeights{},
 	}
 
 	// Apply configuration if provided
 	if config != nil {
-		// Configuration would be stored in service and used in validation
-		// This is a placeholder for future extensibility
+		service.config = *config
+	}
+	if service.config.MaxCommentLength <= 0 {
+		service.config.MaxCommentLength = DefaultMaxCommentLength
+	}
+	if service.config.MaxCommentDepth <= 0 {
+		service.config.MaxCommentDepth = DefaultMaxCommentDepth
+	}
+	if service.config.MaxTreeDepth <= 0 {
+		service.config.MaxTreeDepth = DefaultMaxTreeDepth
+	}
+	if service.config.MaxPageSize <= 0 {
+		service.config.MaxPageSize = DefaultMaxPageSize
+	}
+	if service.config.MaxBatchSize <= 0 {
+		service.config.MaxBatchSize = DefaultMaxBatchSize
+	}
+	if service.config.DefaultPageSize <= 0 {
+		service.config.DefaultPageSize = DefaultPageSize
+	}
+	if service.config.DefaultPageSize > service.config.MaxPageSize {
+		service.config.DefaultPageSize = service.config.MaxPageSize
+	}
+	if service.config.SpamThreshold <= 0 {
+		service.config.SpamThreshold = DefaultSpamThreshold
+	}
+	if service.config.RenderMarkdown {
+		service.markdown = newMarkdownRenderer()
+	}
+	if service.config.LinkPreviewTimeout <= 0 {
+		service.config.LinkPreviewTimeout = DefaultLinkPreviewTimeout
+	}
+	if service.config.IdempotencyTTL <= 0 {
+		service.config.IdempotencyTTL = DefaultIdempotencyTTL
 	}
+	service.idempotency = newMemoryIdempotencyStore(func() time.Time { return service.clock.Now() })
 
 	return service
 }
@@ -58,11 +58,19 @@ func (m *MockRepository) GetCommentByID(ctx context.Context, id string) (*models
 
 	comment, exists := m.comments[id]
 	if !exists {
-		return nil, errors.New("comment not found")
+		return nil, repository.ErrNotFound
 	}
 	return comment, nil
 }
 
+func (m *MockRepository) GetCommentForUpdate(ctx context.Context, id string) (*models.Comment, error) {
+	return m.GetCommentByID(ctx, id)
+}
+
+func (m *MockRepository) GetCommentsByIDs(ctx context.Context, ids []string, includeDeleted bool) ([]*models.Comment, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
 func (m *MockRepository) UpdateComment(ctx context.Context, id string, updates *models.UpdateCommentRequest) error {
 	if m.error != nil {
 		return m.error
@@ -87,7 +95,7 @@ func (m *MockRepository) UpdateComment(ctx context.Context, id string, updates *
 	return nil
 }
 
-func (m *MockRepository) DeleteComment(ctx context.Context, id string, userID string) error {
+func (m *MockRepository) DeleteComment(ctx context.Context, id string, userID string, reason *string) error {
 	if m.error != nil {
 		return m.error
 	}
@@ -107,28 +115,28 @@ func (m *MockRepository) DeleteComment(ctx context.Context, id string, userID st
 }
 
 func (m *MockRepository) UpdateVote(ctx context.Context, commentID, userID string, voteType models.VoteType) error {
+	return m.CreateVote(ctx, &models.Vote{CommentID: commentID, UserID: userID, VoteType: voteType})
+}
+
+func (m *MockRepository) CreateVote(ctx context.Context, vote *models.Vote) error {
 	if m.error != nil {
 		return m.error
 	}
 
 	// Check if comment exists
+	commentID := vote.CommentID
 	comment, exists := m.comments[commentID]
 	if !exists {
 		return errors.New("comment not found")
 	}
 
 	// Create or update vote
-	voteKey := commentID + ":" + userID
-	vote := &models.Vote{
-		ID:        voteKey,
-		CommentID: commentID,
-		UserID:    userID,
-		VoteType:  voteType,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	m.votes[voteKey] = vote
+	voteKey := commentID + ":" + vote.UserID
+	vote.ID = voteKey
+	vote.CreatedAt = time.Now()
+	vote.UpdatedAt = time.Now()
+	stored := *vote
+	m.votes[voteKey] = &stored
 
 	// Update comment scores (simplified)
 	upvotes := int64(0)
@@ -152,6 +160,14 @@ func (m *MockRepository) UpdateVote(ctx context.Context, commentID, userID strin
 }
 
 // Implement other required interface methods with minimal implementations
+func (m *MockRepository) HardDeleteComment(ctx context.Context, id string) error {
+	return errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) RemoveComment(ctx context.Context, id string, moderatorID string, reason *string) error {
+	return errors.New("not implemented in mock")
+}
+
 func (m *MockRepository) GetComments(ctx context.Context, filter *models.CommentFilter) ([]*models.Comment, error) {
 	if m.error != nil {
 		return nil, m.error
@@ -180,6 +196,10 @@ func (m *MockRepository) GetCommentsByRootID(ctx context.Context, rootID string,
 	return comments, nil
 }
 
+func (m *MockRepository) GetCommentsSince(ctx context.Context, rootID string, since time.Time, afterID string, limit int) ([]*models.Comment, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
 func (m *MockRepository) GetCommentsByUserID(ctx context.Context, userID string, filter *models.CommentFilter) ([]*models.Comment, error) {
 	if m.error != nil {
 		return nil, m.error
@@ -195,7 +215,19 @@ func (m *MockRepository) GetCommentsByUserID(ctx context.Context, userID string,
 }
 
 // Add stub implementations for other interface methods to satisfy the interface
-func (m *MockRepository) GetCommentChildren(ctx context.Context, parentID string, maxDepth int) ([]*models.Comment, error) {
+func (m *MockRepository) GetCommentChildren(ctx context.Context, parentID string, maxDepth int, filter *models.CommentFilter) ([]*models.Comment, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) MoveComment(ctx context.Context, commentID, newParentID string) error {
+	return errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) GetDirectChildren(ctx context.Context, parentID string, filter *models.CommentFilter) ([]*models.Comment, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) ExportComments(ctx context.Context, rootID, afterPath string, limit int, includeDeleted bool) ([]*models.Comment, error) {
 	return nil, errors.New("not implemented in mock")
 }
 
@@ -203,38 +235,82 @@ func (m *MockRepository) GetCommentTree(ctx context.Context, rootID string, maxD
 	return nil, errors.New("not implemented in mock")
 }
 
+func (m *MockRepository) GetCommentSubtree(ctx context.Context, commentID string, maxDepth int, sortBy string) (*models.CommentTree, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
 func (m *MockRepository) GetCommentPath(ctx context.Context, commentID string) ([]*models.Comment, error) {
 	return nil, errors.New("not implemented in mock")
 }
 
-func (m *MockRepository) CreateVote(ctx context.Context, vote *models.Vote) error {
-	return errors.New("not implemented in mock")
+func (m *MockRepository) GetCommentAncestors(ctx context.Context, commentID string) ([]*models.Comment, error) {
+	return nil, errors.New("not implemented in mock")
 }
 
 func (m *MockRepository) DeleteVote(ctx context.Context, commentID, userID string) error {
 	return errors.New("not implemented in mock")
 }
 
-func (m *MockRepository) GetUserVote(ctx context.Context, commentID, userID string) (*models.Vote, error) {
+func (m *MockRepository) DeleteVotes(ctx context.Context, commentIDs []string, userID string) ([]string, error) {
 	return nil, errors.New("not implemented in mock")
 }
 
+func (m *MockRepository) GetUserVote(ctx context.Context, commentID, userID string) (*models.Vote, error) {
+	if m.error != nil {
+		return nil, m.error
+	}
+
+	return m.votes[commentID+":"+userID], nil
+}
+
 func (m *MockRepository) GetCommentVotes(ctx context.Context, commentID string) ([]*models.Vote, error) {
 	return nil, errors.New("not implemented in mock")
 }
 
+func (m *MockRepository) GetVotesForComments(ctx context.Context, commentIDs []string) (map[string][]*models.Vote, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) GetUserVotes(ctx context.Context, userID string, filter *models.VoteFilter) ([]*models.VotedComment, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) GetVoteBreakdown(ctx context.Context, commentID string) (*models.VoteBreakdown, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
 func (m *MockRepository) GetCommentsWithUserVotes(ctx context.Context, rootID, userID string, filter *models.CommentFilter) ([]*models.Comment, map[string]*models.Vote, error) {
 	return nil, nil, errors.New("not implemented in mock")
 }
 
+func (m *MockRepository) GetUserVotesForComments(ctx context.Context, commentIDs []string, userID string) (map[string]*models.Vote, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) GetCommentTreeWithUserVotes(ctx context.Context, rootID, userID string, maxDepth int, sortBy string) ([]*models.CommentTree, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
 func (m *MockRepository) UpdateCommentScores(ctx context.Context, commentIDs []string) error {
 	return errors.New("not implemented in mock")
 }
 
+func (m *MockRepository) ImportComments(ctx context.Context, comments []*models.Comment) error {
+	return errors.New("not implemented in mock")
+}
+
 func (m *MockRepository) GetCommentStats(ctx context.Context, rootID string) (*models.CommentStats, error) {
 	return nil, errors.New("not implemented in mock")
 }
 
+func (m *MockRepository) GetRootVersion(ctx context.Context, rootID string) (*models.RootVersion, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) GetCommentStatsBatch(ctx context.Context, rootIDs []string) (map[string]*models.CommentStats, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
 func (m *MockRepository) GetUserCommentCount(ctx context.Context, userID string) (int64, error) {
 	return 0, errors.New("not implemented in mock")
 }
@@ -243,6 +319,66 @@ func (m *MockRepository) GetTopComments(ctx context.Context, rootID string, limi
 	return nil, errors.New("not implemented in mock")
 }
 
+func (m *MockRepository) GetTrendingRoots(ctx context.Context, timeRange string, limit int) ([]*models.TrendingRoot, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) GetUserReputation(ctx context.Context, userID string) (*models.UserReputation, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) AddBlock(ctx context.Context, blockerID, blockedID string) error {
+	return errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) RemoveBlock(ctx context.Context, blockerID, blockedID string) error {
+	return errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) GetBlockedUsers(ctx context.Context, blockerID string) ([]string, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) Subscribe(ctx context.Context, userID, rootID string) error {
+	return errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) Unsubscribe(ctx context.Context, userID, rootID string) error {
+	return errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) GetSubscriberUserIDs(ctx context.Context, rootID string) ([]string, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) GetRepliesToUser(ctx context.Context, userID string, filter *models.CommentFilter) ([]*models.Reply, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) GetTopCommenters(ctx context.Context, rootID string, limit int) ([]*models.TopCommenter, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) SetCommentStatus(ctx context.Context, id string, status models.CommentStatus) error {
+	return errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) GetModerationQueue(ctx context.Context, rootID string, filter *models.CommentFilter) ([]*models.Comment, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) GetRootSettings(ctx context.Context, rootID string) (*models.RootSettings, error) {
+	return nil, repository.ErrNotFound // No overrides
+}
+
+func (m *MockRepository) SetRootSettings(ctx context.Context, settings *models.RootSettings) error {
+	return errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) SetLinkPreview(ctx context.Context, id string, preview *models.LinkPreview) error {
+	return errors.New("not implemented in mock")
+}
+
 func (m *MockRepository) PurgeDeletedComments(ctx context.Context, olderThan int) (int64, error) {
 	return 0, errors.New("not implemented in mock")
 }
@@ -251,6 +387,18 @@ func (m *MockRepository) RecalculateCommentScores(ctx context.Context) error {
 	return errors.New("not implemented in mock")
 }
 
+func (m *MockRepository) RecalculateReplyCounts(ctx context.Context) error {
+	return errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) RepairCommentPaths(ctx context.Context, rootID string) (int64, error) {
+	return 0, errors.New("not implemented in mock")
+}
+
+func (m *MockRepository) RecalculateDecayedScores(ctx context.Context, halfLife time.Duration, now time.Time) error {
+	return errors.New("not implemented in mock")
+}
+
 func (m *MockRepository) BeginTx(ctx context.Context) (repository.Repository, error) {
 	return m, nil
 }
@@ -405,13 +553,21 @@ func TestVoteComment_Success(t *testing.T) {
 
 	// Vote on the comment
 	voterUserID := "user-456"
-	err = commentService.VoteComment(ctx, comment.ID, voterUserID, models.VoteTypeUp)
+	votedComment, vote, _, err := commentService.VoteComment(ctx, comment.ID, voterUserID, models.VoteTypeUp)
 
 	// Assert
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
 
+	// The returned comment already reflects the vote
+	if votedComment.Upvotes != 1 || votedComment.Score != 1 {
+		t.Errorf("Expected returned comment with 1 upvote and score 1, got upvotes %d score %d", votedComment.Upvotes, votedComment.Score)
+	}
+	if vote == nil || vote.UserID != voterUserID || vote.VoteType != models.VoteTypeUp {
+		t.Errorf("Expected stored upvote from %s, got %+v", voterUserID, vote)
+	}
+
 	// Verify vote was recorded (check updated comment)
 	updatedComment, err := commentService.GetComment(ctx, comment.ID)
 	if err != nil {
@@ -446,7 +602,7 @@ func TestVoteComment_SelfVote(t *testing.T) {
 	}
 
 	// Try to vote on own comment
-	err = commentService.VoteComment(ctx, comment.ID, comment.UserID, models.VoteTypeUp)
+	_, _, _, err = commentService.VoteComment(ctx, comment.ID, comment.UserID, models.VoteTypeUp)
 
 	// Assert
 	if err == nil {
@@ -454,6 +610,76 @@ func TestVoteComment_SelfVote(t *testing.T) {
 	}
 }
 
+// lookupCountingRepository counts comment lookups, locked or not, noting how
+// many were made before a vote was written
+type lookupCountingRepository struct {
+	*MockRepository
+	lookups           int
+	lookupsBeforeVote int
+}
+
+func (r *lookupCountingRepository) GetCommentByID(ctx context.Context, id string) (*models.Comment, error) {
+	r.lookups++
+	return r.MockRepository.GetCommentByID(ctx, id)
+}
+
+func (r *lookupCountingRepository) GetCommentForUpdate(ctx context.Context, id string) (*models.Comment, error) {
+	r.lookups++
+	return r.MockRepository.GetCommentByID(ctx, id)
+}
+
+func (r *lookupCountingRepository) BeginTx(ctx context.Context) (repository.Repository, error) {
+	return r, nil
+}
+
+func (r *lookupCountingRepository) CreateVote(ctx context.Context, vote *models.Vote) error {
+	r.lookupsBeforeVote = r.lookups
+	return r.MockRepository.CreateVote(ctx, vote)
+}
+
+func TestVoteComment_LooksUpCommentOnce(t *testing.T) {
+	// Setup
+	repo := &lookupCountingRepository{MockRepository: NewMockRepository()}
+	commentService := service.NewCommentService(repo)
+	ctx := context.Background()
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "test-root-1", UserID: "user-123", Content: "Test comment"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	for _, voteType := range []models.VoteType{models.VoteTypeUp, models.VoteTypeDown} {
+		repo.lookups, repo.lookupsBeforeVote = 0, 0
+
+		// Execute
+		_, _, _, err := commentService.VoteComment(ctx, comment.ID, "user-456", voteType)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		// The re-read after the vote picks up the new counts
+		if repo.lookupsBeforeVote != 1 {
+			t.Errorf("Expected 1 comment lookup before voting %s, got %d", voteType, repo.lookupsBeforeVote)
+		}
+	}
+
+	// Rejected votes stop after the single lookup
+	repo.lookups = 0
+	if _, _, _, err := commentService.VoteComment(ctx, comment.ID, comment.UserID, models.VoteTypeUp); !errors.Is(err, service.ErrSelfVote) {
+		t.Fatalf("Expected ErrSelfVote, got: %v", err)
+	}
+	if repo.lookups != 1 {
+		t.Errorf("Expected 1 comment lookup for a self-vote, got %d", repo.lookups)
+	}
+	repo.lookups = 0
+	if _, _, _, err := commentService.VoteComment(ctx, "missing", "user-456", models.VoteTypeUp); !errors.Is(err, service.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound for a missing comment, got: %v", err)
+	}
+	if repo.lookups != 1 {
+		t.Errorf("Expected 1 comment lookup for a missing comment, got %d", repo.lookups)
+	}
+}
+
 func TestUpdateComment_Success(t *testing.T) {
 	// Setup
 	mockRepo := NewMockRepository()
@@ -607,7 +833,7 @@ func BenchmarkVoteComment(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		voterID := "voter-" + string(rune(i))
-		err := commentService.VoteComment(ctx, comment.ID, voterID, models.VoteTypeUp)
+		_, _, _, err := commentService.VoteComment(ctx, comment.ID, voterID, models.VoteTypeUp)
 		if err != nil {
 			b.Fatalf("Benchmark failed: %v", err)
 		}
@@ -0,0 +1,47 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestGetCommentsByIDs_MixedSet(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	var ids []string
+	for _, content := range []string{"First", "Second", "Deleted"} {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: content})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		ids = append(ids, comment.ID)
+	}
+	if err := commentService.DeleteComment(ctx, ids[2], "author"); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+	requested := []string{ids[1], "missing-id", ids[2], ids[0]}
+
+	// Execute
+	comments, err := commentService.GetCommentsByIDs(ctx, requested, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	withDeleted, err := commentService.GetCommentsByIDs(ctx, requested, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if len(comments) != 2 || comments[0].ID != ids[1] || comments[1].ID != ids[0] {
+		t.Fatalf("Expected [second, first] in input order, got %d comments", len(comments))
+	}
+	if len(withDeleted) != 3 || withDeleted[1].ID != ids[2] || !withDeleted[1].IsDeleted {
+		t.Fatalf("Expected deleted comment flagged in position 2, got %d comments", len(withDeleted))
+	}
+}
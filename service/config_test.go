@@ -0,0 +1,233 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestCreateComment_MaxCommentDepth(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{MaxCommentDepth: 2})
+
+	var parentID *string
+	for depth := 0; depth <= 2; depth++ {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", ParentID: parentID, UserID: "author", Content: "Comment"})
+		if err != nil {
+			t.Fatalf("Failed to create comment at depth %d: %v", depth, err)
+		}
+		parentID = &comment.ID
+	}
+
+	// Execute
+	_, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", ParentID: parentID, UserID: "author", Content: "Too deep"})
+
+	// Assert
+	if !errors.Is(err, service.ErrMaxDepthExceeded) {
+		t.Fatalf("Expected ErrMaxDepthExceeded, got: %v", err)
+	}
+	if !errors.Is(err, service.ErrInvalidInput) {
+		t.Fatalf("Expected error to also match ErrInvalidInput, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "2") {
+		t.Fatalf("Expected configured limit in message, got: %v", err)
+	}
+}
+
+func TestGetCommentTree_MaxTreeDepth(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{MaxTreeDepth: 1})
+
+	var parentID *string
+	for depth := 0; depth <= 3; depth++ {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", ParentID: parentID, UserID: "author", Content: "Comment"})
+		if err != nil {
+			t.Fatalf("Failed to create comment at depth %d: %v", depth, err)
+		}
+		parentID = &comment.ID
+	}
+
+	// Execute
+	tree, err := commentService.GetCommentTree(ctx, "product-1", 10, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if len(tree) != 1 || len(tree[0].Children) != 1 || len(tree[0].Children[0].Children) != 0 {
+		t.Fatal("Expected the tree to be capped at depth 1")
+	}
+}
+
+func TestCreateComment_MinCommentLength(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{MinCommentLength: 3})
+
+	tests := []struct {
+		content string
+		wantErr bool
+	}{
+		{content: "hi", wantErr: true},
+		{content: "   hi   ", wantErr: true}, // Surrounding whitespace doesn't count
+		{content: "hello", wantErr: false},
+		{content: "👍👍", wantErr: true},   // 8 bytes but only 2 characters
+		{content: "👍👍👍", wantErr: false}, // Exactly the limit
+	}
+
+	for _, tt := range tests {
+		// Execute
+		_, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: tt.content})
+
+		// Assert
+		if !tt.wantErr {
+			if err != nil {
+				t.Errorf("Expected %q to be accepted, got: %v", tt.content, err)
+			}
+			continue
+		}
+		if !errors.Is(err, service.ErrContentTooShort) || !errors.Is(err, service.ErrInvalidInput) {
+			t.Errorf("Expected ErrContentTooShort for %q, got: %v", tt.content, err)
+		}
+	}
+}
+
+func TestUpdateComment_MinCommentLength(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{MinCommentLength: 3})
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "hello"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	content := "+1"
+
+	// Execute
+	err = commentService.UpdateComment(ctx, comment.ID, "author", &models.UpdateCommentRequest{Content: &content})
+
+	// Assert
+	if !errors.Is(err, service.ErrContentTooShort) {
+		t.Fatalf("Expected ErrContentTooShort, got: %v", err)
+	}
+}
+
+func TestMaxBatchSize_RejectsLargerBatches(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{MaxBatchSize: 2})
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Comment"})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		ids = append(ids, comment.ID)
+	}
+	votes := []models.VoteRequest{{CommentID: ids[0], UserID: "voter", VoteType: models.VoteTypeUp}, {CommentID: ids[1], UserID: "voter", VoteType: models.VoteTypeUp}, {CommentID: ids[2], UserID: "voter", VoteType: models.VoteTypeUp}}
+
+	cases := map[string]func(n int) error{
+		"comments by IDs": func(n int) error {
+			_, err := commentService.GetCommentsByIDs(ctx, ids[:n], false)
+			return err
+		},
+		"stats batch": func(n int) error {
+			_, err := commentService.GetCommentStatsBatch(ctx, []string{"product-1", "product-2", "product-3"}[:n])
+			return err
+		},
+		"batch votes": func(n int) error {
+			return commentService.BatchVoteComments(ctx, votes[:n], "voter")
+		},
+	}
+
+	for name, call := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Execute
+			atLimit := call(2)
+			overLimit := call(3)
+
+			// Assert
+			if errors.Is(atLimit, service.ErrBatchTooLarge) {
+				t.Errorf("Expected a batch of 2 to be allowed, got: %v", atLimit)
+			}
+			var tooLarge *service.BatchTooLargeError
+			if !errors.As(overLimit, &tooLarge) || tooLarge.Size != 3 || tooLarge.Limit != 2 {
+				t.Fatalf("Expected a BatchTooLargeError for 3 items over a limit of 2, got: %v", overLimit)
+			}
+			if !errors.Is(overLimit, service.ErrBatchTooLarge) || !errors.Is(overLimit, service.ErrInvalidInput) {
+				t.Errorf("Expected the error to match ErrBatchTooLarge and ErrInvalidInput, got: %v", overLimit)
+			}
+		})
+	}
+}
+
+func TestMaxBatchSize_SplitsVoteLookups(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{MaxBatchSize: 2})
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Comment"})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		if _, _, _, err := commentService.VoteComment(ctx, comment.ID, "voter", models.VoteTypeUp); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+		ids = append(ids, comment.ID)
+	}
+
+	// Execute
+	votes, err := commentService.GetUserVotesForComments(ctx, ids, "voter")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(votes) != 5 {
+		t.Fatalf("Expected all 5 votes across batches, got %d", len(votes))
+	}
+}
+
+func TestMaxPageSize_ClampsTopLimits(t *testing.T) {
+	// Setup: three users comment once each on three roots
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{MaxPageSize: 2})
+
+	for _, rootID := range []string{"product-1", "product-2", "product-3"} {
+		for _, userID := range []string{"alice", "bob", "carol"} {
+			if _, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: rootID, UserID: userID, Content: "Comment"}); err != nil {
+				t.Fatalf("Failed to create comment: %v", err)
+			}
+		}
+	}
+
+	for _, limit := range []int{2, 3} {
+		// Execute
+		top, err := commentService.GetTopComments(ctx, "product-1", limit, "all")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		trending, err := commentService.GetTrendingRoots(ctx, "all", limit)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		commenters, err := commentService.GetTopCommenters(ctx, "product-1", limit)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		// Assert
+		if len(top) != 2 || len(trending) != 2 || len(commenters) != 2 {
+			t.Errorf("Expected limit %d cut to 2 results each, got %d top comments, %d trending roots and %d commenters", limit, len(top), len(trending), len(commenters))
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestContentLength_CountsRunes(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	// 10000 characters but 30000 bytes
+	longest := strings.Repeat("字", 10000)
+
+	// Execute
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: longest})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected 10000 characters to be accepted on create, got: %v", err)
+	}
+	emoji := strings.Repeat("😀", 9999)
+	if err := commentService.UpdateComment(ctx, comment.ID, "alice", &models.UpdateCommentRequest{Content: &emoji}); err != nil {
+		t.Fatalf("Expected 9999 characters to be accepted on update, got: %v", err)
+	}
+
+	tooLong := longest + "字"
+	if _, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: tooLong}); !errors.Is(err, service.ErrInvalidInput) {
+		t.Fatalf("Expected 10001 characters to be rejected on create, got: %v", err)
+	}
+	if err := commentService.UpdateComment(ctx, comment.ID, "alice", &models.UpdateCommentRequest{Content: &tooLong}); !errors.Is(err, service.ErrInvalidInput) {
+		t.Fatalf("Expected 10001 characters to be rejected on update, got: %v", err)
+	}
+}
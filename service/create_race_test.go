@@ -0,0 +1,87 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/repository"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+// racingRepository runs race right after the first read of parentID, as if
+// another request changed the parent between CreateComment's validation and
+// its insert
+type racingRepository struct {
+	repository.CommentRepository
+	parentID string
+	race     func()
+}
+
+func (r *racingRepository) GetCommentByID(ctx context.Context, id string) (*models.Comment, error) {
+	comment, err := r.CommentRepository.GetCommentByID(ctx, id)
+	if id == r.parentID && r.race != nil {
+		race := r.race
+		r.race = nil
+		race()
+	}
+	return comment, err
+}
+
+func TestCreateComment_ParentChangedMidRequest(t *testing.T) {
+	ctx := context.Background()
+
+	cases := map[string]struct {
+		race    func(repo repository.CommentRepository, parentID string) error
+		wantErr error
+	}{
+		"parent deleted": {
+			race: func(repo repository.CommentRepository, parentID string) error {
+				return repo.DeleteComment(ctx, parentID, "alice", nil)
+			},
+			wantErr: service.ErrNotFound,
+		},
+		"parent rejected": {
+			race: func(repo repository.CommentRepository, parentID string) error {
+				return repo.SetCommentStatus(ctx, parentID, models.CommentStatusRejected)
+			},
+			wantErr: service.ErrInvalidInput,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			store := memory.NewMemoryRepository()
+			repo := &racingRepository{CommentRepository: store}
+			commentService := service.NewCommentService(repo)
+			parent, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Parent"})
+			if err != nil {
+				t.Fatalf("Failed to create parent: %v", err)
+			}
+			repo.parentID = parent.ID
+			repo.race = func() {
+				if err := tc.race(store, parent.ID); err != nil {
+					t.Errorf("Failed to change the parent: %v", err)
+				}
+			}
+
+			// Execute
+			_, err = commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: &parent.ID, UserID: "bob", Content: "Reply"})
+
+			// Assert
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Expected %v, got: %v", tc.wantErr, err)
+			}
+			bobs, err := store.GetCommentsByUserID(ctx, "bob", &models.CommentFilter{})
+			if err != nil {
+				t.Fatalf("Failed to list replies: %v", err)
+			}
+			if len(bobs) != 0 {
+				t.Errorf("Expected no reply to be stored, got %d", len(bobs))
+			}
+		})
+	}
+}
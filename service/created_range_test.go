@@ -0,0 +1,79 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestGetCommentsByRoot_CreatedRange(t *testing.T) {
+	// Setup: one comment a day for four days
+	ctx := context.Background()
+	repo := memory.NewMemoryRepository()
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return start.AddDate(0, 0, n) }
+	var comments []*models.Comment
+	for _, id := range []string{"day-0", "day-1", "day-2", "day-3"} {
+		comments = append(comments, &models.Comment{ID: id, RootID: "post-1", UserID: "alice", Content: "Daily note", Path: id, CreatedAt: day(len(comments))})
+	}
+	if err := repo.ImportComments(ctx, comments); err != nil {
+		t.Fatalf("Failed to import comments: %v", err)
+	}
+	commentService := service.NewCommentService(repo)
+	at := func(n int) *time.Time {
+		bound := day(n)
+		return &bound
+	}
+
+	tests := []struct {
+		name   string
+		filter *models.CommentFilter
+		want   []string
+	}{
+		{name: "after only", filter: &models.CommentFilter{CreatedAfter: at(2)}, want: []string{"day-2", "day-3"}},
+		{name: "before only", filter: &models.CommentFilter{CreatedBefore: at(1)}, want: []string{"day-0"}},
+		{name: "bounded", filter: &models.CommentFilter{CreatedAfter: at(1), CreatedBefore: at(3)}, want: []string{"day-1", "day-2"}},
+		{name: "empty range", filter: &models.CommentFilter{CreatedAfter: at(2), CreatedBefore: at(2)}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filter.SortOrder = "asc"
+
+			// Execute
+			got, err := commentService.GetCommentsByRoot(ctx, "post-1", tt.filter)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %v, got %d comments", tt.want, len(got))
+			}
+			for i, comment := range got {
+				if comment.ID != tt.want[i] {
+					t.Fatalf("Expected %v, got %s at %d", tt.want, comment.ID, i)
+				}
+			}
+		})
+	}
+}
+
+func TestGetCommentsByRoot_CreatedRangeReversed(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	after, before := time.Now(), time.Now().Add(-time.Hour)
+
+	// Execute
+	_, err := commentService.GetCommentsByRoot(context.Background(), "post-1", &models.CommentFilter{CreatedAfter: &after, CreatedBefore: &before})
+
+	// Assert
+	if !errors.Is(err, service.ErrInvalidInput) {
+		t.Fatalf("Expected ErrInvalidInput, got: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package service_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+// fakeClock returns a fixed time that tests can move forward
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestRecalculateDecayedScores_DecaysOldVotes(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Now()}
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	commentService.SetClock(clock)
+
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{
+		RootID:  "product-1",
+		UserID:  "author",
+		Content: "Aging comment",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	for _, voter := range []string{"voter-1", "voter-2"} {
+		if _, _, _, err := commentService.VoteComment(ctx, comment.ID, voter, models.VoteTypeUp); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+	}
+
+	halfLife := 7 * 24 * time.Hour
+
+	// Execute: recompute immediately, then two half-lives later
+	if err := commentService.RecalculateDecayedScores(ctx, halfLife); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	fresh, err := commentService.GetComment(ctx, comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+
+	clock.Advance(2 * halfLife)
+	if err := commentService.RecalculateDecayedScores(ctx, halfLife); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	aged, err := commentService.GetComment(ctx, comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+
+	// Assert
+	if fresh.DecayedScore == nil || math.Abs(*fresh.DecayedScore-2) > 0.01 {
+		t.Fatalf("Expected fresh decayed score of 2, got: %v", fresh.DecayedScore)
+	}
+	if aged.DecayedScore == nil || math.Abs(*aged.DecayedScore-0.5) > 0.01 {
+		t.Fatalf("Expected aged decayed score of 0.5, got: %v", aged.DecayedScore)
+	}
+	if aged.Score != 2 {
+		t.Fatalf("Expected raw score to stay 2, got: %d", aged.Score)
+	}
+}
+
+func TestGetCommentsByRoot_DecayedSort(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Now()}
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	commentService.SetClock(clock)
+
+	popular, _ := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Popular"})
+	quiet, _ := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Quiet"})
+	for _, voter := range []string{"voter-1", "voter-2", "voter-3"} {
+		commentService.VoteComment(ctx, popular.ID, voter, models.VoteTypeUp)
+	}
+	commentService.VoteComment(ctx, quiet.ID, "voter-1", models.VoteTypeDown)
+
+	// Execute
+	clock.Advance(30 * 24 * time.Hour)
+	if err := commentService.RecalculateDecayedScores(ctx, 0); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	comments, err := commentService.GetCommentsByRoot(ctx, "product-1", &models.CommentFilter{SortBy: "decayed"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if len(comments) != 2 || comments[0].ID != popular.ID {
+		t.Fatalf("Expected popular comment first under decayed sort, got %d comments", len(comments))
+	}
+	if *comments[0].DecayedScore >= 3 {
+		t.Fatalf("Expected decay to reduce the effective score below 3, got: %f", *comments[0].DecayedScore)
+	}
+}
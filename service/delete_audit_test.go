@@ -0,0 +1,102 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestDeleteAudit_AuthorAndModerator(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	var ids []string
+	for _, author := range []string{"alice", "alice", "bob"} {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: author, Content: "Hello from " + author})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		ids = append(ids, comment.ID)
+	}
+
+	// Execute
+	if err := commentService.DeleteComment(ctx, ids[0], "alice"); err != nil {
+		t.Fatalf("Failed to delete without a reason: %v", err)
+	}
+	if err := commentService.DeleteCommentWithReason(ctx, ids[1], "alice", "  Posted in the wrong thread "); err != nil {
+		t.Fatalf("Failed to delete with a reason: %v", err)
+	}
+	removed, err := commentService.RemoveComment(ctx, ids[2], "mod-1", "Spam")
+	if err != nil {
+		t.Fatalf("Failed to remove comment: %v", err)
+	}
+
+	// Assert
+	if !removed.IsDeleted || removed.DeletedBy == nil || *removed.DeletedBy != "mod-1" || removed.DeleteReason == nil || *removed.DeleteReason != "Spam" {
+		t.Errorf("Expected the removal to return the moderator and reason, got %+v", removed)
+	}
+
+	comments, err := commentService.GetCommentsByIDs(service.WithModerator(ctx), ids, true)
+	if err != nil {
+		t.Fatalf("Failed to get comments: %v", err)
+	}
+	want := []struct{ deletedBy, reason string }{
+		{"alice", ""},
+		{"alice", "Posted in the wrong thread"},
+		{"mod-1", "Spam"},
+	}
+	for i, comment := range comments {
+		if comment.DeletedBy == nil || *comment.DeletedBy != want[i].deletedBy {
+			t.Errorf("Comment %d: expected DeletedBy %q, got %v", i, want[i].deletedBy, comment.DeletedBy)
+		}
+		reason := ""
+		if comment.DeleteReason != nil {
+			reason = *comment.DeleteReason
+		}
+		if reason != want[i].reason {
+			t.Errorf("Comment %d: expected DeleteReason %q, got %q", i, want[i].reason, reason)
+		}
+	}
+
+	// Everyone else sees the deletions without the audit fields
+	public, err := commentService.GetCommentsByIDs(ctx, ids, true)
+	if err != nil {
+		t.Fatalf("Failed to get comments: %v", err)
+	}
+	for i, comment := range public {
+		if !comment.IsDeleted || comment.DeletedBy != nil || comment.DeleteReason != nil {
+			t.Errorf("Comment %d: expected the audit fields hidden from non-moderators, got %+v", i, comment)
+		}
+	}
+}
+
+func TestDeleteAudit_Validation(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Hello"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Execute
+	longErr := commentService.DeleteCommentWithReason(ctx, comment.ID, "alice", strings.Repeat("x", 501))
+	_, noModeratorErr := commentService.RemoveComment(ctx, comment.ID, "", "Spam")
+	_, missingErr := commentService.RemoveComment(ctx, "missing", "mod-1", "")
+
+	// Assert
+	if !errors.Is(longErr, service.ErrInvalidInput) {
+		t.Errorf("Expected ErrInvalidInput for an overlong reason, got %v", longErr)
+	}
+	if !errors.Is(noModeratorErr, service.ErrInvalidInput) {
+		t.Errorf("Expected ErrInvalidInput without a moderator, got %v", noModeratorErr)
+	}
+	if !errors.Is(missingErr, service.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing comment, got %v", missingErr)
+	}
+}
@@ -0,0 +1,62 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestGetComment_DeletedVisibility(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	const reason = "Posted in the wrong thread"
+
+	cases := map[string]struct {
+		authorsSeeDeleted bool
+		ctx               context.Context
+		wantFound         bool
+	}{
+		"author":             {authorsSeeDeleted: true, ctx: service.WithViewer(ctx, "alice"), wantFound: true},
+		"moderator":          {authorsSeeDeleted: true, ctx: service.WithModerator(ctx), wantFound: true},
+		"anonymous":          {authorsSeeDeleted: true, ctx: ctx},
+		"other user":         {authorsSeeDeleted: true, ctx: service.WithViewer(ctx, "bob")},
+		"author, policy off": {ctx: service.WithViewer(ctx, "alice")},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{AuthorsSeeDeleted: tc.authorsSeeDeleted})
+			created, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Soon gone"})
+			if err != nil {
+				t.Fatalf("Failed to create comment: %v", err)
+			}
+			if err := commentService.DeleteCommentWithReason(ctx, created.ID, "alice", reason); err != nil {
+				t.Fatalf("Failed to delete comment: %v", err)
+			}
+
+			// Execute
+			comment, err := commentService.GetComment(tc.ctx, created.ID)
+
+			// Assert
+			if !tc.wantFound {
+				if !errors.Is(err, service.ErrNotFound) {
+					t.Fatalf("Expected ErrNotFound, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected the deleted comment, got: %v", err)
+			}
+			if !comment.IsDeleted || comment.ID != created.ID {
+				t.Errorf("Expected the comment marked deleted, got is_deleted=%v", comment.IsDeleted)
+			}
+			if moderator := service.IsModerator(tc.ctx); (comment.DeleteReason != nil) != moderator {
+				t.Errorf("Expected the delete reason only for moderators, got %v", comment.DeleteReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestGetDirectChildren(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	parent, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Parent"})
+	if err != nil {
+		t.Fatalf("Failed to create parent: %v", err)
+	}
+	children := map[string]bool{}
+	var firstChild *models.Comment
+	for i := 0; i < 3; i++ {
+		child, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", ParentID: &parent.ID, UserID: "author", Content: "Child"})
+		if err != nil {
+			t.Fatalf("Failed to create child: %v", err)
+		}
+		children[child.ID] = true
+		if firstChild == nil {
+			firstChild = child
+		}
+	}
+	_, err = commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", ParentID: &firstChild.ID, UserID: "author", Content: "Grandchild"})
+	if err != nil {
+		t.Fatalf("Failed to create grandchild: %v", err)
+	}
+
+	// Execute
+	replies, err := commentService.GetDirectChildren(ctx, parent.ID, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if len(replies) != 3 {
+		t.Fatalf("Expected 3 direct children, got: %d", len(replies))
+	}
+	for _, reply := range replies {
+		if !children[reply.ID] || reply.Depth != parent.Depth+1 {
+			t.Fatalf("Expected only direct children, got: %+v", reply)
+		}
+	}
+
+	// Pagination applies to the single level
+	limit, offset := 2, 2
+	page, err := commentService.GetDirectChildren(ctx, parent.ID, &models.CommentFilter{Limit: &limit, Offset: &offset})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(page) != 1 {
+		t.Fatalf("Expected 1 child on the second page, got: %d", len(page))
+	}
+}
+
+func TestGetDirectChildren_ParentNotFound(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	// Execute
+	_, err := commentService.GetDirectChildren(context.Background(), "missing", nil)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for missing parent, got nil")
+	}
+}
+
+func TestGetCommentChildren_Pagination(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	parent, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Parent"})
+	if err != nil {
+		t.Fatalf("Failed to create parent: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		_, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", ParentID: &parent.ID, UserID: "author", Content: "Child"})
+		if err != nil {
+			t.Fatalf("Failed to create child: %v", err)
+		}
+	}
+
+	all := 1000
+	everything, err := commentService.GetCommentChildren(ctx, parent.ID, 1, &models.CommentFilter{Limit: &all})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(everything) != 100 {
+		t.Fatalf("Expected 100 children, got: %d", len(everything))
+	}
+
+	// Execute
+	limit, offset := 20, 40
+	page, err := commentService.GetCommentChildren(ctx, parent.ID, 1, &models.CommentFilter{Limit: &limit, Offset: &offset})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if len(page) != 20 {
+		t.Fatalf("Expected 20 children, got: %d", len(page))
+	}
+	for i, comment := range page {
+		if comment.ID != everything[offset+i].ID {
+			t.Fatalf("Expected page to match path order at %d, got: %s", offset+i, comment.ID)
+		}
+	}
+
+	// Default limit keeps the response bounded
+	defaultPage, err := commentService.GetCommentChildren(ctx, parent.ID, 1, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(defaultPage) != 50 {
+		t.Fatalf("Expected default page of 50, got: %d", len(defaultPage))
+	}
+}
+
+func TestGetCommentChildren_UnderscoreIDMatchesOnlyItsSubtree(t *testing.T) {
+	// Setup: "thread_1" would match "threadX1" as a LIKE pattern
+	ctx := context.Background()
+	repo := memory.NewMemoryRepository()
+	thread, sibling := "thread_1", "threadX1"
+	err := repo.ImportComments(ctx, []*models.Comment{
+		{ID: thread, RootID: "product-1", UserID: "author", Content: "Thread", Path: thread},
+		{ID: sibling, RootID: "product-1", UserID: "author", Content: "Sibling", Path: sibling},
+		{ID: "reply-a", RootID: "product-1", ParentID: &thread, UserID: "author", Content: "Reply", Path: thread + ".reply-a", Depth: 1},
+		{ID: "reply-b", RootID: "product-1", ParentID: &sibling, UserID: "author", Content: "Sibling reply", Path: sibling + ".reply-b", Depth: 1},
+	})
+	if err != nil {
+		t.Fatalf("Failed to import comments: %v", err)
+	}
+	commentService := service.NewCommentService(repo)
+
+	// Execute
+	children, err := commentService.GetCommentChildren(ctx, thread, 10, nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(children) != 1 || children[0].ID != "reply-a" {
+		t.Fatalf("Expected only the thread's own reply, got: %+v", children)
+	}
+}
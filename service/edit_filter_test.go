@@ -0,0 +1,97 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+// seedEdits creates three comments on post-1 edited zero, two and one times,
+// returned in that order
+func seedEdits(t *testing.T, commentService *service.CommentService) []*models.Comment {
+	t.Helper()
+	ctx := context.Background()
+	var comments []*models.Comment
+	for i, edits := range []int{0, 2, 1} {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: fmt.Sprintf("Comment %d", i)})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		for edit := 1; edit <= edits; edit++ {
+			content := fmt.Sprintf("Comment %d, edit %d", i, edit)
+			if err := commentService.UpdateComment(ctx, comment.ID, "alice", &models.UpdateCommentRequest{Content: &content}); err != nil {
+				t.Fatalf("Failed to edit comment: %v", err)
+			}
+		}
+		comments = append(comments, comment)
+	}
+	return comments
+}
+
+func TestGetCommentsByRoot_SortByEditCount(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	seeded := seedEdits(t, commentService)
+
+	// Execute
+	comments, err := commentService.GetCommentsByRoot(ctx, "post-1", &models.CommentFilter{SortBy: "edit_count", SortOrder: "desc"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to get comments: %v", err)
+	}
+	want := []string{seeded[1].ID, seeded[2].ID, seeded[0].ID}
+	if len(comments) != len(want) {
+		t.Fatalf("Expected %d comments, got %d", len(want), len(comments))
+	}
+	for i, comment := range comments {
+		if comment.ID != want[i] {
+			t.Errorf("Expected comment %d to have %d edits, got %d", i, 2-i, comment.EditCount)
+		}
+	}
+}
+
+func TestEditFilters(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	seedEdits(t, commentService)
+	edited, two := true, 2
+
+	cases := map[string]struct {
+		filter models.CommentFilter
+		want   int
+	}{
+		"edited only":         {filter: models.CommentFilter{IsEdited: &edited}, want: 2},
+		"at least two":        {filter: models.CommentFilter{MinEdits: &two}, want: 1},
+		"at most two":         {filter: models.CommentFilter{MaxEdits: &two}, want: 3},
+		"edited, at most two": {filter: models.CommentFilter{IsEdited: &edited, MaxEdits: &two}, want: 2},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Execute
+			byRoot, err := commentService.GetCommentsByRoot(ctx, "post-1", &tc.filter)
+			if err != nil {
+				t.Fatalf("Failed to get comments: %v", err)
+			}
+			withVotes, _, err := commentService.GetCommentsWithUserVotes(ctx, "post-1", "bob", &tc.filter)
+			if err != nil {
+				t.Fatalf("Failed to get comments with votes: %v", err)
+			}
+
+			// Assert
+			if len(byRoot) != tc.want {
+				t.Errorf("Expected %d comments, got %d", tc.want, len(byRoot))
+			}
+			if len(withVotes) != tc.want {
+				t.Errorf("Expected %d comments with votes, got %d", tc.want, len(withVotes))
+			}
+		})
+	}
+}
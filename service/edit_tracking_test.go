@@ -0,0 +1,70 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestUpdateComment_EditTracking(t *testing.T) {
+	newContent := "Edited text"
+	newMedia := "https://example.com/new.png"
+
+	cases := map[string]struct {
+		update     models.UpdateCommentRequest
+		wantEdited bool
+	}{
+		"media only":   {update: models.UpdateCommentRequest{MediaURL: &newMedia}, wantEdited: false},
+		"content only": {update: models.UpdateCommentRequest{Content: &newContent}, wantEdited: true},
+		"both":         {update: models.UpdateCommentRequest{Content: &newContent, MediaURL: &newMedia}, wantEdited: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			ctx := context.Background()
+			commentService := service.NewCommentService(memory.NewMemoryRepository())
+			media := "https://example.com/old.png"
+			created, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Original text", MediaURL: &media})
+			if err != nil {
+				t.Fatalf("Failed to create comment: %v", err)
+			}
+			time.Sleep(time.Millisecond)
+
+			// Execute
+			update := tc.update
+			err = commentService.UpdateComment(ctx, created.ID, "alice", &update)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			updated, err := commentService.GetComment(ctx, created.ID)
+			if err != nil {
+				t.Fatalf("Failed to get comment: %v", err)
+			}
+			if !updated.UpdatedAt.After(created.UpdatedAt) {
+				t.Errorf("Expected updated_at to move past %v, got %v", created.UpdatedAt, updated.UpdatedAt)
+			}
+			if tc.update.MediaURL != nil && (updated.MediaURL == nil || *updated.MediaURL != newMedia) {
+				t.Errorf("Expected the new media URL, got: %v", updated.MediaURL)
+			}
+			if !tc.wantEdited {
+				if updated.IsEdited || updated.EditCount != 0 || updated.ContentUpdatedAt != nil || updated.OriginalContent != nil {
+					t.Errorf("Expected no edit tracked, got is_edited=%v edit_count=%d content_updated_at=%v", updated.IsEdited, updated.EditCount, updated.ContentUpdatedAt)
+				}
+				return
+			}
+			if !updated.IsEdited || updated.EditCount != 1 || updated.ContentUpdatedAt == nil {
+				t.Errorf("Expected one tracked edit, got is_edited=%v edit_count=%d content_updated_at=%v", updated.IsEdited, updated.EditCount, updated.ContentUpdatedAt)
+			}
+			if updated.OriginalContent == nil || *updated.OriginalContent != "Original text" {
+				t.Errorf("Expected the original content kept, got: %v", updated.OriginalContent)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/christopher18/commentific/v2/repository"
+	"github.com/go-playground/validator/v10"
+)
+
+// Typed errors returned by CommentService. Use errors.Is to classify a failure
+// when mapping it onto a transport status (HTTP, gRPC, ...).
+var (
+	// ErrNotFound indicates the referenced comment does not exist or was deleted
+	ErrNotFound = repository.ErrNotFound
+	// ErrInvalidInput indicates the caller supplied a missing or malformed argument
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrNotAuthorized indicates the user may not perform the operation
+	ErrNotAuthorized = errors.New("user not authorized")
+	// ErrSelfVote indicates a user attempted to vote on their own comment
+	ErrSelfVote = errors.New("users cannot vote on their own comments")
+	// ErrHasReplies indicates a comment can't be hard deleted while replies to it exist
+	ErrHasReplies = repository.ErrHasReplies
+	// ErrVersionConflict indicates an update was based on an outdated version of the comment
+	ErrVersionConflict = repository.ErrVersionConflict
+	// ErrInvalidID indicates a supplied comment ID contains the path separator
+	ErrInvalidID = repository.ErrInvalidID
+	// ErrMaxDepthExceeded indicates a reply would nest deeper than the configured limit
+	ErrMaxDepthExceeded = errors.New("maximum comment depth exceeded")
+	// ErrContentTooShort indicates content is shorter than the configured minimum
+	ErrContentTooShort = errors.New("comment content too short")
+	// ErrTooManyLines indicates content has more lines, or consecutive blank lines, than configured
+	ErrTooManyLines = errors.New("comment content has too many lines")
+	// ErrLinkPreviewFailed indicates a comment's link could not be previewed
+	ErrLinkPreviewFailed = errors.New("link preview failed")
+	// ErrRootLocked indicates a root's settings lock it against new comments
+	ErrRootLocked = errors.New("root is locked for new comments")
+	// ErrTimeout indicates a read ran past CommentServiceConfig.QueryTimeout or the caller's deadline
+	ErrTimeout = errors.New("operation timed out")
+	// ErrBatchTooLarge indicates a batch call carried more items than CommentServiceConfig.MaxBatchSize
+	ErrBatchTooLarge = errors.New("batch too large")
+)
+
+// InputError describes a rejected argument. It matches ErrInvalidInput via
+// errors.Is while keeping its own human-readable message.
+type InputError struct {
+	Message string
+	Fields  []FieldError // The rejected fields, when known
+	Err     error        // Underlying cause, if any (e.g. validator.ValidationErrors)
+}
+
+func (e *InputError) Error() string {
+	return e.Message
+}
+
+// Is reports whether the target is ErrInvalidInput
+func (e *InputError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+// Unwrap returns the underlying cause
+func (e *InputError) Unwrap() error {
+	return e.Err
+}
+
+// MaxDepthError reports a reply nested past CommentServiceConfig.MaxCommentDepth.
+// It matches both ErrMaxDepthExceeded and ErrInvalidInput via errors.Is.
+type MaxDepthError struct {
+	Limit int
+}
+
+func (e *MaxDepthError) Error() string {
+	return fmt.Sprintf("%s (limit %d)", ErrMaxDepthExceeded.Error(), e.Limit)
+}
+
+// Is reports whether the target is ErrMaxDepthExceeded or ErrInvalidInput
+func (e *MaxDepthError) Is(target error) bool {
+	return target == ErrMaxDepthExceeded || target == ErrInvalidInput
+}
+
+// ContentTooShortError reports content with fewer characters than
+// CommentServiceConfig.MinCommentLength. It matches both ErrContentTooShort
+// and ErrInvalidInput via errors.Is.
+type ContentTooShortError struct {
+	Limit int
+}
+
+func (e *ContentTooShortError) Error() string {
+	return fmt.Sprintf("%s (minimum %d characters)", ErrContentTooShort.Error(), e.Limit)
+}
+
+// Is reports whether the target is ErrContentTooShort or ErrInvalidInput
+func (e *ContentTooShortError) Is(target error) bool {
+	return target == ErrContentTooShort || target == ErrInvalidInput
+}
+
+// TooManyLinesError reports content with more lines than
+// CommentServiceConfig.MaxLines, or, when Blank is set, a run of more blank
+// lines than MaxBlankLines. It matches both ErrTooManyLines and
+// ErrInvalidInput via errors.Is.
+type TooManyLinesError struct {
+	Lines int
+	Limit int
+	Blank bool
+}
+
+func (e *TooManyLinesError) Error() string {
+	if e.Blank {
+		return fmt.Sprintf("%s: %d consecutive blank lines (limit %d)", ErrTooManyLines.Error(), e.Lines, e.Limit)
+	}
+	return fmt.Sprintf("%s: %d lines (limit %d)", ErrTooManyLines.Error(), e.Lines, e.Limit)
+}
+
+// Is reports whether the target is ErrTooManyLines or ErrInvalidInput
+func (e *TooManyLinesError) Is(target error) bool {
+	return target == ErrTooManyLines || target == ErrInvalidInput
+}
+
+// BatchTooLargeError reports a batch call carrying more items than
+// CommentServiceConfig.MaxBatchSize. It matches both ErrBatchTooLarge and
+// ErrInvalidInput via errors.Is.
+type BatchTooLargeError struct {
+	Size  int
+	Limit int
+}
+
+func (e *BatchTooLargeError) Error() string {
+	return fmt.Sprintf("%s: %d items (limit %d)", ErrBatchTooLarge.Error(), e.Size, e.Limit)
+}
+
+// Is reports whether the target is ErrBatchTooLarge or ErrInvalidInput
+func (e *BatchTooLargeError) Is(target error) bool {
+	return target == ErrBatchTooLarge || target == ErrInvalidInput
+}
+
+// invalidInput builds an InputError from a format string
+func invalidInput(format string, args ...interface{}) error {
+	return &InputError{Message: fmt.Sprintf(format, args...)}
+}
+
+// invalidField builds an InputError for one rejected field
+func invalidField(field, rule, format string, args ...interface{}) error {
+	message := fmt.Sprintf(format, args...)
+	return &InputError{Message: message, Fields: []FieldError{{Field: field, Rule: rule, Message: message}}}
+}
+
+// validationFailed wraps a struct validation error as an InputError. The
+// validator's failures become Fields, and their messages the Message.
+func validationFailed(err error) error {
+	inputErr := &InputError{Message: "validation failed: " + err.Error(), Err: err}
+	var errs validator.ValidationErrors
+	if errors.As(err, &errs) {
+		inputErr.Fields = validationFieldErrors(errs)
+		messages := make([]string, len(inputErr.Fields))
+		for i, field := range inputErr.Fields {
+			messages[i] = field.Message
+		}
+		inputErr.Message = "validation failed: " + strings.Join(messages, "; ")
+	}
+	return inputErr
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+// EventEmitter receives comment events after changes are stored. Emit is
+// called synchronously on the request path, so implementations should hand
+// events off rather than block.
+type EventEmitter interface {
+	Emit(ctx context.Context, event *models.CommentEvent)
+}
+
+// AddEventEmitter registers an emitter to receive comment events
+func (s *CommentService) AddEventEmitter(emitter EventEmitter) {
+	s.emittersMu.Lock()
+	defer s.emittersMu.Unlock()
+
+	s.emitters = append(s.emitters, emitter)
+}
+
+// hasEmitters reports whether anyone is listening, so callers can skip the
+// extra reads needed to build an event
+func (s *CommentService) hasEmitters() bool {
+	s.emittersMu.RLock()
+	defer s.emittersMu.RUnlock()
+
+	return len(s.emitters) > 0
+}
+
+// emit delivers an event to every registered emitter
+func (s *CommentService) emit(ctx context.Context, event *models.CommentEvent) {
+	s.emittersMu.RLock()
+	emitters := s.emitters
+	s.emittersMu.RUnlock()
+
+	event.OccurredAt = time.Now()
+	for _, emitter := range emitters {
+		emitter.Emit(ctx, event)
+	}
+}
+
+// emitCommentEvent re-reads a comment and emits its current state
+func (s *CommentService) emitCommentEvent(ctx context.Context, eventType models.EventType, commentID, userID string, voteType *models.VoteType) {
+	if !s.hasEmitters() {
+		return
+	}
+
+	comment, err := s.repo.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return
+	}
+	// Listeners never saw a comment that is awaiting moderation
+	if comment.Status != models.CommentStatusApproved {
+		return
+	}
+
+	s.emit(ctx, &models.CommentEvent{
+		Type:      eventType,
+		RootID:    comment.RootID,
+		CommentID: comment.ID,
+		UserID:    userID,
+		Comment:   comment,
+		VoteType:  voteType,
+	})
+}
@@ -0,0 +1,128 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestExportRoot_RoundTripsThroughImport(t *testing.T) {
+	// Setup: a -> b -> c and a -> d, with b soft deleted and votes on a
+	ctx := context.Background()
+	source := service.NewCommentService(memory.NewMemoryRepository())
+
+	a, err := source.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Top"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	b, err := source.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: &a.ID, UserID: "bob", Content: "Reply"})
+	if err != nil {
+		t.Fatalf("Failed to create reply: %v", err)
+	}
+	c, err := source.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: &b.ID, UserID: "carol", Content: "Nested"})
+	if err != nil {
+		t.Fatalf("Failed to create nested reply: %v", err)
+	}
+	d, err := source.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: &a.ID, UserID: "dave", Content: "Sibling"})
+	if err != nil {
+		t.Fatalf("Failed to create sibling reply: %v", err)
+	}
+	for _, voter := range []string{"bob", "carol"} {
+		if _, _, _, err := source.VoteComment(ctx, a.ID, voter, models.VoteTypeUp); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+	}
+	if err := source.DeleteComment(ctx, b.ID, "bob"); err != nil {
+		t.Fatalf("Failed to delete reply: %v", err)
+	}
+
+	// Execute
+	export, err := source.ExportRoot(ctx, "post-1", service.ExportOptions{IncludeVotes: true, IncludeDeleted: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	target := service.NewCommentService(memory.NewMemoryRepository())
+	comments := make([]*models.Comment, len(export.Comments))
+	for i, exported := range export.Comments {
+		comments[i] = exported.Comment
+	}
+	if err := target.ImportComments(ctx, comments); err != nil {
+		t.Fatalf("Failed to re-import export: %v", err)
+	}
+
+	// Assert
+	if len(export.Comments) != 4 {
+		t.Fatalf("Expected 4 exported comments, got: %d", len(export.Comments))
+	}
+	seen := map[string]bool{}
+	for _, exported := range export.Comments {
+		if exported.ParentID != nil && !seen[*exported.ParentID] {
+			t.Fatalf("Expected parent %s to be exported before %s", *exported.ParentID, exported.ID)
+		}
+		seen[exported.ID] = true
+	}
+	if export.Comments[0].ID != a.ID || len(export.Comments[0].Votes) != 2 {
+		t.Fatalf("Expected top-level comment first with 2 votes, got: %+v", export.Comments[0])
+	}
+
+	imported, err := target.GetCommentsByIDs(ctx, []string{a.ID, b.ID, c.ID, d.ID}, true)
+	if err != nil {
+		t.Fatalf("Failed to read imported comments: %v", err)
+	}
+	if len(imported) != 4 {
+		t.Fatalf("Expected 4 imported comments, got: %d", len(imported))
+	}
+	originals := map[string]*models.Comment{a.ID: a, b.ID: b, c.ID: c, d.ID: d}
+	for _, comment := range imported {
+		original := originals[comment.ID]
+		if comment.Path != original.Path || comment.Content != original.Content {
+			t.Fatalf("Expected imported comment to match original, got: %+v", comment)
+		}
+		if comment.IsDeleted != (comment.ID == b.ID) {
+			t.Fatalf("Expected only %s to stay deleted, got: %+v", b.ID, comment)
+		}
+	}
+	top, err := target.GetComment(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("Failed to get imported top-level comment: %v", err)
+	}
+	if top.Score != 2 {
+		t.Fatalf("Expected score 2, got: %d", top.Score)
+	}
+}
+
+func TestExportRoot_SkipsDeletedByDefault(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	kept, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Kept"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	removed, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "bob", Content: "Removed"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	if err := commentService.DeleteComment(ctx, removed.ID, "bob"); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+
+	// Execute
+	export, err := commentService.ExportRoot(ctx, "post-1", service.ExportOptions{})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(export.Comments) != 1 || export.Comments[0].ID != kept.ID {
+		t.Fatalf("Expected only the live comment, got: %d comments", len(export.Comments))
+	}
+	if export.Comments[0].Votes != nil {
+		t.Fatalf("Expected no votes without IncludeVotes, got: %v", export.Comments[0].Votes)
+	}
+}
@@ -0,0 +1,123 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestHardDeleteComment_Leaf(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	repo := memory.NewMemoryRepository()
+	commentService := service.NewCommentService(repo)
+
+	parent, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Parent"})
+	if err != nil {
+		t.Fatalf("Failed to create parent: %v", err)
+	}
+	leaf, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", ParentID: &parent.ID, UserID: "replier", Content: "Leaf"})
+	if err != nil {
+		t.Fatalf("Failed to create leaf: %v", err)
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, leaf.ID, "voter", models.VoteTypeUp); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	// Execute
+	err = commentService.HardDeleteComment(ctx, leaf.ID, "replier")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	remaining, err := commentService.GetCommentsByIDs(ctx, []string{leaf.ID}, true)
+	if err != nil {
+		t.Fatalf("Failed to look up comment: %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Fatal("Expected the comment to be gone even when including deleted comments")
+	}
+	if vote, _ := repo.GetUserVote(ctx, leaf.ID, "voter"); vote != nil {
+		t.Fatal("Expected the comment's votes to be removed")
+	}
+	updatedParent, _ := commentService.GetComment(ctx, parent.ID)
+	if updatedParent.ReplyCount != 0 || updatedParent.DescendantCount != 0 {
+		t.Fatalf("Expected parent counts to drop, got: %d replies, %d descendants", updatedParent.ReplyCount, updatedParent.DescendantCount)
+	}
+}
+
+func TestHardDeleteComment_ParentWithReplies(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	parent, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Parent"})
+	if err != nil {
+		t.Fatalf("Failed to create parent: %v", err)
+	}
+	reply, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", ParentID: &parent.ID, UserID: "replier", Content: "Reply"})
+	if err != nil {
+		t.Fatalf("Failed to create reply: %v", err)
+	}
+	// Soft-deleted replies still block erasure
+	if err := commentService.DeleteComment(ctx, reply.ID, "replier"); err != nil {
+		t.Fatalf("Failed to soft delete reply: %v", err)
+	}
+
+	// Execute
+	err = commentService.HardDeleteComment(ctx, parent.ID, "author")
+
+	// Assert
+	if !errors.Is(err, service.ErrHasReplies) {
+		t.Fatalf("Expected ErrHasReplies, got: %v", err)
+	}
+	if _, err := commentService.GetComment(ctx, parent.ID); err != nil {
+		t.Fatalf("Expected parent to remain, got: %v", err)
+	}
+}
+
+func TestHardDeleteComment_NotAuthor(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Mine"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Execute
+	err = commentService.HardDeleteComment(ctx, comment.ID, "someone-else")
+
+	// Assert
+	if !errors.Is(err, service.ErrNotAuthorized) {
+		t.Fatalf("Expected ErrNotAuthorized, got: %v", err)
+	}
+}
+
+func TestHardDeleteComment_Moderator(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Erase me"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Execute
+	err = commentService.HardDeleteComment(service.WithModerator(ctx), comment.ID, "moderator")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := commentService.GetComment(ctx, comment.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Fatalf("Expected the comment to be gone, got: %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+// DefaultIdempotencyTTL is how long an idempotency key is remembered when
+// CommentServiceConfig.IdempotencyTTL is unset
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// IdempotencyStore remembers which comment each idempotency key created. The
+// default keeps keys in memory, so replicas behind a load balancer should
+// share a store backed by Redis or the database through SetIdempotencyStore.
+type IdempotencyStore interface {
+	// Get returns the comment ID stored for key, or "" when there is none or
+	// it has expired
+	Get(ctx context.Context, key string) (string, error)
+	// Put stores commentID for key until ttl has passed
+	Put(ctx context.Context, key, commentID string, ttl time.Duration) error
+}
+
+// SetIdempotencyStore replaces the store used by CreateCommentIdempotent
+func (s *CommentService) SetIdempotencyStore(store IdempotencyStore) {
+	s.idempotency = store
+}
+
+// CreateCommentIdempotent creates a comment at most once per idempotency key
+// and user, so a client can safely retry a create whose response it never
+// saw. A repeat of a key returns the comment the first request created, with
+// replayed set, instead of creating another. An empty key creates as usual,
+// and so does any key from a guest without a token, who has no identity to
+// scope it to until CreateComment issues one.
+func (s *CommentService) CreateCommentIdempotent(ctx context.Context, req *models.CreateCommentRequest, key string) (_ *models.Comment, replayed bool, err error) {
+	if key == "" || (req.Anonymous && req.UserID == "") {
+		comment, err := s.CreateComment(ctx, req)
+		return comment, false, err
+	}
+	if len(key) > 255 {
+		return nil, false, invalidInput("idempotency key must be at most 255 characters")
+	}
+
+	// Keys are per user, so one user's key can never return another's comment
+	scoped := req.UserID + "\x00" + key
+	unlock := s.idempotencyLocks.lock(scoped)
+	defer unlock()
+
+	commentID, err := s.idempotency.Get(ctx, scoped)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to look up idempotency key: %w", err)
+	}
+	if commentID != "" {
+		comment, err := s.repo.GetCommentByID(ctx, commentID)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get comment created with idempotency key: %w", err)
+		}
+		s.renderContent(comment)
+		return comment, true, nil
+	}
+
+	comment, err := s.CreateComment(ctx, req)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.idempotency.Put(ctx, scoped, comment.ID, s.config.IdempotencyTTL); err != nil {
+		// The comment exists either way; a retry would just create it again
+		s.logger.WarnContext(ctx, "failed to store idempotency key", "method", "CommentService.CreateCommentIdempotent",
+			"comment_id", comment.ID, "error", err)
+	}
+	return comment, false, nil
+}
+
+// memoryIdempotencyStore is the default IdempotencyStore, good for a single
+// instance
+type memoryIdempotencyStore struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]idempotencyEntry
+}
+
+type idempotencyEntry struct {
+	commentID string
+	expires   time.Time
+}
+
+func newMemoryIdempotencyStore(now func() time.Time) *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{now: now, entries: make(map[string]idempotencyEntry)}
+}
+
+func (m *memoryIdempotencyStore) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok || !m.now().Before(entry.expires) {
+		return "", nil
+	}
+	return entry.commentID, nil
+}
+
+func (m *memoryIdempotencyStore) Put(ctx context.Context, key, commentID string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	for k, entry := range m.entries {
+		if !now.Before(entry.expires) {
+			delete(m.entries, k)
+		}
+	}
+	m.entries[key] = idempotencyEntry{commentID: commentID, expires: now.Add(ttl)}
+	return nil
+}
+
+// keyLocks serializes work on the same key while letting different keys
+// proceed in parallel, so concurrent retries can't both miss the store
+type keyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	sync.Mutex
+	waiters int
+}
+
+// lock blocks until key is free and returns the function that frees it
+func (k *keyLocks) lock(key string) func() {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = make(map[string]*keyLock)
+	}
+	l, ok := k.locks[key]
+	if !ok {
+		l = &keyLock{}
+		k.locks[key] = l
+	}
+	l.waiters++
+	k.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		k.mu.Lock()
+		l.waiters--
+		if l.waiters == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
@@ -0,0 +1,133 @@
+package service_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestCreateCommentIdempotent_RepeatReturnsOriginal(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	repo := memory.NewMemoryRepository()
+	commentService := service.NewCommentService(repo)
+	request := func() *models.CreateCommentRequest {
+		return &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Posted from a train"}
+	}
+
+	// Execute
+	first, replayed, err := commentService.CreateCommentIdempotent(ctx, request(), "key-1")
+	if err != nil || replayed {
+		t.Fatalf("Expected the first request to create, got replayed=%v err=%v", replayed, err)
+	}
+	second, replayed, err := commentService.CreateCommentIdempotent(ctx, request(), "key-1")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !replayed || second.ID != first.ID {
+		t.Fatalf("Expected the original comment %s replayed, got %s (replayed=%v)", first.ID, second.ID, replayed)
+	}
+	stats, _ := repo.GetCommentStats(ctx, "post-1")
+	if stats.TotalCount != 1 {
+		t.Fatalf("Expected one comment, got %d", stats.TotalCount)
+	}
+
+	// Another user's request with the same key is their own
+	other, replayed, err := commentService.CreateCommentIdempotent(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "bob", Content: "Me too"}, "key-1")
+	if err != nil || replayed || other.ID == first.ID {
+		t.Fatalf("Expected keys to be scoped per user, got %+v (replayed=%v, err %v)", other, replayed, err)
+	}
+}
+
+func TestCreateCommentIdempotent_KeyExpires(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Now()}
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{IdempotencyTTL: time.Hour})
+	commentService.SetClock(clock)
+	first, _, err := commentService.CreateCommentIdempotent(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Hello"}, "key-1")
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Execute
+	clock.Advance(2 * time.Hour)
+	second, replayed, err := commentService.CreateCommentIdempotent(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Hello"}, "key-1")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if replayed || second.ID == first.ID {
+		t.Fatal("Expected an expired key to create a new comment")
+	}
+}
+
+func TestCreateCommentIdempotent_ConcurrentRetries(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	repo := memory.NewMemoryRepository()
+	commentService := service.NewCommentService(repo)
+
+	// Execute
+	var wg sync.WaitGroup
+	ids := make([]string, 10)
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			comment, _, err := commentService.CreateCommentIdempotent(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Retry storm"}, "key-1")
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+			ids[i] = comment.ID
+		}(i)
+	}
+	wg.Wait()
+
+	// Assert
+	for _, id := range ids {
+		if id != ids[0] {
+			t.Fatalf("Expected every retry to get the same comment, got %v", ids)
+		}
+	}
+	stats, _ := repo.GetCommentStats(ctx, "post-1")
+	if stats.TotalCount != 1 {
+		t.Fatalf("Expected one comment, got %d", stats.TotalCount)
+	}
+}
+
+func TestCreateCommentIdempotent_TokenlessGuestsShareNoKeys(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{AllowAnonymous: true})
+	request := func() *models.CreateCommentRequest {
+		return &models.CreateCommentRequest{RootID: "post-1", Anonymous: true, Content: "Hello from a guest"}
+	}
+	first, _, err := commentService.CreateCommentIdempotent(ctx, request(), "key-1")
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Execute
+	second, replayed, err := commentService.CreateCommentIdempotent(ctx, request(), "key-1")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if replayed || second.ID == first.ID {
+		t.Fatalf("Expected a second guest to get their own comment, got %s (replayed=%v)", second.ID, replayed)
+	}
+	if second.UserID == first.UserID {
+		t.Errorf("Expected the second guest to get their own token, got %s", second.UserID)
+	}
+}
@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+	"github.com/google/uuid"
+)
+
+func TestImportComments_BuildsTreeFromUnorderedBatch(t *testing.T) {
+	// Setup: a -> b -> c and a -> d, listed children first
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	created := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	a := &models.Comment{ID: uuid.New().String(), RootID: "legacy-1", UserID: "alice", Content: "Top", CreatedAt: created, Upvotes: 3}
+	b := &models.Comment{ID: uuid.New().String(), RootID: "legacy-1", ParentID: &a.ID, UserID: "bob", Content: "Reply", CreatedAt: created.Add(time.Hour)}
+	c := &models.Comment{ID: uuid.New().String(), RootID: "legacy-1", ParentID: &b.ID, UserID: "carol", Content: "Nested", CreatedAt: created.Add(2 * time.Hour)}
+	d := &models.Comment{ID: uuid.New().String(), RootID: "legacy-1", ParentID: &a.ID, UserID: "dave", Content: "Sibling", CreatedAt: created.Add(3 * time.Hour)}
+
+	// Execute
+	err := commentService.ImportComments(ctx, []*models.Comment{c, d, b, a})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	tree, err := commentService.GetCommentTree(ctx, "legacy-1", 10, "created_at")
+	if err != nil {
+		t.Fatalf("Failed to get tree: %v", err)
+	}
+	if len(tree) != 1 || tree[0].Comment.ID != a.ID {
+		t.Fatalf("Expected a single top-level comment %s, got: %d roots", a.ID, len(tree))
+	}
+	if len(tree[0].Children) != 2 {
+		t.Fatalf("Expected 2 replies to the top-level comment, got: %d", len(tree[0].Children))
+	}
+
+	top, err := commentService.GetComment(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("Failed to get imported comment: %v", err)
+	}
+	if !top.CreatedAt.Equal(created) || top.Score != 3 {
+		t.Fatalf("Expected created_at and score to be preserved, got: %v, score %d", top.CreatedAt, top.Score)
+	}
+	if top.ReplyCount != 2 || top.DescendantCount != 3 {
+		t.Fatalf("Expected 2 replies and 3 descendants, got: %d, %d", top.ReplyCount, top.DescendantCount)
+	}
+
+	nested, err := commentService.GetComment(ctx, c.ID)
+	if err != nil {
+		t.Fatalf("Failed to get nested comment: %v", err)
+	}
+	if nested.Path != a.ID+"."+b.ID+"."+c.ID || nested.Depth != 2 {
+		t.Fatalf("Expected path %s at depth 2, got: %s at depth %d", a.ID+"."+b.ID+"."+c.ID, nested.Path, nested.Depth)
+	}
+}
+
+func TestImportComments_AttachesToStoredParent(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	parent, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Existing"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	reply := &models.Comment{ID: uuid.New().String(), RootID: "product-1", ParentID: &parent.ID, UserID: "importer", Content: "Imported reply"}
+
+	// Execute
+	err = commentService.ImportComments(ctx, []*models.Comment{reply})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if reply.Path != parent.ID+"."+reply.ID || reply.Depth != 1 {
+		t.Fatalf("Expected reply under stored parent, got: %s at depth %d", reply.Path, reply.Depth)
+	}
+}
+
+func TestImportComments_RejectsMissingParent(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	missing := uuid.New().String()
+	top := &models.Comment{ID: uuid.New().String(), RootID: "legacy-1", UserID: "alice", Content: "Top"}
+	orphan := &models.Comment{ID: uuid.New().String(), RootID: "legacy-1", ParentID: &missing, UserID: "bob", Content: "Orphan"}
+
+	// Execute
+	err := commentService.ImportComments(ctx, []*models.Comment{top, orphan})
+
+	// Assert
+	if !errors.Is(err, service.ErrInvalidInput) {
+		t.Fatalf("Expected ErrInvalidInput, got: %v", err)
+	}
+	if _, err := commentService.GetComment(ctx, top.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Fatalf("Expected nothing to be imported, got: %v", err)
+	}
+}
+
+func TestImportComments_RejectsDottedID(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	dotted := &models.Comment{ID: "legacy.42", RootID: "legacy-1", UserID: "alice", Content: "Top"}
+
+	// Execute
+	err := commentService.ImportComments(ctx, []*models.Comment{dotted})
+
+	// Assert
+	if !errors.Is(err, service.ErrInvalidInput) || !errors.Is(err, service.ErrInvalidID) {
+		t.Fatalf("Expected ErrInvalidInput and ErrInvalidID, got: %v", err)
+	}
+	if _, err := commentService.GetComment(ctx, dotted.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Fatalf("Expected nothing to be imported, got: %v", err)
+	}
+}
+
+func TestCreateComment_RepositoryRejectsDottedID(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	repo := memory.NewMemoryRepository()
+	comment := &models.Comment{ID: "thread.1", RootID: "post-1", UserID: "alice", Content: "Hello", Status: models.CommentStatusApproved}
+
+	// Execute
+	err := repo.CreateComment(ctx, comment)
+
+	// Assert
+	if !errors.Is(err, service.ErrInvalidID) {
+		t.Fatalf("Expected ErrInvalidID, got: %v", err)
+	}
+}
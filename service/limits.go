@@ -0,0 +1,30 @@
+package service
+
+import (
+	"slices"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+// Limits are the effective limits of a service's configuration, for clients
+// that size their inputs and pick their options from the server instead of
+// hardcoding them
+type Limits struct {
+	MinCommentLength int      `json:"min_comment_length"` // Fewest characters content may have; 0 means any non-empty content
+	MaxCommentLength int      `json:"max_comment_length"` // Most characters content may have
+	MaxDepth         int      `json:"max_depth"`          // Deepest depth a reply may have; top-level comments are depth 0
+	MaxPageSize      int      `json:"max_page_size"`      // Largest limit a list read honors
+	SortFields       []string `json:"sort_fields"`        // Accepted sort_by values
+}
+
+// Limits reports the limits the service enforces, after defaults are applied.
+// Per-root settings may override MaxDepth for a given root.
+func (s *CommentService) Limits() Limits {
+	return Limits{
+		MinCommentLength: s.config.MinCommentLength,
+		MaxCommentLength: s.config.MaxCommentLength,
+		MaxDepth:         s.config.MaxCommentDepth,
+		MaxPageSize:      s.config.MaxPageSize,
+		SortFields:       slices.Clone(models.SortFields),
+	}
+}
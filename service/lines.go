@@ -0,0 +1,47 @@
+package service
+
+import "strings"
+
+// limitLines holds content to MaxLines and MaxBlankLines. With CollapseLines
+// set it returns content cut down to fit; otherwise it rejects content that
+// doesn't with a *TooManyLinesError.
+func (s *CommentService) limitLines(content string) (string, error) {
+	maxLines, maxBlank := s.config.MaxLines, s.config.MaxBlankLines
+	if maxLines <= 0 && maxBlank <= 0 {
+		return content, nil
+	}
+	lines := strings.Split(content, "\n")
+
+	if maxBlank > 0 {
+		kept := lines[:0:0]
+		run, longest := 0, 0
+		for _, line := range lines {
+			if strings.TrimSpace(line) != "" {
+				run = 0
+				kept = append(kept, line)
+				continue
+			}
+			run++
+			longest = max(longest, run)
+			if run <= maxBlank {
+				kept = append(kept, line)
+			}
+		}
+		if longest > maxBlank {
+			if !s.config.CollapseLines {
+				return "", &TooManyLinesError{Lines: longest, Limit: maxBlank, Blank: true}
+			}
+			lines = kept
+		}
+	}
+
+	if maxLines > 0 && len(lines) > maxLines {
+		if !s.config.CollapseLines {
+			return "", &TooManyLinesError{Lines: len(lines), Limit: maxLines}
+		}
+		// Run the overflow onto the last line allowed, keeping its text
+		last := strings.Fields(strings.Join(lines[maxLines-1:], " "))
+		lines = append(lines[:maxLines-1], strings.Join(last, " "))
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,105 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestCreateComment_BlankLineWall(t *testing.T) {
+	wall := "top" + strings.Repeat("\n", 200) + "bottom"
+
+	t.Run("rejected", func(t *testing.T) {
+		// Setup
+		commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{MaxBlankLines: 2})
+
+		// Execute
+		_, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: wall})
+
+		// Assert
+		var tooMany *service.TooManyLinesError
+		if !errors.As(err, &tooMany) || !errors.Is(err, service.ErrTooManyLines) || !errors.Is(err, service.ErrInvalidInput) {
+			t.Fatalf("Expected a *TooManyLinesError, got: %v", err)
+		}
+		if !tooMany.Blank || tooMany.Lines != 199 || tooMany.Limit != 2 {
+			t.Errorf("Expected 199 blank lines against a limit of 2, got: %+v", tooMany)
+		}
+		if fields := service.FieldErrors(err); len(fields) != 1 || fields[0].Field != "content" || fields[0].Rule != "max_blank_lines" {
+			t.Errorf("Expected a max_blank_lines content error, got: %+v", fields)
+		}
+	})
+
+	t.Run("collapsed", func(t *testing.T) {
+		// Setup
+		commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{MaxBlankLines: 2, CollapseLines: true})
+
+		// Execute
+		comment, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: wall})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if comment.Content != "top\n\n\nbottom" {
+			t.Errorf("Expected the wall cut to two blank lines, got %q", comment.Content)
+		}
+	})
+}
+
+func TestCreateComment_MaxLines(t *testing.T) {
+	content := "one\ntwo\n\nthree\nfour\nfive"
+
+	t.Run("rejected", func(t *testing.T) {
+		// Setup
+		commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{MaxLines: 3})
+
+		// Execute
+		_, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: content})
+
+		// Assert
+		var tooMany *service.TooManyLinesError
+		if !errors.As(err, &tooMany) || tooMany.Blank || tooMany.Lines != 6 || tooMany.Limit != 3 {
+			t.Fatalf("Expected 6 lines against a limit of 3, got: %v", err)
+		}
+		if fields := service.FieldErrors(err); len(fields) != 1 || fields[0].Rule != "max_lines" {
+			t.Errorf("Expected a max_lines content error, got: %+v", fields)
+		}
+	})
+
+	t.Run("collapsed", func(t *testing.T) {
+		// Setup
+		commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{MaxLines: 3, CollapseLines: true})
+
+		// Execute
+		comment, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: content})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if comment.Content != "one\ntwo\nthree four five" {
+			t.Errorf("Expected the overflow run onto the third line, got %q", comment.Content)
+		}
+	})
+
+	t.Run("within limits", func(t *testing.T) {
+		// Setup
+		commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{MaxLines: 6, MaxBlankLines: 1})
+
+		// Execute
+		comment, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: content})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if comment.Content != content {
+			t.Errorf("Expected content unchanged, got %q", comment.Content)
+		}
+	})
+}
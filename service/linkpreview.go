@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/christopher18/commentific/v2/models"
+	"golang.org/x/net/html"
+)
+
+// DefaultLinkPreviewTimeout bounds each preview fetch when
+// CommentServiceConfig.LinkPreviewTimeout is unset
+const DefaultLinkPreviewTimeout = 5 * time.Second
+
+// LinkPreviewer fetches preview metadata for a comment's LinkURL. With one set
+// through SetLinkPreviewer, previews are fetched in the background after a
+// comment is created or its link is edited, and on demand by GetLinkPreview.
+type LinkPreviewer interface {
+	Preview(ctx context.Context, linkURL string) (*models.LinkPreview, error)
+}
+
+// SetLinkPreviewer replaces the previewer used for comment links. A nil
+// previewer, the default, turns link previews off.
+func (s *CommentService) SetLinkPreviewer(previewer LinkPreviewer) {
+	s.linkPreviewer = previewer
+}
+
+// GetLinkPreview returns the preview card for a comment's link, fetching and
+// storing it first if the background fetch hasn't finished (or failed).
+// ErrNotFound means the comment has no link or previews are turned off;
+// ErrLinkPreviewFailed means the page could not be fetched in time.
+func (s *CommentService) GetLinkPreview(ctx context.Context, commentID string) (_ *models.LinkPreview, err error) {
+	ctx, span := s.startSpan(ctx, "GetLinkPreview", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
+
+	if commentID == "" {
+		return nil, invalidInput("comment ID is required")
+	}
+
+	comment, err := s.repo.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+	if !visibleToViewer(ctx, comment) {
+		return nil, fmt.Errorf("failed to get comment: %w", ErrNotFound)
+	}
+	if comment.LinkURL == nil || *comment.LinkURL == "" {
+		return nil, fmt.Errorf("comment has no link: %w", ErrNotFound)
+	}
+	if comment.LinkPreview != nil && comment.LinkPreview.URL == *comment.LinkURL {
+		return comment.LinkPreview, nil
+	}
+	if s.linkPreviewer == nil {
+		return nil, fmt.Errorf("link previews are not enabled: %w", ErrNotFound)
+	}
+
+	return s.fetchLinkPreview(ctx, comment.ID, *comment.LinkURL)
+}
+
+// fetchLinkPreviewAsync fetches and stores a comment's link preview without
+// holding up the caller. The fetch outlives the request but not the timeout.
+func (s *CommentService) fetchLinkPreviewAsync(ctx context.Context, comment *models.Comment) {
+	if s.linkPreviewer == nil || comment.LinkURL == nil || *comment.LinkURL == "" {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	commentID, linkURL := comment.ID, *comment.LinkURL
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+		if _, err := s.fetchLinkPreview(ctx, commentID, linkURL); err != nil {
+			s.logger.WarnContext(ctx, "link preview failed", "method", "CommentService.fetchLinkPreview", "comment_id", commentID, "error", err)
+		}
+	}()
+}
+
+// Drain waits for the work the service carries on after a call returns, such
+// as link preview fetches, to finish, or for ctx to be done. Call it on
+// shutdown before closing the database.
+func (s *CommentService) Drain(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// fetchLinkPreview fetches the preview for linkURL within the configured
+// timeout and stores it on the comment. A comment whose link changed in the
+// meantime keeps no preview, but the fetched one is still returned.
+func (s *CommentService) fetchLinkPreview(ctx context.Context, commentID, linkURL string) (*models.LinkPreview, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, s.config.LinkPreviewTimeout)
+	defer cancel()
+
+	preview, err := s.linkPreviewer.Preview(fetchCtx, linkURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLinkPreviewFailed, err)
+	}
+	preview.URL = linkURL
+	if preview.FetchedAt.IsZero() {
+		preview.FetchedAt = s.clock.Now()
+	}
+
+	if err := s.repo.SetLinkPreview(ctx, commentID, preview); err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to store link preview: %w", err)
+	}
+	return preview, nil
+}
+
+// OpenGraphPreviewer is a LinkPreviewer that reads the Open Graph tags
+// (og:title, og:description, og:image, og:site_name) of an HTML page,
+// falling back to <title> and the description meta tag. It fetches whatever
+// URL users link to, so give it a Client whose transport refuses internal
+// addresses when the service runs next to anything sensitive.
+type OpenGraphPreviewer struct {
+	Client   *http.Client // http.DefaultClient when nil; the service applies its own timeout
+	MaxBytes int64        // Most of a page read looking for tags; 1 MiB when zero
+}
+
+// Preview fetches linkURL and extracts its preview metadata
+func (p *OpenGraphPreviewer) Preview(ctx context.Context, linkURL string) (*models.LinkPreview, error) {
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	maxBytes := p.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = 1 << 20
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, linkURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: unexpected status %s", linkURL, resp.Status)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/html" {
+		return nil, fmt.Errorf("fetching %s: not an HTML page (%s)", linkURL, mediaType)
+	}
+
+	preview := parseOpenGraph(io.LimitReader(resp.Body, maxBytes))
+	preview.URL = linkURL
+	if preview.ImageURL != "" {
+		// Images may be given relative to the page, which may have redirected
+		if image, err := resp.Request.URL.Parse(preview.ImageURL); err == nil {
+			preview.ImageURL = image.String()
+		}
+	}
+	return preview, nil
+}
+
+// parseOpenGraph reads preview metadata from the head of an HTML document
+func parseOpenGraph(r io.Reader) *models.LinkPreview {
+	preview := &models.LinkPreview{}
+	var title, description string
+
+	tokenizer := html.NewTokenizer(r)
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return finishPreview(preview, title, description)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "body":
+				return finishPreview(preview, title, description)
+			case "title":
+				if tokenizer.Next() == html.TextToken {
+					title = strings.TrimSpace(string(tokenizer.Text()))
+				}
+			case "meta":
+				key, content := metaTag(token)
+				switch key {
+				case "og:title":
+					preview.Title = content
+				case "og:description":
+					preview.Description = content
+				case "og:image", "og:image:url":
+					if preview.ImageURL == "" {
+						preview.ImageURL = content
+					}
+				case "og:site_name":
+					preview.SiteName = content
+				case "description":
+					description = content
+				}
+			}
+		case html.EndTagToken:
+			if name, _ := tokenizer.TagName(); string(name) == "head" {
+				return finishPreview(preview, title, description)
+			}
+		}
+	}
+}
+
+// metaTag returns the property (or name) of a meta tag, lowercased, and its
+// trimmed content
+func metaTag(token html.Token) (key, content string) {
+	for _, attr := range token.Attr {
+		switch attr.Key {
+		case "property":
+			key = attr.Val
+		case "name":
+			if key == "" {
+				key = attr.Val
+			}
+		case "content":
+			content = strings.TrimSpace(attr.Val)
+		}
+	}
+	return strings.ToLower(key), content
+}
+
+// finishPreview fills in missing Open Graph fields from the plain HTML ones
+func finishPreview(preview *models.LinkPreview, title, description string) *models.LinkPreview {
+	if preview.Title == "" {
+		preview.Title = title
+	}
+	if preview.Description == "" {
+		preview.Description = description
+	}
+	return preview
+}
@@ -0,0 +1,245 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+const articlePage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Fallback title</title>
+  <meta property="og:title" content="Gophers &amp; Friends">
+  <meta property="og:description" content="A field guide to gophers">
+  <meta property="og:image" content="/images/gopher.png">
+  <meta property="og:site_name" content="Example News">
+</head>
+<body><meta property="og:title" content="Ignored outside the head"></body>
+</html>`
+
+// newPreviewServer serves articlePage at /article, a page without Open Graph
+// tags at /plain and a page that never answers in time at /slow
+func newPreviewServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(articlePage))
+	})
+	mux.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Plain page</title><meta name="description" content="Nothing fancy"></head></html>`))
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// waitForLinkPreview polls until the background fetch has stored a preview
+func waitForLinkPreview(t *testing.T, repo *memory.MemoryRepository, commentID string) *models.LinkPreview {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		comment, err := repo.GetCommentByID(context.Background(), commentID)
+		if err != nil {
+			t.Fatalf("Failed to get comment: %v", err)
+		}
+		if comment.LinkPreview != nil {
+			return comment.LinkPreview
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for the link preview")
+	return nil
+}
+
+func TestCreateComment_FetchesLinkPreviewInBackground(t *testing.T) {
+	// Setup
+	server := newPreviewServer(t)
+	repo := memory.NewMemoryRepository()
+	commentService := service.NewCommentService(repo)
+	commentService.SetLinkPreviewer(&service.OpenGraphPreviewer{Client: server.Client()})
+	link := server.URL + "/article"
+
+	// Execute
+	comment, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Worth a read", LinkURL: &link})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Assert
+	preview := waitForLinkPreview(t, repo, comment.ID)
+	if preview.URL != link {
+		t.Errorf("Expected the preview for %s, got %s", link, preview.URL)
+	}
+	if preview.Title != "Gophers & Friends" {
+		t.Errorf("Expected the og:title, got %q", preview.Title)
+	}
+	if preview.Description != "A field guide to gophers" {
+		t.Errorf("Expected the og:description, got %q", preview.Description)
+	}
+	if preview.ImageURL != server.URL+"/images/gopher.png" {
+		t.Errorf("Expected the og:image resolved against the page, got %q", preview.ImageURL)
+	}
+	if preview.SiteName != "Example News" {
+		t.Errorf("Expected the og:site_name, got %q", preview.SiteName)
+	}
+	if preview.FetchedAt.IsZero() {
+		t.Error("Expected FetchedAt to be set")
+	}
+}
+
+func TestCreateComment_SlowLinkPreviewDoesNotBlock(t *testing.T) {
+	// Setup
+	server := newPreviewServer(t)
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{LinkPreviewTimeout: 50 * time.Millisecond})
+	commentService.SetLinkPreviewer(&service.OpenGraphPreviewer{Client: server.Client()})
+	link := server.URL + "/slow"
+
+	// Execute
+	started := time.Now()
+	comment, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Slow site", LinkURL: &link})
+	elapsed := time.Since(started)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if elapsed > 40*time.Millisecond {
+		t.Errorf("Expected creation not to wait for the preview, took %v", elapsed)
+	}
+
+	// On demand, the fetch gives up at the timeout
+	started = time.Now()
+	_, err = commentService.GetLinkPreview(context.Background(), comment.ID)
+	if !errors.Is(err, service.ErrLinkPreviewFailed) {
+		t.Fatalf("Expected ErrLinkPreviewFailed, got: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("Expected the fetch to stop at the timeout, took %v", elapsed)
+	}
+}
+
+func TestGetLinkPreview_FetchesOnDemand(t *testing.T) {
+	// Setup
+	server := newPreviewServer(t)
+	repo := memory.NewMemoryRepository()
+	link := server.URL + "/plain"
+	if err := repo.CreateComment(context.Background(), &models.Comment{ID: "c1", RootID: "post-1", UserID: "alice", Content: "Posted before previews", LinkURL: &link}); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	commentService := service.NewCommentService(repo)
+
+	// Previews are off until a previewer is set
+	if _, err := commentService.GetLinkPreview(context.Background(), "c1"); !errors.Is(err, service.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound without a previewer, got: %v", err)
+	}
+	commentService.SetLinkPreviewer(&service.OpenGraphPreviewer{Client: server.Client()})
+
+	// Execute
+	preview, err := commentService.GetLinkPreview(context.Background(), "c1")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if preview.Title != "Plain page" || preview.Description != "Nothing fancy" {
+		t.Errorf("Expected the title and description tags as fallbacks, got %+v", preview)
+	}
+	stored, _ := repo.GetCommentByID(context.Background(), "c1")
+	if stored.LinkPreview == nil || stored.LinkPreview.Title != "Plain page" {
+		t.Errorf("Expected the preview to be stored on the comment, got %+v", stored.LinkPreview)
+	}
+}
+
+func TestUpdateComment_NewLinkReplacesPreview(t *testing.T) {
+	// Setup
+	server := newPreviewServer(t)
+	repo := memory.NewMemoryRepository()
+	commentService := service.NewCommentService(repo)
+	commentService.SetLinkPreviewer(&service.OpenGraphPreviewer{Client: server.Client()})
+	ctx := context.Background()
+	link := server.URL + "/plain"
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "First link", LinkURL: &link})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	waitForLinkPreview(t, repo, comment.ID)
+
+	// Execute
+	newLink := server.URL + "/article"
+	if err := commentService.UpdateComment(ctx, comment.ID, "alice", &models.UpdateCommentRequest{LinkURL: &newLink}); err != nil {
+		t.Fatalf("Failed to update comment: %v", err)
+	}
+
+	// Assert
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		stored, _ := repo.GetCommentByID(ctx, comment.ID)
+		if stored.LinkPreview != nil && stored.LinkPreview.URL == newLink {
+			if stored.LinkPreview.Title != "Gophers & Friends" {
+				t.Errorf("Expected the new page's title, got %q", stored.LinkPreview.Title)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for the preview of the new link")
+}
+
+// heldPreviewer answers once release is closed
+type heldPreviewer struct {
+	release chan struct{}
+}
+
+func (p heldPreviewer) Preview(ctx context.Context, url string) (*models.LinkPreview, error) {
+	<-p.release
+	return &models.LinkPreview{Title: "Held"}, nil
+}
+
+func TestDrain_WaitsForBackgroundPreviews(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	repo := memory.NewMemoryRepository()
+	commentService := service.NewCommentService(repo)
+	previewer := heldPreviewer{release: make(chan struct{})}
+	commentService.SetLinkPreviewer(previewer)
+	link := "https://example.com/article"
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Look", LinkURL: &link})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Execute
+	shortCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	timedOut := commentService.Drain(shortCtx)
+	close(previewer.release)
+	drained := commentService.Drain(ctx)
+
+	// Assert
+	if !errors.Is(timedOut, context.DeadlineExceeded) {
+		t.Errorf("Expected Drain to give up while the fetch is held, got: %v", timedOut)
+	}
+	if drained != nil {
+		t.Fatalf("Expected Drain to return once the fetch finished, got: %v", drained)
+	}
+	stored, err := repo.GetCommentByID(ctx, comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	if stored.LinkPreview == nil || stored.LinkPreview.Title != "Held" {
+		t.Errorf("Expected the preview stored by the time Drain returned, got: %+v", stored.LinkPreview)
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+)
+
+// discardLogger drops every record until SetLogger is called
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// SetLogger replaces the logger used for service-level warnings such as
+// rejected input. A nil logger restores the default, which logs nothing.
+// Records logged for a request carry its ID as request_id; see WithRequestID.
+func (s *CommentService) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = discardLogger
+	}
+	s.logger = slog.New(NewRequestIDHandler(logger.Handler()))
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying the ID that correlates one
+// request's work across services. The HTTP router sets it from X-Request-ID.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request's ID, or "" if none was set
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// requestIDHandler adds the request ID from a record's context to the record
+type requestIDHandler struct {
+	slog.Handler
+}
+
+// NewRequestIDHandler wraps next so that records logged with a context from
+// WithRequestID include the ID as the request_id attribute
+func NewRequestIDHandler(next slog.Handler) slog.Handler {
+	if _, ok := next.(requestIDHandler); ok {
+		return next
+	}
+	return requestIDHandler{next}
+}
+
+func (h requestIDHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		record = record.Clone()
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h requestIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return requestIDHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h requestIDHandler) WithGroup(name string) slog.Handler {
+	return requestIDHandler{h.Handler.WithGroup(name)}
+}
+
+// warnInvalidInput logs err at warn level when it is an input error
+func (s *CommentService) warnInvalidInput(ctx context.Context, method string, err error) {
+	if err == nil || !errors.Is(err, ErrInvalidInput) {
+		return
+	}
+	s.logger.WarnContext(ctx, "invalid input rejected", "method", "CommentService."+method, "error", err)
+}
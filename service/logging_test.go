@@ -0,0 +1,56 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestCreateComment_LogsValidationFailure(t *testing.T) {
+	// Setup
+	var buf bytes.Buffer
+	svc := service.NewCommentService(memory.NewMemoryRepository())
+	svc.SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	// Execute
+	_, err := svc.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "product-1", UserID: "user-1", Content: "   "})
+
+	// Assert
+	if err == nil {
+		t.Fatalf("Expected error for blank content, got: nil")
+	}
+	line := buf.String()
+	if !strings.Contains(line, "level=WARN") || !strings.Contains(line, "method=CommentService.CreateComment") {
+		t.Fatalf("Expected warning for CreateComment, got: %s", line)
+	}
+}
+
+func TestSetLogger_AddsRequestID(t *testing.T) {
+	// Setup
+	var buf bytes.Buffer
+	svc := service.NewCommentService(memory.NewMemoryRepository())
+	svc.SetLogger(slog.New(slog.NewTextHandler(&buf, nil)).With("component", "comments"))
+	ctx := service.WithRequestID(context.Background(), "req-42")
+
+	// Execute
+	svc.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "user-1", Content: "   "})
+	svc.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "product-1", UserID: "user-1", Content: "   "})
+
+	// Assert
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected two warnings, got: %s", buf.String())
+	}
+	if !strings.Contains(lines[0], "component=comments") || !strings.Contains(lines[0], "request_id=req-42") {
+		t.Errorf("Expected the request ID alongside the logger's attributes, got: %s", lines[0])
+	}
+	if strings.Contains(lines[1], "request_id") {
+		t.Errorf("Expected no request ID without one in the context, got: %s", lines[1])
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultPurgeOlderThanDays is how old soft-deleted comments must be before
+// the scheduled purge removes them when MaintenanceConfig.PurgeOlderThanDays
+// is unset
+const DefaultPurgeOlderThanDays = 30
+
+// Ticker delivers ticks until stopped, like time.Ticker
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// TickerClock is a Clock that also makes tickers. MaintenanceScheduler uses
+// the service's clock for its schedule when it implements TickerClock, and
+// real tickers otherwise.
+type TickerClock interface {
+	Clock
+	NewTicker(d time.Duration) Ticker
+}
+
+// timeTicker adapts time.Ticker to Ticker
+type timeTicker struct {
+	*time.Ticker
+}
+
+func (t timeTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return timeTicker{time.NewTicker(d)}
+}
+
+// MaintenanceConfig chooses which maintenance jobs a MaintenanceScheduler
+// runs and how often. A zero interval leaves that job off.
+type MaintenanceConfig struct {
+	PurgeInterval       time.Duration // How often to run PurgeOldDeletedComments
+	PurgeOlderThanDays  int           // Age in days at which soft-deleted comments are purged (default 30)
+	RecalculateInterval time.Duration // How often to run RecalculateAllScores
+}
+
+// MaintenanceScheduler runs the service's maintenance jobs on a schedule, so
+// embedders don't need their own cron. Results and failures are logged
+// through the service's logger.
+type MaintenanceScheduler struct {
+	service *CommentService
+	config  MaintenanceConfig
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewMaintenanceScheduler creates a scheduler for the service's maintenance
+// jobs. Nothing runs until Start is called.
+func NewMaintenanceScheduler(service *CommentService, config MaintenanceConfig) *MaintenanceScheduler {
+	if config.PurgeOlderThanDays <= 0 {
+		config.PurgeOlderThanDays = DefaultPurgeOlderThanDays
+	}
+	return &MaintenanceScheduler{service: service, config: config}
+}
+
+// Start runs the enabled jobs in the background, each time its interval
+// passes, until Stop is called or ctx is done. It fails if no job is enabled
+// or the scheduler is already running.
+func (m *MaintenanceScheduler) Start(ctx context.Context) error {
+	if m.config.PurgeInterval <= 0 && m.config.RecalculateInterval <= 0 {
+		return errors.New("maintenance scheduler has no jobs enabled")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cancel != nil {
+		return errors.New("maintenance scheduler is already running")
+	}
+
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.done = make(chan struct{})
+	go m.run(ctx, m.done)
+	return nil
+}
+
+// Stop ends the schedule and waits for a job in progress to finish. It does
+// nothing if the scheduler isn't running.
+func (m *MaintenanceScheduler) Stop() {
+	m.mu.Lock()
+	cancel, done := m.cancel, m.done
+	m.cancel, m.done = nil, nil
+	m.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// run waits for ticks and runs the matching job until ctx is done
+func (m *MaintenanceScheduler) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	clock, ok := m.service.clock.(TickerClock)
+	if !ok {
+		clock = systemClock{}
+	}
+
+	// A nil channel never delivers, leaving disabled jobs idle
+	var purgeTicks, recalculateTicks <-chan time.Time
+	if m.config.PurgeInterval > 0 {
+		ticker := clock.NewTicker(m.config.PurgeInterval)
+		defer ticker.Stop()
+		purgeTicks = ticker.C()
+	}
+	if m.config.RecalculateInterval > 0 {
+		ticker := clock.NewTicker(m.config.RecalculateInterval)
+		defer ticker.Stop()
+		recalculateTicks = ticker.C()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-purgeTicks:
+			m.purge(ctx)
+		case <-recalculateTicks:
+			m.recalculate(ctx)
+		}
+	}
+}
+
+func (m *MaintenanceScheduler) purge(ctx context.Context) {
+	purged, err := m.service.PurgeOldDeletedComments(ctx, m.config.PurgeOlderThanDays)
+	if err != nil {
+		m.service.logger.WarnContext(ctx, "scheduled purge failed", "method", "CommentService.PurgeOldDeletedComments", "error", err)
+		return
+	}
+	m.service.logger.InfoContext(ctx, "purged deleted comments", "method", "CommentService.PurgeOldDeletedComments",
+		"older_than_days", m.config.PurgeOlderThanDays, "purged", purged)
+}
+
+func (m *MaintenanceScheduler) recalculate(ctx context.Context) {
+	if err := m.service.RecalculateAllScores(ctx); err != nil {
+		m.service.logger.WarnContext(ctx, "scheduled score recalculation failed", "method", "CommentService.RecalculateAllScores", "error", err)
+		return
+	}
+	m.service.logger.InfoContext(ctx, "recalculated comment scores", "method", "CommentService.RecalculateAllScores")
+}
@@ -0,0 +1,129 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+// manualTicker ticks only when the test says so
+type manualTicker struct {
+	interval time.Duration
+	ticks    chan time.Time
+}
+
+func (t *manualTicker) C() <-chan time.Time { return t.ticks }
+func (t *manualTicker) Stop()               {}
+
+// tickingClock is a fakeClock whose tickers are handed to the test as they
+// are created
+type tickingClock struct {
+	fakeClock
+	tickers chan *manualTicker
+}
+
+func (c *tickingClock) NewTicker(d time.Duration) service.Ticker {
+	ticker := &manualTicker{interval: d, ticks: make(chan time.Time)}
+	c.tickers <- ticker
+	return ticker
+}
+
+// purgeRecordingRepository reports the purges it is asked to run, dropping
+// reports while the last one is unread
+type purgeRecordingRepository struct {
+	*memory.MemoryRepository
+	purges chan int
+}
+
+func (r *purgeRecordingRepository) PurgeDeletedComments(ctx context.Context, olderThan int) (int64, error) {
+	select {
+	case r.purges <- olderThan:
+	default:
+	}
+	return r.MemoryRepository.PurgeDeletedComments(ctx, olderThan)
+}
+
+func TestMaintenanceScheduler_RunsPurgeOnSchedule(t *testing.T) {
+	// Setup
+	repo := &purgeRecordingRepository{MemoryRepository: memory.NewMemoryRepository(), purges: make(chan int, 1)}
+	clock := &tickingClock{fakeClock: fakeClock{now: time.Now()}, tickers: make(chan *manualTicker, 1)}
+	var logs bytes.Buffer
+	commentService := service.NewCommentService(repo)
+	commentService.SetClock(clock)
+	commentService.SetLogger(slog.New(slog.NewTextHandler(&logs, nil)))
+	scheduler := service.NewMaintenanceScheduler(commentService, service.MaintenanceConfig{PurgeInterval: time.Minute, PurgeOlderThanDays: 7})
+
+	// Execute
+	if err := scheduler.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start scheduler: %v", err)
+	}
+	ticker := <-clock.tickers
+	if ticker.interval != time.Minute {
+		t.Fatalf("Expected a ticker every minute, got %v", ticker.interval)
+	}
+	for i := 0; i < 2; i++ {
+		ticker.ticks <- clock.Now()
+
+		// Assert
+		select {
+		case days := <-repo.purges:
+			if days != 7 {
+				t.Fatalf("Expected a purge of comments older than 7 days, got %d", days)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected tick %d to run the purge", i+1)
+		}
+	}
+	scheduler.Stop()
+
+	if !strings.Contains(logs.String(), "purged deleted comments") {
+		t.Errorf("Expected the purge to be logged, got: %s", logs.String())
+	}
+	if err := scheduler.Start(context.Background()); err != nil {
+		t.Fatalf("Expected a stopped scheduler to restart, got: %v", err)
+	}
+	<-clock.tickers
+	scheduler.Stop()
+}
+
+func TestMaintenanceScheduler_RequiresAJob(t *testing.T) {
+	// Setup
+	scheduler := service.NewMaintenanceScheduler(service.NewCommentService(memory.NewMemoryRepository()), service.MaintenanceConfig{})
+
+	// Execute
+	err := scheduler.Start(context.Background())
+
+	// Assert
+	if err == nil {
+		scheduler.Stop()
+		t.Fatal("Expected an error when no job is enabled, got nil")
+	}
+}
+
+func TestMaintenanceScheduler_RealTicker(t *testing.T) {
+	// Setup
+	repo := &purgeRecordingRepository{MemoryRepository: memory.NewMemoryRepository(), purges: make(chan int, 1)}
+	scheduler := service.NewMaintenanceScheduler(service.NewCommentService(repo), service.MaintenanceConfig{PurgeInterval: 10 * time.Millisecond})
+
+	// Execute
+	if err := scheduler.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start scheduler: %v", err)
+	}
+	defer scheduler.Stop()
+
+	// Assert
+	select {
+	case days := <-repo.purges:
+		if days != service.DefaultPurgeOlderThanDays {
+			t.Fatalf("Expected the default age of %d days, got %d", service.DefaultPurgeOlderThanDays, days)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the purge to run within a second")
+	}
+}
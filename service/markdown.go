@@ -0,0 +1,62 @@
+package service
+
+import (
+	"bytes"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+)
+
+// markdownRenderer turns comment Markdown into HTML that is safe to embed.
+// goldmark already drops raw HTML and dangerous link schemes; the bluemonday
+// user-content policy is a second line of defense and adds rel="nofollow".
+type markdownRenderer struct {
+	markdown goldmark.Markdown
+	policy   *bluemonday.Policy
+}
+
+func newMarkdownRenderer() *markdownRenderer {
+	return &markdownRenderer{
+		markdown: goldmark.New(),
+		policy:   bluemonday.UGCPolicy(),
+	}
+}
+
+// render converts CommonMark content to sanitized HTML
+func (r *markdownRenderer) render(content string) (string, error) {
+	var buf bytes.Buffer
+	if err := r.markdown.Convert([]byte(content), &buf); err != nil {
+		return "", err
+	}
+	return r.policy.Sanitize(buf.String()), nil
+}
+
+// renderContent fills in ContentHTML when CommentServiceConfig.RenderMarkdown
+// is set. Content itself is left as stored.
+func (s *CommentService) renderContent(comments ...*models.Comment) {
+	if s.markdown == nil {
+		return
+	}
+	for _, comment := range comments {
+		if comment == nil {
+			continue
+		}
+		html, err := s.markdown.render(comment.Content)
+		if err != nil {
+			continue
+		}
+		comment.ContentHTML = &html
+	}
+}
+
+// renderTree fills in ContentHTML throughout a comment tree
+func (s *CommentService) renderTree(nodes []*models.CommentTree) {
+	if s.markdown == nil {
+		return
+	}
+	for _, node := range nodes {
+		s.renderContent(node.Comment)
+		s.renderTree(node.Children)
+	}
+}
@@ -0,0 +1,74 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestRenderMarkdown_PopulatesSanitizedHTML(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{RenderMarkdown: true})
+	content := "**bold** _italic_ [docs](https://example.com) `code`\n\n- one\n- two\n\n<script>alert(1)</script> [bad](javascript:alert(1))"
+	created, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: content})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Execute
+	comment, err := commentService.GetComment(ctx, created.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if comment.Content != content {
+		t.Fatalf("Expected raw content to be unchanged, got: %q", comment.Content)
+	}
+	if comment.ContentHTML == nil {
+		t.Fatal("Expected ContentHTML to be populated")
+	}
+	html := *comment.ContentHTML
+	for _, want := range []string{
+		"<strong>bold</strong>",
+		"<em>italic</em>",
+		`<a href="https://example.com" rel="nofollow">docs</a>`,
+		"<code>code</code>",
+		"<li>one</li>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected HTML to contain %q, got: %s", want, html)
+		}
+	}
+	if strings.Contains(html, "<script") || strings.Contains(html, "javascript:") {
+		t.Errorf("Expected script and javascript: links to be stripped, got: %s", html)
+	}
+
+	// Listings are rendered too
+	comments, err := commentService.GetCommentsByRoot(ctx, "post-1", nil)
+	if err != nil || len(comments) != 1 || comments[0].ContentHTML == nil {
+		t.Fatalf("Expected rendered HTML in listings, got: %+v (err %v)", comments, err)
+	}
+}
+
+func TestRenderMarkdown_OffByDefault(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	// Execute
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "**bold**"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if comment.ContentHTML != nil {
+		t.Fatalf("Expected no ContentHTML without RenderMarkdown, got: %q", *comment.ContentHTML)
+	}
+}
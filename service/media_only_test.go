@@ -0,0 +1,84 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestCreateComment_MediaOnly(t *testing.T) {
+	media := "https://example.com/cat.png"
+	badMedia := "ftp://example.com/cat.png"
+
+	cases := map[string]struct {
+		allow   bool
+		req     models.CreateCommentRequest
+		wantErr bool
+	}{
+		"allowed with media":        {allow: true, req: models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "  ", MediaURL: &media}},
+		"rejected by default":       {allow: false, req: models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "  ", MediaURL: &media}, wantErr: true},
+		"rejected without media":    {allow: true, req: models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "  "}, wantErr: true},
+		"rejected with a bad media": {allow: true, req: models.CreateCommentRequest{RootID: "post-1", UserID: "alice", MediaURL: &badMedia}, wantErr: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			ctx := context.Background()
+			commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{AllowMediaOnlyComments: tc.allow, MinCommentLength: 3})
+
+			// Execute
+			req := tc.req
+			comment, err := commentService.CreateComment(ctx, &req)
+
+			// Assert
+			if tc.wantErr {
+				if !errors.Is(err, service.ErrInvalidInput) {
+					t.Fatalf("Expected ErrInvalidInput, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if comment.Content != "" || comment.MediaURL == nil || *comment.MediaURL != media {
+				t.Errorf("Expected an image-only comment, got content %q and media %v", comment.Content, comment.MediaURL)
+			}
+		})
+	}
+}
+
+func TestUpdateComment_MediaOnly(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{AllowMediaOnlyComments: true})
+	media := "https://example.com/cat.png"
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Look at this", MediaURL: &media})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Execute
+	empty := ""
+	clearText := commentService.UpdateComment(ctx, comment.ID, "alice", &models.UpdateCommentRequest{Content: &empty})
+	clearMedia := commentService.UpdateComment(ctx, comment.ID, "alice", &models.UpdateCommentRequest{MediaURL: &empty})
+
+	// Assert
+	if clearText != nil {
+		t.Fatalf("Expected the text to be removable while the media stays, got: %v", clearText)
+	}
+	if !errors.Is(clearMedia, service.ErrInvalidInput) {
+		t.Fatalf("Expected removing the media of a comment without text to fail, got: %v", clearMedia)
+	}
+	stored, err := commentService.GetComment(ctx, comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	if stored.Content != "" || stored.MediaURL == nil || *stored.MediaURL != media {
+		t.Errorf("Expected the image-only comment to remain, got content %q and media %v", stored.Content, stored.MediaURL)
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+type viewerKey struct{}
+
+type moderatorKey struct{}
+
+// WithViewer returns a context carrying the ID of the user reading comments.
+// Reads made with it also return that user's own comments that are awaiting
+// moderation or were rejected; everyone else only sees approved comments.
+func WithViewer(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, viewerKey{}, userID)
+}
+
+// ViewerFromContext returns the reading user's ID, or "" if none was set
+func ViewerFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(viewerKey{}).(string)
+	return userID
+}
+
+// WithModerator returns a context marking the reader as a moderator. Reads
+// made with it keep DeletedBy and DeleteReason on deleted comments; everyone
+// else gets them cleared.
+func WithModerator(ctx context.Context) context.Context {
+	return context.WithValue(ctx, moderatorKey{}, true)
+}
+
+// IsModerator reports whether ctx was marked with WithModerator
+func IsModerator(ctx context.Context) bool {
+	moderator, _ := ctx.Value(moderatorKey{}).(bool)
+	return moderator
+}
+
+// hideDeletionAudit clears who deleted each comment and why unless the
+// reader in ctx is a moderator
+func hideDeletionAudit(ctx context.Context, comments ...*models.Comment) {
+	if IsModerator(ctx) {
+		return
+	}
+	for _, comment := range comments {
+		if comment != nil {
+			comment.DeletedBy = nil
+			comment.DeleteReason = nil
+		}
+	}
+}
+
+// applyViewer lets the viewer in ctx see their own unapproved comments in a listing
+func applyViewer(ctx context.Context, filter *models.CommentFilter) {
+	if viewer := ViewerFromContext(ctx); viewer != "" {
+		filter.ViewerID = &viewer
+	}
+}
+
+// visibleToViewer reports whether the viewer in ctx may see comment
+func visibleToViewer(ctx context.Context, comment *models.Comment) bool {
+	if comment.Status == models.CommentStatusApproved {
+		return true
+	}
+	viewer := ViewerFromContext(ctx)
+	return viewer != "" && viewer == comment.UserID
+}
+
+// ApproveComment publishes a pending, quarantined or previously rejected
+// comment. Callers are responsible for checking that moderatorID may moderate
+// the root.
+func (s *CommentService) ApproveComment(ctx context.Context, commentID, moderatorID string) (_ *models.Comment, err error) {
+	ctx, span := s.startSpan(ctx, "ApproveComment", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
+
+	comment, err := s.moderate(ctx, commentID, moderatorID, models.CommentStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	// Listeners hear about a held comment once it is public
+	s.emitCommentEvent(ctx, models.EventCommentCreated, commentID, comment.UserID, nil)
+	return comment, nil
+}
+
+// RejectComment turns down a pending or quarantined comment, keeping it hidden
+// from everyone but its author. Approved comments are removed with
+// DeleteComment instead.
+// Callers are responsible for checking that moderatorID may moderate the root.
+func (s *CommentService) RejectComment(ctx context.Context, commentID, moderatorID string) (_ *models.Comment, err error) {
+	ctx, span := s.startSpan(ctx, "RejectComment", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
+
+	return s.moderate(ctx, commentID, moderatorID, models.CommentStatusRejected)
+}
+
+// RemoveComment soft deletes a comment whoever wrote it, recording
+// moderatorID as DeletedBy along with reason, which may be empty. It returns
+// the removed comment with both fields set.
+// Callers are responsible for checking that moderatorID may moderate the root.
+func (s *CommentService) RemoveComment(ctx context.Context, commentID, moderatorID, reason string) (_ *models.Comment, err error) {
+	ctx, span := s.startSpan(ctx, "RemoveComment", attrCommentID.String(commentID))
+	defer func() { endSpan(span, err) }()
+
+	if commentID == "" {
+		return nil, invalidInput("comment ID is required")
+	}
+	if moderatorID == "" {
+		return nil, invalidInput("moderator ID is required")
+	}
+	why, err := deleteReason(reason)
+	if err != nil {
+		return nil, err
+	}
+
+	// Look up the root before the comment disappears from reads
+	comment, err := s.repo.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("comment not found: %w", err)
+	}
+
+	if err := s.repo.RemoveComment(ctx, commentID, moderatorID, why); err != nil {
+		return nil, err
+	}
+
+	// Listeners never saw a comment that was still held
+	if comment.Status == models.CommentStatusApproved {
+		s.emit(ctx, &models.CommentEvent{
+			Type:      models.EventCommentDeleted,
+			RootID:    comment.RootID,
+			CommentID: commentID,
+			UserID:    moderatorID,
+		})
+	}
+
+	removed, err := s.repo.GetCommentsByIDs(ctx, []string{commentID}, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(removed) == 0 {
+		return nil, fmt.Errorf("comment not found: %w", ErrNotFound)
+	}
+	s.renderContent(removed[0])
+	return removed[0], nil
+}
+
+// moderate moves a comment that is not yet approved to status
+func (s *CommentService) moderate(ctx context.Context, commentID, moderatorID string, status models.CommentStatus) (*models.Comment, error) {
+	if commentID == "" {
+		return nil, invalidInput("comment ID is required")
+	}
+	if moderatorID == "" {
+		return nil, invalidInput("moderator ID is required")
+	}
+
+	comment, err := s.repo.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("comment not found: %w", err)
+	}
+	if comment.Status == status {
+		s.renderContent(comment)
+		return comment, nil
+	}
+	if comment.Status == models.CommentStatusApproved {
+		return nil, invalidInput("comment has already been approved")
+	}
+
+	if err := s.repo.SetCommentStatus(ctx, commentID, status); err != nil {
+		return nil, fmt.Errorf("failed to set comment status: %w", err)
+	}
+
+	comment, err = s.repo.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	s.renderContent(comment)
+	return comment, nil
+}
+
+// GetModerationQueue retrieves a page of a root's comments awaiting
+// moderation, oldest first. Callers are responsible for checking that the
+// requesting user may moderate the root.
+func (s *CommentService) GetModerationQueue(ctx context.Context, rootID string, filter *models.CommentFilter) (_ []*models.Comment, err error) {
+	ctx, span := s.startSpan(ctx, "GetModerationQueue", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	if rootID == "" {
+		return nil, invalidInput("root ID is required")
+	}
+
+	if filter == nil {
+		filter = &models.CommentFilter{}
+	}
+	s.clampPage(&filter.Limit, &filter.Offset)
+
+	comments, err := s.repo.GetModerationQueue(ctx, rootID, filter)
+	if err != nil {
+		return nil, err
+	}
+	s.renderContent(comments...)
+	return comments, nil
+}
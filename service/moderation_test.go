@@ -0,0 +1,115 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestPreModeration_HidesCommentsUntilApproved(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	repo := memory.NewMemoryRepository()
+	if err := repo.CreateComment(ctx, &models.Comment{ID: "top", RootID: "post-1", UserID: "alice", Content: "Published before moderation"}); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	commentService := service.NewCommentServiceWithConfig(repo, &service.CommentServiceConfig{PreModeration: true})
+	parentID := "top"
+
+	// Execute
+	reply, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: &parentID, UserID: "bob", Content: "Held for review"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if reply.Status != models.CommentStatusPending {
+		t.Fatalf("Expected a pending comment, got status %q", reply.Status)
+	}
+
+	// Only the author sees it
+	if _, err := commentService.GetComment(ctx, reply.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound for an anonymous reader, got: %v", err)
+	}
+	if _, err := commentService.GetComment(service.WithViewer(ctx, "bob"), reply.ID); err != nil {
+		t.Fatalf("Expected the author to see their pending comment, got: %v", err)
+	}
+	public, err := commentService.GetCommentsByRoot(service.WithViewer(ctx, "carol"), "post-1", nil)
+	if err != nil || len(public) != 1 {
+		t.Fatalf("Expected only the approved comment for another user, got %d comments (err %v)", len(public), err)
+	}
+	own, err := commentService.GetCommentsByRoot(service.WithViewer(ctx, "bob"), "post-1", nil)
+	if err != nil || len(own) != 2 {
+		t.Fatalf("Expected the author to see both comments, got %d comments (err %v)", len(own), err)
+	}
+	parent, _ := commentService.GetComment(ctx, "top")
+	if parent.ReplyCount != 0 {
+		t.Fatalf("Expected pending replies to be left out of reply counts, got %d", parent.ReplyCount)
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, reply.ID, "carol", models.VoteTypeUp); !errors.Is(err, service.ErrInvalidInput) {
+		t.Fatalf("Expected voting on a pending comment to be refused, got: %v", err)
+	}
+
+	queue, err := commentService.GetModerationQueue(ctx, "post-1", nil)
+	if err != nil || len(queue) != 1 || queue[0].ID != reply.ID {
+		t.Fatalf("Expected the reply in the moderation queue, got: %+v (err %v)", queue, err)
+	}
+
+	// Approval publishes it
+	approved, err := commentService.ApproveComment(ctx, reply.ID, "mod")
+	if err != nil {
+		t.Fatalf("Failed to approve comment: %v", err)
+	}
+	if approved.Status != models.CommentStatusApproved {
+		t.Fatalf("Expected an approved comment, got status %q", approved.Status)
+	}
+	if _, err := commentService.GetComment(ctx, reply.ID); err != nil {
+		t.Fatalf("Expected the approved comment to be public, got: %v", err)
+	}
+	parent, _ = commentService.GetComment(ctx, "top")
+	if parent.ReplyCount != 1 || parent.DescendantCount != 1 {
+		t.Fatalf("Expected approval to update reply counts, got reply_count %d descendant_count %d", parent.ReplyCount, parent.DescendantCount)
+	}
+	if queue, _ := commentService.GetModerationQueue(ctx, "post-1", nil); len(queue) != 0 {
+		t.Fatalf("Expected an empty moderation queue, got %d comments", len(queue))
+	}
+	if _, err := commentService.RejectComment(ctx, reply.ID, "mod"); !errors.Is(err, service.ErrInvalidInput) {
+		t.Fatalf("Expected rejecting an approved comment to be refused, got: %v", err)
+	}
+}
+
+func TestRejectComment_KeepsCommentHidden(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{PreModeration: true})
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "bob", Content: "Buy cheap watches"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Execute
+	rejected, err := commentService.RejectComment(ctx, comment.ID, "mod")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if rejected.Status != models.CommentStatusRejected {
+		t.Fatalf("Expected a rejected comment, got status %q", rejected.Status)
+	}
+	if _, err := commentService.GetComment(ctx, comment.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound for a rejected comment, got: %v", err)
+	}
+	if queue, _ := commentService.GetModerationQueue(ctx, "post-1", nil); len(queue) != 0 {
+		t.Fatalf("Expected rejected comments to leave the queue, got %d comments", len(queue))
+	}
+	parentID := comment.ID
+	_, err = commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: &parentID, UserID: "carol", Content: "Reply"})
+	if !errors.Is(err, service.ErrInvalidInput) {
+		t.Fatalf("Expected replies to a rejected comment to be refused, got: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestMoveComment_RewritesSubtree(t *testing.T) {
+	// Setup: a -> b -> c, and a separate top-level comment d
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	create := func(parentID *string) *models.Comment {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", ParentID: parentID, UserID: "author", Content: "Comment"})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		return comment
+	}
+	a := create(nil)
+	b := create(&a.ID)
+	c := create(&b.ID)
+	d := create(nil)
+
+	// Execute
+	moved, err := commentService.MoveComment(ctx, b.ID, d.ID, "moderator")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if moved.ParentID == nil || *moved.ParentID != d.ID {
+		t.Fatalf("Expected parent %s, got: %v", d.ID, moved.ParentID)
+	}
+	if moved.Path != d.ID+"."+b.ID || moved.Depth != 1 {
+		t.Fatalf("Expected moved path %s at depth 1, got: %s at depth %d", d.ID+"."+b.ID, moved.Path, moved.Depth)
+	}
+
+	child, err := commentService.GetComment(ctx, c.ID)
+	if err != nil {
+		t.Fatalf("Failed to get descendant: %v", err)
+	}
+	if child.Path != d.ID+"."+b.ID+"."+c.ID || child.Depth != 2 {
+		t.Fatalf("Expected descendant path rewritten, got: %s at depth %d", child.Path, child.Depth)
+	}
+
+	oldParent, _ := commentService.GetComment(ctx, a.ID)
+	newParent, _ := commentService.GetComment(ctx, d.ID)
+	if oldParent.ReplyCount != 0 || oldParent.DescendantCount != 0 {
+		t.Fatalf("Expected old parent to lose the subtree, got: %d replies, %d descendants", oldParent.ReplyCount, oldParent.DescendantCount)
+	}
+	if newParent.ReplyCount != 1 || newParent.DescendantCount != 2 {
+		t.Fatalf("Expected new parent to gain the subtree, got: %d replies, %d descendants", newParent.ReplyCount, newParent.DescendantCount)
+	}
+}
+
+func TestMoveComment_RejectsCycles(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	parent, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Parent"})
+	if err != nil {
+		t.Fatalf("Failed to create parent: %v", err)
+	}
+	child, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", ParentID: &parent.ID, UserID: "author", Content: "Child"})
+	if err != nil {
+		t.Fatalf("Failed to create child: %v", err)
+	}
+	other, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-2", UserID: "author", Content: "Other root"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		commentID   string
+		newParentID string
+	}{
+		{name: "self parent", commentID: parent.ID, newParentID: parent.ID},
+		{name: "under own reply", commentID: parent.ID, newParentID: child.ID},
+		{name: "different root", commentID: child.ID, newParentID: other.ID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Execute
+			_, err := commentService.MoveComment(ctx, tt.commentID, tt.newParentID, "moderator")
+
+			// Assert
+			if !errors.Is(err, service.ErrInvalidInput) {
+				t.Fatalf("Expected ErrInvalidInput, got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ContentNormalization chooses what CreateComment and UpdateComment strip
+// from content, before its length is checked, beyond the surrounding
+// whitespace they always trim
+type ContentNormalization int
+
+const (
+	// NormalizeStandard, the default, strips control characters other than
+	// newlines and tabs, and invisible zero-width characters. Runs of
+	// zero-width joiners and non-joiners, which emoji sequences and some
+	// scripts need, collapse to one.
+	NormalizeStandard ContentNormalization = iota
+	// NormalizeStrict is NormalizeStandard that also strips every joiner and
+	// non-joiner, splitting emoji sequences into their parts
+	NormalizeStrict
+	// NormalizeNone only trims surrounding whitespace
+	NormalizeNone
+)
+
+// Zero-width characters that are invisible wherever they appear
+const (
+	zeroWidthSpace     = '\u200b'
+	wordJoiner         = '\u2060'
+	byteOrderMark      = '\ufeff'
+	zeroWidthNonJoiner = '\u200c'
+	zeroWidthJoiner    = '\u200d'
+)
+
+// normalizeContent trims content and applies the configured normalization
+func (s *CommentService) normalizeContent(content string) string {
+	if s.config.ContentNormalization != NormalizeNone {
+		content = stripInvisible(content, s.config.ContentNormalization == NormalizeStrict)
+	}
+	return strings.TrimSpace(content)
+}
+
+// stripInvisible drops control characters other than newlines and tabs and
+// the invisible zero-width characters. Joiners and non-joiners are dropped
+// too when strict is set; otherwise a run of them keeps only its first.
+func stripInvisible(content string, strict bool) string {
+	var b strings.Builder
+	b.Grow(len(content))
+	var prev rune
+	for _, r := range content {
+		switch {
+		case r == '\n' || r == '\t':
+		case unicode.IsControl(r), r == zeroWidthSpace, r == wordJoiner, r == byteOrderMark:
+			continue
+		case r == zeroWidthJoiner || r == zeroWidthNonJoiner:
+			if strict || prev == zeroWidthJoiner || prev == zeroWidthNonJoiner {
+				continue
+			}
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
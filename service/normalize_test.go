@@ -0,0 +1,97 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestCreateComment_NormalizesContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  service.ContentNormalization
+		content string
+		want    string
+	}{
+		{"zero-width space", service.NormalizeStandard, "he\u200bl\u200b\u200blo", "hello"},
+		{"control bytes", service.NormalizeStandard, "a\x00b\x07c\x1bd\x7f", "abcd"},
+		{"keeps newlines and tabs", service.NormalizeStandard, "line one\r\n\tline two", "line one\n\tline two"},
+		{"word joiner and BOM", service.NormalizeStandard, "\ufeffword\u2060joined", "wordjoined"},
+		{"collapses joiner runs", service.NormalizeStandard, "👩\u200d\u200d\u200d💻", "👩\u200d💻"},
+		{"trims what stripping exposes", service.NormalizeStandard, "\u200b  hi  \x00", "hi"},
+		{"strict drops joiners", service.NormalizeStrict, "👩\u200d💻 \u200cok", "👩💻 ok"},
+		{"none only trims", service.NormalizeNone, " a\u200bb\x00 ", "a\u200bb\x00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{ContentNormalization: tt.policy})
+
+			// Execute
+			comment, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: tt.content})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if comment.Content != tt.want {
+				t.Errorf("Expected content %q, got %q", tt.want, comment.Content)
+			}
+		})
+	}
+}
+
+func TestCreateComment_NormalizesBeforeLengthChecks(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{MinCommentLength: 3, MaxCommentLength: 5})
+
+	// Execute
+	_, invisibleErr := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "\u200b\u200b\u200b\x00"})
+	_, shortErr := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "o\u200b\u200bk"})
+	padded, paddedErr := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "he\u200b\u200b\u200b\u200bllo"})
+
+	// Assert
+	if !errors.Is(invisibleErr, service.ErrInvalidInput) {
+		t.Errorf("Expected only invisible characters to be rejected as empty, got: %v", invisibleErr)
+	}
+	if !errors.Is(shortErr, service.ErrContentTooShort) {
+		t.Errorf("Expected zero-width padding not to count toward the minimum, got: %v", shortErr)
+	}
+	if paddedErr != nil {
+		t.Fatalf("Expected zero-width padding not to count toward the maximum, got: %v", paddedErr)
+	}
+	if padded.Content != "hello" {
+		t.Errorf("Expected content %q, got %q", "hello", padded.Content)
+	}
+}
+
+func TestUpdateComment_NormalizesContent(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "hello"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	content := "edi\u200bted\x08"
+
+	// Execute
+	err = commentService.UpdateComment(ctx, comment.ID, "alice", &models.UpdateCommentRequest{Content: &content})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	updated, err := commentService.GetComment(ctx, comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	if updated.Content != "edited" {
+		t.Errorf("Expected content %q, got %q", "edited", updated.Content)
+	}
+}
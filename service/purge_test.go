@@ -0,0 +1,50 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestPurgeDeletedComments_RemovesVotes(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	repo := memory.NewMemoryRepository()
+	commentService := service.NewCommentService(repo)
+	deleted, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "To be deleted"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	live, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Still here"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	for _, comment := range []*models.Comment{deleted, live} {
+		if _, _, _, err := commentService.VoteComment(ctx, comment.ID, "voter", models.VoteTypeUp); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+	}
+	if err := commentService.DeleteComment(ctx, deleted.ID, "alice"); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+
+	// Execute: an age of 0 days purges everything deleted so far
+	purged, err := repo.PurgeDeletedComments(ctx, 0)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if purged != 1 {
+		t.Fatalf("Expected 1 comment purged, got %d", purged)
+	}
+	if votes, _ := repo.GetCommentVotes(ctx, deleted.ID); len(votes) != 0 {
+		t.Fatalf("Expected the purged comment's votes to be gone, got %d", len(votes))
+	}
+	if votes, _ := repo.GetCommentVotes(ctx, live.ID); len(votes) != 1 {
+		t.Fatalf("Expected the live comment to keep its vote, got %d", len(votes))
+	}
+}
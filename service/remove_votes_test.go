@@ -0,0 +1,68 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestBatchRemoveVotes(t *testing.T) {
+	// Setup: carol votes on two comments and dave on one of them
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{MaxBatchSize: 3})
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Vote on me"})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		ids = append(ids, comment.ID)
+	}
+	for _, vote := range []struct {
+		commentID, userID string
+		voteType          models.VoteType
+	}{
+		{ids[0], "carol", models.VoteTypeUp},
+		{ids[1], "carol", models.VoteTypeDown},
+		{ids[0], "dave", models.VoteTypeUp},
+	} {
+		if _, _, _, err := commentService.VoteComment(ctx, vote.commentID, vote.userID, vote.voteType); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+	}
+
+	// Execute: the third comment has no vote from carol
+	err := commentService.BatchRemoveVotes(ctx, ids, "carol")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	comments, err := commentService.GetCommentsByIDs(ctx, ids[:2], false)
+	if err != nil {
+		t.Fatalf("Failed to get comments: %v", err)
+	}
+	if comments[0].Upvotes != 1 || comments[0].Score != 1 {
+		t.Errorf("Expected only dave's upvote left on the first comment, got %d up and score %d", comments[0].Upvotes, comments[0].Score)
+	}
+	if comments[1].Downvotes != 0 || comments[1].Score != 0 {
+		t.Errorf("Expected no votes left on the second comment, got %d down and score %d", comments[1].Downvotes, comments[1].Score)
+	}
+	votes, err := commentService.GetUserVotesForComments(ctx, ids, "carol")
+	if err != nil {
+		t.Fatalf("Failed to get votes: %v", err)
+	}
+	if len(votes) != 0 {
+		t.Errorf("Expected carol to have no votes left, got %d", len(votes))
+	}
+
+	err = commentService.BatchRemoveVotes(ctx, append(ids, ids[0]), "carol")
+	if !errors.Is(err, service.ErrBatchTooLarge) {
+		t.Errorf("Expected ErrBatchTooLarge past MaxBatchSize, got: %v", err)
+	}
+}
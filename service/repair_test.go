@@ -0,0 +1,54 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestRepairCommentPaths_ConsistentTree(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	parent, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "author", Content: "Parent"})
+	if err != nil {
+		t.Fatalf("Failed to create parent: %v", err)
+	}
+	child, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", ParentID: &parent.ID, UserID: "author", Content: "Child"})
+	if err != nil {
+		t.Fatalf("Failed to create child: %v", err)
+	}
+
+	// Execute
+	repaired, err := commentService.RepairCommentPaths(ctx, "product-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if repaired != 0 {
+		t.Fatalf("Expected no repairs on a consistent tree, got: %d", repaired)
+	}
+	unchanged, _ := commentService.GetComment(ctx, child.ID)
+	if unchanged.Path != parent.ID+"."+child.ID || unchanged.Depth != 1 {
+		t.Fatalf("Expected path to be untouched, got: %s at depth %d", unchanged.Path, unchanged.Depth)
+	}
+}
+
+func TestRepairCommentPaths_RequiresRoot(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	// Execute
+	_, err := commentService.RepairCommentPaths(context.Background(), "")
+
+	// Assert
+	if !errors.Is(err, service.ErrInvalidInput) {
+		t.Fatalf("Expected ErrInvalidInput, got: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestGetRepliesToUser(t *testing.T) {
+	// Setup: bob and then carol reply to alice; alice answers herself and
+	// dave replies to bob, neither of which belongs in her feed
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	now := time.Now()
+	seed := func(id, userID string, parent *models.Comment, age time.Duration) *models.Comment {
+		comment := &models.Comment{ID: id, RootID: "post-1", UserID: userID, Content: "Comment by " + userID, CreatedAt: now.Add(-age)}
+		if parent != nil {
+			comment.ParentID = &parent.ID
+		}
+		return comment
+	}
+	question := seed("00000000-0000-0000-0000-000000000001", "alice", nil, time.Hour)
+	fromBob := seed("00000000-0000-0000-0000-000000000002", "bob", question, 30*time.Minute)
+	fromCarol := seed("00000000-0000-0000-0000-000000000003", "carol", question, 10*time.Minute)
+	err := commentService.ImportComments(ctx, []*models.Comment{
+		question,
+		fromBob,
+		fromCarol,
+		seed("00000000-0000-0000-0000-000000000004", "alice", question, 5*time.Minute),
+		seed("00000000-0000-0000-0000-000000000005", "dave", fromBob, time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("Failed to seed comments: %v", err)
+	}
+
+	// Execute
+	replies, err := commentService.GetRepliesToUser(ctx, "alice", nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(replies) != 2 {
+		t.Fatalf("Expected 2 replies, got: %d", len(replies))
+	}
+	if replies[0].ID != fromCarol.ID || replies[1].ID != fromBob.ID {
+		t.Errorf("Expected carol's reply then bob's, got: %s, %s", replies[0].UserID, replies[1].UserID)
+	}
+	for _, reply := range replies {
+		if reply.Parent == nil || reply.Parent.ID != question.ID || reply.Parent.Content != question.Content {
+			t.Errorf("Expected each reply to carry alice's comment, got: %+v", reply.Parent)
+		}
+	}
+
+	// Pages follow the same order
+	limit, offset := 1, 1
+	page, err := commentService.GetRepliesToUser(ctx, "alice", &models.CommentFilter{Limit: &limit, Offset: &offset})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(page) != 1 || page[0].ID != fromBob.ID {
+		t.Errorf("Expected the second page to hold bob's reply, got: %+v", page)
+	}
+}
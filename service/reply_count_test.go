@@ -0,0 +1,74 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestReplyCounts_NestedCreatesAndDelete(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	create := func(parentID *string, userID string) *models.Comment {
+		t.Helper()
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{
+			RootID:   "product-1",
+			ParentID: parentID,
+			UserID:   userID,
+			Content:  "Comment",
+		})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		return comment
+	}
+	counts := func(id string) (int, int) {
+		t.Helper()
+		comment, err := commentService.GetComment(ctx, id)
+		if err != nil {
+			t.Fatalf("Failed to get comment: %v", err)
+		}
+		return comment.ReplyCount, comment.DescendantCount
+	}
+
+	// Execute: root -> (a -> a1, a2), b
+	root := create(nil, "author")
+	a := create(&root.ID, "user-a")
+	create(&a.ID, "user-a1")
+	a2 := create(&a.ID, "user-a2")
+	create(&root.ID, "user-b")
+
+	// Assert
+	if replies, descendants := counts(root.ID); replies != 2 || descendants != 4 {
+		t.Fatalf("Expected root to have 2 replies and 4 descendants, got %d and %d", replies, descendants)
+	}
+	if replies, descendants := counts(a.ID); replies != 2 || descendants != 2 {
+		t.Fatalf("Expected a to have 2 replies and 2 descendants, got %d and %d", replies, descendants)
+	}
+
+	// Execute: delete a nested reply
+	if err := commentService.DeleteComment(ctx, a2.ID, "user-a2"); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+
+	// Assert
+	if replies, descendants := counts(root.ID); replies != 2 || descendants != 3 {
+		t.Fatalf("Expected root to have 2 replies and 3 descendants after delete, got %d and %d", replies, descendants)
+	}
+	if replies, descendants := counts(a.ID); replies != 1 || descendants != 1 {
+		t.Fatalf("Expected a to have 1 reply and 1 descendant after delete, got %d and %d", replies, descendants)
+	}
+
+	// Backfill reproduces the incrementally maintained counts
+	if err := commentService.BackfillReplyCounts(ctx); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if replies, descendants := counts(root.ID); replies != 2 || descendants != 3 {
+		t.Fatalf("Expected backfill to keep 2 replies and 3 descendants, got %d and %d", replies, descendants)
+	}
+}
@@ -0,0 +1,103 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestGetUserReputation_AcrossRoots(t *testing.T) {
+	// Setup: alice comments on two roots and collects votes on both
+	ctx := context.Background()
+	repo := memory.NewMemoryRepository()
+	commentService := service.NewCommentService(repo)
+	create := func(rootID, userID string) *models.Comment {
+		t.Helper()
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: rootID, UserID: userID, Content: "Comment"})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		return comment
+	}
+	vote := func(commentID, userID string, voteType models.VoteType) {
+		t.Helper()
+		if _, _, _, err := commentService.VoteComment(ctx, commentID, userID, voteType); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+	}
+	onFirst := create("post-1", "alice")
+	onSecond := create("post-2", "alice")
+	deleted := create("post-3", "alice")
+	other := create("post-1", "bob")
+	vote(onFirst.ID, "bob", models.VoteTypeUp)
+	vote(onFirst.ID, "carol", models.VoteTypeUp)
+	vote(onSecond.ID, "bob", models.VoteTypeUp)
+	vote(onSecond.ID, "carol", models.VoteTypeDown)
+	vote(deleted.ID, "bob", models.VoteTypeUp)
+	vote(other.ID, "alice", models.VoteTypeUp)
+	if err := commentService.DeleteComment(ctx, deleted.ID, "alice"); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+	// A self-vote that bypassed the service, as an import might leave
+	if err := repo.CreateVote(ctx, &models.Vote{CommentID: onSecond.ID, UserID: "alice", VoteType: models.VoteTypeUp}); err != nil {
+		t.Fatalf("Failed to seed self-vote: %v", err)
+	}
+
+	// Execute
+	reputation, err := commentService.GetUserReputation(ctx, "alice")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	want := models.UserReputation{UserID: "alice", CommentCount: 2, TotalScore: 2, Upvotes: 3, Downvotes: 1}
+	if *reputation != want {
+		t.Errorf("Expected %+v, got: %+v", want, *reputation)
+	}
+
+	// Unknown users have no reputation yet
+	reputation, err = commentService.GetUserReputation(ctx, "nobody")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if reputation.CommentCount != 0 || reputation.TotalScore != 0 {
+		t.Errorf("Expected zero reputation, got: %+v", reputation)
+	}
+}
+
+func TestGetUserReputation_Weighted(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	commentService.SetVoteWeightResolver(roleWeights{experts: map[string]bool{"expert": true}})
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Comment"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	for userID, voteType := range map[string]models.VoteType{"expert": models.VoteTypeUp, "bob": models.VoteTypeDown} {
+		if _, _, _, err := commentService.VoteComment(ctx, comment.ID, userID, voteType); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+	}
+
+	// Execute
+	reputation, err := commentService.GetUserReputation(ctx, "alice")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	voted, err := commentService.GetComment(ctx, comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	if reputation.TotalScore != 2 || reputation.TotalScore != voted.Score {
+		t.Errorf("Expected a total of 2 matching the comment's score %d, got: %+v", voted.Score, reputation)
+	}
+	if reputation.Upvotes != 1 || reputation.Downvotes != 1 {
+		t.Errorf("Expected one vote each way, got: %+v", reputation)
+	}
+}
@@ -0,0 +1,69 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestSearchAllComments(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	seed := []models.CreateCommentRequest{
+		{RootID: "product-1", UserID: "alice", Content: "Great battery life"},
+		{RootID: "product-2", UserID: "bob", Content: "Battery died after a week"},
+		{RootID: "product-3", UserID: "alice", Content: "Nice screen"},
+	}
+	for i := range seed {
+		if _, err := commentService.CreateComment(ctx, &seed[i]); err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+	}
+
+	// Execute
+	results, err := commentService.SearchAllComments(ctx, "battery", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 matches across roots, got: %d", len(results))
+	}
+	roots := map[string]bool{}
+	for _, comment := range results {
+		roots[comment.RootID] = true
+	}
+	if !roots["product-1"] || !roots["product-2"] {
+		t.Fatalf("Expected matches from product-1 and product-2, got: %v", roots)
+	}
+
+	// Narrow to a single user
+	userID := "alice"
+	results, err = commentService.SearchAllComments(ctx, "battery", &models.CommentFilter{UserID: &userID})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(results) != 1 || results[0].UserID != "alice" {
+		t.Fatalf("Expected 1 match for alice, got: %d", len(results))
+	}
+}
+
+func TestSearchAllComments_ShortQuery(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	// Execute
+	_, err := commentService.SearchAllComments(context.Background(), " ab ", nil)
+
+	// Assert
+	if !errors.Is(err, service.ErrInvalidInput) {
+		t.Fatalf("Expected ErrInvalidInput, got: %v", err)
+	}
+}
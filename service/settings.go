@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+// rootRules is the configuration in effect for one root: its overrides
+// applied over CommentServiceConfig
+type rootRules struct {
+	maxDepth       int
+	locked         bool
+	preModeration  bool
+	allowAnonymous bool
+}
+
+// rulesFor resolves the rules for rootID, falling back to the service
+// configuration for anything the root doesn't override
+func (s *CommentService) rulesFor(ctx context.Context, rootID string) (rootRules, error) {
+	rules := rootRules{
+		maxDepth:       s.config.MaxCommentDepth,
+		preModeration:  s.config.PreModeration,
+		allowAnonymous: s.config.AllowAnonymous,
+	}
+
+	settings, err := s.repo.GetRootSettings(ctx, rootID)
+	if errors.Is(err, ErrNotFound) {
+		return rules, nil
+	}
+	if err != nil {
+		return rules, fmt.Errorf("failed to get root settings: %w", err)
+	}
+
+	if settings.MaxDepth != nil {
+		rules.maxDepth = *settings.MaxDepth
+	}
+	if settings.Locked != nil {
+		rules.locked = *settings.Locked
+	}
+	if settings.PreModeration != nil {
+		rules.preModeration = *settings.PreModeration
+	}
+	if settings.AllowAnonymous != nil {
+		rules.allowAnonymous = *settings.AllowAnonymous
+	}
+	return rules, nil
+}
+
+// GetRootSettings retrieves the overrides set for a root. A root without any
+// gets settings with every field nil.
+func (s *CommentService) GetRootSettings(ctx context.Context, rootID string) (_ *models.RootSettings, err error) {
+	ctx, span := s.startSpan(ctx, "GetRootSettings", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	if rootID == "" {
+		return nil, invalidInput("root ID is required")
+	}
+
+	settings, err := s.repo.GetRootSettings(ctx, rootID)
+	if errors.Is(err, ErrNotFound) {
+		return &models.RootSettings{RootID: rootID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SetRootSettings replaces the overrides for settings.RootID; nil fields go
+// back to the service configuration. Callers are responsible for checking
+// that the user may administer the root.
+func (s *CommentService) SetRootSettings(ctx context.Context, settings *models.RootSettings) (_ *models.RootSettings, err error) {
+	if settings == nil {
+		return nil, invalidInput("settings are required")
+	}
+	ctx, span := s.startSpan(ctx, "SetRootSettings", attrRootID.String(settings.RootID))
+	defer func() { endSpan(span, err) }()
+
+	if settings.RootID == "" {
+		return nil, invalidInput("root ID is required")
+	}
+	if settings.MaxDepth != nil && *settings.MaxDepth < 0 {
+		return nil, invalidInput("max depth cannot be negative")
+	}
+
+	if err := s.repo.SetRootSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
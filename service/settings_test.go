@@ -0,0 +1,142 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestRootSettings_LockOverridesGlobalDefault(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	existing, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "article-1", UserID: "alice", Content: "Before the lock"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	locked := true
+	if _, err := commentService.SetRootSettings(ctx, &models.RootSettings{RootID: "article-1", Locked: &locked}); err != nil {
+		t.Fatalf("Failed to set root settings: %v", err)
+	}
+
+	// Execute
+	_, lockedErr := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "article-1", UserID: "bob", Content: "Too late"})
+	_, replyErr := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "article-1", ParentID: &existing.ID, UserID: "bob", Content: "Also too late"})
+	_, otherErr := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "thread-1", UserID: "bob", Content: "Forum threads never lock"})
+
+	// Assert
+	if !errors.Is(lockedErr, service.ErrRootLocked) || !errors.Is(replyErr, service.ErrRootLocked) {
+		t.Errorf("Expected ErrRootLocked on the locked root, got %v and %v", lockedErr, replyErr)
+	}
+	if otherErr != nil {
+		t.Errorf("Expected other roots to follow the global default, got %v", otherErr)
+	}
+	if _, err := commentService.GetComment(ctx, existing.ID); err != nil {
+		t.Errorf("Expected existing comments to stay readable, got %v", err)
+	}
+}
+
+func TestRootSettings_OverridesFallBackToConfig(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{AllowAnonymous: true})
+	maxDepth, preModeration, allowAnonymous := 1, true, false
+	if _, err := commentService.SetRootSettings(ctx, &models.RootSettings{RootID: "post-1", MaxDepth: &maxDepth, PreModeration: &preModeration, AllowAnonymous: &allowAnonymous}); err != nil {
+		t.Fatalf("Failed to set root settings: %v", err)
+	}
+	create := func(rootID string, parentID *string) (*models.Comment, error) {
+		return commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: rootID, ParentID: parentID, UserID: "alice", Content: "Hello"})
+	}
+
+	// Execute
+	held, err := create("post-1", nil)
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	public, err := create("post-2", nil)
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	_, anonymousErr := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", Anonymous: true, Content: "Guest"})
+	_, guestErr := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-2", Anonymous: true, Content: "Guest"})
+
+	// Assert
+	if held.Status != models.CommentStatusPending || public.Status != models.CommentStatusApproved {
+		t.Errorf("Expected pending on post-1 and approved on post-2, got %s and %s", held.Status, public.Status)
+	}
+	if !errors.Is(anonymousErr, service.ErrInvalidInput) || guestErr != nil {
+		t.Errorf("Expected guests refused on post-1 only, got %v and %v", anonymousErr, guestErr)
+	}
+
+	// Max depth: approve the held comment so it can be replied to
+	if _, err := commentService.ApproveComment(ctx, held.ID, "mod-1"); err != nil {
+		t.Fatalf("Failed to approve comment: %v", err)
+	}
+	reply, err := create("post-1", &held.ID)
+	if err != nil {
+		t.Fatalf("Failed to reply: %v", err)
+	}
+	if _, err := commentService.ApproveComment(ctx, reply.ID, "mod-1"); err != nil {
+		t.Fatalf("Failed to approve reply: %v", err)
+	}
+	_, deepErr := create("post-1", &reply.ID)
+	var depthErr *service.MaxDepthError
+	if !errors.As(deepErr, &depthErr) || depthErr.Limit != 1 {
+		t.Errorf("Expected a MaxDepthError with limit 1, got %v", deepErr)
+	}
+}
+
+func TestRootSettings_MaxDepthLimitsTreeReads(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	var parentID *string
+	for i := 0; i < 3; i++ {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: parentID, UserID: "alice", Content: "Nested"})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		parentID = &comment.ID
+	}
+	maxDepth := 1
+	if _, err := commentService.SetRootSettings(ctx, &models.RootSettings{RootID: "post-1", MaxDepth: &maxDepth}); err != nil {
+		t.Fatalf("Failed to set root settings: %v", err)
+	}
+
+	// Execute
+	tree, err := commentService.GetCommentTree(ctx, "post-1", 10, "")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to get tree: %v", err)
+	}
+	if len(tree) != 1 || len(tree[0].Children) != 1 || len(tree[0].Children[0].Children) != 0 {
+		t.Errorf("Expected the tree to stop at depth 1, got %+v", tree)
+	}
+}
+
+func TestRootSettings_GetAndValidate(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	negative := -1
+
+	// Execute
+	empty, err := commentService.GetRootSettings(ctx, "post-1")
+	_, invalidErr := commentService.SetRootSettings(ctx, &models.RootSettings{RootID: "post-1", MaxDepth: &negative})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to get root settings: %v", err)
+	}
+	if empty.RootID != "post-1" || empty.MaxDepth != nil || empty.Locked != nil || empty.PreModeration != nil || empty.AllowAnonymous != nil {
+		t.Errorf("Expected empty settings for a root without overrides, got %+v", empty)
+	}
+	if !errors.Is(invalidErr, service.ErrInvalidInput) {
+		t.Errorf("Expected ErrInvalidInput for a negative max depth, got %v", invalidErr)
+	}
+}
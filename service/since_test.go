@@ -0,0 +1,154 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestGetCommentsSince(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	create := func(content string) *models.Comment {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: content})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		return comment
+	}
+	// poll returns the changes after cursor and moves it past them
+	cursor, afterID := time.Now().Add(-time.Second), ""
+	poll := func() []*models.Comment {
+		t.Helper()
+		time.Sleep(time.Millisecond)
+		comments, err := commentService.GetCommentsSince(ctx, "post-1", cursor, afterID, 0)
+		if err != nil {
+			t.Fatalf("Failed to poll: %v", err)
+		}
+		if len(comments) > 0 {
+			cursor, afterID = comments[len(comments)-1].UpdatedAt, comments[len(comments)-1].ID
+		}
+		return comments
+	}
+
+	// Execute and Assert: creations
+	first := create("First")
+	second := create("Second")
+	if _, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-2", UserID: "alice", Content: "Elsewhere"}); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	created := poll()
+	if len(created) != 2 || created[0].ID != first.ID || created[1].ID != second.ID {
+		t.Fatalf("Expected both new comments in creation order, got %+v", created)
+	}
+	if again := poll(); len(again) != 0 {
+		t.Fatalf("Expected nothing new, got %d comments", len(again))
+	}
+
+	// Edits
+	newContent := "First, edited"
+	if err := commentService.UpdateComment(ctx, first.ID, "alice", &models.UpdateCommentRequest{Content: &newContent}); err != nil {
+		t.Fatalf("Failed to edit: %v", err)
+	}
+	edited := poll()
+	if len(edited) != 1 || edited[0].ID != first.ID || edited[0].Content != "First, edited" {
+		t.Fatalf("Expected only the edited comment, got %+v", edited)
+	}
+
+	// Deletions
+	if err := commentService.DeleteComment(ctx, second.ID, "alice"); err != nil {
+		t.Fatalf("Failed to delete: %v", err)
+	}
+	deleted := poll()
+	if len(deleted) != 1 || deleted[0].ID != second.ID {
+		t.Fatalf("Expected only the deleted comment, got %+v", deleted)
+	}
+	if !deleted[0].IsDeleted || deleted[0].Content != "" {
+		t.Errorf("Expected a tombstone with its content cleared, got deleted=%v content=%q", deleted[0].IsDeleted, deleted[0].Content)
+	}
+}
+
+func TestGetCommentsSince_Pages(t *testing.T) {
+	// Setup: five comments stamped with the same time, so pages must resume
+	// inside the tie
+	ctx := context.Background()
+	repo := memory.NewMemoryRepository()
+	commentService := service.NewCommentService(repo)
+	stamp := time.Now().Add(-time.Minute).UTC()
+	var comments []*models.Comment
+	for _, id := range []string{"c-3", "c-1", "c-5", "c-2", "c-4"} {
+		comments = append(comments, &models.Comment{ID: id, RootID: "post-1", UserID: "alice", Content: "Imported", Path: id, CreatedAt: stamp, UpdatedAt: stamp})
+	}
+	if err := repo.ImportComments(ctx, comments); err != nil {
+		t.Fatalf("Failed to import comments: %v", err)
+	}
+
+	// Execute
+	var seen []string
+	since, afterID := stamp.Add(-time.Second), ""
+	for pages := 0; pages < 5; pages++ {
+		page, err := commentService.GetCommentsSince(ctx, "post-1", since, afterID, 2)
+		if err != nil {
+			t.Fatalf("Failed to poll: %v", err)
+		}
+		if len(page) > 2 {
+			t.Fatalf("Expected at most 2 comments a page, got %d", len(page))
+		}
+		for _, comment := range page {
+			seen = append(seen, comment.ID)
+		}
+		if len(page) < 2 {
+			break
+		}
+		since, afterID = page[len(page)-1].UpdatedAt, page[len(page)-1].ID
+	}
+
+	// Assert
+	if len(seen) != 5 {
+		t.Fatalf("Expected every comment exactly once across the pages, got %v", seen)
+	}
+	for i := 1; i < len(seen); i++ {
+		if seen[i] <= seen[i-1] {
+			t.Fatalf("Expected comments in ID order within the tie, got %v", seen)
+		}
+	}
+}
+
+func TestGetCommentsSince_NeverApprovedDeletions(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{PreModeration: true})
+	cutoff := time.Now().Add(-time.Second)
+	pending, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Never shown"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	shown, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Shown"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	if _, err := commentService.ApproveComment(ctx, shown.ID, "moderator"); err != nil {
+		t.Fatalf("Failed to approve comment: %v", err)
+	}
+	for _, comment := range []*models.Comment{pending, shown} {
+		if err := commentService.DeleteComment(ctx, comment.ID, "alice"); err != nil {
+			t.Fatalf("Failed to delete comment: %v", err)
+		}
+	}
+
+	// Execute
+	comments, err := commentService.GetCommentsSince(ctx, "post-1", cutoff, "", 0)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(comments) != 1 || comments[0].ID != shown.ID || !comments[0].IsDeleted {
+		t.Errorf("Expected only the approved comment's tombstone, got %+v", comments)
+	}
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+// DefaultSpamThreshold is the spam score above which new comments are
+// quarantined when CommentServiceConfig.SpamThreshold is unset
+const DefaultSpamThreshold = 0.8
+
+// SpamScorer rates new comments before they are stored, from 0 (clean) to 1
+// (certainly spam). Replace the default, which scores everything 0, with
+// SetSpamScorer.
+type SpamScorer interface {
+	Score(ctx context.Context, comment *models.Comment) (float64, error)
+}
+
+// noopSpamScorer lets every comment through
+type noopSpamScorer struct{}
+
+func (noopSpamScorer) Score(ctx context.Context, comment *models.Comment) (float64, error) {
+	return 0, nil
+}
+
+// SetSpamScorer replaces the scorer consulted by CreateComment. A nil scorer
+// restores the default, which quarantines nothing.
+func (s *CommentService) SetSpamScorer(scorer SpamScorer) {
+	if scorer == nil {
+		scorer = noopSpamScorer{}
+	}
+	s.spamScorer = scorer
+}
+
+// quarantineIfSpam holds comment for moderation as quarantined when its spam
+// score is above the threshold. A failing scorer is logged and the comment
+// keeps its status, so an outage doesn't block commenting.
+func (s *CommentService) quarantineIfSpam(ctx context.Context, comment *models.Comment) {
+	score, err := s.spamScorer.Score(ctx, comment)
+	if err != nil {
+		s.logger.WarnContext(ctx, "spam scoring failed", "method", "CommentService.CreateComment", "error", err)
+		return
+	}
+	if score > s.config.SpamThreshold {
+		comment.Status = models.CommentStatusQuarantined
+	}
+}
@@ -0,0 +1,100 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+// keywordScorer rates content containing "casino" as spam
+type keywordScorer struct {
+	err error
+}
+
+func (s keywordScorer) Score(ctx context.Context, comment *models.Comment) (float64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	if strings.Contains(comment.Content, "casino") {
+		return 0.95, nil
+	}
+	return 0.1, nil
+}
+
+func TestCreateComment_QuarantinesSpam(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	commentService.SetSpamScorer(keywordScorer{})
+
+	// Execute
+	spam, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "spammer", Content: "Best online casino bonuses"})
+	if err != nil {
+		t.Fatalf("Failed to create spam comment: %v", err)
+	}
+	ham, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Great write-up"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Assert
+	if spam.Status != models.CommentStatusQuarantined {
+		t.Fatalf("Expected high-score content to be quarantined, got status %q", spam.Status)
+	}
+	if ham.Status != models.CommentStatusApproved {
+		t.Fatalf("Expected low-score content to be published, got status %q", ham.Status)
+	}
+	if _, err := commentService.GetComment(ctx, spam.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Fatalf("Expected quarantined comment to be hidden, got: %v", err)
+	}
+	queue, err := commentService.GetModerationQueue(ctx, "post-1", nil)
+	if err != nil || len(queue) != 1 || queue[0].ID != spam.ID {
+		t.Fatalf("Expected the quarantined comment in the moderation queue, got: %+v (err %v)", queue, err)
+	}
+
+	// A moderator can release a false positive
+	approved, err := commentService.ApproveComment(ctx, spam.ID, "mod")
+	if err != nil || approved.Status != models.CommentStatusApproved {
+		t.Fatalf("Expected the quarantined comment to be approved, got: %+v (err %v)", approved, err)
+	}
+}
+
+func TestCreateComment_SpamThreshold(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{SpamThreshold: 0.05})
+	commentService.SetSpamScorer(keywordScorer{})
+
+	// Execute
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Great write-up"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if comment.Status != models.CommentStatusQuarantined {
+		t.Fatalf("Expected a score above the lowered threshold to quarantine, got status %q", comment.Status)
+	}
+}
+
+func TestCreateComment_SpamScorerFailurePublishes(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	commentService.SetSpamScorer(keywordScorer{err: errors.New("scorer unavailable")})
+
+	// Execute
+	comment, err := commentService.CreateComment(context.Background(), &models.CreateCommentRequest{RootID: "post-1", UserID: "spammer", Content: "Best online casino bonuses"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if comment.Status != models.CommentStatusApproved {
+		t.Fatalf("Expected the comment to be published when scoring fails, got status %q", comment.Status)
+	}
+}
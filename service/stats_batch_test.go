@@ -0,0 +1,84 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestGetCommentStatsBatch(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	seed := map[string]int{"product-1": 3, "product-2": 1, "product-3": 2}
+	for rootID, count := range seed {
+		for i := 0; i < count; i++ {
+			_, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: rootID, UserID: "author", Content: "Comment"})
+			if err != nil {
+				t.Fatalf("Failed to create comment: %v", err)
+			}
+		}
+	}
+
+	// Execute
+	stats, err := commentService.GetCommentStatsBatch(ctx, []string{"product-1", "product-2", "product-3", "empty-product"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if len(stats) != 4 {
+		t.Fatalf("Expected stats for 4 roots, got: %d", len(stats))
+	}
+	for rootID, count := range seed {
+		if stats[rootID] == nil || stats[rootID].TotalCount != int64(count) {
+			t.Fatalf("Expected %d comments for %s, got: %+v", count, rootID, stats[rootID])
+		}
+	}
+	empty := stats["empty-product"]
+	if empty == nil || empty.RootID != "empty-product" || empty.TotalCount != 0 {
+		t.Fatalf("Expected zero-valued stats for empty root, got: %+v", empty)
+	}
+}
+
+func TestGetCommentStats_Participants(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	create := func(userID string, parentID *string) *models.Comment {
+		t.Helper()
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: parentID, UserID: userID, Content: "Comment"})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		return comment
+	}
+	first := create("alice", nil)
+	create("alice", &first.ID)
+	reply := create("bob", &first.ID)
+	deleted := create("carol", nil)
+	if err := commentService.DeleteComment(ctx, deleted.ID, "carol"); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+
+	// Execute
+	stats, err := commentService.GetCommentStats(ctx, "post-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if stats.ParticipantCount != 2 {
+		t.Errorf("Expected 2 participants, got: %d", stats.ParticipantCount)
+	}
+	if stats.AvgDepth < 0.66 || stats.AvgDepth > 0.67 {
+		t.Errorf("Expected an average depth of 2/3, got: %v", stats.AvgDepth)
+	}
+	if stats.LastCommentAt == nil || !stats.LastCommentAt.Equal(reply.CreatedAt) {
+		t.Errorf("Expected the last comment time %v, got: %v", reply.CreatedAt, stats.LastCommentAt)
+	}
+}
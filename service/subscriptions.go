@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+
+	"github.com/christopher18/commentific/v2/models"
+)
+
+// Subscribe makes userID a subscriber of rootID. Subscribing again is not an
+// error.
+func (s *CommentService) Subscribe(ctx context.Context, userID, rootID string) (err error) {
+	ctx, span := s.startSpan(ctx, "Subscribe", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	if err := checkSubscription(userID, rootID); err != nil {
+		return err
+	}
+	return s.repo.Subscribe(ctx, userID, rootID)
+}
+
+// Unsubscribe drops userID from the subscribers of rootID. Unsubscribing
+// when not subscribed is not an error.
+func (s *CommentService) Unsubscribe(ctx context.Context, userID, rootID string) (err error) {
+	ctx, span := s.startSpan(ctx, "Unsubscribe", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	if err := checkSubscription(userID, rootID); err != nil {
+		return err
+	}
+	return s.repo.Unsubscribe(ctx, userID, rootID)
+}
+
+// GetSubscriberUserIDs lists the users subscribed to rootID, sorted. An
+// EventEmitter can call it on comment.created to fan notifications out,
+// skipping the event's own UserID.
+func (s *CommentService) GetSubscriberUserIDs(ctx context.Context, rootID string) (_ []string, err error) {
+	ctx, span := s.startSpan(ctx, "GetSubscriberUserIDs", attrRootID.String(rootID))
+	defer func() { endSpan(span, err) }()
+
+	if rootID == "" {
+		return nil, invalidInput("root ID is required")
+	}
+	return s.repo.GetSubscriberUserIDs(ctx, rootID)
+}
+
+// checkSubscription validates the user and root named in a subscription
+func checkSubscription(userID, rootID string) error {
+	if userID == "" {
+		return invalidField("user_id", "required", "user ID is required")
+	}
+	if rootID == "" {
+		return invalidField("root_id", "required", "root ID is required")
+	}
+	return nil
+}
+
+// autoSubscribe subscribes a new comment's author to its root when
+// AutoSubscribe is set. The comment is already stored, so a failure is
+// logged rather than returned.
+func (s *CommentService) autoSubscribe(ctx context.Context, comment *models.Comment) {
+	if !s.config.AutoSubscribe {
+		return
+	}
+	if err := s.repo.Subscribe(ctx, comment.UserID, comment.RootID); err != nil {
+		s.logger.WarnContext(ctx, "auto-subscribe failed", "method", "CommentService.CreateComment",
+			"comment_id", comment.ID, "error", err)
+	}
+}
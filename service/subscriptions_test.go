@@ -0,0 +1,86 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestSubscribe(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	// Execute
+	for _, userID := range []string{"bob", "alice", "bob"} {
+		if err := commentService.Subscribe(ctx, userID, "post-1"); err != nil {
+			t.Fatalf("Failed to subscribe %s: %v", userID, err)
+		}
+	}
+	subscribed, err := commentService.GetSubscriberUserIDs(ctx, "post-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := commentService.Unsubscribe(ctx, "bob", "post-1"); err != nil {
+		t.Fatalf("Failed to unsubscribe: %v", err)
+	}
+	// Unsubscribing twice is harmless
+	if err := commentService.Unsubscribe(ctx, "bob", "post-1"); err != nil {
+		t.Fatalf("Failed to unsubscribe again: %v", err)
+	}
+	unsubscribed, err := commentService.GetSubscriberUserIDs(ctx, "post-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	other, err := commentService.GetSubscriberUserIDs(ctx, "post-2")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if strings.Join(subscribed, ",") != "alice,bob" {
+		t.Errorf("Expected alice and bob once each, got: %v", subscribed)
+	}
+	if strings.Join(unsubscribed, ",") != "alice" {
+		t.Errorf("Expected only alice after bob unsubscribed, got: %v", unsubscribed)
+	}
+	if len(other) != 0 {
+		t.Errorf("Expected no subscribers on another root, got: %v", other)
+	}
+}
+
+func TestCreateComment_AutoSubscribe(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	off := service.NewCommentService(memory.NewMemoryRepository())
+	on := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{AutoSubscribe: true})
+
+	for name, tc := range map[string]struct {
+		commentService *service.CommentService
+		want           string
+	}{
+		"disabled": {commentService: off, want: ""},
+		"enabled":  {commentService: on, want: "alice"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Execute
+			_, err := tc.commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Hello"})
+			if err != nil {
+				t.Fatalf("Failed to create comment: %v", err)
+			}
+			subscribers, err := tc.commentService.GetSubscriberUserIDs(ctx, "post-1")
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			// Assert
+			if strings.Join(subscribers, ",") != tc.want {
+				t.Errorf("Expected subscribers %q, got: %v", tc.want, subscribers)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestGetCommentSubtree(t *testing.T) {
+	// Setup: a -> b -> c, a sibling thread s -> t, and a pending reply to a
+	ctx := context.Background()
+	repo := memory.NewMemoryRepository()
+	commentService := service.NewCommentService(repo)
+	create := func(parentID *string, content string) *models.Comment {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: parentID, UserID: "alice", Content: content})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		return comment
+	}
+	a := create(nil, "Thread")
+	b := create(&a.ID, "Reply")
+	create(&b.ID, "Nested reply")
+	s := create(nil, "Sibling thread")
+	create(&s.ID, "Sibling reply")
+	pending := create(&a.ID, "Awaiting review")
+	if err := repo.SetCommentStatus(ctx, pending.ID, models.CommentStatusPending); err != nil {
+		t.Fatalf("Failed to hold comment: %v", err)
+	}
+
+	// Execute
+	tree, err := commentService.GetCommentSubtree(ctx, b.ID, 0, "")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if tree.Comment.ID != b.ID || len(tree.Children) != 1 || tree.Children[0].Comment.Content != "Nested reply" {
+		t.Fatalf("Expected the reply with its nested reply, got %+v", tree)
+	}
+
+	tree, err = commentService.GetCommentSubtree(ctx, a.ID, 1, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(tree.Children) != 1 || tree.Children[0].Comment.ID != b.ID {
+		t.Fatalf("Expected only the approved reply, got %d children", len(tree.Children))
+	}
+	if len(tree.Children[0].Children) != 0 {
+		t.Fatal("Expected max depth 1 to leave out the nested reply")
+	}
+
+	if _, err := commentService.GetCommentSubtree(ctx, "missing", 0, ""); !errors.Is(err, service.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got: %v", err)
+	}
+	if _, err := commentService.GetCommentSubtree(ctx, "", 0, ""); !errors.Is(err, service.ErrInvalidInput) {
+		t.Fatalf("Expected ErrInvalidInput, got: %v", err)
+	}
+}
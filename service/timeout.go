@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// withQueryTimeout bounds an expensive read by CommentServiceConfig.QueryTimeout.
+// A caller's own deadline is left alone, as is ctx when no timeout is set.
+func (s *CommentService) withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.config.QueryTimeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, s.config.QueryTimeout)
+}
+
+// timeoutError marks err as ErrTimeout when it happened because ctx ran out
+// of time. Drivers don't always wrap context.DeadlineExceeded, so the context
+// is checked rather than the error.
+func timeoutError(ctx context.Context, err error) error {
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) && !errors.Is(err, ErrTimeout) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return err
+}
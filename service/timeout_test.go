@@ -0,0 +1,120 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+// blockingRepository stands in for a database stuck on an expensive query:
+// its tree, top-comment and search reads wait until the context is done
+type blockingRepository struct {
+	*memory.MemoryRepository
+}
+
+func (r blockingRepository) GetCommentTree(ctx context.Context, rootID string, maxDepth int, sortBy string) ([]*models.CommentTree, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (r blockingRepository) GetTopComments(ctx context.Context, rootID string, limit int, timeRange string) ([]*models.Comment, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (r blockingRepository) GetCommentsByRootID(ctx context.Context, rootID string, filter *models.CommentFilter) ([]*models.Comment, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (r blockingRepository) GetComments(ctx context.Context, filter *models.CommentFilter) ([]*models.Comment, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestQueryTimeout_BoundsExpensiveReads(t *testing.T) {
+	// Setup
+	repo := blockingRepository{memory.NewMemoryRepository()}
+	commentService := service.NewCommentServiceWithConfig(repo, &service.CommentServiceConfig{QueryTimeout: 20 * time.Millisecond})
+	ctx := context.Background()
+
+	reads := map[string]func() error{
+		"GetCommentTree": func() error {
+			_, err := commentService.GetCommentTree(ctx, "post-1", 0, "")
+			return err
+		},
+		"GetTopComments": func() error {
+			_, err := commentService.GetTopComments(ctx, "post-1", 10, "")
+			return err
+		},
+		"SearchComments": func() error {
+			_, err := commentService.SearchComments(ctx, "post-1", "gopher", nil)
+			return err
+		},
+		"SearchAllComments": func() error {
+			_, err := commentService.SearchAllComments(ctx, "gopher", nil)
+			return err
+		},
+	}
+
+	for name, read := range reads {
+		t.Run(name, func(t *testing.T) {
+			// Execute
+			started := time.Now()
+			err := read()
+			elapsed := time.Since(started)
+
+			// Assert
+			if !errors.Is(err, service.ErrTimeout) {
+				t.Fatalf("Expected ErrTimeout, got: %v", err)
+			}
+			if elapsed > time.Second {
+				t.Errorf("Expected the read to stop at the timeout, took %v", elapsed)
+			}
+		})
+	}
+}
+
+func TestQueryTimeout_CallerDeadlineWins(t *testing.T) {
+	// Setup
+	repo := blockingRepository{memory.NewMemoryRepository()}
+	commentService := service.NewCommentServiceWithConfig(repo, &service.CommentServiceConfig{QueryTimeout: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// Execute
+	started := time.Now()
+	_, err := commentService.GetCommentTree(ctx, "post-1", 0, "")
+
+	// Assert
+	if !errors.Is(err, service.ErrTimeout) {
+		t.Fatalf("Expected ErrTimeout, got: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("Expected the caller's deadline to apply, took %v", elapsed)
+	}
+}
+
+func TestQueryTimeout_CancellationIsNotATimeout(t *testing.T) {
+	// Setup
+	repo := blockingRepository{memory.NewMemoryRepository()}
+	commentService := service.NewCommentService(repo)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	// Execute
+	_, err := commentService.GetTopComments(ctx, "post-1", 10, "")
+
+	// Assert
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the cancellation error, got: %v", err)
+	}
+	if errors.Is(err, service.ErrTimeout) {
+		t.Error("Expected a cancelled read not to be reported as a timeout")
+	}
+}
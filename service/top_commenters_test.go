@@ -0,0 +1,71 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+	"github.com/google/uuid"
+)
+
+func TestGetTopCommenters_RanksByActivity(t *testing.T) {
+	// Setup: alice has 3 comments, bob and carol 2 each with bob scoring
+	// higher, and dave only deleted comments
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	now := time.Now()
+	seed := func(rootID, userID string, upvotes int64, deleted bool) *models.Comment {
+		return &models.Comment{ID: uuid.New().String(), RootID: rootID, UserID: userID, Content: "Hello", CreatedAt: now, Upvotes: upvotes, IsDeleted: deleted}
+	}
+	err := commentService.ImportComments(ctx, []*models.Comment{
+		seed("post-1", "alice", 0, false),
+		seed("post-1", "alice", 0, false),
+		seed("post-1", "alice", 1, false),
+		seed("post-1", "bob", 4, false),
+		seed("post-1", "bob", 0, false),
+		seed("post-1", "carol", 2, false),
+		seed("post-1", "carol", 0, false),
+		seed("post-1", "dave", 9, true),
+		seed("post-1", "dave", 9, true),
+		seed("post-1", "dave", 9, true),
+		seed("post-1", "dave", 9, true),
+		seed("post-2", "carol", 0, false),
+	})
+	if err != nil {
+		t.Fatalf("Failed to seed comments: %v", err)
+	}
+
+	// Execute
+	commenters, err := commentService.GetTopCommenters(ctx, "post-1", 10)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	want := []models.TopCommenter{
+		{UserID: "alice", CommentCount: 3, TotalScore: 1},
+		{UserID: "bob", CommentCount: 2, TotalScore: 4},
+		{UserID: "carol", CommentCount: 2, TotalScore: 2},
+	}
+	if len(commenters) != len(want) {
+		t.Fatalf("Expected %d commenters, got: %+v", len(want), commenters)
+	}
+	for i := range want {
+		if *commenters[i] != want[i] {
+			t.Errorf("Expected %+v at position %d, got: %+v", want[i], i, *commenters[i])
+		}
+	}
+
+	// The limit keeps the most active
+	commenters, err = commentService.GetTopCommenters(ctx, "post-1", 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(commenters) != 1 || commenters[0].UserID != "alice" {
+		t.Fatalf("Expected only alice, got: %+v", commenters)
+	}
+}
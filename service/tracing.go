@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Span attribute keys recorded on CommentService spans
+const (
+	attrRootID    = attribute.Key("commentific.root_id")
+	attrCommentID = attribute.Key("commentific.comment_id")
+)
+
+// defaultTracer records nothing until SetTracer is called
+var defaultTracer trace.Tracer = noop.NewTracerProvider().Tracer("")
+
+// SetTracer replaces the tracer used for CommentService spans, e.g.
+// otel.Tracer("commentific") once a tracer provider is configured
+func (s *CommentService) SetTracer(tracer trace.Tracer) {
+	s.tracer = tracer
+}
+
+// startSpan opens a span named after a CommentService method
+func (s *CommentService) startSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, "CommentService."+method, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err, if any, and ends the span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,60 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/service"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing_GetCommentTreeEmitsSpan(t *testing.T) {
+	// Setup
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	commentService.SetTracer(provider.Tracer("commentific"))
+
+	// Execute
+	if _, err := commentService.GetCommentTree(context.Background(), "product-1", 5, ""); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "CommentService.GetCommentTree" {
+		t.Fatalf("Expected one CommentService.GetCommentTree span, got: %d", len(spans))
+	}
+	var rootID string
+	for _, attr := range spans[0].Attributes() {
+		if attr.Key == "commentific.root_id" {
+			rootID = attr.Value.AsString()
+		}
+	}
+	if rootID != "product-1" {
+		t.Fatalf("Expected root_id attribute product-1, got: %q", rootID)
+	}
+}
+
+func TestTracing_RecordsErrors(t *testing.T) {
+	// Setup
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	commentService.SetTracer(provider.Tracer("commentific"))
+
+	// Execute
+	_, err := commentService.GetComment(context.Background(), "missing")
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for missing comment, got nil")
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Status().Code != otelcodes.Error {
+		t.Fatal("Expected the GetComment span to carry an error status")
+	}
+}
@@ -0,0 +1,48 @@
+package service
+
+import "github.com/christopher18/commentific/v2/models"
+
+// TreeOptions shapes a comment tree after it is read
+type TreeOptions struct {
+	// MinScore collapses comments scoring below it. They stay in the tree
+	// with Collapsed set, so clients can show them folded and let users
+	// expand them. Nil collapses nothing.
+	MinScore *int64
+	// CollapseReplies also collapses every reply under a collapsed comment
+	CollapseReplies bool
+	// MaxChildrenPerNode keeps only the first replies of each comment, in
+	// the tree's sort order, and marks the comment HasMoreChildren with the
+	// count left out. Zero keeps them all.
+	MaxChildrenPerNode int
+}
+
+// apply shapes nodes in place
+func (opts TreeOptions) apply(nodes []*models.CommentTree) {
+	if opts.MinScore != nil {
+		collapseBelow(nodes, *opts.MinScore, opts.CollapseReplies, false)
+	}
+	if opts.MaxChildrenPerNode > 0 {
+		limitChildren(nodes, opts.MaxChildrenPerNode)
+	}
+}
+
+// limitChildren trims every node's replies to at most max
+func limitChildren(nodes []*models.CommentTree, max int) {
+	for _, node := range nodes {
+		if len(node.Children) > max {
+			node.RemainingChildren = len(node.Children) - max
+			node.HasMoreChildren = true
+			node.Children = node.Children[:max]
+		}
+		limitChildren(node.Children, max)
+	}
+}
+
+// collapseBelow flags nodes scoring under minScore, and with replies set,
+// everything under them. inherited is true below a collapsed node.
+func collapseBelow(nodes []*models.CommentTree, minScore int64, replies, inherited bool) {
+	for _, node := range nodes {
+		node.Collapsed = inherited || node.Comment.Score < minScore
+		collapseBelow(node.Children, minScore, replies, replies && node.Collapsed)
+	}
+}
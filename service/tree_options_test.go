@@ -0,0 +1,102 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestGetCommentTreeWithOptions_CollapsesLowScores(t *testing.T) {
+	// Setup: parent -> buried (-10) -> nested
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	create := func(parentID *string, content string) *models.Comment {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: parentID, UserID: "alice", Content: content})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		return comment
+	}
+	parent := create(nil, "Parent")
+	buried := create(&parent.ID, "Buried")
+	create(&buried.ID, "Nested")
+	for i := 0; i < 10; i++ {
+		if _, _, _, err := commentService.VoteComment(ctx, buried.ID, fmt.Sprintf("voter-%d", i), models.VoteTypeDown); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+	}
+	minScore := int64(-5)
+
+	// Execute
+	tree, err := commentService.GetCommentTreeWithOptions(ctx, "post-1", 0, "", service.TreeOptions{MinScore: &minScore})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(tree) != 1 || len(tree[0].Children) != 1 || len(tree[0].Children[0].Children) != 1 {
+		t.Fatalf("Expected every comment to stay in the tree, got %+v", tree)
+	}
+	if tree[0].Collapsed {
+		t.Error("Expected the parent to stay expanded")
+	}
+	if node := tree[0].Children[0]; !node.Collapsed || node.Comment.Score != -10 {
+		t.Errorf("Expected the -10 comment to be collapsed, got collapsed=%v score=%d", node.Collapsed, node.Comment.Score)
+	}
+	if tree[0].Children[0].Children[0].Collapsed {
+		t.Error("Expected the nested reply to stay expanded without CollapseReplies")
+	}
+
+	tree, err = commentService.GetCommentTreeWithOptions(ctx, "post-1", 0, "", service.TreeOptions{MinScore: &minScore, CollapseReplies: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if tree[0].Collapsed || !tree[0].Children[0].Children[0].Collapsed {
+		t.Error("Expected CollapseReplies to collapse the nested reply and leave the parent expanded")
+	}
+}
+
+func TestGetCommentTreeWithOptions_MaxChildrenPerNode(t *testing.T) {
+	// Setup: a comment with 50 replies, the last of them the best scored
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	parent, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Popular"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	var best *models.Comment
+	for i := 0; i < 50; i++ {
+		best, err = commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: &parent.ID, UserID: "bob", Content: fmt.Sprintf("Reply %d", i)})
+		if err != nil {
+			t.Fatalf("Failed to create reply: %v", err)
+		}
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, best.ID, "alice", models.VoteTypeUp); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	// Execute
+	tree, err := commentService.GetCommentTreeWithOptions(ctx, "post-1", 0, "score", service.TreeOptions{MaxChildrenPerNode: 10})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	node := tree[0]
+	if len(node.Children) != 10 {
+		t.Fatalf("Expected 10 replies, got %d", len(node.Children))
+	}
+	if !node.HasMoreChildren || node.RemainingChildren != 40 {
+		t.Errorf("Expected 40 more replies to be reported, got has_more=%v remaining=%d", node.HasMoreChildren, node.RemainingChildren)
+	}
+	if node.Children[0].Comment.ID != best.ID {
+		t.Errorf("Expected the best-scored reply first, got %q", node.Children[0].Comment.Content)
+	}
+	if node.Children[0].HasMoreChildren {
+		t.Error("Expected a reply without replies not to report more")
+	}
+}
@@ -0,0 +1,129 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestGetCommentTreeWithUserVotes(t *testing.T) {
+	// Setup: carol upvotes a thread and downvotes a reply; dave votes on the rest
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	thread, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Thread"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	reply, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: &thread.ID, UserID: "bob", Content: "Reply"})
+	if err != nil {
+		t.Fatalf("Failed to create reply: %v", err)
+	}
+	other, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "bob", Content: "Other thread"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	for _, vote := range []struct {
+		commentID, userID string
+		voteType          models.VoteType
+	}{
+		{thread.ID, "carol", models.VoteTypeUp},
+		{reply.ID, "carol", models.VoteTypeDown},
+		{other.ID, "dave", models.VoteTypeUp},
+	} {
+		if _, _, _, err := commentService.VoteComment(ctx, vote.commentID, vote.userID, vote.voteType); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+	}
+
+	// Execute
+	tree, err := commentService.GetCommentTreeWithUserVotes(ctx, "post-1", "carol", 10, "created_at")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	nodes := map[string]*models.CommentTree{}
+	var walk func([]*models.CommentTree)
+	walk = func(level []*models.CommentTree) {
+		for _, node := range level {
+			nodes[node.Comment.ID] = node
+			walk(node.Children)
+		}
+	}
+	walk(tree)
+	if len(tree) != 2 || len(nodes) != 3 {
+		t.Fatalf("Expected 2 threads and 3 comments, got %d threads and %d comments", len(tree), len(nodes))
+	}
+	if len(nodes[thread.ID].Children) != 1 || nodes[thread.ID].Children[0] != nodes[reply.ID] {
+		t.Fatalf("Expected the reply nested under its thread, got: %+v", nodes[thread.ID].Children)
+	}
+	if vote := nodes[thread.ID].Comment.CurrentUserVote; vote == nil || *vote != models.VoteTypeUp {
+		t.Errorf("Expected carol's upvote on the thread, got: %v", vote)
+	}
+	if vote := nodes[reply.ID].Comment.CurrentUserVote; vote == nil || *vote != models.VoteTypeDown {
+		t.Errorf("Expected carol's downvote on the reply, got: %v", vote)
+	}
+	if vote := nodes[other.ID].Comment.CurrentUserVote; vote != nil {
+		t.Errorf("Expected no vote from carol on the other thread, got: %v", *vote)
+	}
+
+	if _, err := commentService.GetCommentTreeWithUserVotes(ctx, "post-1", "", 10, ""); err == nil {
+		t.Error("Expected an error without a user ID")
+	}
+}
+
+func TestGetCommentsWithUserVotes_InlineMatchesMap(t *testing.T) {
+	// Setup: carol votes on two of three comments
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	var comments []*models.Comment
+	for i := 0; i < 3; i++ {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Vote on me"})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		comments = append(comments, comment)
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, comments[0].ID, "carol", models.VoteTypeUp); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, comments[1].ID, "carol", models.VoteTypeDown); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	// Execute
+	listed, votes, err := commentService.GetCommentsWithUserVotes(ctx, "post-1", "carol", nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(listed) != 3 || len(votes) != 2 {
+		t.Fatalf("Expected 3 comments and 2 votes, got %d and %d", len(listed), len(votes))
+	}
+	for _, comment := range listed {
+		vote, voted := votes[comment.ID]
+		switch {
+		case !voted && comment.CurrentUserVote != nil:
+			t.Errorf("Expected no inline vote on %s, got: %v", comment.ID, *comment.CurrentUserVote)
+		case voted && (comment.CurrentUserVote == nil || *comment.CurrentUserVote != vote.VoteType):
+			t.Errorf("Expected the inline vote on %s to be %v, got: %v", comment.ID, vote.VoteType, comment.CurrentUserVote)
+		}
+	}
+
+	// The plain list doesn't know who is asking
+	plain, err := commentService.GetCommentsByRoot(ctx, "post-1", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for _, comment := range plain {
+		if comment.CurrentUserVote != nil {
+			t.Errorf("Expected no inline vote on the plain list, got: %v on %s", *comment.CurrentUserVote, comment.ID)
+		}
+	}
+}
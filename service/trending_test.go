@@ -0,0 +1,69 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+	"github.com/google/uuid"
+)
+
+func TestGetTrendingRoots_RanksByRecentActivity(t *testing.T) {
+	// Setup: busy has 3 recent comments, quiet 1, tied 1 with a higher
+	// score, and stale only old comments
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	now := time.Now()
+	recent := now.Add(-time.Hour)
+	old := now.AddDate(0, 0, -3)
+	seed := func(rootID string, createdAt time.Time, upvotes int64) *models.Comment {
+		return &models.Comment{ID: uuid.New().String(), RootID: rootID, UserID: "alice", Content: "Hello", CreatedAt: createdAt, Upvotes: upvotes}
+	}
+	err := commentService.ImportComments(ctx, []*models.Comment{
+		seed("busy", recent, 0),
+		seed("busy", recent, 0),
+		seed("busy", recent, 0),
+		seed("quiet", recent, 0),
+		seed("tied", recent, 5),
+		seed("stale", old, 50),
+		seed("stale", old, 50),
+		seed("stale", old, 50),
+		seed("stale", old, 50),
+	})
+	if err != nil {
+		t.Fatalf("Failed to seed comments: %v", err)
+	}
+
+	// Execute
+	roots, err := commentService.GetTrendingRoots(ctx, "day", 10)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	want := []string{"busy", "tied", "quiet"}
+	if len(roots) != len(want) {
+		t.Fatalf("Expected %d trending roots, got: %d", len(want), len(roots))
+	}
+	for i, rootID := range want {
+		if roots[i].RootID != rootID {
+			t.Fatalf("Expected %s at position %d, got: %s", rootID, i, roots[i].RootID)
+		}
+	}
+	if roots[0].CommentCount != 3 || roots[1].TotalScore != 5 {
+		t.Fatalf("Expected 3 comments on busy and score 5 on tied, got: %d, %d", roots[0].CommentCount, roots[1].TotalScore)
+	}
+
+	// The stale root leads once the window covers its comments
+	roots, err = commentService.GetTrendingRoots(ctx, "week", 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(roots) != 1 || roots[0].RootID != "stale" {
+		t.Fatalf("Expected stale to lead the week, got: %+v", roots)
+	}
+}
@@ -0,0 +1,44 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestGetCommentsByUser_FilterByRoot(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	for _, rootID := range []string{"product-1", "product-1", "product-2"} {
+		_, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: rootID, UserID: "alice", Content: "Comment"})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+	}
+	_, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "product-1", UserID: "bob", Content: "Comment"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Execute
+	rootID := "product-1"
+	comments, err := commentService.GetCommentsByUser(ctx, "alice", &models.CommentFilter{RootID: &rootID})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if len(comments) != 2 {
+		t.Fatalf("Expected 2 comments, got: %d", len(comments))
+	}
+	for _, comment := range comments {
+		if comment.RootID != "product-1" || comment.UserID != "alice" {
+			t.Fatalf("Expected alice's product-1 comments only, got: %+v", comment)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestGetUserVotes_AcrossRoots(t *testing.T) {
+	// Setup: carol upvotes two comments on post-1 and downvotes one on post-2
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	var comments []*models.Comment
+	for _, rootID := range []string{"post-1", "post-1", "post-2"} {
+		comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: rootID, UserID: "alice", Content: "Vote on me"})
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		comments = append(comments, comment)
+	}
+	for i, voteType := range []models.VoteType{models.VoteTypeUp, models.VoteTypeUp, models.VoteTypeDown} {
+		if _, _, _, err := commentService.VoteComment(ctx, comments[i].ID, "carol", voteType); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, comments[0].ID, "dave", models.VoteTypeDown); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	// Execute
+	voted, err := commentService.GetUserVotes(ctx, "carol", nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(voted) != 3 {
+		t.Fatalf("Expected 3 voted comments, got: %d", len(voted))
+	}
+	byID := map[string]*models.VotedComment{}
+	for _, v := range voted {
+		byID[v.ID] = v
+	}
+	if byID[comments[1].ID] == nil || byID[comments[1].ID].VoteType != models.VoteTypeUp {
+		t.Fatalf("Expected an upvote on %s, got: %+v", comments[1].ID, byID[comments[1].ID])
+	}
+	if byID[comments[2].ID] == nil || byID[comments[2].ID].VoteType != models.VoteTypeDown || byID[comments[2].ID].RootID != "post-2" {
+		t.Fatalf("Expected a downvote on %s in post-2, got: %+v", comments[2].ID, byID[comments[2].ID])
+	}
+
+	upvote := models.VoteTypeUp
+	rootID := "post-1"
+	voted, err = commentService.GetUserVotes(ctx, "carol", &models.VoteFilter{RootID: &rootID, VoteType: &upvote})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(voted) != 2 {
+		t.Fatalf("Expected 2 upvotes on post-1, got: %d", len(voted))
+	}
+}
+
+func TestGetUserVotes_ExcludesDeletedComments(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Soon gone"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, comment.ID, "carol", models.VoteTypeUp); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+	if err := commentService.DeleteComment(ctx, comment.ID, "alice"); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+
+	// Execute
+	voted, err := commentService.GetUserVotes(ctx, "carol", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	withDeleted, err := commentService.GetUserVotes(ctx, "carol", &models.VoteFilter{IncludeDeleted: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if len(voted) != 0 {
+		t.Fatalf("Expected votes on deleted comments to be hidden, got: %d", len(voted))
+	}
+	if len(withDeleted) != 1 || !withDeleted[0].IsDeleted {
+		t.Fatalf("Expected the deleted comment with IncludeDeleted, got: %+v", withDeleted)
+	}
+}
+
+func TestGetUserVotes_RejectsNoneFilter(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	none := models.VoteTypeNone
+
+	// Execute
+	_, err := commentService.GetUserVotes(context.Background(), "carol", &models.VoteFilter{VoteType: &none})
+
+	// Assert
+	if !errors.Is(err, service.ErrInvalidInput) {
+		t.Fatalf("Expected ErrInvalidInput, got: %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// FieldError describes why one request field was rejected
+type FieldError struct {
+	Field   string `json:"field"`   // JSON name of the field, or "" when the problem isn't tied to one
+	Rule    string `json:"rule"`    // The check that failed, e.g. "required" or "max"
+	Message string `json:"message"` // Human-readable explanation
+}
+
+// ValidationResult is the outcome of a dry-run validation
+type ValidationResult struct {
+	Valid  bool         `json:"valid"`
+	Fields []FieldError `json:"fields,omitempty"` // Every problem found, in the order CreateComment checks them
+}
+
+// ValidateCreate runs the checks CreateComment makes on req without storing
+// anything, so a client can validate a draft as the user types. Problems
+// with the draft come back in the result; the error is reserved for failures
+// such as the database being unreachable.
+func (s *CommentService) ValidateCreate(ctx context.Context, req *models.CreateCommentRequest) (_ *ValidationResult, err error) {
+	ctx, span := s.startSpan(ctx, "ValidateCreate")
+	defer func() { endSpan(span, err) }()
+
+	if req == nil {
+		return nil, invalidInput("request is required")
+	}
+
+	// Leave the caller's request as it was
+	draft := *req
+	_, _, problems, err := s.draftComment(ctx, &draft)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ValidationResult{Valid: len(problems) == 0}
+	for _, problem := range problems {
+		result.Fields = append(result.Fields, FieldErrors(problem)...)
+	}
+	return result, nil
+}
+
+// draftComment runs every check CreateComment makes on req and builds the
+// comment it would store, normalizing req.Content on the way, along with the
+// rules of its root. It returns all the problems it finds with the request,
+// in the order CreateComment reports them, skipping checks that depend on a
+// failed one. err is set when a check could not run at all.
+func (s *CommentService) draftComment(ctx context.Context, req *models.CreateCommentRequest) (_ *models.Comment, _ rootRules, problems []error, err error) {
+	// Validate the request, leaving the text of a media-only comment to the
+	// checks below
+	content := s.normalizeContent(req.Content)
+	mediaOnly := content == "" && s.allowsEmptyContent(req.MediaURL)
+	if mediaOnly {
+		err = s.validator.StructExcept(req, "Content")
+	} else {
+		err = s.validator.Struct(req)
+	}
+	if err != nil {
+		return nil, rootRules{}, []error{validationFailed(err)}, nil
+	}
+
+	// Sanitize content
+	req.Content = content
+	if req.Content == "" {
+		if !mediaOnly {
+			problems = append(problems, invalidField("content", "required", "comment content cannot be empty"))
+		}
+	} else if req.Content, err = s.limitLines(req.Content); err != nil {
+		problems = append(problems, err)
+	} else if err := s.checkContentLength(req.Content); err != nil {
+		problems = append(problems, err)
+	}
+
+	// Validate URLs if provided
+	if req.MediaURL != nil && *req.MediaURL != "" && !s.isValidURL(*req.MediaURL) {
+		problems = append(problems, invalidField("media_url", "url", "invalid media URL"))
+	}
+	if req.LinkURL != nil && *req.LinkURL != "" && !s.isValidURL(*req.LinkURL) {
+		problems = append(problems, invalidField("link_url", "url", "invalid link URL"))
+	}
+
+	rules, err := s.rulesFor(ctx, req.RootID)
+	if err != nil {
+		return nil, rootRules{}, nil, err
+	}
+	if rules.locked {
+		problems = append(problems, ErrRootLocked)
+	}
+
+	comment := &models.Comment{
+		ID:       uuid.New().String(),
+		RootID:   req.RootID,
+		ParentID: req.ParentID,
+		UserID:   req.UserID,
+		Content:  req.Content,
+		MediaURL: req.MediaURL,
+		LinkURL:  req.LinkURL,
+		Status:   models.CommentStatusApproved,
+	}
+	if rules.preModeration {
+		comment.Status = models.CommentStatusPending
+	}
+	if req.Anonymous {
+		if err := s.prepareAnonymous(comment, req, rules.allowAnonymous); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	// Validate parent comment exists and belongs to same root if parentID is provided
+	if req.ParentID != nil {
+		problem, err := s.checkParent(ctx, *req.ParentID, req.RootID, rules.maxDepth)
+		if err != nil {
+			return nil, rootRules{}, nil, err
+		}
+		if problem != nil {
+			problems = append(problems, problem)
+		}
+	}
+
+	return comment, rules, problems, nil
+}
+
+// checkParent reports why a reply can't be made under parentID, or nil if it
+// can. err is set when the parent could not be read.
+func (s *CommentService) checkParent(ctx context.Context, parentID, rootID string, maxDepth int) (problem, err error) {
+	parent, err := s.repo.GetCommentByID(ctx, parentID)
+	if errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("parent comment not found: %w", err), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent comment: %w", err)
+	}
+	return parentProblem(parent, rootID, maxDepth), nil
+}
+
+// parentProblem reports why a reply in rootID can't be made under parent, or
+// nil if it can
+func parentProblem(parent *models.Comment, rootID string, maxDepth int) error {
+	if parent.RootID != rootID {
+		return invalidField("parent_id", "same_root", "parent comment belongs to different root")
+	}
+	if parent.Status != models.CommentStatusApproved {
+		return invalidField("parent_id", "approved", "cannot reply to a comment that has not been approved")
+	}
+	if parent.Depth >= maxDepth { // Prevent extremely deep nesting
+		return &MaxDepthError{Limit: maxDepth}
+	}
+	return nil
+}
+
+// FieldErrors describes err field by field, for the errors CreateComment
+// returns for a bad request. Other errors come back as a single entry with
+// no field.
+func FieldErrors(err error) []FieldError {
+	var inputErr *InputError
+	var tooShort *ContentTooShortError
+	var tooManyLines *TooManyLinesError
+	var tooDeep *MaxDepthError
+	switch {
+	case errors.As(err, &inputErr) && len(inputErr.Fields) > 0:
+		return inputErr.Fields
+	case errors.As(err, &tooShort):
+		return []FieldError{{Field: "content", Rule: "min", Message: err.Error()}}
+	case errors.As(err, &tooManyLines):
+		rule := "max_lines"
+		if tooManyLines.Blank {
+			rule = "max_blank_lines"
+		}
+		return []FieldError{{Field: "content", Rule: rule, Message: err.Error()}}
+	case errors.As(err, &tooDeep):
+		return []FieldError{{Field: "parent_id", Rule: "max_depth", Message: err.Error()}}
+	case errors.Is(err, ErrRootLocked):
+		return []FieldError{{Field: "root_id", Rule: "unlocked", Message: err.Error()}}
+	case errors.Is(err, ErrNotFound):
+		return []FieldError{{Field: "parent_id", Rule: "exists", Message: err.Error()}}
+	default:
+		return []FieldError{{Rule: "invalid", Message: err.Error()}}
+	}
+}
+
+// newValidator builds the struct validator, naming fields by their JSON
+// names so errors match what clients send
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+// validationFieldErrors translates the failures reported by the validator
+func validationFieldErrors(errs validator.ValidationErrors) []FieldError {
+	fields := make([]FieldError, 0, len(errs))
+	for _, fe := range errs {
+		var message string
+		switch fe.Tag() {
+		case "required", "required_unless":
+			message = fmt.Sprintf("%s is required", fe.Field())
+		case "min":
+			message = fmt.Sprintf("%s must be at least %s characters", fe.Field(), fe.Param())
+		case "max":
+			message = fmt.Sprintf("%s must be at most %s characters", fe.Field(), fe.Param())
+		case "oneof":
+			message = fmt.Sprintf("%s must be one of %s", fe.Field(), fe.Param())
+		default:
+			message = fmt.Sprintf("%s failed the %s check", fe.Field(), fe.Tag())
+		}
+		fields = append(fields, FieldError{Field: fe.Field(), Rule: fe.Tag(), Message: message})
+	}
+	return fields
+}
@@ -0,0 +1,117 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestValidateCreate_ValidDraft(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	repo := memory.NewMemoryRepository()
+	commentService := service.NewCommentService(repo)
+	link := "https://example.com"
+
+	// Execute
+	result, err := commentService.ValidateCreate(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "  Looks good  ", LinkURL: &link})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !result.Valid || len(result.Fields) != 0 {
+		t.Errorf("Expected a valid draft, got %+v", result)
+	}
+	stats, err := commentService.GetCommentStats(ctx, "post-1")
+	if err != nil {
+		t.Fatalf("Failed to get stats: %v", err)
+	}
+	if stats.TotalCount != 0 {
+		t.Errorf("Expected nothing to be stored, got %d comments", stats.TotalCount)
+	}
+}
+
+func TestValidateCreate_InvalidDrafts(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{MinCommentLength: 5, MaxCommentDepth: 1})
+	parent, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Parent comment"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	reply, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", ParentID: &parent.ID, UserID: "bob", Content: "Reply comment"})
+	if err != nil {
+		t.Fatalf("Failed to create reply: %v", err)
+	}
+	badURL := "ftp://example.com/file"
+	missing := "missing"
+
+	cases := map[string]struct {
+		req  models.CreateCommentRequest
+		want []string // field:rule
+	}{
+		"missing fields": {
+			req:  models.CreateCommentRequest{},
+			want: []string{"root_id:required", "user_id:required_unless", "content:required"},
+		},
+		"blank content": {
+			req:  models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "   "},
+			want: []string{"content:required"},
+		},
+		"short content and bad URLs": {
+			req:  models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Hi", MediaURL: &badURL, LinkURL: &badURL},
+			want: []string{"content:min", "media_url:url", "link_url:url"},
+		},
+		"too long": {
+			req:  models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: strings.Repeat("a", 10001)},
+			want: []string{"content:max"},
+		},
+		"guests disabled": {
+			req:  models.CreateCommentRequest{RootID: "post-1", Anonymous: true, Content: "Hello there"},
+			want: []string{"anonymous:allowed"},
+		},
+		"missing parent": {
+			req:  models.CreateCommentRequest{RootID: "post-1", ParentID: &missing, UserID: "alice", Content: "Hello there"},
+			want: []string{"parent_id:exists"},
+		},
+		"too deep": {
+			req:  models.CreateCommentRequest{RootID: "post-1", ParentID: &reply.ID, UserID: "alice", Content: "Hello there"},
+			want: []string{"parent_id:max_depth"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Execute
+			result, err := commentService.ValidateCreate(ctx, &tc.req)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if result.Valid {
+				t.Fatalf("Expected an invalid draft")
+			}
+			var got []string
+			for _, field := range result.Fields {
+				if field.Message == "" {
+					t.Errorf("Expected a message for %s", field.Field)
+				}
+				got = append(got, field.Field+":"+field.Rule)
+			}
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("Expected %v, got %v", tc.want, got)
+			}
+
+			// CreateComment refuses the same draft with the first problem
+			if _, err := commentService.CreateComment(ctx, &tc.req); err == nil {
+				t.Errorf("Expected CreateComment to refuse the draft")
+			}
+		})
+	}
+}
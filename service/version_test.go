@@ -0,0 +1,89 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestUpdateComment_RejectsStaleVersion(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "First draft"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	if comment.Version != 1 {
+		t.Fatalf("Expected a new comment at version 1, got %d", comment.Version)
+	}
+
+	// Two editors read version 1
+	readVersion := comment.Version
+	first, second := "Edited by the author", "Redacted by a moderator"
+
+	// Execute
+	err = commentService.UpdateComment(ctx, comment.ID, "alice", &models.UpdateCommentRequest{Content: &first, Version: &readVersion})
+	if err != nil {
+		t.Fatalf("Expected the first update to succeed, got: %v", err)
+	}
+	err = commentService.UpdateComment(ctx, comment.ID, "alice", &models.UpdateCommentRequest{Content: &second, Version: &readVersion})
+
+	// Assert
+	if !errors.Is(err, service.ErrVersionConflict) {
+		t.Fatalf("Expected ErrVersionConflict for the stale update, got: %v", err)
+	}
+	stored, err := commentService.GetComment(ctx, comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	if stored.Content != first {
+		t.Errorf("Expected the first edit to survive, got %q", stored.Content)
+	}
+	if stored.Version != 2 {
+		t.Errorf("Expected version 2 after one update, got %d", stored.Version)
+	}
+
+	// Retrying against the current version goes through, as do unversioned updates
+	current := stored.Version
+	if err := commentService.UpdateComment(ctx, comment.ID, "alice", &models.UpdateCommentRequest{Content: &second, Version: &current}); err != nil {
+		t.Fatalf("Expected the retried update to succeed, got: %v", err)
+	}
+	if err := commentService.UpdateComment(ctx, comment.ID, "alice", &models.UpdateCommentRequest{Content: &first}); err != nil {
+		t.Fatalf("Expected an unversioned update to succeed, got: %v", err)
+	}
+	stored, _ = commentService.GetComment(ctx, comment.ID)
+	if stored.Version != 4 {
+		t.Errorf("Expected version 4 after three updates, got %d", stored.Version)
+	}
+}
+
+func TestUpdateComment_RepositoryRejectsStaleVersion(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	repo := memory.NewMemoryRepository()
+	if err := repo.CreateComment(ctx, &models.Comment{ID: "c1", RootID: "post-1", UserID: "alice", Content: "Original"}); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	stale := int64(1)
+	edit := "Concurrent edit"
+	if err := repo.UpdateComment(ctx, "c1", &models.UpdateCommentRequest{Content: &edit}); err != nil {
+		t.Fatalf("Failed to update comment: %v", err)
+	}
+
+	// Execute
+	late := "Late edit"
+	err := repo.UpdateComment(ctx, "c1", &models.UpdateCommentRequest{Content: &late, Version: &stale})
+
+	// Assert
+	if !errors.Is(err, service.ErrVersionConflict) {
+		t.Fatalf("Expected ErrVersionConflict, got: %v", err)
+	}
+	if err := repo.UpdateComment(ctx, "missing", &models.UpdateCommentRequest{Content: &late, Version: &stale}); !errors.Is(err, service.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound for a missing comment, got: %v", err)
+	}
+}
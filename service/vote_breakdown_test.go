@@ -0,0 +1,62 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestGetVoteBreakdown_MixedVotes(t *testing.T) {
+	// Setup: three upvotes, two downvotes, one of which was changed from up
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Divisive"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	votes := []struct {
+		userID   string
+		voteType models.VoteType
+	}{
+		{"bob", models.VoteTypeUp},
+		{"carol", models.VoteTypeUp},
+		{"dave", models.VoteTypeUp},
+		{"erin", models.VoteTypeDown},
+		{"frank", models.VoteTypeUp},
+		{"frank", models.VoteTypeDown},
+	}
+	for _, v := range votes {
+		if _, _, _, err := commentService.VoteComment(ctx, comment.ID, v.userID, v.voteType); err != nil {
+			t.Fatalf("Failed to vote: %v", err)
+		}
+	}
+
+	// Execute
+	breakdown, err := commentService.GetVoteBreakdown(ctx, comment.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if breakdown.Upvotes != 3 || breakdown.Downvotes != 2 || breakdown.Voters != 5 {
+		t.Fatalf("Expected 3 up, 2 down from 5 voters, got: %+v", breakdown)
+	}
+}
+
+func TestGetVoteBreakdown_NotFound(t *testing.T) {
+	// Setup
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+
+	// Execute
+	_, err := commentService.GetVoteBreakdown(context.Background(), "missing")
+
+	// Assert
+	if !errors.Is(err, service.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got: %v", err)
+	}
+}
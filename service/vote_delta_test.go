@@ -0,0 +1,88 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestVoteComment_ScoreDelta(t *testing.T) {
+	cases := map[string]struct {
+		prior     models.VoteType // VoteTypeNone for a first-time vote
+		vote      models.VoteType
+		wantDelta int64
+	}{
+		"first upvote":   {vote: models.VoteTypeUp, wantDelta: 1},
+		"first downvote": {vote: models.VoteTypeDown, wantDelta: -1},
+		"up to down":     {prior: models.VoteTypeUp, vote: models.VoteTypeDown, wantDelta: -2},
+		"down to up":     {prior: models.VoteTypeDown, vote: models.VoteTypeUp, wantDelta: 2},
+		"repeated vote":  {prior: models.VoteTypeUp, vote: models.VoteTypeUp, wantDelta: 0},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			ctx := context.Background()
+			commentService := service.NewCommentService(memory.NewMemoryRepository())
+			comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Vote on me"})
+			if err != nil {
+				t.Fatalf("Failed to create comment: %v", err)
+			}
+			// Another voter's vote shouldn't count toward the delta
+			if _, _, _, err := commentService.VoteComment(ctx, comment.ID, "carol", models.VoteTypeUp); err != nil {
+				t.Fatalf("Failed to vote: %v", err)
+			}
+			if tc.prior != models.VoteTypeNone {
+				if _, _, _, err := commentService.VoteComment(ctx, comment.ID, "bob", tc.prior); err != nil {
+					t.Fatalf("Failed to cast prior vote: %v", err)
+				}
+			}
+			before, err := commentService.GetComment(ctx, comment.ID)
+			if err != nil {
+				t.Fatalf("Failed to get comment: %v", err)
+			}
+
+			// Execute
+			after, _, delta, err := commentService.VoteComment(ctx, comment.ID, "bob", tc.vote)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if delta != tc.wantDelta {
+				t.Errorf("Expected a delta of %d, got %d", tc.wantDelta, delta)
+			}
+			if after.Score-before.Score != delta {
+				t.Errorf("Expected the delta to match the score change from %d to %d, got %d", before.Score, after.Score, delta)
+			}
+		})
+	}
+}
+
+func TestVoteComment_ScoreDeltaWeighted(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	commentService.SetVoteWeightResolver(roleWeights{experts: map[string]bool{"expert": true}})
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Vote on me"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, comment.ID, "expert", models.VoteTypeUp); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	// Execute
+	_, _, delta, err := commentService.VoteComment(ctx, comment.ID, "expert", models.VoteTypeDown)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if delta != -6 {
+		t.Errorf("Expected a weight-3 switch from up to down to move the score by -6, got %d", delta)
+	}
+}
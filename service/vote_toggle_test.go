@@ -0,0 +1,84 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+func TestVoteComment_ToggleVotes(t *testing.T) {
+	for _, voteType := range []models.VoteType{models.VoteTypeUp, models.VoteTypeDown} {
+		t.Run(voteType.String(), func(t *testing.T) {
+			// Setup
+			ctx := context.Background()
+			commentService := service.NewCommentServiceWithConfig(memory.NewMemoryRepository(), &service.CommentServiceConfig{ToggleVotes: true})
+			comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Vote on me"})
+			if err != nil {
+				t.Fatalf("Failed to create comment: %v", err)
+			}
+			if _, _, _, err := commentService.VoteComment(ctx, comment.ID, "bob", voteType); err != nil {
+				t.Fatalf("Failed to vote: %v", err)
+			}
+
+			// Execute
+			toggled, vote, delta, err := commentService.VoteComment(ctx, comment.ID, "bob", voteType)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if vote != nil {
+				t.Errorf("Expected the vote to be cleared, got: %+v", vote)
+			}
+			if toggled.Score != 0 || toggled.Upvotes != 0 || toggled.Downvotes != 0 {
+				t.Errorf("Expected the score back at baseline, got score %d with %d up and %d down", toggled.Score, toggled.Upvotes, toggled.Downvotes)
+			}
+			if delta != -int64(voteType) {
+				t.Errorf("Expected a delta of %d undoing the vote, got %d", -int64(voteType), delta)
+			}
+			stored, err := commentService.GetUserVote(ctx, comment.ID, "bob")
+			if err != nil {
+				t.Fatalf("Failed to get vote: %v", err)
+			}
+			if stored != nil {
+				t.Errorf("Expected no stored vote, got: %+v", stored)
+			}
+
+			// A third vote casts it again
+			again, vote, _, err := commentService.VoteComment(ctx, comment.ID, "bob", voteType)
+			if err != nil {
+				t.Fatalf("Failed to vote again: %v", err)
+			}
+			if vote == nil || again.Score != int64(voteType) {
+				t.Errorf("Expected the vote back with score %d, got vote %+v and score %d", voteType, vote, again.Score)
+			}
+		})
+	}
+}
+
+func TestVoteComment_RepeatKeepsVoteByDefault(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "Vote on me"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	if _, _, _, err := commentService.VoteComment(ctx, comment.ID, "bob", models.VoteTypeUp); err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+
+	// Execute
+	repeated, vote, delta, err := commentService.VoteComment(ctx, comment.ID, "bob", models.VoteTypeUp)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if vote == nil || repeated.Score != 1 || delta != 0 {
+		t.Errorf("Expected the upvote to stand with score 1 and delta 0, got vote %+v, score %d, delta %d", vote, repeated.Score, delta)
+	}
+}
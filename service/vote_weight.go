@@ -0,0 +1,39 @@
+package service
+
+import "context"
+
+// VoteWeightResolver decides how many points a user's vote moves a comment's
+// score, e.g. 3 for verified experts. Votes are stored as cast along with
+// their weight; upvote and downvote counts still count voters. Replace the
+// default, which weighs every vote 1, with SetVoteWeightResolver.
+type VoteWeightResolver interface {
+	VoteWeight(ctx context.Context, userID string) (int, error)
+}
+
+// unitVoteWeights counts every vote once
+type unitVoteWeights struct{}
+
+func (unitVoteWeights) VoteWeight(ctx context.Context, userID string) (int, error) {
+	return 1, nil
+}
+
+// SetVoteWeightResolver replaces the resolver consulted by VoteComment. A nil
+// resolver restores the default, which weighs every vote 1.
+func (s *CommentService) SetVoteWeightResolver(resolver VoteWeightResolver) {
+	if resolver == nil {
+		resolver = unitVoteWeights{}
+	}
+	s.voteWeights = resolver
+}
+
+// voteWeight asks the resolver for userID's weight. A failing resolver is
+// logged and the vote counts once, so an outage doesn't block voting;
+// weights below 1 count once too.
+func (s *CommentService) voteWeight(ctx context.Context, userID string) int {
+	weight, err := s.voteWeights.VoteWeight(ctx, userID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "vote weight lookup failed", "method", "CommentService.VoteComment", "error", err)
+		return 1
+	}
+	return max(weight, 1)
+}
@@ -0,0 +1,101 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christopher18/commentific/v2/memory"
+	"github.com/christopher18/commentific/v2/models"
+	"github.com/christopher18/commentific/v2/service"
+)
+
+// roleWeights weighs verified experts' votes 3 and everyone else's 1
+type roleWeights struct {
+	experts map[string]bool
+	err     error
+}
+
+func (w roleWeights) VoteWeight(ctx context.Context, userID string) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	if w.experts[userID] {
+		return 3, nil
+	}
+	return 1, nil
+}
+
+func TestVoteComment_WeightedByResolver(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	repo := memory.NewMemoryRepository()
+	commentService := service.NewCommentService(repo)
+	commentService.SetVoteWeightResolver(roleWeights{experts: map[string]bool{"expert": true}})
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "A claim"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Execute
+	afterExpert, vote, _, err := commentService.VoteComment(ctx, comment.ID, "expert", models.VoteTypeUp)
+	if err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+	afterBoth, _, _, err := commentService.VoteComment(ctx, comment.ID, "bob", models.VoteTypeDown)
+	if err != nil {
+		t.Fatalf("Failed to vote: %v", err)
+	}
+	flipped, _, _, err := commentService.VoteComment(ctx, comment.ID, "expert", models.VoteTypeDown)
+	if err != nil {
+		t.Fatalf("Failed to flip vote: %v", err)
+	}
+
+	// Assert
+	if afterExpert.Score != 3 || afterExpert.Upvotes != 1 {
+		t.Errorf("Expected the expert's upvote to add 3 points from 1 voter, got score %d from %d upvotes", afterExpert.Score, afterExpert.Upvotes)
+	}
+	if vote.Weight != 3 || vote.VoteType != models.VoteTypeUp {
+		t.Errorf("Expected the raw upvote stored with weight 3, got: %+v", vote)
+	}
+	if afterBoth.Score != 2 {
+		t.Errorf("Expected score 3 - 1 = 2, got %d", afterBoth.Score)
+	}
+	if flipped.Score != -4 || flipped.Downvotes != 2 {
+		t.Errorf("Expected score -3 - 1 = -4 from 2 downvotes, got %d from %d", flipped.Score, flipped.Downvotes)
+	}
+
+	// Recomputing keeps the weights
+	if err := repo.UpdateCommentScores(ctx, []string{comment.ID}); err != nil {
+		t.Fatalf("Failed to update scores: %v", err)
+	}
+	recomputed, err := commentService.GetComment(ctx, comment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	if recomputed.Score != -4 {
+		t.Errorf("Expected UpdateCommentScores to keep the weighted score -4, got %d", recomputed.Score)
+	}
+}
+
+func TestVoteComment_WeightResolverFailureCountsOnce(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	commentService := service.NewCommentService(memory.NewMemoryRepository())
+	commentService.SetVoteWeightResolver(roleWeights{err: errors.New("directory unavailable")})
+	comment, err := commentService.CreateComment(ctx, &models.CreateCommentRequest{RootID: "post-1", UserID: "alice", Content: "A claim"})
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	// Execute
+	voted, _, _, err := commentService.VoteComment(ctx, comment.ID, "expert", models.VoteTypeUp)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected the vote to go through, got: %v", err)
+	}
+	if voted.Score != 1 {
+		t.Errorf("Expected the vote to count once, got score %d", voted.Score)
+	}
+}